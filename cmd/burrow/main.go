@@ -9,7 +9,9 @@ import (
 
 func main() {
 	if err := ui.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		if ui.Reportable(err) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		os.Exit(ui.ExitCode(err))
 	}
 }
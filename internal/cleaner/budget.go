@@ -0,0 +1,45 @@
+package cleaner
+
+import (
+	"sort"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+// PlanBudget picks results of the given risk level until their combined size
+// reaches target. At each step it prefers the smallest result that covers the
+// remaining amount on its own, and otherwise takes the largest one, so the
+// plan overshoots the target as little as possible.
+func PlanBudget(results []rules.Result, risk rules.RiskLevel, target int64) ([]rules.Result, int64) {
+	var pool []rules.Result
+	for _, res := range results {
+		if res.Rule.RiskLevel == risk && res.TotalSize > 0 {
+			pool = append(pool, res)
+		}
+	}
+
+	// Largest first
+	sort.Slice(pool, func(i, j int) bool {
+		return pool[i].TotalSize > pool[j].TotalSize
+	})
+
+	var selected []rules.Result
+	var total int64
+	for total < target && len(pool) > 0 {
+		remaining := target - total
+
+		pick := 0
+		for i := len(pool) - 1; i >= 0; i-- {
+			if pool[i].TotalSize >= remaining {
+				pick = i
+				break
+			}
+		}
+
+		selected = append(selected, pool[pick])
+		total += pool[pick].TotalSize
+		pool = append(pool[:pick], pool[pick+1:]...)
+	}
+
+	return selected, total
+}
@@ -0,0 +1,53 @@
+package cleaner
+
+import (
+	"testing"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+func result(name string, risk rules.RiskLevel, size int64) rules.Result {
+	return rules.Result{
+		Rule:      rules.CleanupRule{Name: name, RiskLevel: risk},
+		TotalSize: size,
+	}
+}
+
+func TestPlanBudget(t *testing.T) {
+	results := []rules.Result{
+		result("huge", rules.RiskSafe, 100),
+		result("medium", rules.RiskSafe, 30),
+		result("small", rules.RiskSafe, 5),
+		result("risky", rules.RiskCaution, 500),
+	}
+
+	tests := []struct {
+		name      string
+		target    int64
+		wantNames []string
+		wantTotal int64
+	}{
+		{"Single best fit", 20, []string{"medium"}, 30},
+		{"Exact small", 5, []string{"small"}, 5},
+		{"Needs several", 120, []string{"huge", "medium"}, 130},
+		{"Not reachable", 1000, []string{"huge", "medium", "small"}, 135},
+		{"Zero target", 0, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, total := PlanBudget(results, rules.RiskSafe, tt.target)
+			if total != tt.wantTotal {
+				t.Errorf("total = %d, want %d", total, tt.wantTotal)
+			}
+			if len(selected) != len(tt.wantNames) {
+				t.Fatalf("selected %d results, want %d", len(selected), len(tt.wantNames))
+			}
+			for i, name := range tt.wantNames {
+				if selected[i].Rule.Name != name {
+					t.Errorf("selected[%d] = %s, want %s", i, selected[i].Rule.Name, name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package cleaner
+
+import (
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+// TrashCap is the check of a cleanup against the trash size cap.
+type TrashCap struct {
+	Cap      int64
+	Used     int64 // what the trash holds now
+	Incoming int64 // what the cleanup adds
+	// Purge lists the oldest sessions to purge, oldest first, so the trash
+	// stays under the cap; Freed is their size.
+	Purge []TrashSession
+	Freed int64
+}
+
+// Over reports whether the cleanup would take the trash past the cap.
+func (p *TrashCap) Over() bool {
+	return p.Cap > 0 && p.Used+p.Incoming > p.Cap
+}
+
+// Fits reports whether the trash stays under the cap once Purge is purged.
+func (p *TrashCap) Fits() bool {
+	return p.Used-p.Freed+p.Incoming <= p.Cap
+}
+
+// CheckTrashCap checks whether trashing results would take the trash past
+// limit and picks the oldest sessions to purge first. A limit of 0 means no
+// cap.
+func (c *Cleaner) CheckTrashCap(results []rules.Result, limit int64) (*TrashCap, error) {
+	p := &TrashCap{Cap: limit}
+	for _, res := range results {
+		if !res.Rule.ReportOnly {
+			p.Incoming += res.TotalSize
+		}
+	}
+	if limit <= 0 {
+		return p, nil
+	}
+
+	sessions, err := c.trashManager.Sessions()
+	if err != nil {
+		return nil, err
+	}
+	sizes := make([]int64, len(sessions))
+	for i, s := range sessions {
+		sizes[i], _ = pathSize(s.Dir)
+		p.Used += sizes[i]
+	}
+	if !p.Over() {
+		return p, nil
+	}
+
+	// Sessions are newest first; a session still being written is never
+	// purged
+	for i := len(sessions) - 1; i >= 0 && !p.Fits(); i-- {
+		if sessions[i].Interrupted {
+			continue
+		}
+		p.Purge = append(p.Purge, sessions[i])
+		p.Freed += sizes[i]
+	}
+	return p, nil
+}
+
+// PurgeForCap purges the sessions the cap check picked.
+func (c *Cleaner) PurgeForCap(p *TrashCap) error {
+	for _, s := range p.Purge {
+		if err := c.trashManager.Purge(s.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+func TestCheckTrashCap(t *testing.T) {
+	base := t.TempDir()
+	tm := &TrashManager{TrashBaseDir: base}
+	for _, id := range []string{"20240101_000000", "20240102_000000", "20240103_000000"} {
+		if err := os.MkdirAll(filepath.Join(base, id), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(base, id, "data"), []byte(strings.Repeat("x", 100)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The oldest session is still being written, so it is never purged
+	if err := os.WriteFile(filepath.Join(base, "20240101_000000", journalName), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Cleaner{trashManager: tm}
+	results := []rules.Result{{TotalSize: 50}, {Rule: rules.CleanupRule{ReportOnly: true}, TotalSize: 1000}}
+
+	p, err := c.CheckTrashCap(results, 250)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Incoming != 50 || p.Used != 302 || !p.Over() {
+		t.Fatalf("plan = %+v, want 302 used, 50 incoming, over the cap", p)
+	}
+	if len(p.Purge) != 2 || p.Purge[0].ID != "20240102_000000" || p.Purge[1].ID != "20240103_000000" || !p.Fits() {
+		t.Errorf("purge = %+v, want the two sessions that are not interrupted", p.Purge)
+	}
+
+	if err := c.PurgeForCap(p); err != nil {
+		t.Fatal(err)
+	}
+	if sessions, _ := tm.Sessions(); len(sessions) != 1 {
+		t.Errorf("%d sessions left, want 1", len(sessions))
+	}
+
+	if p, _ := c.CheckTrashCap(results, 0); p.Over() || len(p.Purge) != 0 {
+		t.Errorf("no cap: plan = %+v", p)
+	}
+}
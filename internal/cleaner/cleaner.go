@@ -1,16 +1,36 @@
 package cleaner
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/ismailtsdln/burrow/internal/history"
 	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/usage"
 )
 
 // Cleaner coordinates the cleanup process.
 type Cleaner struct {
 	trashManager *TrashManager
+
+	// UseSystemTrash sends files to the OS trash (Finder Trash, Recycle Bin)
+	// instead of Burrow's own trash. Undo is then handled by the OS.
+	UseSystemTrash bool
+
+	// TrashChecksums records checksums of trashed entries for undo to verify.
+	TrashChecksums bool
+
+	// Note describes the cleanup in its history entry and trash manifest.
+	Note string
+
+	// Stop, when closed, interrupts a cleanup after the paths in progress;
+	// the result then covers what was cleaned and the error wraps
+	// ErrInterrupted.
+	Stop <-chan struct{}
 }
 
 // NewCleaner creates a new cleaner instance.
@@ -25,63 +45,259 @@ type CleanResult struct {
 	ReclaimedSpace int64
 	FileCount      int
 	TrashSession   string
+	CategoryStats  map[string]int64
 }
 
-// Clean executes the cleanup of the provided results.
-func (c *Cleaner) Clean(results []rules.Result, dryRun bool, permanent bool) (*CleanResult, error) {
-	var totalSpace int64
-	var totalPaths []string
+// PartialError reports a cleanup that failed or was interrupted after
+// removing some paths. Unless they were deleted permanently, they are in
+// Session, where undo can restore them.
+type PartialError struct {
+	Session string
+	Err     error
+}
+
+func (e *PartialError) Error() string { return e.Err.Error() }
+
+func (e *PartialError) Unwrap() error { return e.Err }
 
-	categoryStats := make(map[string]int64)
+// Cleanups that bypass Burrow's trash are recorded under one of these
+// markers followed by the time, e.g. PERMANENT-20260105_093000. Versions
+// before that recorded the bare marker.
+const (
+	PermanentMarker   = "PERMANENT"
+	SystemTrashMarker = "SYSTEM-TRASH"
+)
+
+// bypassID names a cleanup that bypassed the trash, unique like the
+// timestamped trash sessions.
+func bypassID(marker string) string {
+	return marker + "-" + time.Now().Format(sessionLayout)
+}
 
-	for _, res := range results {
-		totalSpace += res.TotalSize
-		totalPaths = append(totalPaths, res.FoundPaths...)
-		categoryStats[res.Rule.Category] += res.TotalSize
+// BypassMarker returns the marker id was recorded under, or "" for a trash
+// session.
+func BypassMarker(id string) string {
+	for _, m := range []string{PermanentMarker, SystemTrashMarker} {
+		if id == m || strings.HasPrefix(id, m+"-") {
+			return m
+		}
 	}
+	return ""
+}
+
+// removeAll deletes a path permanently; tests replace it to inject failures.
+var removeAll = os.RemoveAll
+
+// Clean executes the cleanup of the provided results in three phases: it
+// plans the paths to remove, verifies them against the disk and the safety
+// guards, and only then removes them. Results of report-only rules are never
+// touched. If any path changed or became unsafe after the scan, nothing is
+// removed and the error is a *VerifyError. If the trash move fails partway,
+// the result covers what was moved and the error is a *PartialError.
+func (c *Cleaner) Clean(results []rules.Result, dryRun bool, permanent bool) (*CleanResult, error) {
+	plan := NewPlan(results)
+	t, totalPaths := plan.tally, plan.Paths()
 
 	if dryRun {
 		return &CleanResult{
-			ReclaimedSpace: totalSpace,
+			ReclaimedSpace: t.space,
 			FileCount:      len(totalPaths),
 			TrashSession:   "DRY-RUN",
+			CategoryStats:  t.categories,
 		}, nil
 	}
 
+	if problems := plan.Verify(); len(problems) > 0 {
+		return nil, &VerifyError{Problems: problems}
+	}
+	if stopped(c.Stop) {
+		return nil, ErrInterrupted
+	}
+
 	var session string
+	var partial error
 	if permanent {
-		for _, path := range totalPaths {
-			if err := os.RemoveAll(path); err != nil {
-				return nil, err
+		session = bypassID(PermanentMarker)
+		var removed []TrashEntry
+		for _, s := range plan.Steps {
+			if stopped(c.Stop) {
+				if len(removed) == 0 {
+					return nil, ErrInterrupted
+				}
+				partial = &PartialError{Session: session, Err: ErrInterrupted}
+				t, totalPaths = movedTally(results, removed)
+				break
 			}
+			if err := removeAll(s.Path); err != nil {
+				// What was deleted before the failure is still recorded
+				if len(removed) == 0 {
+					return nil, err
+				}
+				partial = &PartialError{Session: session, Err: fmt.Errorf("failed to delete %s: %w", s.Path, err)}
+				t, totalPaths = movedTally(results, removed)
+				break
+			}
+			removed = append(removed, TrashEntry{OriginalPath: s.Path, Size: max(s.Size, 0)})
+		}
+	} else if c.UseSystemTrash {
+		if err := moveToSystemTrash(totalPaths); err != nil {
+			return nil, err
 		}
-		session = "PERMANENT"
+		session = bypassID(SystemTrashMarker)
 	} else {
 		var err error
+		c.trashManager.Checksums = c.TrashChecksums
+		c.trashManager.Note = c.Note
+		c.trashManager.Stop = c.Stop
+		c.trashManager.BatchWithin = plan.batchWithin
 		session, err = c.trashManager.MoveToTrash(totalPaths)
 		if err != nil {
-			return nil, err
+			if session == "" {
+				return nil, err
+			}
+			manifest, merr := c.trashManager.Manifest(session)
+			if merr != nil {
+				return nil, err
+			}
+			partial = &PartialError{Session: session, Err: err}
+			t, totalPaths = movedTally(results, manifest.Entries)
 		}
 	}
 
+	// Lifetime stats start from the history the first time, so record them
+	// before this cleanup is in it
+	usage.NewManager().RecordCleanup(t.byRule, len(totalPaths))
+
 	// Save to history
 	histMgr := history.NewManager()
 	histMgr.Save(history.Entry{
 		ID:             session,
 		Timestamp:      time.Now(),
-		ReclaimedBytes: totalSpace,
+		ReclaimedBytes: t.space,
 		FileCount:      len(totalPaths),
-		CategoryStats:  categoryStats,
+		CategoryStats:  t.categories,
+		Rules:          t.rules,
+		Paths:          totalPaths,
+		Note:           c.Note,
 	})
 
 	return &CleanResult{
-		ReclaimedSpace: totalSpace,
+		ReclaimedSpace: t.space,
 		FileCount:      len(totalPaths),
 		TrashSession:   session,
-	}, nil
+		CategoryStats:  t.categories,
+	}, partial
+}
+
+// tally totals a cleanup's space by category and rule.
+type tally struct {
+	space      int64
+	rules      []string
+	categories map[string]int64
+	byRule     map[string]int64
+}
+
+func newTally() *tally {
+	return &tally{categories: make(map[string]int64), byRule: make(map[string]int64)}
+}
+
+func (t *tally) add(rule rules.CleanupRule, size int64) {
+	t.space += size
+	if !slices.Contains(t.rules, rule.Name) {
+		t.rules = append(t.rules, rule.Name)
+	}
+	t.categories[rule.Category] += size
+	t.byRule[rule.Name] += size
+}
+
+// movedTally totals only the entries a failed cleanup moved, each counted
+// for the first rule with a path it holds or lies in.
+func movedTally(results []rules.Result, entries []TrashEntry) (*tally, []string) {
+	t := newTally()
+	var paths []string
+	for _, e := range entries {
+		paths = append(paths, e.OriginalPath)
+		for _, res := range results {
+			if !res.Rule.ReportOnly && slices.ContainsFunc(res.FoundPaths, func(p string) bool {
+				return within(p, e.OriginalPath) || within(e.OriginalPath, p)
+			}) {
+				t.add(res.Rule, e.Size)
+				break
+			}
+		}
+	}
+	return t, paths
+}
+
+// within reports whether path is dir or lies below it.
+func within(path, dir string) bool {
+	path, dir = filepath.Clean(path), filepath.Clean(dir)
+	return path == dir || strings.HasPrefix(path, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
 }
 
 // Undo restores the last cleanup session.
-func (c *Cleaner) Undo() error {
-	return c.trashManager.RestoreLast()
+func (c *Cleaner) Undo() (*RestoreReport, error) {
+	latest, err := c.trashManager.LatestSession()
+	if err != nil {
+		return nil, err
+	}
+	return c.UndoSession(latest)
+}
+
+// UndoSession restores the given cleanup session.
+func (c *Cleaner) UndoSession(id string) (*RestoreReport, error) {
+	if id == "" || filepath.Base(id) != id {
+		return nil, fmt.Errorf("invalid session id: %q", id)
+	}
+	if !exists(filepath.Join(c.trashManager.TrashBaseDir, id)) {
+		return nil, fmt.Errorf("trash session %s not found (see 'burrow trash list')", id)
+	}
+	report, err := c.trashManager.Restore(id)
+	if report != nil {
+		markRestored(report)
+		usage.NewManager().RecordUndo()
+	}
+	return report, err
+}
+
+// UndoSince restores every cleanup session since the given time, newest
+// first; a zero time restores them all.
+func (c *Cleaner) UndoSince(since time.Time) ([]*RestoreReport, error) {
+	reports, err := c.trashManager.RestoreSince(since)
+	for _, r := range reports {
+		markRestored(r)
+	}
+	if len(reports) > 0 {
+		usage.NewManager().RecordUndo()
+	}
+	return reports, err
+}
+
+// markRestored notes in the history how much of a session undo put back,
+// including entries that failed verification: they left the trash too.
+func markRestored(r *RestoreReport) {
+	history.NewManager().MarkRestored(r.Session, r.Count(RestoreOK)+r.Count(RestoreUnverified))
+}
+
+// outermost drops duplicate paths and paths that lie below another path in
+// the list, keeping the original order.
+func outermost(paths []string) []string {
+	var out []string
+	for i, p := range paths {
+		covered := false
+		for j, q := range paths {
+			if i == j {
+				continue
+			}
+			cp, cq := filepath.Clean(p), filepath.Clean(q)
+			if strings.HasPrefix(cp, strings.TrimSuffix(cq, string(filepath.Separator))+string(filepath.Separator)) || (cp == cq && j < i) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			out = append(out, p)
+		}
+	}
+	return out
 }
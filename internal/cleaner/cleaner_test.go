@@ -0,0 +1,81 @@
+package cleaner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ismailtsdln/burrow/internal/history"
+	"github.com/ismailtsdln/burrow/internal/paths"
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+func TestOutermost(t *testing.T) {
+	got := outermost([]string{
+		"/h/Library/Caches/org.scalameta.metals",
+		"/h/Library/Caches",
+		"/h/.npm",
+		"/h/.npm",
+		"/h/.npmrc",
+	})
+	want := []string{"/h/Library/Caches", "/h/.npm", "/h/.npmrc"}
+	if !slices.Equal(got, want) {
+		t.Errorf("outermost = %v, want %v", got, want)
+	}
+}
+
+func TestMovedTally(t *testing.T) {
+	results := []rules.Result{
+		{Rule: rules.CleanupRule{Name: "npm", Category: "Dev"}, FoundPaths: []string{"/h/.npm/a", "/h/.npm/b"}},
+		{Rule: rules.CleanupRule{Name: "Logs", Category: "System"}, FoundPaths: []string{"/h/logs"}},
+	}
+	// The npm files were batched into their parent; the logs never moved
+	tl, paths := movedTally(results, []TrashEntry{{OriginalPath: "/h/.npm", Batched: true, Size: 30}})
+	if tl.space != 30 || tl.byRule["npm"] != 30 || tl.categories["Dev"] != 30 {
+		t.Errorf("tally = %+v, want 30 bytes for npm", tl)
+	}
+	if !slices.Equal(tl.rules, []string{"npm"}) || !slices.Equal(paths, []string{"/h/.npm"}) {
+		t.Errorf("rules %v, paths %v", tl.rules, paths)
+	}
+}
+
+func TestCleanPermanentPartial(t *testing.T) {
+	t.Setenv(paths.DataEnv, t.TempDir())
+	root := t.TempDir()
+	var results []rules.Result
+	for _, name := range []string{"a", "b"} {
+		path := filepath.Join(root, name)
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, rules.Result{
+			Rule:       rules.CleanupRule{Name: name, Category: "Test"},
+			FoundPaths: []string{path},
+			Stats:      []rules.PathStats{{Path: path, Kind: rules.KindFile, Size: 1, Files: 1}},
+		})
+	}
+
+	failing := filepath.Join(root, "b")
+	removeAll = func(path string) error {
+		if path == failing {
+			return errors.New("device busy")
+		}
+		return os.RemoveAll(path)
+	}
+	defer func() { removeAll = os.RemoveAll }()
+
+	res, err := (&Cleaner{trashManager: &TrashManager{TrashBaseDir: t.TempDir()}}).Clean(results, false, true)
+	var pe *PartialError
+	if !errors.As(err, &pe) || BypassMarker(pe.Session) != PermanentMarker || pe.Session == PermanentMarker {
+		t.Fatalf("err = %v, want a permanent PartialError", err)
+	}
+	if res == nil || res.FileCount != 1 || res.ReclaimedSpace != 1 {
+		t.Fatalf("result = %+v, want the one deleted path", res)
+	}
+	entries, _ := history.NewManager().Load()
+	if len(entries) != 1 || !slices.Equal(entries[0].Paths, []string{filepath.Join(root, "a")}) {
+		t.Errorf("history = %+v, want the deleted path recorded", entries)
+	}
+}
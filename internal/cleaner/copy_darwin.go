@@ -0,0 +1,22 @@
+package cleaner
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// copyPath clones a file or directory recursively for the cross-volume
+// fallback. cp(1) copies with copyfile(3), which keeps permissions,
+// ownership where allowed, ACLs, extended attributes such as quarantine flags
+// and Finder tags, and timestamps. Symlinks are copied as links.
+func (tm *TrashManager) copyPath(src, dst string) error {
+	out, err := exec.Command("/bin/cp", "-pR", src, dst).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
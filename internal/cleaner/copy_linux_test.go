@@ -0,0 +1,33 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"syscall"
+	"testing"
+)
+
+func TestTrashManager_CopyPreservesXattrs(t *testing.T) {
+	tempDir := t.TempDir()
+	src := filepath.Join(tempDir, "download.dmg")
+	if err := os.WriteFile(src, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.Setxattr(src, "user.burrow.test", []byte("kept"), 0); err != nil {
+		t.Skipf("file system does not support user xattrs: %v", err)
+	}
+
+	dst := filepath.Join(tempDir, "copy.dmg")
+	if err := (&TrashManager{}).copyPath(src, dst); err != nil {
+		t.Fatalf("copyPath failed: %v", err)
+	}
+	if names := listXattrs(dst); !slices.Contains(names, "user.burrow.test") {
+		t.Fatalf("xattrs = %v, want user.burrow.test", names)
+	}
+	value := make([]byte, 16)
+	n, err := syscall.Getxattr(dst, "user.burrow.test", value)
+	if err != nil || string(value[:n]) != "kept" {
+		t.Errorf("xattr value = %q, %v; want %q", value[:n], err, "kept")
+	}
+}
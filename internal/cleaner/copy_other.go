@@ -0,0 +1,94 @@
+//go:build !darwin
+
+package cleaner
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// copyPath clones a file or directory recursively for the cross-volume
+// fallback. Symlinks are recreated as links instead of copying the data they
+// point to, and permissions, ownership where allowed, extended attributes,
+// and modification times are carried over.
+func (tm *TrashManager) copyPath(src, dst string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		if err := os.Symlink(target, dst); err != nil {
+			return err
+		}
+		copyOwner(info, dst)
+		return nil
+	}
+
+	if info.IsDir() {
+		return tm.copyDir(src, dst, info)
+	}
+	return tm.copyFile(src, dst, info)
+}
+
+func (tm *TrashManager) copyFile(src, dst string, info os.FileInfo) error {
+	source, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	destination, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	defer destination.Close()
+
+	if _, err := io.Copy(destination, source); err != nil {
+		return err
+	}
+	if err := destination.Close(); err != nil {
+		return err
+	}
+	return copyMetadata(src, dst, info)
+}
+
+func (tm *TrashManager) copyDir(src, dst string, info os.FileInfo) error {
+	// Stay writable until the contents are in place
+	if err := os.Mkdir(dst, 0700); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := tm.copyPath(filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	// Copying the contents updated the directory's times, so they go last
+	return copyMetadata(src, dst, info)
+}
+
+// copyMetadata applies the extended attributes, owner, mode, and
+// modification time of src to dst. The owner is set before the mode because
+// changing it clears setuid bits.
+func copyMetadata(src, dst string, info os.FileInfo) error {
+	if err := copyXattrs(src, dst); err != nil {
+		return err
+	}
+	copyOwner(info, dst)
+	if err := os.Chmod(dst, info.Mode()); err != nil {
+		return err
+	}
+	return os.Chtimes(dst, time.Time{}, info.ModTime())
+}
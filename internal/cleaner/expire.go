@@ -0,0 +1,70 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/history"
+)
+
+// ExpiresAt returns when a session trashed at t stops being undoable under
+// the undo window, or the zero time when there is no window.
+func ExpiresAt(t time.Time, window time.Duration) time.Time {
+	if window <= 0 {
+		return time.Time{}
+	}
+	return t.Add(window)
+}
+
+// Trashed returns when the session was created.
+func (s TrashSession) Trashed() time.Time {
+	if s.Manifest != nil && !s.Manifest.Timestamp.IsZero() {
+		return s.Manifest.Timestamp
+	}
+	return sessionTime(s.ID, s.Dir)
+}
+
+// Expired returns the sessions whose undo window closed by now, oldest
+// first. Orphaned sessions are left for 'trash repair' and interrupted ones
+// for Recover.
+func (tm *TrashManager) Expired(window time.Duration, now time.Time) ([]TrashSession, error) {
+	if window <= 0 {
+		return nil, nil
+	}
+	sessions, err := tm.Sessions()
+	if err != nil {
+		return nil, err
+	}
+	var expired []TrashSession
+	for i := len(sessions) - 1; i >= 0; i-- {
+		s := sessions[i]
+		if s.Orphaned || s.Interrupted || now.Before(ExpiresAt(s.Trashed(), window)) {
+			continue
+		}
+		expired = append(expired, s)
+	}
+	return expired, nil
+}
+
+// FinalizeExpired purges the sessions whose undo window closed and marks
+// them finalized in the history. It returns the IDs it purged.
+func (c *Cleaner) FinalizeExpired(window time.Duration, now time.Time) ([]string, error) {
+	expired, err := c.trashManager.Expired(window, now)
+	if err != nil {
+		return nil, err
+	}
+	var purged []string
+	var errs []error
+	for _, s := range expired {
+		if err := c.trashManager.Purge(s.ID); err != nil {
+			errs = append(errs, fmt.Errorf("failed to finalize session %s: %w", s.ID, err))
+			continue
+		}
+		purged = append(purged, s.ID)
+	}
+	if err := history.NewManager().MarkFinalized(purged, now); err != nil {
+		errs = append(errs, err)
+	}
+	return purged, errors.Join(errs...)
+}
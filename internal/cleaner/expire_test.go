@@ -0,0 +1,68 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/history"
+	"github.com/ismailtsdln/burrow/internal/paths"
+)
+
+func TestFinalizeExpired(t *testing.T) {
+	t.Setenv(paths.DataEnv, t.TempDir())
+	base := t.TempDir()
+	tm := &TrashManager{TrashBaseDir: base}
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.Local)
+
+	sessions := map[string]time.Time{
+		"20240101_000000": now.Add(-200 * time.Hour),
+		"20240105_000000": now.Add(-100 * time.Hour),
+		"20240110_000000": now.Add(-time.Hour),
+	}
+	h := history.NewManager()
+	for id, ts := range sessions {
+		dir := filepath.Join(base, id)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := writeManifest(filepath.Join(dir, manifestName), &TrashManifest{Timestamp: ts}); err != nil {
+			t.Fatal(err)
+		}
+		if err := h.Save(history.Entry{ID: id, Timestamp: ts}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A cleanup still moving into an old session is never finalized
+	if err := os.WriteFile(filepath.Join(base, "20240101_000000", journalName), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Cleaner{trashManager: tm}
+	if purged, err := c.FinalizeExpired(0, now); err != nil || len(purged) != 0 {
+		t.Fatalf("no window: purged %v, %v", purged, err)
+	}
+
+	purged, err := c.FinalizeExpired(72*time.Hour, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(purged) != 1 || purged[0] != "20240105_000000" {
+		t.Fatalf("purged %v, want only the expired session", purged)
+	}
+
+	e, err := h.Find("20240105_000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !e.Finalized.Equal(now) {
+		t.Errorf("finalized = %v, want %v", e.Finalized, now)
+	}
+	if got := tm.SessionState(*e); got != SessionFinalized {
+		t.Errorf("state = %q, want %q", got, SessionFinalized)
+	}
+	if got := tm.SessionState(history.Entry{ID: "20240110_000000"}); got != SessionRestorable {
+		t.Errorf("recent session: state = %q, want %q", got, SessionRestorable)
+	}
+}
@@ -0,0 +1,259 @@
+package cleaner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// journalName is the write-ahead plan of a session still being moved. It
+	// is removed once the final manifest is on disk, so a session holding one
+	// was interrupted (or is still running).
+	journalName = "journal.json"
+	// partialSuffix marks a cross-volume copy that is not complete yet.
+	partialSuffix = ".burrow-partial"
+)
+
+// IsSessionFile reports whether name is a file Burrow writes into a trash
+// session of its own, as opposed to an entry moved there.
+func IsSessionFile(name string) bool {
+	return name == manifestName || name == journalName
+}
+
+// Journal lists every move MoveToTrash is about to make. It is synced to
+// disk before the first file moves; after a crash, Recover compares it with
+// what is actually on disk.
+type Journal struct {
+	PID     int          `json:"pid"`
+	Started time.Time    `json:"started"`
+	Note    string       `json:"note,omitempty"`
+	Entries []TrashEntry `json:"entries"`
+}
+
+// faultHook, when set by tests, is called at each step of a move so a
+// crash can be injected there.
+var faultHook func(point string)
+
+func fault(point string) {
+	if faultHook != nil {
+		faultHook(point)
+	}
+}
+
+// writeFileSync writes data to path atomically and durably: the data is
+// synced before the rename and the directory after it.
+func writeFileSync(path string, data []byte) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	syncDir(filepath.Dir(path))
+	return nil
+}
+
+// syncDir flushes a directory's entries. Not every platform can open a
+// directory for syncing, so failures are ignored.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
+
+func writeJournal(path string, j *Journal) error {
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileSync(path, data)
+}
+
+func readJournal(path string) (*Journal, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var j Journal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("failed to parse journal: %w", err)
+	}
+	return &j, nil
+}
+
+// Recovery is what Recover found in one interrupted session.
+type Recovery struct {
+	Session string
+	// Recorded counts the entries that were moved but missing from the
+	// manifest and are now recorded, so undo can restore them.
+	Recorded int
+	// Leftovers are original paths that still exist although a complete
+	// copy of them is in the trash: the crash hit while the original was
+	// being removed. They are left alone.
+	Leftovers []string
+	// Removed is set when nothing had been moved and the session was dropped.
+	Removed bool
+}
+
+// Recover finishes the bookkeeping of sessions whose MoveToTrash was
+// interrupted, such as by a crash or a kill. Every planned move is checked
+// against the disk: moved entries missing from the manifest are recorded,
+// incomplete copies are deleted (their source is still intact), and the
+// journal is removed. Sessions of a process that is still running are
+// skipped.
+func (tm *TrashManager) Recover() ([]Recovery, error) {
+	dirs, err := os.ReadDir(tm.TrashBaseDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read trash directory: %w", err)
+	}
+
+	var recovered []Recovery
+	var errs []error
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		j, err := readJournal(filepath.Join(tm.TrashBaseDir, d.Name(), journalName))
+		if err != nil {
+			if !os.IsNotExist(err) {
+				errs = append(errs, fmt.Errorf("session %s: %w", d.Name(), err))
+			}
+			continue
+		}
+		if j.PID != os.Getpid() && processAlive(j.PID) {
+			continue
+		}
+		r, err := tm.recoverSession(d.Name(), j)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("session %s: %w", d.Name(), err))
+			continue
+		}
+		recovered = append(recovered, r)
+	}
+	return recovered, errors.Join(errs...)
+}
+
+// recoverSession reconciles one session's journal with its manifest and the
+// disk, then replaces the journal with a complete manifest.
+func (tm *TrashManager) recoverSession(id string, j *Journal) (Recovery, error) {
+	sessionDir := filepath.Join(tm.TrashBaseDir, id)
+	r := Recovery{Session: id}
+
+	manifest := &TrashManifest{Timestamp: j.Started, Note: j.Note, Entries: []TrashEntry{}}
+	if m, err := tm.readManifest(id); err == nil {
+		manifest = m
+	}
+	recorded := make(map[string]bool, len(manifest.Entries))
+	for _, e := range manifest.Entries {
+		recorded[e.TrashPath] = true
+	}
+
+	for _, job := range j.Entries {
+		entries, leftovers := tm.recoverEntry(job, recorded)
+		manifest.Entries = append(manifest.Entries, entries...)
+		r.Recorded += len(entries)
+		r.Leftovers = append(r.Leftovers, leftovers...)
+	}
+
+	if len(manifest.Entries) == 0 {
+		r.Removed = true
+		return r, os.RemoveAll(sessionDir)
+	}
+	if err := writeManifest(filepath.Join(sessionDir, manifestName), manifest); err != nil {
+		return r, fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return r, os.Remove(filepath.Join(sessionDir, journalName))
+}
+
+// recoverEntry returns the manifest entries for a planned move that are on
+// disk but not yet recorded, and the original paths left behind by a copy.
+func (tm *TrashManager) recoverEntry(job TrashEntry, recorded map[string]bool) ([]TrashEntry, []string) {
+	dropPartial(job)
+	if recorded[job.TrashPath] {
+		return nil, nil
+	}
+	if _, err := os.Lstat(job.TrashPath); err != nil {
+		return nil, nil
+	}
+	_, origErr := os.Lstat(job.OriginalPath)
+
+	if !job.Batched {
+		entry := tm.recoveredEntry(job)
+		if origErr == nil {
+			return []TrashEntry{entry}, []string{job.OriginalPath}
+		}
+		return []TrashEntry{entry}, nil
+	}
+
+	// A batched directory was either renamed whole (leaving an empty
+	// directory, or nothing yet, in its place) or moved child by child
+	// into a stand-in directory
+	if origErr != nil {
+		if info, err := os.Lstat(job.TrashPath); err == nil {
+			recreateDir(job.OriginalPath, info)
+		}
+		return []TrashEntry{tm.recoveredEntry(job)}, nil
+	}
+	if left, err := os.ReadDir(job.OriginalPath); err == nil && len(left) == 0 {
+		return []TrashEntry{tm.recoveredEntry(job)}, nil
+	}
+
+	children, _ := os.ReadDir(job.TrashPath)
+	var entries []TrashEntry
+	var leftovers []string
+	for _, c := range children {
+		if name, ok := strings.CutSuffix(c.Name(), partialSuffix); ok && exists(filepath.Join(job.OriginalPath, name)) {
+			dropPartial(TrashEntry{OriginalPath: filepath.Join(job.OriginalPath, name), TrashPath: filepath.Join(job.TrashPath, name)})
+			continue
+		}
+		child, left := tm.recoverEntry(TrashEntry{
+			OriginalPath: filepath.Join(job.OriginalPath, c.Name()),
+			TrashPath:    filepath.Join(job.TrashPath, c.Name()),
+		}, recorded)
+		entries = append(entries, child...)
+		leftovers = append(leftovers, left...)
+	}
+	if len(entries) == 0 {
+		os.Remove(job.TrashPath)
+	}
+	return entries, leftovers
+}
+
+// dropPartial deletes an incomplete cross-volume copy of job. A copy that
+// never got renamed into place left its source untouched.
+func dropPartial(job TrashEntry) {
+	if _, err := os.Lstat(job.OriginalPath); err == nil {
+		os.RemoveAll(job.TrashPath + partialSuffix)
+	}
+}
+
+// recoveredEntry fills in what moveEntry would have recorded for job.
+func (tm *TrashManager) recoveredEntry(job TrashEntry) TrashEntry {
+	if info, err := os.Lstat(job.TrashPath); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		job.SymlinkTarget, _ = os.Readlink(job.TrashPath)
+	}
+	tm.recordMetadata(&job)
+	return job
+}
@@ -0,0 +1,165 @@
+package cleaner
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/ismailtsdln/burrow/internal/safety"
+)
+
+// crashExit is the exit code of a helper process that crashed on purpose.
+const crashExit = 3
+
+// TestCrashHelper is run in a child process by TestMoveToTrash_Crash. It
+// moves the paths it is given and exits abruptly at the requested fault
+// point, leaving the session as a killed process would.
+func TestCrashHelper(t *testing.T) {
+	point := os.Getenv("BURROW_CRASH_POINT")
+	if point == "" {
+		t.Skip("helper process only")
+	}
+	after, _ := strconv.Atoi(os.Getenv("BURROW_CRASH_AFTER"))
+	var srcs []string
+	json.Unmarshal([]byte(os.Getenv("BURROW_CRASH_PATHS")), &srcs)
+
+	hits := 0
+	faultHook = func(p string) {
+		if p == point {
+			if hits++; hits >= after {
+				os.Exit(crashExit)
+			}
+		}
+	}
+	trash := os.Getenv("BURROW_CRASH_TRASH")
+	tm := &TrashManager{TrashBaseDir: trash, BatchWithin: map[string]safety.Options{filepath.Dir(trash): {}}}
+	tm.MoveToTrash(srcs)
+}
+
+func TestMoveToTrash_Crash(t *testing.T) {
+	cases := []struct {
+		point string
+		after int
+	}{
+		{"journaled", 1},
+		{"moved", 1},
+		{"moved", 3},
+		{"renamed", 1},
+		{"flushed", 1},
+	}
+	for _, c := range cases {
+		t.Run(c.point+"-"+strconv.Itoa(c.after), func(t *testing.T) {
+			base := t.TempDir()
+			trash := filepath.Join(base, "trash")
+			files := map[string]string{}
+			var srcs []string
+			// batch/ is moved whole; loose/ keeps an unselected file, so its
+			// selected files are moved one by one
+			for _, name := range []string{"batch/x", "batch/y", "loose/a", "loose/b", "loose/c", "loose/d", "loose/keep"} {
+				p := filepath.Join(base, filepath.FromSlash(name))
+				os.MkdirAll(filepath.Dir(p), 0755)
+				if err := os.WriteFile(p, []byte(name), 0644); err != nil {
+					t.Fatal(err)
+				}
+				files[p] = name
+				if filepath.Base(p) != "keep" {
+					srcs = append(srcs, p)
+				}
+			}
+
+			data, _ := json.Marshal(srcs)
+			cmd := exec.Command(os.Args[0], "-test.run=^TestCrashHelper$")
+			cmd.Env = append(os.Environ(),
+				"BURROW_CRASH_POINT="+c.point,
+				"BURROW_CRASH_AFTER="+strconv.Itoa(c.after),
+				"BURROW_CRASH_PATHS="+string(data),
+				"BURROW_CRASH_TRASH="+trash,
+			)
+			if err := cmd.Run(); err == nil || cmd.ProcessState.ExitCode() != crashExit {
+				t.Fatalf("helper did not crash at %s: %v", c.point, err)
+			}
+
+			tm := &TrashManager{TrashBaseDir: trash}
+			if _, err := tm.Recover(); err != nil {
+				t.Fatal(err)
+			}
+			sessions, err := tm.Sessions()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Every file is either still in place or recorded in a manifest
+			tracked := map[string]bool{}
+			for _, s := range sessions {
+				if s.Orphaned {
+					t.Fatalf("session %s is orphaned after recovery", s.ID)
+				}
+				if exists(filepath.Join(s.Dir, journalName)) {
+					t.Errorf("session %s still has a journal", s.ID)
+				}
+				for _, e := range s.Manifest.Entries {
+					if !exists(e.TrashPath) {
+						t.Errorf("manifest entry %s is missing from the trash", e.TrashPath)
+					}
+					tracked[e.OriginalPath] = true
+				}
+			}
+			for _, src := range srcs {
+				if !exists(src) && !tracked[src] && !tracked[filepath.Dir(src)] {
+					t.Errorf("%s was lost untracked", src)
+				}
+			}
+
+			for _, s := range sessions {
+				if _, err := tm.Restore(s.ID); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for p, want := range files {
+				if got, err := os.ReadFile(p); err != nil || string(got) != want {
+					t.Errorf("%s after undo = %q, %v", p, got, err)
+				}
+			}
+		})
+	}
+}
+
+func TestTrashManager_RecoverCopies(t *testing.T) {
+	base := t.TempDir()
+	tm := &TrashManager{TrashBaseDir: filepath.Join(base, "trash")}
+	sessionDir := filepath.Join(tm.TrashBaseDir, "20240101_120000")
+	os.MkdirAll(sessionDir, 0755)
+
+	// copied: the copy was still under its partial name
+	copied := TrashEntry{OriginalPath: filepath.Join(base, "copied"), TrashPath: filepath.Join(sessionDir, "copied")}
+	os.WriteFile(copied.OriginalPath, []byte("data"), 0644)
+	os.WriteFile(copied.TrashPath+partialSuffix, []byte("da"), 0644)
+	// removing: the copy was complete, the original not yet removed
+	removing := TrashEntry{OriginalPath: filepath.Join(base, "removing"), TrashPath: filepath.Join(sessionDir, "removing")}
+	os.WriteFile(removing.OriginalPath, []byte("data"), 0644)
+	os.WriteFile(removing.TrashPath, []byte("data"), 0644)
+
+	if err := writeJournal(filepath.Join(sessionDir, journalName), &Journal{Entries: []TrashEntry{copied, removing}}); err != nil {
+		t.Fatal(err)
+	}
+	recovered, err := tm.Recover()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recovered) != 1 || recovered[0].Recorded != 1 || len(recovered[0].Leftovers) != 1 || recovered[0].Leftovers[0] != removing.OriginalPath {
+		t.Fatalf("unexpected recovery %+v", recovered)
+	}
+	if exists(copied.TrashPath + partialSuffix) {
+		t.Error("partial copy was not removed")
+	}
+	if got, _ := os.ReadFile(copied.OriginalPath); string(got) != "data" {
+		t.Errorf("source of the partial copy changed: %q", got)
+	}
+	manifest, err := tm.readManifest("20240101_120000")
+	if err != nil || len(manifest.Entries) != 1 || manifest.Entries[0].OriginalPath != removing.OriginalPath {
+		t.Fatalf("manifest = %+v, %v", manifest, err)
+	}
+}
@@ -0,0 +1,98 @@
+package cleaner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// checksumSource hashes an entry before it moves, when checksums are
+// enabled, so the recorded value describes the original: a copy damaged on
+// its way into the trash then fails verification on undo instead of passing
+// it. Batched directories are not hashed; see verifyRestored.
+func (tm *TrashManager) checksumSource(entry *TrashEntry) {
+	if tm.Checksums && !entry.Batched {
+		entry.Checksum, _ = treeChecksum(entry.OriginalPath)
+	}
+}
+
+// recordMetadata describes an entry from its copy in the trash, for 'trash
+// show' and for verifying the restore on undo.
+func (tm *TrashManager) recordMetadata(entry *TrashEntry) {
+	info, err := os.Lstat(entry.TrashPath)
+	if err != nil {
+		return
+	}
+	entry.Mode = info.Mode()
+	entry.Owner = ownerOf(info)
+	if info.Mode()&os.ModeSymlink == 0 {
+		entry.Xattrs = listXattrs(entry.TrashPath)
+	}
+	entry.Size, entry.Files, _ = treeStats(entry.TrashPath)
+}
+
+// treeStats returns the total size and number of files under path.
+func treeStats(path string) (int64, int, error) {
+	var size int64
+	files := 0
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+			files++
+		}
+		return nil
+	})
+	return size, files, err
+}
+
+// treeChecksum hashes the names, link targets, and file contents under path,
+// in walk order, so any lost or altered file changes the result.
+func treeChecksum(path string) (string, error) {
+	h := sha256.New()
+	err := filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(path, p)
+		io.WriteString(h, filepath.ToSlash(rel)+"\x00")
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			io.WriteString(h, target+"\x00")
+		case info.Mode().IsRegular():
+			f, err := os.Open(p)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			if _, err := io.Copy(h, f); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// splitXattrNames splits a NUL-separated list of attribute names.
+func splitXattrNames(buf []byte) []string {
+	var names []string
+	for _, name := range strings.Split(string(buf), "\x00") {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,156 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/safety"
+)
+
+// Plan is what a cleanup removes: the outermost cleanable paths of the
+// results, each with what the scan saw there.
+type Plan struct {
+	Steps []PlanStep
+	tally *tally
+	// batchWithin holds the directories pattern rules searched, inside
+	// which the trash may move a fully selected directory whole.
+	batchWithin map[string]safety.Options
+}
+
+// PlanStep is one path of a plan.
+type PlanStep struct {
+	Path string
+	Rule rules.CleanupRule
+	// Kind is what the scan found at Path, or "" when it did not record it.
+	Kind string
+	// Size is the scanned size of the path, or -1 when it is unknown.
+	Size int64
+	// Newest is the newest modification the scan counted for the rule;
+	// a file modified later changed after the scan.
+	Newest time.Time
+}
+
+// NewPlan plans the cleanup of results. Results of report-only rules are
+// left out, and paths below another planned path are covered by it.
+func NewPlan(results []rules.Result) *Plan {
+	p := &Plan{tally: newTally(), batchWithin: make(map[string]safety.Options)}
+	steps := make(map[string]PlanStep)
+	var all []string
+	for _, res := range results {
+		if res.Rule.ReportOnly {
+			continue
+		}
+		p.tally.add(res.Rule, res.TotalSize)
+
+		var newest time.Time
+		stats := make(map[string]rules.PathStats)
+		for _, st := range res.Stats {
+			stats[st.Path] = st
+			if st.Newest.After(newest) {
+				newest = st.Newest
+			}
+			if res.Rule.FileLevel() {
+				p.batchWithin[st.Path] = res.Rule.SafetyOptions()
+			}
+		}
+		for _, path := range res.FoundPaths {
+			if _, ok := steps[path]; ok {
+				continue
+			}
+			step := PlanStep{Path: path, Rule: res.Rule, Size: -1, Newest: newest}
+			if st, ok := stats[path]; ok {
+				step.Kind, step.Size = st.Kind, st.Size
+			} else if res.Rule.FileLevel() {
+				// Pattern rules list the files they matched
+				step.Kind = rules.KindFile
+			}
+			steps[path] = step
+			all = append(all, path)
+		}
+	}
+	// Rules can overlap, such as one for ~/Library/Caches and one for a
+	// cache inside it; moving the parent takes the child along.
+	for _, path := range outermost(all) {
+		p.Steps = append(p.Steps, steps[path])
+	}
+	return p
+}
+
+// Paths returns the planned paths in order.
+func (p *Plan) Paths() []string {
+	paths := make([]string, len(p.Steps))
+	for i, s := range p.Steps {
+		paths[i] = s.Path
+	}
+	return paths
+}
+
+// Problem is a planned path that changed or became unsafe after the scan.
+type Problem struct {
+	Path   string
+	Rule   string
+	Reason string
+}
+
+// VerifyError aborts a cleanup whose plan no longer matches the disk.
+// Nothing was cleaned.
+type VerifyError struct {
+	Problems []Problem
+}
+
+func (e *VerifyError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d path(s) changed since the scan, so nothing was cleaned; scan again and retry:", len(e.Problems))
+	for _, p := range e.Problems {
+		fmt.Fprintf(&b, "\n  %s (%s): %s", p.Path, p.Rule, p.Reason)
+	}
+	return b.String()
+}
+
+// Verify re-checks every planned path just before it is cleaned: it must
+// still exist as the scan saw it, and pass the safety guards again, which
+// may have changed with the config. Directories are caches that keep
+// changing, so only their kind is compared; files must not have been
+// modified after the scan.
+func (p *Plan) Verify() []Problem {
+	var problems []Problem
+	for _, s := range p.Steps {
+		if reason := s.verify(); reason != "" {
+			problems = append(problems, Problem{Path: s.Path, Rule: s.Rule.Name, Reason: reason})
+		}
+	}
+	return problems
+}
+
+func (s PlanStep) verify() string {
+	info, err := os.Lstat(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "no longer exists"
+	}
+	if err != nil {
+		return err.Error()
+	}
+	if v := safety.CheckWith(s.Path, s.Rule.SafetyOptions()); !v.Safe {
+		return "unsafe: " + v.Reason
+	}
+
+	kind := rules.PathKind(info.Mode())
+	if s.Kind != "" && kind != s.Kind {
+		return fmt.Sprintf("was a %s, now a %s", s.Kind, kind)
+	}
+	if kind != rules.KindFile {
+		return ""
+	}
+	if s.Size >= 0 && info.Size() != s.Size {
+		return fmt.Sprintf("size changed from %d to %d bytes", s.Size, info.Size())
+	}
+	if !s.Newest.IsZero() && info.ModTime().After(s.Newest) {
+		return "modified after the scan"
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package cleaner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/paths"
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/safety"
+)
+
+func TestCleanVerifiesPlan(t *testing.T) {
+	t.Setenv(paths.DataEnv, t.TempDir())
+	root := t.TempDir()
+	scanned := time.Now().Add(-time.Hour)
+	write := func(name, data string) string {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		os.Chtimes(path, scanned, scanned)
+		return path
+	}
+	cache := filepath.Dir(write("cache/blob", "cache"))
+	image := write("disk.img", "image")
+	fileStat := func(path string, size int64) rules.PathStats {
+		return rules.PathStats{Path: path, Kind: rules.KindFile, Size: size, Files: 1, Newest: scanned}
+	}
+	results := func() []rules.Result {
+		return []rules.Result{
+			{Rule: rules.CleanupRule{Name: "Cache"}, FoundPaths: []string{cache}, Stats: []rules.PathStats{{Path: cache, Kind: rules.KindDirectory, Newest: scanned}}},
+			{Rule: rules.CleanupRule{Name: "Image"}, FoundPaths: []string{image}, Stats: []rules.PathStats{fileStat(image, 5)}},
+		}
+	}
+
+	if problems := NewPlan(results()).Verify(); len(problems) != 0 {
+		t.Fatalf("unchanged plan: %+v", problems)
+	}
+
+	// A cache that keeps filling is still as scanned
+	write("cache/new", "more")
+	if problems := NewPlan(results()).Verify(); len(problems) != 0 {
+		t.Fatalf("growing cache: %+v", problems)
+	}
+
+	write("disk.img", "resized image")
+	safety.SetPolicy(safety.Policy{ProtectedPaths: []string{cache}})
+	defer safety.SetPolicy(safety.Policy{})
+
+	tm := &TrashManager{TrashBaseDir: t.TempDir()}
+	_, err := (&Cleaner{trashManager: tm}).Clean(results(), false, false)
+	var ve *VerifyError
+	if !errors.As(err, &ve) || len(ve.Problems) != 2 {
+		t.Fatalf("err = %v, want a report of both paths", err)
+	}
+	if !strings.HasPrefix(ve.Problems[0].Reason, "unsafe: ") || !strings.HasPrefix(ve.Problems[1].Reason, "size changed") {
+		t.Errorf("problems = %+v", ve.Problems)
+	}
+	for _, p := range []string{cache, image} {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("%s was cleaned despite the failed verification", p)
+		}
+	}
+	if sessions, _ := tm.Sessions(); len(sessions) != 0 {
+		t.Errorf("trash sessions = %v, want none", sessions)
+	}
+
+	// A directory replaced by a file, and a path that is gone
+	safety.SetPolicy(safety.Policy{})
+	os.RemoveAll(cache)
+	write("cache", "file")
+	os.Remove(image)
+	problems := NewPlan(results()).Verify()
+	if len(problems) != 2 || problems[0].Reason != "was a directory, now a file" || problems[1].Reason != "no longer exists" {
+		t.Errorf("problems = %+v", problems)
+	}
+}
@@ -0,0 +1,46 @@
+//go:build !windows
+
+package cleaner
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+func isCrossDevice(errno syscall.Errno) bool {
+	return errno == syscall.EXDEV
+}
+
+// ownerOf returns the numeric "uid:gid" owning a file.
+func ownerOf(info os.FileInfo) string {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return fmt.Sprintf("%d:%d", st.Uid, st.Gid)
+	}
+	return ""
+}
+
+// ownedByCurrentUser reports whether the user and group running Burrow own
+// the file described by info, so a file it creates in its place matches.
+func ownedByCurrentUser(info os.FileInfo) bool {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	return ok && int(st.Uid) == os.Getuid() && int(st.Gid) == os.Getgid()
+}
+
+// copyOwner gives dst the owner and group of the file described by info.
+// Only root may give files away, so failures are ignored; the copy then
+// belongs to the user running Burrow, as it would with cp.
+func copyOwner(info os.FileInfo, dst string) {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		os.Lchown(dst, int(st.Uid), int(st.Gid))
+	}
+}
+
+// processAlive reports whether a process with the given ID is running.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
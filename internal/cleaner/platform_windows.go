@@ -0,0 +1,43 @@
+//go:build windows
+
+package cleaner
+
+import (
+	"os"
+	"syscall"
+)
+
+// errorNotSameDevice is ERROR_NOT_SAME_DEVICE, returned by MoveFileEx across volumes.
+const errorNotSameDevice syscall.Errno = 17
+
+func isCrossDevice(errno syscall.Errno) bool {
+	return errno == errorNotSameDevice || errno == syscall.EXDEV
+}
+
+// ownerOf is not recorded on Windows, where ownership is part of the ACL.
+func ownerOf(os.FileInfo) string {
+	return ""
+}
+
+// ownedByCurrentUser is false on Windows: a recreated folder would not get
+// its ACL back, so folders are never moved whole.
+func ownedByCurrentUser(os.FileInfo) bool {
+	return false
+}
+
+// copyOwner is a no-op on Windows; new files inherit the folder's ACL.
+func copyOwner(os.FileInfo, string) {}
+
+// processAlive reports whether a process with the given ID is running;
+// FindProcess opens a handle, which fails for one that has exited.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	p.Release()
+	return true
+}
@@ -0,0 +1,52 @@
+package cleaner
+
+import (
+	"github.com/ismailtsdln/burrow/internal/disk"
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+// DefaultMinFreeSpace is kept free on the trash volume when no value is configured.
+const DefaultMinFreeSpace = 1 << 30 // 1 GB
+
+// Preflight is the free-space assessment made before a trash-based cleanup.
+type Preflight struct {
+	Available    int64 // Free bytes on the trash volume
+	CopyBytes    int64 // Bytes that must be copied because they live on another volume
+	MinFreeSpace int64
+	NearlyFull   bool // The trash volume is below the minimum free space already
+}
+
+// OK reports whether the trash strategy can proceed safely.
+func (p *Preflight) OK() bool {
+	return !p.NearlyFull && p.Available-p.CopyBytes >= p.MinFreeSpace
+}
+
+// CheckSpace verifies that moving the results to trash won't exhaust the disk.
+// Same-volume moves are renames and need no space, but they also don't free
+// any until the trash is purged; cross-volume moves need a full copy first.
+func (c *Cleaner) CheckSpace(results []rules.Result, minFree int64) (*Preflight, error) {
+	if minFree <= 0 {
+		minFree = DefaultMinFreeSpace
+	}
+
+	trashDir := c.trashManager.TrashBaseDir
+	usage, err := disk.UsageOf(trashDir)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Preflight{
+		Available:    usage.Free,
+		MinFreeSpace: minFree,
+		NearlyFull:   usage.Free < minFree,
+	}
+	for _, res := range results {
+		for _, path := range res.FoundPaths {
+			if !disk.SameVolume(path, trashDir) {
+				size, _ := pathSize(path)
+				p.CopyBytes += size
+			}
+		}
+	}
+	return p, nil
+}
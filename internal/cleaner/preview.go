@@ -0,0 +1,132 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// errMissingFromTrash means an entry's trashed copy is gone, e.g. because
+// it was deleted by hand.
+var errMissingFromTrash = errors.New("no longer in the trash")
+
+// PreviewRestore reports what Restore would do with each entry of session
+// id, without changing anything. Entries that would be restored are
+// RestoreOK; the details note when they replace an empty directory or
+// merge into an existing one.
+func (tm *TrashManager) PreviewRestore(id string) (*RestoreReport, error) {
+	return tm.previewRestore(id, map[string]bool{})
+}
+
+// PreviewRestoreSince is PreviewRestore for the sessions RestoreSince would
+// restore, newest first, including the older copies of a path that a newer
+// session would already have put back.
+func (tm *TrashManager) PreviewRestoreSince(since time.Time) ([]*RestoreReport, error) {
+	sessions, err := tm.Sessions()
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := make(map[string]bool)
+	var reports []*RestoreReport
+	var errs []error
+	for _, s := range sessions {
+		if s.Orphaned {
+			if since.IsZero() || !sessionTime(s.ID, s.Dir).Before(since) {
+				errs = append(errs, fmt.Errorf("session %s has no manifest (orphaned); run 'burrow trash repair %s' first", s.ID, s.ID))
+			}
+			continue
+		}
+		if s.Manifest.Timestamp.Before(since) {
+			continue
+		}
+		report, err := tm.previewRestore(s.ID, claimed)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("session %s: %w", s.ID, err))
+			continue
+		}
+		reports = append(reports, report)
+	}
+	return reports, errors.Join(errs...)
+}
+
+// previewRestore checks each entry against the file system and against the
+// paths that newer sessions claimed.
+func (tm *TrashManager) previewRestore(id string, claimed map[string]bool) (*RestoreReport, error) {
+	manifest, err := tm.readManifest(id)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("session %s has no manifest (orphaned); run 'burrow trash repair %s' first", id, id)
+		}
+		return nil, err
+	}
+
+	report := &RestoreReport{Session: id}
+	for _, entry := range manifest.Entries {
+		if entry.OriginalPath == "" {
+			report.add(entry.TrashPath, errUnknownOrigin)
+			continue
+		}
+		detail, err := checkRestore(entry, claimed[entry.OriginalPath])
+		report.add(entry.OriginalPath, err)
+		if err == nil {
+			report.Entries[len(report.Entries)-1].Detail = detail
+			claimed[entry.OriginalPath] = true
+		}
+	}
+	return report, nil
+}
+
+// checkRestore predicts the outcome of restoreEntry. taken means a newer
+// session restores the same path first.
+func checkRestore(entry TrashEntry, taken bool) (string, error) {
+	if _, err := os.Lstat(entry.TrashPath); err != nil {
+		return "", errMissingFromTrash
+	}
+
+	info, err := os.Lstat(entry.OriginalPath)
+	exists := err == nil
+	switch {
+	case taken:
+		if entry.Batched {
+			return "merges into the copy restored from a newer session", nil
+		}
+		return "", errConflict
+	case !exists:
+		return "", nil
+	case entry.SymlinkTarget != "":
+		return "", fmt.Errorf("%s already exists", entry.OriginalPath)
+	case !info.IsDir():
+		return "", errConflict
+	}
+
+	// An empty directory in its place is replaced
+	existing, err := os.ReadDir(entry.OriginalPath)
+	if err != nil {
+		return "", err
+	}
+	if len(existing) == 0 {
+		return "replaces an empty directory", nil
+	}
+	if !entry.Batched {
+		return "", errConflict
+	}
+
+	// Batches merge in whatever does not collide
+	children, err := os.ReadDir(entry.TrashPath)
+	if err != nil {
+		return "", err
+	}
+	conflicts := 0
+	for _, c := range children {
+		if _, err := os.Lstat(filepath.Join(entry.OriginalPath, c.Name())); err == nil {
+			conflicts++
+		}
+	}
+	if conflicts > 0 {
+		return "", fmt.Errorf("%d of %d items already exist there and would stay in trash: %w", conflicts, len(children), errConflict)
+	}
+	return "merges into the existing directory", nil
+}
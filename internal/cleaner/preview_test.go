@@ -0,0 +1,66 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTrashManager_PreviewRestore(t *testing.T) {
+	tempDir := t.TempDir()
+	tm := &TrashManager{TrashBaseDir: filepath.Join(tempDir, "trash")}
+	free := filepath.Join(tempDir, "cache", "free")
+	taken := filepath.Join(tempDir, "cache", "taken")
+	gone := filepath.Join(tempDir, "cache", "gone")
+
+	id := "20240101_120000"
+	dir := filepath.Join(tm.TrashBaseDir, id)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := TrashManifest{Timestamp: time.Now()}
+	for _, original := range []string{free, taken, gone} {
+		trashPath := filepath.Join(dir, filepath.Base(original))
+		if original != gone {
+			if err := os.WriteFile(trashPath, []byte("x"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		manifest.Entries = append(manifest.Entries, TrashEntry{OriginalPath: original, TrashPath: trashPath})
+	}
+	if err := writeManifest(filepath.Join(dir, manifestName), &manifest); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(taken), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(taken, []byte("in the way"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := tm.PreviewRestore(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]RestoreStatus{free: RestoreOK, taken: RestoreSkipped, gone: RestoreFailed}
+	for _, e := range report.Entries {
+		if e.Status != want[e.Path] {
+			t.Errorf("%s: status %v, want %v", e.Path, e.Status, want[e.Path])
+		}
+	}
+	if len(report.Entries) != len(want) {
+		t.Errorf("got %d entries, want %d", len(report.Entries), len(want))
+	}
+
+	// Nothing moved
+	if _, err := os.Stat(free); !os.IsNotExist(err) {
+		t.Errorf("preview restored %s", free)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "free")); err != nil {
+		t.Errorf("preview touched the trash: %v", err)
+	}
+	if data, _ := os.ReadFile(taken); string(data) != "in the way" {
+		t.Errorf("preview overwrote %s", taken)
+	}
+}
@@ -0,0 +1,75 @@
+package cleaner
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/units"
+)
+
+// staleAfter is how long a candidate must sit untouched to get the full
+// staleness bonus; data idle for longer is no more worth cleaning.
+const staleAfter = 90 * units.Day
+
+// Recommendation is a cleanup candidate ranked by how worthwhile cleaning
+// it is.
+type Recommendation struct {
+	Result rules.Result `json:"result"`
+	Score  float64      `json:"score"`
+	// IdleDays is how long nothing under the candidate has changed, or -1
+	// when that is unknown.
+	IdleDays int `json:"idle_days"`
+}
+
+// ZeroRisk reports whether the recommendation is Safe and cheap to rebuild.
+func (r Recommendation) ZeroRisk() bool {
+	return r.Result.Rule.RiskLevel == rules.RiskSafe && r.Result.Rule.RebuildCost.Level != rules.RebuildHigh
+}
+
+// Recommend ranks results by size, discounted by rebuild cost and risk and
+// boosted by how long they have been idle, best first. Manual-risk and
+// report-only results are never recommended.
+func Recommend(results []rules.Result, now time.Time) []Recommendation {
+	var recs []Recommendation
+	for _, res := range results {
+		if res.Rule.ReportOnly || res.TotalSize == 0 {
+			continue
+		}
+		var riskFactor float64
+		switch res.Rule.RiskLevel {
+		case rules.RiskSafe:
+			riskFactor = 1
+		case rules.RiskCaution:
+			riskFactor = 0.5
+		default:
+			continue
+		}
+
+		rec := Recommendation{Result: res, IdleDays: -1}
+		staleness := 1.0
+		if newest := res.Summary().Newest; !newest.IsZero() {
+			idle := min(max(now.Sub(newest), 0), staleAfter)
+			rec.IdleDays = int(now.Sub(newest) / units.Day)
+			staleness += float64(idle) / float64(staleAfter)
+		}
+		rec.Score = res.Value() * staleness * riskFactor
+		recs = append(recs, rec)
+	}
+
+	sort.SliceStable(recs, func(i, j int) bool { return recs[i].Score > recs[j].Score })
+	return recs
+}
+
+// SplitRecommendations separates the zero-risk recommendations from the rest,
+// keeping their order.
+func SplitRecommendations(recs []Recommendation) (zeroRisk, rest []Recommendation) {
+	for _, r := range recs {
+		if r.ZeroRisk() {
+			zeroRisk = append(zeroRisk, r)
+		} else {
+			rest = append(rest, r)
+		}
+	}
+	return zeroRisk, rest
+}
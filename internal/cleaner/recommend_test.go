@@ -0,0 +1,44 @@
+package cleaner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+func TestRecommend(t *testing.T) {
+	now := time.Now()
+	stale := result("stale", rules.RiskSafe, 100)
+	stale.Stats = []rules.PathStats{{Size: 100, Files: 1, Newest: now.Add(-180 * 24 * time.Hour)}}
+	costly := result("costly", rules.RiskSafe, 300)
+	costly.Rule.RebuildCost.Level = rules.RebuildHigh
+	reportOnly := result("report-only", rules.RiskSafe, 1000)
+	reportOnly.Rule.ReportOnly = true
+
+	recs := Recommend([]rules.Result{
+		result("fresh", rules.RiskSafe, 150),
+		result("caution", rules.RiskCaution, 250),
+		result("manual", rules.RiskManual, 5000),
+		stale, costly, reportOnly,
+	}, now)
+
+	// stale: 100/2*2 = 100, fresh: 150/2 = 75, caution: 250/2*0.5 = 62.5, costly: 300/6 = 50
+	want := []string{"stale", "fresh", "caution", "costly"}
+	if len(recs) != len(want) {
+		t.Fatalf("got %d recommendations, want %v", len(recs), want)
+	}
+	for i, name := range want {
+		if recs[i].Result.Rule.Name != name {
+			t.Errorf("recs[%d] = %s, want %s", i, recs[i].Result.Rule.Name, name)
+		}
+	}
+	if recs[0].IdleDays != 180 || recs[1].IdleDays != -1 {
+		t.Errorf("idle days = %d, %d; want 180, -1", recs[0].IdleDays, recs[1].IdleDays)
+	}
+
+	zeroRisk, rest := SplitRecommendations(recs)
+	if len(zeroRisk) != 2 || len(rest) != 2 || rest[0].Result.Rule.Name != "caution" {
+		t.Errorf("split = %d zero-risk, rest %+v", len(zeroRisk), rest)
+	}
+}
@@ -0,0 +1,113 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ismailtsdln/burrow/internal/units"
+)
+
+// RestoreStatus is the outcome of restoring one trash entry.
+type RestoreStatus string
+
+const (
+	RestoreOK      RestoreStatus = "restored"
+	RestoreSkipped RestoreStatus = "skipped" // Left in the trash on purpose
+	RestoreFailed  RestoreStatus = "failed"
+	// RestoreUnverified entries are back in place, but not as they were
+	// trashed: their size, file count, or checksum differs.
+	RestoreUnverified RestoreStatus = "unverified"
+)
+
+// RestoredEntry is what happened to one entry of a session on undo.
+type RestoredEntry struct {
+	Path   string        `json:"path"`
+	Status RestoreStatus `json:"status"`
+	Detail string        `json:"detail,omitempty"`
+}
+
+// RestoreReport summarizes the restore of one trash session.
+type RestoreReport struct {
+	Session string          `json:"session"`
+	Entries []RestoredEntry `json:"entries"`
+}
+
+// Count returns how many entries ended with status.
+func (r *RestoreReport) Count(status RestoreStatus) int {
+	n := 0
+	for _, e := range r.Entries {
+		if e.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// errUnknownOrigin marks entries of repaired sessions whose original
+// location could not be determined.
+var errUnknownOrigin = errors.New("original location is unknown; left in trash")
+
+// unverifiedError wraps why an entry that was moved back failed
+// verification.
+type unverifiedError struct{ err error }
+
+func (e unverifiedError) Error() string { return e.err.Error() }
+func (e unverifiedError) Unwrap() error { return e.err }
+
+// add records the outcome of restoring path. Conflicts and unknown origins
+// are deliberate skips; anything else is a failure.
+func (r *RestoreReport) add(path string, err error) {
+	e := RestoredEntry{Path: path, Status: RestoreOK}
+	var unverified unverifiedError
+	switch {
+	case err == nil:
+	case errors.Is(err, errConflict) || errors.Is(err, errUnknownOrigin):
+		e.Status, e.Detail = RestoreSkipped, err.Error()
+	case errors.As(err, &unverified):
+		e.Status, e.Detail = RestoreUnverified, err.Error()
+	default:
+		e.Status, e.Detail = RestoreFailed, err.Error()
+	}
+	r.Entries = append(r.Entries, e)
+}
+
+// verifyRestored checks that a restored entry is back in place and, when
+// its size, file count, or checksum was recorded at trash time, that nothing
+// was lost or altered on the way.
+// Batched directories may have been merged with new content, so only their
+// presence is checked.
+func verifyRestored(entry TrashEntry) error {
+	if _, err := os.Lstat(entry.OriginalPath); err != nil {
+		return fmt.Errorf("missing after restore: %w", err)
+	}
+	if entry.SymlinkTarget != "" {
+		if target, err := os.Readlink(entry.OriginalPath); err != nil || target != entry.SymlinkTarget {
+			return fmt.Errorf("restored link does not point to %s", entry.SymlinkTarget)
+		}
+		return nil
+	}
+	if entry.Batched || (entry.Size == 0 && entry.Files == 0) {
+		return nil
+	}
+	size, files, err := treeStats(entry.OriginalPath)
+	if err != nil {
+		return fmt.Errorf("could not verify restore: %w", err)
+	}
+	if size != entry.Size {
+		return fmt.Errorf("restored %s, but %s was trashed", units.FormatSize(size), units.FormatSize(entry.Size))
+	}
+	if entry.Files > 0 && files != entry.Files {
+		return fmt.Errorf("restored %d files, but %d were trashed", files, entry.Files)
+	}
+	if entry.Checksum != "" {
+		sum, err := treeChecksum(entry.OriginalPath)
+		if err != nil {
+			return fmt.Errorf("could not verify restore: %w", err)
+		}
+		if sum != entry.Checksum {
+			return fmt.Errorf("restored content does not match its checksum")
+		}
+	}
+	return nil
+}
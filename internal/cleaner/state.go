@@ -0,0 +1,61 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/ismailtsdln/burrow/internal/history"
+)
+
+// SessionState says what is left of the trash session of a history entry.
+type SessionState string
+
+const (
+	SessionRestorable  SessionState = "restorable"
+	SessionPartial     SessionState = "partially restored"
+	SessionRestored    SessionState = "restored"
+	SessionPurged      SessionState = "purged"
+	SessionFinalized   SessionState = "finalized"
+	SessionOrphaned    SessionState = "orphaned"
+	SessionPermanent   SessionState = "deleted"
+	SessionSystemTrash SessionState = "system trash"
+)
+
+// SessionState reports whether the session of a history entry can still be
+// restored. A session that undo emptied is restored; one that is gone
+// without undo having restored anything was purged, or finalized when its
+// undo window closed.
+func (tm *TrashManager) SessionState(e history.Entry) SessionState {
+	switch BypassMarker(e.ID) {
+	case PermanentMarker:
+		return SessionPermanent
+	case SystemTrashMarker:
+		return SessionSystemTrash
+	}
+
+	if e.ID == "" || filepath.Base(e.ID) != e.ID {
+		return SessionPurged
+	}
+	if _, err := os.Stat(filepath.Join(tm.TrashBaseDir, e.ID)); err != nil {
+		switch {
+		case e.Restored > 0:
+			return SessionRestored
+		case !e.Finalized.IsZero():
+			return SessionFinalized
+		}
+		return SessionPurged
+	}
+	manifest, err := tm.readManifest(e.ID)
+	if err != nil {
+		return SessionOrphaned
+	}
+	if e.Restored > 0 {
+		return SessionPartial
+	}
+	for _, entry := range manifest.Entries {
+		if _, err := os.Lstat(entry.TrashPath); err != nil {
+			return SessionPartial
+		}
+	}
+	return SessionRestorable
+}
@@ -0,0 +1,64 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/history"
+)
+
+func TestTrashManager_SessionState(t *testing.T) {
+	tempDir := t.TempDir()
+	tm := &TrashManager{TrashBaseDir: filepath.Join(tempDir, "trash")}
+
+	id := "20240101_120000"
+	dir := filepath.Join(tm.TrashBaseDir, id)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := TrashManifest{Timestamp: time.Now()}
+	for _, name := range []string{"a", "b"} {
+		trashPath := filepath.Join(dir, name)
+		if err := os.WriteFile(trashPath, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		manifest.Entries = append(manifest.Entries, TrashEntry{OriginalPath: filepath.Join(tempDir, name), TrashPath: trashPath})
+	}
+	if err := writeManifest(filepath.Join(dir, manifestName), &manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	entry := history.Entry{ID: id}
+	if got := tm.SessionState(entry); got != SessionRestorable {
+		t.Errorf("full session: got %q, want %q", got, SessionRestorable)
+	}
+
+	if err := os.Remove(filepath.Join(dir, "a")); err != nil {
+		t.Fatal(err)
+	}
+	if got := tm.SessionState(entry); got != SessionPartial {
+		t.Errorf("session missing an item: got %q, want %q", got, SessionPartial)
+	}
+
+	if err := tm.Purge(id); err != nil {
+		t.Fatal(err)
+	}
+	if got := tm.SessionState(entry); got != SessionPurged {
+		t.Errorf("purged session: got %q, want %q", got, SessionPurged)
+	}
+	entry.Restored = 2
+	if got := tm.SessionState(entry); got != SessionRestored {
+		t.Errorf("session emptied by undo: got %q, want %q", got, SessionRestored)
+	}
+
+	for _, id := range []string{"PERMANENT", "PERMANENT-20260105_093000"} {
+		if got := tm.SessionState(history.Entry{ID: id}); got != SessionPermanent {
+			t.Errorf("permanent cleanup %s: got %q, want %q", id, got, SessionPermanent)
+		}
+	}
+	if got := tm.SessionState(history.Entry{ID: "SYSTEM-TRASH-20260105_093000"}); got != SessionSystemTrash {
+		t.Errorf("system-trash cleanup: got %q, want %q", got, SessionSystemTrash)
+	}
+}
@@ -0,0 +1,35 @@
+//go:build darwin
+
+package cleaner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// moveToSystemTrash moves paths into ~/.Trash so they show up in Finder's Trash.
+func moveToSystemTrash(paths []string) error {
+	home, _ := os.UserHomeDir()
+	trashDir := filepath.Join(home, ".Trash")
+	tm := NewTrashManager()
+
+	for _, path := range paths {
+		target := filepath.Join(trashDir, filepath.Base(path))
+		if _, err := os.Lstat(target); err == nil {
+			target = fmt.Sprintf("%s %s", target, time.Now().Format("15.04.05"))
+		}
+		// File-level rules can trash many same-named files within a second
+		for i, stamped := 2, target; ; i++ {
+			if _, err := os.Lstat(target); err != nil {
+				break
+			}
+			target = fmt.Sprintf("%s %d", stamped, i)
+		}
+		if err := tm.movePath(path, target); err != nil {
+			return fmt.Errorf("failed to move %s to Trash: %w", path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+//go:build !windows && !darwin
+
+package cleaner
+
+import "errors"
+
+func moveToSystemTrash(paths []string) error {
+	return errors.New("system trash is not supported on this platform")
+}
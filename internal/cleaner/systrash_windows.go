@@ -0,0 +1,58 @@
+//go:build windows
+
+package cleaner
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	foDelete          = 0x0003
+	fofSilent         = 0x0004
+	fofNoConfirmation = 0x0010
+	fofAllowUndo      = 0x0040
+	fofNoErrorUI      = 0x0400
+)
+
+// shFileOpStruct mirrors SHFILEOPSTRUCTW.
+type shFileOpStruct struct {
+	hwnd                  uintptr
+	wFunc                 uint32
+	pFrom                 *uint16
+	pTo                   *uint16
+	fFlags                uint16
+	fAnyOperationsAborted int32
+	hNameMappings         uintptr
+	lpszProgressTitle     *uint16
+}
+
+var procSHFileOperationW = syscall.NewLazyDLL("shell32.dll").NewProc("SHFileOperationW")
+
+// moveToSystemTrash sends paths to the Windows Recycle Bin so they can be
+// restored from Explorer.
+func moveToSystemTrash(paths []string) error {
+	for _, path := range paths {
+		// pFrom must be double-NUL terminated
+		from, err := syscall.UTF16FromString(path)
+		if err != nil {
+			return err
+		}
+		from = append(from, 0)
+
+		op := shFileOpStruct{
+			wFunc:  foDelete,
+			pFrom:  &from[0],
+			fFlags: fofAllowUndo | fofNoConfirmation | fofSilent | fofNoErrorUI,
+		}
+		ret, _, _ := procSHFileOperationW.Call(uintptr(unsafe.Pointer(&op)))
+		if ret != 0 {
+			return fmt.Errorf("failed to move %s to Recycle Bin (code 0x%x)", path, ret)
+		}
+		if op.fAnyOperationsAborted != 0 {
+			return fmt.Errorf("moving %s to Recycle Bin was aborted", path)
+		}
+	}
+	return nil
+}
@@ -4,84 +4,430 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
+
+	"github.com/ismailtsdln/burrow/internal/paths"
+	"github.com/ismailtsdln/burrow/internal/safety"
+)
+
+const (
+	manifestName = "manifest.json"
+
+	// trashWorkers bounds concurrent renames. Renames are metadata-only, so a
+	// few workers are enough to hide per-call latency on large batches.
+	trashWorkers = 8
+	// manifestBatch is how many moves are recorded between manifest writes.
+	manifestBatch = 500
+	// minCollapse is how many selected files a directory must hold before it
+	// is moved as a whole instead of file by file.
+	minCollapse = 2
 )
 
 // TrashManifest stores information about trashed files for undo operations.
 type TrashManifest struct {
-	Timestamp time.Time    `json:"timestamp"`
-	Entries   []TrashEntry `json:"entries"`
+	Timestamp time.Time `json:"timestamp"`
+	// Note is the user's description of the cleanup, if any.
+	Note    string       `json:"note,omitempty"`
+	Entries []TrashEntry `json:"entries"`
 }
 
 // TrashEntry maps a trashed file to its original location.
 type TrashEntry struct {
 	OriginalPath string `json:"original_path"`
 	TrashPath    string `json:"trash_path"`
+	// SymlinkTarget is set when the trashed path was a symlink; undo recreates
+	// the link itself rather than restoring a copy of what it pointed to.
+	SymlinkTarget string `json:"symlink_target,omitempty"`
+	// Batched is set when the entry is a directory whose entire contents were
+	// selected and moved in one rename; an empty directory is left in its place.
+	Batched bool `json:"batched,omitempty"`
+	// Size and Files total the entry's files when it was trashed; undo
+	// compares them to verify the restore.
+	Size  int64 `json:"size,omitempty"`
+	Files int   `json:"files,omitempty"`
+	// Mode, Owner ("uid:gid"), and Xattrs (attribute names) describe the
+	// entry itself, for 'trash show'.
+	Mode   os.FileMode `json:"mode,omitempty"`
+	Owner  string      `json:"owner,omitempty"`
+	Xattrs []string    `json:"xattrs,omitempty"`
+	// Checksum covers the names and contents of everything in the entry, as
+	// they were before it moved. It is only recorded when checksums are
+	// enabled, as it reads every file.
+	Checksum string `json:"checksum,omitempty"`
 }
 
 // TrashManager handles moving files to trash and restoring them.
 type TrashManager struct {
 	TrashBaseDir string
+	// Checksums records a checksum of each entry at trash time, which undo
+	// then verifies.
+	Checksums bool
+	// Note is recorded in the manifest of the sessions MoveToTrash creates.
+	Note string
+	// Stop, when closed, interrupts MoveToTrash and Purge at the next entry;
+	// see ErrInterrupted.
+	Stop <-chan struct{}
+	// BatchWithin maps the directories pattern rules searched to their
+	// rule's safety options. MoveToTrash only moves a directory whole when
+	// it lies inside one of them; see collapseParents.
+	BatchWithin map[string]safety.Options
+}
+
+// ErrInterrupted reports a cleanup or purge stopped through Stop. What was
+// done before it stopped is recorded: moved entries in the session's
+// manifest, and entries a purge did not reach still listed in theirs.
+var ErrInterrupted = errors.New("interrupted")
+
+// stopped reports whether stop has been closed.
+func stopped(stop <-chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
 }
 
 // NewTrashManager creates a new trash manager.
 func NewTrashManager() *TrashManager {
-	home, _ := os.UserHomeDir()
 	return &TrashManager{
-		TrashBaseDir: filepath.Join(home, ".burrow", "trash"),
+		TrashBaseDir: paths.TrashDir(),
 	}
 }
 
-// MoveToTrash moves a path to a timestamped trash directory.
+// MoveToTrash moves paths to a timestamped trash directory. Paths that make
+// up the entire contents of their parent directory are moved as one unit, and
+// renames run on a small worker pool. Before anything moves, the plan is
+// synced to the session's journal; the manifest is written every
+// manifestBatch moves and the journal removed once it is complete. If the
+// process dies in between, Recover rebuilds the manifest from the journal,
+// so no moved file is left untracked. When a move fails after others
+// succeeded, the session ID is returned with the error.
 func (tm *TrashManager) MoveToTrash(paths []string) (string, error) {
-	timestamp := time.Now().Format("20060102_150405")
-	sessionDir := filepath.Join(tm.TrashBaseDir, timestamp)
+	// A data directory on an unmounted volume must not be silently recreated
+	// on the boot disk, so its parent has to exist already
+	if parent := filepath.Dir(filepath.Dir(tm.TrashBaseDir)); !exists(parent) {
+		return "", fmt.Errorf("trash location %s is unavailable (is its volume mounted?)", tm.TrashBaseDir)
+	}
 
-	if err := os.MkdirAll(sessionDir, 0755); err != nil {
+	timestamp, err := tm.newSession()
+	if err != nil {
 		return "", fmt.Errorf("failed to create trash directory: %w", err)
 	}
+	sessionDir := filepath.Join(tm.TrashBaseDir, timestamp)
 
-	manifest := TrashManifest{
-		Timestamp: time.Now(),
-		Entries:   make([]TrashEntry, 0),
+	// File-level rules trash many files that share a name (e.g. several
+	// "data" blobs), so each session entry gets a unique name
+	units, batched := collapseParents(paths, tm.BatchWithin)
+	used := make(map[string]bool, len(units))
+	jobs := make([]TrashEntry, len(units))
+	for i, path := range units {
+		jobs[i] = TrashEntry{
+			OriginalPath: path,
+			TrashPath:    filepath.Join(sessionDir, uniqueName(used, filepath.Base(path))),
+			Batched:      batched[path],
+		}
+	}
+
+	journal := filepath.Join(sessionDir, journalName)
+	if err := writeJournal(journal, &Journal{PID: os.Getpid(), Started: time.Now(), Note: tm.Note, Entries: jobs}); err != nil {
+		os.RemoveAll(sessionDir)
+		return "", fmt.Errorf("failed to write journal: %w", err)
 	}
+	fault("journaled")
 
-	for _, path := range paths {
-		targetName := filepath.Base(path)
-		// Handle potential name collisions in the trash session
-		trashPath := filepath.Join(sessionDir, targetName)
+	rec := &manifestRecorder{
+		path:     filepath.Join(sessionDir, manifestName),
+		manifest: TrashManifest{Timestamp: time.Now(), Note: tm.Note, Entries: make([]TrashEntry, 0, len(units))},
+	}
 
-		if err := tm.movePath(path, trashPath); err != nil {
-			return "", fmt.Errorf("failed to move %s to trash: %w", path, err)
+	next := make(chan TrashEntry)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var moveErr error
+	for w := 0; w < trashWorkers && w < len(jobs); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range next {
+				entries, err := tm.moveEntry(job)
+				rec.add(entries...)
+				if err != nil {
+					mu.Lock()
+					if moveErr == nil {
+						moveErr = err
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, job := range jobs {
+		mu.Lock()
+		stop := moveErr != nil
+		mu.Unlock()
+		if stop || rec.failed() {
+			break
 		}
+		if stopped(tm.Stop) {
+			// The moves in flight finish and are recorded below
+			mu.Lock()
+			moveErr = ErrInterrupted
+			mu.Unlock()
+			break
+		}
+		next <- job
+	}
+	close(next)
+	wg.Wait()
 
-		manifest.Entries = append(manifest.Entries, TrashEntry{
-			OriginalPath: path,
-			TrashPath:    trashPath,
+	// Record whatever was moved even on failure, so undo can bring it back
+	if err := rec.flush(); err != nil {
+		return "", fmt.Errorf("failed to write manifest: %w", err)
+	}
+	fault("flushed")
+	os.Remove(journal)
+	if moveErr != nil {
+		if len(rec.manifest.Entries) == 0 {
+			os.RemoveAll(sessionDir)
+			return "", moveErr
+		}
+		return timestamp, moveErr
+	}
+	return timestamp, nil
+}
+
+// moveEntry moves one unit to the trash and returns the manifest entries it
+// produced. A batched directory is renamed in one step and recreated empty so
+// the location its files came from still exists; if the rename fails (e.g. the
+// directory is a mount point), its contents are moved one by one instead.
+func (tm *TrashManager) moveEntry(job TrashEntry) ([]TrashEntry, error) {
+	if job.Batched {
+		info, err := os.Lstat(job.OriginalPath)
+		if err == nil {
+			if err = os.Rename(job.OriginalPath, job.TrashPath); err == nil {
+				fault("renamed")
+				tm.recordMetadata(&job)
+				if err := recreateDir(job.OriginalPath, info); err != nil {
+					return []TrashEntry{job}, fmt.Errorf("moved %s to trash but could not recreate it: %w", job.OriginalPath, err)
+				}
+				return []TrashEntry{job}, nil
+			}
+		}
+		return tm.moveChildren(job)
+	}
+
+	if info, err := os.Lstat(job.OriginalPath); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		job.SymlinkTarget, _ = os.Readlink(job.OriginalPath)
+	}
+	tm.checksumSource(&job)
+	if err := tm.movePath(job.OriginalPath, job.TrashPath); err != nil {
+		return nil, fmt.Errorf("failed to move %s to trash: %w", job.OriginalPath, err)
+	}
+	fault("moved")
+	tm.recordMetadata(&job)
+	return []TrashEntry{job}, nil
+}
+
+// moveChildren is the fallback for a batched directory that could not be
+// renamed: each child is moved into a directory standing in for it.
+func (tm *TrashManager) moveChildren(job TrashEntry) ([]TrashEntry, error) {
+	children, err := os.ReadDir(job.OriginalPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to move %s to trash: %w", job.OriginalPath, err)
+	}
+	if err := os.Mkdir(job.TrashPath, 0755); err != nil {
+		return nil, fmt.Errorf("failed to move %s to trash: %w", job.OriginalPath, err)
+	}
+
+	var entries []TrashEntry
+	for _, c := range children {
+		entries = append(entries, TrashEntry{
+			OriginalPath: filepath.Join(job.OriginalPath, c.Name()),
+			TrashPath:    filepath.Join(job.TrashPath, c.Name()),
 		})
+		moved, err := tm.moveEntry(entries[len(entries)-1])
+		if err != nil {
+			return entries[:len(entries)-1], err
+		}
+		entries[len(entries)-1] = moved[0]
 	}
+	return entries, nil
+}
 
-	manifestData, _ := json.MarshalIndent(manifest, "", "  ")
-	if err := os.WriteFile(filepath.Join(sessionDir, "manifest.json"), manifestData, 0644); err != nil {
-		return "", fmt.Errorf("failed to write manifest: %w", err)
+// manifestRecorder collects entries from concurrent moves and rewrites the
+// manifest every manifestBatch entries.
+type manifestRecorder struct {
+	mu       sync.Mutex
+	path     string
+	manifest TrashManifest
+	pending  int
+	err      error
+}
+
+func (r *manifestRecorder) add(entries ...TrashEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.manifest.Entries = append(r.manifest.Entries, entries...)
+	r.pending += len(entries)
+	if r.pending >= manifestBatch && r.err == nil {
+		r.err = writeManifest(r.path, &r.manifest)
+		r.pending = 0
+	}
+}
+
+// failed reports whether an intermediate manifest write failed; moving more
+// files without a record of them would make them unrecoverable.
+func (r *manifestRecorder) failed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err != nil
+}
+
+func (r *manifestRecorder) flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sort.Slice(r.manifest.Entries, func(i, j int) bool {
+		return r.manifest.Entries[i].OriginalPath < r.manifest.Entries[j].OriginalPath
+	})
+	if err := writeManifest(r.path, &r.manifest); err != nil {
+		return err
 	}
+	return r.err
+}
 
-	return timestamp, nil
+// writeManifest replaces the manifest at path atomically and durably, so a
+// crash during an intermediate write leaves the previous batch readable.
+func writeManifest(path string, manifest *TrashManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileSync(path, data)
+}
+
+// collapseParents replaces groups of paths that make up the entire contents
+// of their parent directory with that directory, so a single rename moves
+// them all. Only directories below one of within's searched directories
+// are collapsed, since nothing else was planned, and only when they pass
+// their rule's safety guards and recreateDir can put them back exactly.
+// The returned set marks which units are such directories.
+func collapseParents(paths []string, within map[string]safety.Options) ([]string, map[string]bool) {
+	selected := make(map[string]bool, len(paths))
+	byDir := make(map[string]int)
+	for _, p := range paths {
+		selected[p] = true
+		byDir[filepath.Dir(p)]++
+	}
+
+	collapsible := make(map[string]bool)
+	for dir, n := range byDir {
+		if n < minCollapse || selected[dir] {
+			continue
+		}
+		opts, ok := batchRoot(dir, within)
+		collapsible[dir] = ok && safety.CheckWith(dir, opts).Safe && restorable(dir) && ownsDir(dir, selected)
+	}
+
+	var units []string
+	batched := make(map[string]bool)
+	for _, p := range paths {
+		dir := filepath.Dir(p)
+		if !collapsible[dir] {
+			units = append(units, p)
+			continue
+		}
+		if !batched[dir] {
+			batched[dir] = true
+			units = append(units, dir)
+		}
+	}
+	return units, batched
+}
+
+// batchRoot returns the safety options of the searched directory dir lies
+// strictly inside, if any.
+func batchRoot(dir string, within map[string]safety.Options) (safety.Options, bool) {
+	for root, opts := range within {
+		if filepath.Clean(root) != dir && safety.MatchPath(dir, root) {
+			return opts, true
+		}
+	}
+	return safety.Options{}, false
+}
+
+// restorable reports whether recreateDir can put dir back as it is: it
+// must belong to the user running Burrow, so the new directory gets the
+// same owner, and carry no extended attributes (which also hold ACLs).
+func restorable(dir string) bool {
+	info, err := os.Lstat(dir)
+	return err == nil && info.IsDir() && ownedByCurrentUser(info) && len(listXattrs(dir)) == 0
+}
+
+// recreateDir creates an empty directory at path with the mode described
+// by info, after the directory there was moved to the trash whole. If a
+// directory was created there in the meantime, it is kept as it is.
+func recreateDir(path string, info os.FileInfo) error {
+	if err := os.Mkdir(path, 0700); err != nil {
+		if now, lerr := os.Lstat(path); lerr == nil && now.IsDir() {
+			return nil
+		}
+		return err
+	}
+	// Mkdir applies the umask, so the mode is set exactly afterwards
+	return os.Chmod(path, info.Mode()&(os.ModePerm|os.ModeSetuid|os.ModeSetgid|os.ModeSticky))
+}
+
+// ownsDir reports whether every entry of dir is in selected, meaning moving
+// dir itself takes nothing else along.
+func ownsDir(dir string, selected map[string]bool) bool {
+	if filepath.Dir(dir) == dir {
+		return false
+	}
+	if info, err := os.Lstat(dir); err != nil || !info.IsDir() {
+		return false
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) == 0 {
+		return false
+	}
+	for _, e := range entries {
+		if !selected[filepath.Join(dir, e.Name())] {
+			return false
+		}
+	}
+	return true
 }
 
 // RestoreLast restores the most recent trash session.
-func (tm *TrashManager) RestoreLast() error {
+func (tm *TrashManager) RestoreLast() (*RestoreReport, error) {
+	latest, err := tm.LatestSession()
+	if err != nil {
+		return nil, err
+	}
+	return tm.Restore(latest)
+}
+
+// LatestSession returns the ID of the most recent trash session.
+func (tm *TrashManager) LatestSession() (string, error) {
 	entries, err := os.ReadDir(tm.TrashBaseDir)
 	if err != nil {
-		return fmt.Errorf("failed to read trash directory: %w", err)
+		return "", fmt.Errorf("failed to read trash directory: %w", err)
 	}
 
 	if len(entries) == 0 {
-		return fmt.Errorf("no trash sessions found")
+		return "", fmt.Errorf("no trash sessions found")
 	}
 
 	// Find the most recent session (by folder name)
@@ -93,115 +439,518 @@ func (tm *TrashManager) RestoreLast() error {
 	}
 
 	if latest == "" {
-		return fmt.Errorf("no valid trash sessions found")
+		return "", fmt.Errorf("no valid trash sessions found")
 	}
+	return latest, nil
+}
 
-	sessionDir := filepath.Join(tm.TrashBaseDir, latest)
-	manifestData, err := os.ReadFile(filepath.Join(sessionDir, "manifest.json"))
+// Restore moves every entry of the given session back to its original
+// location, verifies it, and reports the outcome per entry. Entries that
+// could not be restored stay in the session.
+func (tm *TrashManager) Restore(id string) (*RestoreReport, error) {
+	sessionDir := filepath.Join(tm.TrashBaseDir, id)
+	manifest, err := tm.readManifest(id)
 	if err != nil {
-		return fmt.Errorf("failed to read manifest for session %s: %w", latest, err)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("session %s has no manifest (orphaned); run 'burrow trash repair %s' first", id, id)
+		}
+		return nil, err
 	}
 
-	var manifest TrashManifest
-	if err := json.Unmarshal(manifestData, &manifest); err != nil {
-		return fmt.Errorf("failed to parse manifest: %w", err)
+	report := &RestoreReport{Session: id}
+	for _, entry := range manifest.Entries {
+		if entry.OriginalPath == "" {
+			report.add(entry.TrashPath, errUnknownOrigin)
+			continue
+		}
+		report.add(entry.OriginalPath, tm.restoreEntry(entry))
 	}
 
+	// Drop the stand-in directories of batches that were moved file by file
 	for _, entry := range manifest.Entries {
-		if err := os.MkdirAll(filepath.Dir(entry.OriginalPath), 0755); err != nil {
-			continue // Best effort
+		if dir := filepath.Dir(entry.TrashPath); dir != sessionDir {
+			os.Remove(dir)
 		}
-		if err := tm.movePath(entry.TrashPath, entry.OriginalPath); err != nil {
-			fmt.Printf("Warning: Failed to restore %s: %v\n", entry.OriginalPath, err)
+	}
+
+	// Keep the session if anything could not be restored
+	remaining, _ := os.ReadDir(sessionDir)
+	for _, r := range remaining {
+		if r.Name() != manifestName {
+			return report, nil
 		}
 	}
 
 	// Clean up the empty trash session directory
-	return os.RemoveAll(sessionDir)
+	return report, os.RemoveAll(sessionDir)
 }
 
-// movePath attempts to rename a file/directory, and falls back to copy+delete if it fails due to being on a different device.
-func (tm *TrashManager) movePath(src, dst string) error {
-	err := os.Rename(src, dst)
-	if err == nil {
-		return nil
+// restoreEntry puts one entry back and verifies the result.
+func (tm *TrashManager) restoreEntry(entry TrashEntry) error {
+	if err := os.MkdirAll(filepath.Dir(entry.OriginalPath), 0755); err != nil {
+		return err
 	}
+	var err error
+	switch {
+	case entry.Batched:
+		err = tm.restoreBatched(entry)
+	case entry.SymlinkTarget != "":
+		err = tm.restoreSymlink(entry)
+	default:
+		err = tm.restorePlain(entry)
+	}
+	if err != nil {
+		return err
+	}
+	if err := verifyRestored(entry); err != nil {
+		return unverifiedError{err}
+	}
+	return nil
+}
 
-	// Check if the error is a cross-device link error (EXDEV)
-	var linkErr *os.LinkError
-	if errors.As(err, &linkErr) {
-		if errno, ok := linkErr.Err.(syscall.Errno); ok && errno == syscall.EXDEV {
-			// Fallback to copy and delete
-			if err := tm.copyPath(src, dst); err != nil {
-				return fmt.Errorf("failed to copy during fallback: %w", err)
+// RestoreSince restores every session created at or after since, newest
+// first, and returns a report per session it processed. A zero since
+// restores all sessions. When sessions trashed the same path, the newest copy
+// wins and older ones stay in the trash. Orphaned sessions are skipped.
+func (tm *TrashManager) RestoreSince(since time.Time) ([]*RestoreReport, error) {
+	sessions, err := tm.Sessions()
+	if err != nil {
+		return nil, err
+	}
+
+	var restored []*RestoreReport
+	var errs []error
+	for _, s := range sessions {
+		if s.Orphaned {
+			if since.IsZero() || !sessionTime(s.ID, s.Dir).Before(since) {
+				errs = append(errs, fmt.Errorf("session %s has no manifest (orphaned); run 'burrow trash repair %s' first", s.ID, s.ID))
 			}
-			return os.RemoveAll(src)
+			continue
+		}
+		if s.Manifest.Timestamp.Before(since) {
+			continue
+		}
+		report, err := tm.Restore(s.ID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("session %s: %w", s.ID, err))
+		}
+		if report != nil {
+			restored = append(restored, report)
 		}
 	}
+	return restored, errors.Join(errs...)
+}
 
-	return err
+// TrashSession describes a session directory in the trash.
+type TrashSession struct {
+	ID       string
+	Dir      string
+	Manifest *TrashManifest // nil when the session is orphaned
+	Orphaned bool
+	// Interrupted is set while the session has a journal: a cleanup is
+	// still moving into it, or was killed before Recover ran.
+	Interrupted bool
 }
 
-// copyPath copies a file or directory recursively.
-func (tm *TrashManager) copyPath(src, dst string) error {
-	info, err := os.Lstat(src)
+// Sessions returns all trash sessions, newest first. Sessions whose manifest
+// is missing or unreadable are reported as orphaned.
+func (tm *TrashManager) Sessions() ([]TrashSession, error) {
+	entries, err := os.ReadDir(tm.TrashBaseDir)
 	if err != nil {
-		return err
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read trash directory: %w", err)
 	}
 
-	if info.IsDir() {
-		return tm.copyDir(src, dst)
+	var sessions []TrashSession
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		session := TrashSession{
+			ID:  entry.Name(),
+			Dir: filepath.Join(tm.TrashBaseDir, entry.Name()),
+		}
+		if manifest, err := tm.readManifest(entry.Name()); err == nil {
+			session.Manifest = manifest
+		} else {
+			session.Orphaned = true
+		}
+		session.Interrupted = exists(filepath.Join(session.Dir, journalName))
+		sessions = append(sessions, session)
 	}
-	return tm.copyFile(src, dst)
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].ID > sessions[j].ID
+	})
+	return sessions, nil
 }
 
-func (tm *TrashManager) copyFile(src, dst string) error {
-	source, err := os.Open(src)
-	if err != nil {
-		return err
+// RepairOrphan rebuilds a best-effort manifest for a session that lost it.
+// Entries still recorded in the session's journal or an unfinished manifest
+// write are taken as they are. Any other item is matched by name against the
+// candidate paths (usually the session's history paths and the expanded rule
+// paths), but only when exactly one candidate carries its name; ambiguous and
+// unmatched items are recorded without an original location so they can
+// still be listed or purged, never restored to a guessed place.
+func (tm *TrashManager) RepairOrphan(id string, candidates []string) (*TrashManifest, error) {
+	sessionDir := filepath.Join(tm.TrashBaseDir, id)
+	if _, err := tm.readManifest(id); err == nil {
+		return nil, fmt.Errorf("session %s already has a valid manifest", id)
 	}
-	defer source.Close()
 
-	destination, err := os.Create(dst)
+	items, err := os.ReadDir(sessionDir)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to read session %s: %w", id, err)
 	}
-	defer destination.Close()
 
-	if _, err := io.Copy(destination, source); err != nil {
-		return err
+	recorded := make(map[string]TrashEntry)
+	for _, name := range []string{journalName, manifestName + ".tmp"} {
+		for _, e := range recordedEntries(filepath.Join(sessionDir, name)) {
+			e.TrashPath = filepath.Join(sessionDir, sessionRel(e.TrashPath, id))
+			recorded[filepath.Base(e.TrashPath)] = e
+		}
+	}
+
+	byName := make(map[string][]string)
+	for _, c := range candidates {
+		c = filepath.Clean(c)
+		name := filepath.Base(c)
+		if !slices.Contains(byName[name], c) {
+			byName[name] = append(byName[name], c)
+		}
 	}
 
-	info, err := os.Stat(src)
+	manifest := TrashManifest{
+		Timestamp: sessionTime(id, sessionDir),
+		Entries:   make([]TrashEntry, 0),
+	}
+	// Items sharing a name were renamed apart (e.g. "data" and "data~2"), so
+	// which original each came from is unknown
+	shared := make(map[string]int)
+	for _, item := range items {
+		shared[baseName(item.Name())]++
+	}
+	for _, item := range items {
+		name := item.Name()
+		if name == manifestName || name == journalName || name == manifestName+".tmp" {
+			continue
+		}
+		if e, ok := recorded[name]; ok && e.OriginalPath != "" {
+			manifest.Entries = append(manifest.Entries, e)
+			continue
+		}
+
+		entry := TrashEntry{TrashPath: filepath.Join(sessionDir, name)}
+		if matches := byName[name]; len(matches) == 1 && shared[name] == 1 {
+			entry.OriginalPath = matches[0]
+		}
+		manifest.Entries = append(manifest.Entries, entry)
+	}
+
+	if err := writeManifest(filepath.Join(sessionDir, manifestName), &manifest); err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// recordedEntries returns the entries of a journal or manifest file, or none
+// when it is missing or unreadable.
+func recordedEntries(path string) []TrashEntry {
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return err
+		return nil
+	}
+	var recorded struct {
+		Entries []TrashEntry `json:"entries"`
 	}
-	return os.Chmod(dst, info.Mode())
+	if json.Unmarshal(data, &recorded) != nil {
+		return nil
+	}
+	return recorded.Entries
 }
 
-func (tm *TrashManager) copyDir(src, dst string) error {
-	info, err := os.Stat(src)
+// PruneMissing drops the manifest entries of a session whose trashed copy
+// no longer exists, and returns how many it dropped. A session left without
+// entries is removed.
+func (tm *TrashManager) PruneMissing(id string) (int, error) {
+	manifest, err := tm.Manifest(id)
 	if err != nil {
-		return err
+		return 0, err
 	}
+	kept := make([]TrashEntry, 0, len(manifest.Entries))
+	for _, e := range manifest.Entries {
+		if _, err := os.Lstat(e.TrashPath); err == nil {
+			kept = append(kept, e)
+		}
+	}
+	dropped := len(manifest.Entries) - len(kept)
+	if dropped == 0 {
+		return 0, nil
+	}
+	sessionDir := filepath.Join(tm.TrashBaseDir, id)
+	if len(kept) == 0 {
+		return dropped, os.RemoveAll(sessionDir)
+	}
+	manifest.Entries = kept
+	return dropped, writeManifest(filepath.Join(sessionDir, manifestName), manifest)
+}
 
-	if err := os.MkdirAll(dst, info.Mode()); err != nil {
-		return err
+// Purge permanently deletes a trash session, one manifest entry at a time.
+// If Stop is closed midway, the manifest is rewritten to list only the
+// entries left and the error is ErrInterrupted, so the session stays
+// consistent and restorable.
+func (tm *TrashManager) Purge(id string) error {
+	if id == "" || filepath.Base(id) != id {
+		return fmt.Errorf("invalid session id: %q", id)
+	}
+	sessionDir := filepath.Join(tm.TrashBaseDir, id)
+	if _, err := os.Stat(sessionDir); err != nil {
+		return fmt.Errorf("session %s not found", id)
+	}
+	if manifest, err := tm.readManifest(id); err == nil {
+		for _, e := range manifest.Entries {
+			if stopped(tm.Stop) {
+				if _, err := tm.PruneMissing(id); err != nil {
+					return fmt.Errorf("failed to record the interrupted purge of %s: %w", id, err)
+				}
+				return ErrInterrupted
+			}
+			if e.TrashPath == "" || !within(e.TrashPath, sessionDir) {
+				continue
+			}
+			if err := os.RemoveAll(e.TrashPath); err != nil {
+				return fmt.Errorf("failed to purge %s: %w", e.TrashPath, err)
+			}
+		}
 	}
+	return os.RemoveAll(sessionDir)
+}
+
+// TrashUsage is what the trash holds until it is purged.
+type TrashUsage struct {
+	Sessions int       `json:"sessions"`
+	Bytes    int64     `json:"bytes"`
+	Oldest   time.Time `json:"oldest,omitzero"`
+}
 
-	entries, err := os.ReadDir(src)
+// Usage totals the sessions in the trash.
+func (tm *TrashManager) Usage() (TrashUsage, error) {
+	sessions, err := tm.Sessions()
 	if err != nil {
-		return err
+		return TrashUsage{}, err
 	}
+	var usage TrashUsage
+	for _, s := range sessions {
+		size, _ := pathSize(s.Dir)
+		usage.Sessions++
+		usage.Bytes += size
+
+		created := time.Time{}
+		if s.Manifest != nil {
+			created = s.Manifest.Timestamp
+		} else if info, err := os.Stat(s.Dir); err == nil {
+			created = info.ModTime()
+		}
+		if !created.IsZero() && (usage.Oldest.IsZero() || created.Before(usage.Oldest)) {
+			usage.Oldest = created
+		}
+	}
+	return usage, nil
+}
 
-	for _, entry := range entries {
-		srcEntry := filepath.Join(src, entry.Name())
-		dstEntry := filepath.Join(dst, entry.Name())
+// Manifest returns the manifest of a trash session.
+func (tm *TrashManager) Manifest(id string) (*TrashManifest, error) {
+	if id == "" || filepath.Base(id) != id {
+		return nil, fmt.Errorf("invalid session id: %q", id)
+	}
+	return tm.readManifest(id)
+}
+
+func (tm *TrashManager) readManifest(id string) (*TrashManifest, error) {
+	manifestData, err := os.ReadFile(filepath.Join(tm.TrashBaseDir, id, manifestName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest for session %s: %w", id, err)
+	}
+
+	var manifest TrashManifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
 
-		if err := tm.copyPath(srcEntry, dstEntry); err != nil {
-			return err
+	// Entries record absolute paths; rebase them if the data directory moved
+	sessionDir := filepath.Join(tm.TrashBaseDir, id)
+	for i, e := range manifest.Entries {
+		if e.TrashPath != "" && !strings.HasPrefix(e.TrashPath, sessionDir+string(filepath.Separator)) {
+			manifest.Entries[i].TrashPath = filepath.Join(sessionDir, sessionRel(e.TrashPath, id))
 		}
 	}
+	return &manifest, nil
+}
 
-	return nil
+// sessionRel returns the part of trashPath below its session directory,
+// identified by the last path element equal to id.
+func sessionRel(trashPath, id string) string {
+	parts := strings.Split(filepath.ToSlash(trashPath), "/")
+	for i := len(parts) - 2; i >= 0; i-- {
+		if parts[i] == id {
+			return filepath.FromSlash(strings.Join(parts[i+1:], "/"))
+		}
+	}
+	return filepath.Base(trashPath)
+}
+
+// sessionTime derives a session's timestamp from its ID, falling back to the directory mtime.
+func sessionTime(id, dir string) time.Time {
+	stamp, _, _ := strings.Cut(id, "-")
+	if t, err := time.ParseInLocation(sessionLayout, stamp, time.Local); err == nil {
+		return t
+	}
+	if info, err := os.Stat(dir); err == nil {
+		return info.ModTime()
+	}
+	return time.Now()
+}
+
+// sessionLayout is how sessions are named after the time they started.
+const sessionLayout = "20060102_150405"
+
+// newSession creates the directory of a new session and returns its ID. A
+// session started in the same second as another gets a suffix (-2, -3, ...)
+// instead of sharing its directory.
+func (tm *TrashManager) newSession() (string, error) {
+	if err := os.MkdirAll(tm.TrashBaseDir, 0755); err != nil {
+		return "", err
+	}
+	stamp := time.Now().Format(sessionLayout)
+	id := stamp
+	for i := 2; ; i++ {
+		err := os.Mkdir(filepath.Join(tm.TrashBaseDir, id), 0755)
+		if !errors.Is(err, os.ErrExist) {
+			return id, err
+		}
+		id = fmt.Sprintf("%s-%d", stamp, i)
+	}
+}
+
+// uniqueName returns name, or name~2, name~3, ... if it was already used, and
+// marks the result as used.
+func uniqueName(used map[string]bool, name string) string {
+	candidate := name
+	for i := 2; used[candidate] || candidate == manifestName || candidate == journalName || strings.HasSuffix(candidate, partialSuffix); i++ {
+		candidate = fmt.Sprintf("%s~%d", name, i)
+	}
+	used[candidate] = true
+	return candidate
+}
+
+// baseName strips the "~N" suffix uniqueName adds to a repeated name.
+func baseName(name string) string {
+	i := strings.LastIndex(name, "~")
+	if i <= 0 {
+		return name
+	}
+	if _, err := strconv.Atoi(name[i+1:]); err != nil {
+		return name
+	}
+	return name[:i]
+}
+
+// exists reports whether path can be stat'ed.
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// movePath attempts to rename a file/directory, and falls back to copy+delete if it fails due to being on a different device.
+// The copy is made under a partial name and renamed into place once complete,
+// so after a crash an incomplete copy is never mistaken for the original.
+func (tm *TrashManager) movePath(src, dst string) error {
+	err := os.Rename(src, dst)
+	if err == nil {
+		return nil
+	}
+
+	// Check if the error is a cross-device link error (EXDEV)
+	var linkErr *os.LinkError
+	if errors.As(err, &linkErr) {
+		if errno, ok := linkErr.Err.(syscall.Errno); ok && isCrossDevice(errno) {
+			// Fallback to copy and delete
+			partial := dst + partialSuffix
+			if err := tm.copyPath(src, partial); err != nil {
+				// Leave the source intact and drop the partial copy
+				os.RemoveAll(partial)
+				return fmt.Errorf("failed to copy during fallback: %w", err)
+			}
+			fault("copied")
+			if err := os.Rename(partial, dst); err != nil {
+				os.RemoveAll(partial)
+				return err
+			}
+			fault("copy-renamed")
+			return os.RemoveAll(src)
+		}
+	}
+
+	return err
+}
+
+// errConflict means something was created at an entry's original location
+// after it was trashed; the trashed copy is left where it is.
+var errConflict = errors.New("something else exists there now; left in trash")
+
+// restorePlain moves an entry back unless its original location has been
+// taken in the meantime. An empty directory in its place is replaced.
+func (tm *TrashManager) restorePlain(entry TrashEntry) error {
+	if info, err := os.Lstat(entry.OriginalPath); err == nil {
+		if !info.IsDir() || os.Remove(entry.OriginalPath) != nil {
+			return errConflict
+		}
+	}
+	return tm.movePath(entry.TrashPath, entry.OriginalPath)
+}
+
+// restoreBatched puts back a directory that was moved as a whole. The empty
+// directory left in its place is replaced; if something was created there
+// since, the trashed contents are merged in without overwriting anything.
+func (tm *TrashManager) restoreBatched(entry TrashEntry) error {
+	if err := os.Remove(entry.OriginalPath); err == nil || os.IsNotExist(err) {
+		return tm.movePath(entry.TrashPath, entry.OriginalPath)
+	}
+
+	children, err := os.ReadDir(entry.TrashPath)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, c := range children {
+		dst := filepath.Join(entry.OriginalPath, c.Name())
+		if _, err := os.Lstat(dst); err == nil {
+			errs = append(errs, fmt.Errorf("%s: %w", dst, errConflict))
+			continue
+		}
+		if err := tm.movePath(filepath.Join(entry.TrashPath, c.Name()), dst); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		os.Remove(entry.TrashPath)
+	}
+	return errors.Join(errs...)
+}
+
+// restoreSymlink recreates a trashed symlink from its recorded target and
+// drops whatever is left of it in the trash.
+func (tm *TrashManager) restoreSymlink(entry TrashEntry) error {
+	if err := os.Symlink(entry.SymlinkTarget, entry.OriginalPath); err != nil {
+		return err
+	}
+	return os.RemoveAll(entry.TrashPath)
+}
+
+// pathSize returns the total size of the files below path.
+func pathSize(path string) (int64, error) {
+	size, _, err := treeStats(path)
+	return size, err
 }
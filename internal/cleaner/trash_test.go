@@ -1,9 +1,16 @@
 package cleaner
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/safety"
 )
 
 func TestTrashManager_MovePath(t *testing.T) {
@@ -77,3 +84,649 @@ func TestTrashManager_CopyPath_Dir(t *testing.T) {
 		t.Errorf("file2 content mismatch: %s", string(content2))
 	}
 }
+
+func TestTrashManager_RepairOrphan(t *testing.T) {
+	tm := NewTrashManager()
+	tempDir, err := os.MkdirTemp("", "burrow-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	tm.TrashBaseDir = filepath.Join(tempDir, "trash")
+	sessionDir := filepath.Join(tm.TrashBaseDir, "20240101_120000")
+	if err := os.MkdirAll(filepath.Join(sessionDir, "DerivedData"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sessionDir, "unknown.bin"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := tm.Sessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 1 || !sessions[0].Orphaned {
+		t.Fatalf("expected one orphaned session, got %+v", sessions)
+	}
+
+	original := filepath.Join(tempDir, "Xcode", "DerivedData")
+	manifest, err := tm.RepairOrphan("20240101_120000", []string{original})
+	if err != nil {
+		t.Fatalf("RepairOrphan failed: %v", err)
+	}
+	if len(manifest.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(manifest.Entries))
+	}
+
+	if _, err := tm.Restore("20240101_120000"); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if _, err := os.Stat(original); err != nil {
+		t.Errorf("matched entry was not restored: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(sessionDir, "unknown.bin")); err != nil {
+		t.Errorf("unmatched entry should remain in trash: %v", err)
+	}
+}
+
+func TestTrashManager_RepairOrphanAmbiguous(t *testing.T) {
+	tm := NewTrashManager()
+	tempDir := t.TempDir()
+	tm.TrashBaseDir = filepath.Join(tempDir, "trash")
+	id := "20240101_120000"
+	sessionDir := filepath.Join(tm.TrashBaseDir, id)
+	for _, name := range []string{"Cache", "data", "data~2", "logs"} {
+		if err := os.MkdirAll(filepath.Join(sessionDir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The journal still knows where "logs" came from
+	journaled := filepath.Join(tempDir, "app", "logs")
+	err := writeJournal(filepath.Join(sessionDir, journalName), &Journal{Entries: []TrashEntry{
+		{OriginalPath: journaled, TrashPath: filepath.Join("/elsewhere", id, "logs")},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := tm.RepairOrphan(id, []string{
+		filepath.Join(tempDir, "a", "Cache"),
+		filepath.Join(tempDir, "b", "Cache"),
+		filepath.Join(tempDir, "a", "data"),
+		filepath.Join(tempDir, "b", "logs"),
+	})
+	if err != nil {
+		t.Fatalf("RepairOrphan failed: %v", err)
+	}
+
+	got := make(map[string]string)
+	for _, e := range manifest.Entries {
+		got[filepath.Base(e.TrashPath)] = e.OriginalPath
+	}
+	want := map[string]string{"Cache": "", "data": "", "data~2": "", "logs": journaled}
+	if len(got) != len(want) {
+		t.Fatalf("entries = %v, want %v", got, want)
+	}
+	for name, orig := range want {
+		if got[name] != orig {
+			t.Errorf("%s restored to %q, want %q", name, got[name], orig)
+		}
+	}
+}
+
+func TestTrashManager_Symlinks(t *testing.T) {
+	tm := NewTrashManager()
+	tempDir, err := os.MkdirTemp("", "burrow-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	tm.TrashBaseDir = filepath.Join(tempDir, "trash")
+
+	target := filepath.Join(tempDir, "real")
+	if err := os.MkdirAll(target, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(target, "data.bin"), []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(tempDir, "link")
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatal(err)
+	}
+
+	// Copy fallback must recreate the link, not the linked data
+	copied := filepath.Join(tempDir, "copied-link")
+	if err := tm.copyPath(link, copied); err != nil {
+		t.Fatalf("copyPath failed: %v", err)
+	}
+	if info, err := os.Lstat(copied); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("copied path is not a symlink: %v", err)
+	}
+
+	session, err := tm.MoveToTrash([]string{link})
+	if err != nil {
+		t.Fatalf("MoveToTrash failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(target, "data.bin")); err != nil {
+		t.Errorf("trashing a link must not touch its target: %v", err)
+	}
+
+	if _, err := tm.Restore(session); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	got, err := os.Readlink(link)
+	if err != nil {
+		t.Fatalf("restored path is not a symlink: %v", err)
+	}
+	if got != target {
+		t.Errorf("restored link points to %s, want %s", got, target)
+	}
+}
+
+func TestTrashManager_RestoreAfterRelocation(t *testing.T) {
+	base := t.TempDir()
+	tm := &TrashManager{TrashBaseDir: filepath.Join(base, "old", "trash")}
+
+	src := filepath.Join(base, "cache")
+	if err := os.WriteFile(src, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	id, err := tm.MoveToTrash([]string{src})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Relocate the whole data directory, as a migration would
+	if err := os.Rename(filepath.Join(base, "old"), filepath.Join(base, "new")); err != nil {
+		t.Fatal(err)
+	}
+	tm.TrashBaseDir = filepath.Join(base, "new", "trash")
+
+	if _, err := tm.Restore(id); err != nil {
+		t.Fatal(err)
+	}
+	if content, err := os.ReadFile(src); err != nil || string(content) != "data" {
+		t.Errorf("restored content = %q, %v; want data", content, err)
+	}
+}
+
+func TestTrashManager_NameCollisions(t *testing.T) {
+	base := t.TempDir()
+	tm := &TrashManager{TrashBaseDir: filepath.Join(base, "trash")}
+
+	var srcs []string
+	for _, dir := range []string{"a", "b", "c"} {
+		src := filepath.Join(base, dir, "data")
+		if err := os.MkdirAll(filepath.Dir(src), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(src, []byte(dir), 0644); err != nil {
+			t.Fatal(err)
+		}
+		srcs = append(srcs, src)
+	}
+
+	id, err := tm.MoveToTrash(srcs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tm.Restore(id); err != nil {
+		t.Fatal(err)
+	}
+	for _, src := range srcs {
+		want := filepath.Base(filepath.Dir(src))
+		if content, err := os.ReadFile(src); err != nil || string(content) != want {
+			t.Errorf("%s restored as %q, %v; want %q", src, content, err, want)
+		}
+	}
+}
+
+func TestTrashManager_BatchedParents(t *testing.T) {
+	base := t.TempDir()
+	logs := filepath.Join(base, "logs")
+	tm := &TrashManager{TrashBaseDir: filepath.Join(base, "trash"), BatchWithin: map[string]safety.Options{logs: {}}}
+
+	full := filepath.Join(base, "logs", "full")
+	partial := filepath.Join(base, "logs", "partial")
+	var srcs []string
+	for _, dir := range []string{full, partial} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range []string{"a.log", "b.log", "c.log"} {
+			p := filepath.Join(dir, name)
+			if err := os.WriteFile(p, []byte(name), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if dir == full || name != "c.log" {
+				srcs = append(srcs, p)
+			}
+		}
+	}
+
+	if err := os.Chmod(full, 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	// Outside the searched directories nothing is moved whole
+	if units, _ := collapseParents(srcs, map[string]safety.Options{full: {}}); len(units) != len(srcs) {
+		t.Errorf("collapsed the searched directory itself: %v", units)
+	}
+
+	id, err := tm.MoveToTrash(srcs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := tm.readManifest(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// full/ moves as one entry; partial/ keeps c.log, so its files move singly
+	if len(manifest.Entries) != 3 {
+		t.Fatalf("expected 3 entries, got %+v", manifest.Entries)
+	}
+	if entries, err := os.ReadDir(full); err != nil || len(entries) != 0 {
+		t.Errorf("batched directory should be left empty, got %v, %v", entries, err)
+	}
+	if info, err := os.Stat(full); err != nil || info.Mode().Perm() != 0750 {
+		t.Errorf("recreated directory = %v, %v; want mode 0750", info, err)
+	}
+	if _, err := os.Stat(filepath.Join(partial, "c.log")); err != nil {
+		t.Errorf("unselected file was moved: %v", err)
+	}
+
+	if _, err := tm.Restore(id); err != nil {
+		t.Fatal(err)
+	}
+	for _, src := range srcs {
+		if content, err := os.ReadFile(src); err != nil || string(content) != filepath.Base(src) {
+			t.Errorf("%s restored as %q, %v", src, content, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tm.TrashBaseDir, id)); !os.IsNotExist(err) {
+		t.Errorf("session should be removed after a full restore, got %v", err)
+	}
+}
+
+func TestTrashManager_RestoreSince(t *testing.T) {
+	tempDir := t.TempDir()
+	tm := &TrashManager{TrashBaseDir: filepath.Join(tempDir, "trash")}
+	shared := filepath.Join(tempDir, "cache", "blob")
+	other := filepath.Join(tempDir, "cache", "other")
+
+	// Two sessions trashed the same path; the newer one also trashed another
+	sessions := []struct {
+		id    string
+		age   time.Duration
+		files map[string]string // original path -> content
+	}{
+		{"20240101_100000", 3 * time.Hour, map[string]string{shared: "old"}},
+		{"20240101_120000", 10 * time.Minute, map[string]string{shared: "new", other: "x"}},
+	}
+	for _, s := range sessions {
+		dir := filepath.Join(tm.TrashBaseDir, s.id)
+		manifest := TrashManifest{Timestamp: time.Now().Add(-s.age)}
+		for original, content := range s.files {
+			trashPath := filepath.Join(dir, filepath.Base(original))
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(trashPath, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			manifest.Entries = append(manifest.Entries, TrashEntry{OriginalPath: original, TrashPath: trashPath})
+		}
+		if err := writeManifest(filepath.Join(dir, manifestName), &manifest); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	restored, err := tm.RestoreSince(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restored) != 1 || restored[0].Session != "20240101_120000" {
+		t.Fatalf("restored %v, want only the recent session", restored)
+	}
+
+	restored, err = tm.RestoreSince(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restored) != 1 || restored[0].Session != "20240101_100000" {
+		t.Fatalf("restored %v, want the remaining session", restored)
+	}
+	if n := restored[0].Count(RestoreSkipped); n != 1 {
+		t.Errorf("older session skipped %d entries, want the conflicting one", n)
+	}
+	if data, _ := os.ReadFile(shared); string(data) != "new" {
+		t.Errorf("%s = %q, want the newest copy", shared, data)
+	}
+	if _, err := os.Stat(filepath.Join(tm.TrashBaseDir, "20240101_100000", "blob")); err != nil {
+		t.Errorf("conflicting older copy should stay in the trash: %v", err)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("%s was not restored: %v", other, err)
+	}
+}
+
+func TestTrashManager_RestoreVerifies(t *testing.T) {
+	tempDir := t.TempDir()
+	tm := &TrashManager{TrashBaseDir: filepath.Join(tempDir, "trash")}
+	intact := filepath.Join(tempDir, "intact.bin")
+	damaged := filepath.Join(tempDir, "damaged.bin")
+	for _, p := range []string{intact, damaged} {
+		if err := os.WriteFile(p, make([]byte, 4096), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	id, err := tm.MoveToTrash([]string{intact, damaged})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Simulate data lost while the file sat in the trash
+	if err := os.Truncate(filepath.Join(tm.TrashBaseDir, id, "damaged.bin"), 100); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := tm.Restore(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Count(RestoreOK) != 1 || report.Count(RestoreUnverified) != 1 {
+		t.Fatalf("report = %+v, want one restored and one unverified entry", report.Entries)
+	}
+	for _, e := range report.Entries {
+		if e.Path == damaged && (e.Status != RestoreUnverified || e.Detail == "") {
+			t.Errorf("damaged entry = %+v, want a failed verification", e)
+		}
+	}
+	// It is back in place rather than left in the trash
+	if _, err := os.Stat(damaged); err != nil {
+		t.Errorf("damaged entry was not moved back: %v", err)
+	}
+}
+
+func TestTrashManager_ChecksumsRecordedAndVerified(t *testing.T) {
+	tempDir := t.TempDir()
+	tm := &TrashManager{TrashBaseDir: filepath.Join(tempDir, "trash"), Checksums: true}
+	dir := filepath.Join(tempDir, "cache")
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.bin", "sub/b.bin"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("original"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	id, err := tm.MoveToTrash([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := tm.Manifest(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := manifest.Entries[0]
+	if e.Size != 16 || e.Files != 2 || !e.Mode.IsDir() || !strings.HasPrefix(e.Checksum, "sha256:") {
+		t.Fatalf("entry = %+v, want size 16, 2 files, a directory mode, and a checksum", e)
+	}
+
+	// Same size, different content: only the checksum can tell
+	if err := os.WriteFile(filepath.Join(e.TrashPath, "a.bin"), []byte("modified"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	report, err := tm.Restore(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Count(RestoreUnverified) != 1 || !strings.Contains(report.Entries[0].Detail, "checksum") {
+		t.Fatalf("report = %+v, want a failed checksum verification", report.Entries)
+	}
+}
+
+func TestTrashManager_ChecksumCoversSource(t *testing.T) {
+	tempDir := t.TempDir()
+	tm := &TrashManager{TrashBaseDir: filepath.Join(tempDir, "trash"), Checksums: true}
+	src := filepath.Join(tempDir, "blob")
+	if err := os.WriteFile(src, []byte("original"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Damage the copy right after it lands in the trash
+	faultHook = func(point string) {
+		if point == "moved" {
+			matches, _ := filepath.Glob(filepath.Join(tm.TrashBaseDir, "*", "blob"))
+			for _, m := range matches {
+				os.WriteFile(m, []byte("modified"), 0644)
+			}
+		}
+	}
+	defer func() { faultHook = nil }()
+
+	id, err := tm.MoveToTrash([]string{src})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := tm.Restore(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Count(RestoreUnverified) != 1 {
+		t.Errorf("report = %+v, want the damaged copy to fail verification", report.Entries)
+	}
+}
+
+func TestTrashManager_CopyPreservesMetadata(t *testing.T) {
+	tempDir := t.TempDir()
+	tm := &TrashManager{}
+
+	src := filepath.Join(tempDir, "src")
+	if err := os.MkdirAll(filepath.Join(src, "private"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(src, "private", "tool")
+	if err := os.WriteFile(file, []byte("#!/bin/sh\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("private/tool", filepath.Join(src, "current")); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-72 * time.Hour).Truncate(time.Second)
+	os.Chmod(file, 0750)
+	os.Chmod(filepath.Join(src, "private"), 0700)
+	for _, p := range []string{file, filepath.Join(src, "private"), src} {
+		if err := os.Chtimes(p, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dst := filepath.Join(tempDir, "dst")
+	if err := tm.copyPath(src, dst); err != nil {
+		t.Fatalf("copyPath failed: %v", err)
+	}
+
+	for _, rel := range []string{"", "private", "private/tool"} {
+		want, _ := os.Stat(filepath.Join(src, rel))
+		got, err := os.Stat(filepath.Join(dst, rel))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Mode() != want.Mode() {
+			t.Errorf("%q: mode = %v, want %v", rel, got.Mode(), want.Mode())
+		}
+		if !got.ModTime().Equal(old) {
+			t.Errorf("%q: modified %v, want %v", rel, got.ModTime(), old)
+		}
+	}
+	if target, err := os.Readlink(filepath.Join(dst, "current")); err != nil || target != "private/tool" {
+		t.Errorf("link = %q, %v; want a link to private/tool", target, err)
+	}
+}
+
+func TestMoveToTrash_PartialFailure(t *testing.T) {
+	base := t.TempDir()
+	tm := &TrashManager{TrashBaseDir: filepath.Join(base, "trash")}
+
+	src := filepath.Join(base, "cache")
+	if err := os.WriteFile(src, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	id, err := tm.MoveToTrash([]string{src, filepath.Join(base, "gone")})
+	if err == nil {
+		t.Fatal("expected an error for the missing path")
+	}
+	if id == "" {
+		t.Fatal("session ID lost although an item was moved")
+	}
+	m, err := tm.Manifest(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Entries) != 1 || m.Entries[0].OriginalPath != src {
+		t.Errorf("manifest entries = %+v, want only %s", m.Entries, src)
+	}
+}
+
+func TestMoveToTrash_Note(t *testing.T) {
+	base := t.TempDir()
+	tm := &TrashManager{TrashBaseDir: filepath.Join(base, "trash"), Note: "before Xcode 16 upgrade"}
+
+	src := filepath.Join(base, "cache")
+	if err := os.WriteFile(src, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	id, err := tm.MoveToTrash([]string{src})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := tm.Manifest(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Note != tm.Note {
+		t.Errorf("manifest note = %q, want %q", m.Note, tm.Note)
+	}
+}
+
+func TestMoveToTrash_Interrupted(t *testing.T) {
+	base := t.TempDir()
+	stop := make(chan struct{})
+	tm := &TrashManager{TrashBaseDir: filepath.Join(base, "trash"), Stop: stop}
+
+	// The kept file stops the cached files from being moved as one directory
+	cache := filepath.Join(base, "cache")
+	if err := os.MkdirAll(cache, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cache, "keep"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	var srcs []string
+	for i := range 40 {
+		src := filepath.Join(cache, fmt.Sprintf("blob%d", i))
+		if err := os.WriteFile(src, []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		srcs = append(srcs, src)
+	}
+
+	var once sync.Once
+	faultHook = func(point string) {
+		if point == "moved" {
+			once.Do(func() { close(stop) })
+		}
+	}
+	defer func() { faultHook = nil }()
+
+	id, err := tm.MoveToTrash(srcs)
+	if !errors.Is(err, ErrInterrupted) || id == "" {
+		t.Fatalf("MoveToTrash = %q, %v; want the session and ErrInterrupted", id, err)
+	}
+	m, err := tm.Manifest(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	moved := 0
+	for _, src := range srcs {
+		if _, err := os.Stat(src); os.IsNotExist(err) {
+			moved++
+		}
+	}
+	if moved == 0 || moved == len(srcs) || len(m.Entries) != moved {
+		t.Errorf("moved %d of %d, manifest lists %d; want every move recorded", moved, len(srcs), len(m.Entries))
+	}
+	if exists(filepath.Join(tm.TrashBaseDir, id, journalName)) {
+		t.Error("journal left behind after an interrupted cleanup")
+	}
+
+	if exists(filepath.Join(tm.TrashBaseDir, id, journalName)) {
+		t.Error("journal left behind after an interrupted cleanup")
+	}
+}
+
+func TestPurge_Interrupted(t *testing.T) {
+	base := t.TempDir()
+	tm := &TrashManager{TrashBaseDir: filepath.Join(base, "trash")}
+	var srcs []string
+	for _, name := range []string{"a", "b", "c"} {
+		src := filepath.Join(base, name)
+		if err := os.WriteFile(src, []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		srcs = append(srcs, src)
+	}
+	id, err := tm.MoveToTrash(srcs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := tm.Manifest(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first entry is gone when the purge is interrupted; the manifest
+	// must list only what is left
+	os.Remove(m.Entries[0].TrashPath)
+	stop := make(chan struct{})
+	close(stop)
+	tm.Stop = stop
+	if err := tm.Purge(id); !errors.Is(err, ErrInterrupted) {
+		t.Fatalf("Purge = %v, want ErrInterrupted", err)
+	}
+	m, err = tm.Manifest(id)
+	if err != nil || len(m.Entries) != 2 {
+		t.Fatalf("manifest after purge = %+v, %v; want 2 entries", m, err)
+	}
+	for _, e := range m.Entries {
+		if !exists(e.TrashPath) {
+			t.Errorf("%s listed but deleted", e.TrashPath)
+		}
+	}
+}
+
+func TestTrashManager_SessionsInTheSameSecond(t *testing.T) {
+	tm := &TrashManager{TrashBaseDir: t.TempDir()}
+	seen := make(map[string]bool)
+	for range 3 {
+		id, err := tm.newSession()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[id] {
+			t.Fatalf("session %s was handed out twice", id)
+		}
+		seen[id] = true
+	}
+
+	// A directory already named after this second is never shared
+	stamp := time.Now().Format(sessionLayout)
+	os.Mkdir(filepath.Join(tm.TrashBaseDir, stamp), 0755)
+	if id, err := tm.newSession(); err != nil || id == stamp {
+		t.Errorf("newSession() = %q, %v; want a suffix after %s", id, err, stamp)
+	}
+}
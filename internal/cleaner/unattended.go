@@ -0,0 +1,48 @@
+package cleaner
+
+import (
+	"fmt"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+// Unattended holds the limits a cleanup started with nobody there to answer
+// prompts, by the watch loop or the API, must respect.
+type Unattended struct {
+	MinFreeSpace int64
+	TrashCap     int64
+	// PurgeOverCap purges the oldest trash sessions to stay under the cap
+	// (trash_cap_action "purge"); otherwise a cleanup over the cap fails.
+	PurgeOverCap bool
+}
+
+// PrepareUnattended runs the checks the CLI asks about before a trash-based
+// cleanup: the trash volume must keep enough free space, and the trash must
+// stay under its cap, purging old sessions first when that is allowed.
+// Permanent and system-trash cleanups need neither. It returns the sessions
+// it purged, if any; an error means nothing should be cleaned.
+func (c *Cleaner) PrepareUnattended(results []rules.Result, permanent bool, u Unattended) (*TrashCap, error) {
+	if permanent || c.UseSystemTrash {
+		return nil, nil
+	}
+
+	// Like the CLI, a free-space check that can't run doesn't block the cleanup
+	if p, err := c.CheckSpace(results, u.MinFreeSpace); err == nil && !p.OK() {
+		if p.NearlyFull {
+			return nil, fmt.Errorf("disk is nearly full (%d bytes free, minimum %d); trashing files would not free any space", p.Available, p.MinFreeSpace)
+		}
+		return nil, fmt.Errorf("not enough free space to copy %d bytes into trash (%d bytes free, minimum %d must remain)", p.CopyBytes, p.Available, p.MinFreeSpace)
+	}
+
+	p, err := c.CheckTrashCap(results, u.TrashCap)
+	if err != nil || !p.Over() {
+		return nil, nil
+	}
+	if !u.PurgeOverCap {
+		return nil, fmt.Errorf("cleanup would take the trash to %d bytes, over its %d-byte cap; purge old sessions or set trash_cap_action to \"purge\"", p.Used+p.Incoming, p.Cap)
+	}
+	if err := c.PurgeForCap(p); err != nil {
+		return nil, fmt.Errorf("failed to purge old trash sessions: %w", err)
+	}
+	return p, nil
+}
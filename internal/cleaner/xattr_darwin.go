@@ -0,0 +1,28 @@
+package cleaner
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// xattrNoFollow is XATTR_NOFOLLOW from <sys/xattr.h>.
+const xattrNoFollow = 0x0001
+
+// listXattrs returns the names of the extended attributes set on path, such
+// as com.apple.quarantine.
+func listXattrs(path string) []string {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return nil
+	}
+	size, _, errno := syscall.Syscall6(syscall.SYS_LISTXATTR, uintptr(unsafe.Pointer(p)), 0, 0, xattrNoFollow, 0, 0)
+	if errno != 0 || size == 0 {
+		return nil
+	}
+	buf := make([]byte, size)
+	size, _, errno = syscall.Syscall6(syscall.SYS_LISTXATTR, uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(&buf[0])), size, xattrNoFollow, 0, 0)
+	if errno != 0 {
+		return nil
+	}
+	return splitXattrNames(buf[:size])
+}
@@ -0,0 +1,39 @@
+package cleaner
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// listXattrs returns the names of the extended attributes set on path.
+func listXattrs(path string) []string {
+	size, err := syscall.Listxattr(path, nil)
+	if err != nil || size <= 0 {
+		return nil
+	}
+	buf := make([]byte, size)
+	if size, err = syscall.Listxattr(path, buf); err != nil {
+		return nil
+	}
+	return splitXattrNames(buf[:size])
+}
+
+// copyXattrs copies the extended attributes of src to dst. Attributes the
+// destination file system or the user's privileges don't allow are dropped.
+func copyXattrs(src, dst string) error {
+	for _, name := range listXattrs(src) {
+		size, err := syscall.Getxattr(src, name, nil)
+		if err != nil {
+			return err
+		}
+		value := make([]byte, size)
+		if size, err = syscall.Getxattr(src, name, value); err != nil {
+			return err
+		}
+		err = syscall.Setxattr(dst, name, value[:size], 0)
+		if err != nil && err != syscall.ENOTSUP && err != syscall.EPERM {
+			return fmt.Errorf("failed to copy attribute %s: %w", name, err)
+		}
+	}
+	return nil
+}
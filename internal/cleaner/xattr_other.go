@@ -0,0 +1,13 @@
+//go:build !linux && !darwin
+
+package cleaner
+
+// listXattrs is not supported on this platform.
+func listXattrs(string) []string {
+	return nil
+}
+
+// copyXattrs is not supported on this platform.
+func copyXattrs(src, dst string) error {
+	return nil
+}
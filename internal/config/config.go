@@ -2,37 +2,191 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
-	"path/filepath"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/paths"
+	"github.com/ismailtsdln/burrow/internal/units"
 )
 
 // Config represents the user configuration for Burrow.
 type Config struct {
-	DisabledCategories []string `json:"disabled_categories"`
-	ExcludedPaths      []string `json:"excluded_paths"`
-	SizeThresholdMB    int64    `json:"size_threshold_mb"`
-	EnableAuth         bool     `json:"enable_auth"`
+	DisabledCategories []string  `json:"disabled_categories"`
+	ExcludedPaths      []string  `json:"excluded_paths"`
+	SizeThresholdMB    int64     `json:"size_threshold_mb"`
+	EnableAuth         bool      `json:"enable_auth"`
+	UseSystemTrash     bool      `json:"use_system_trash"`
+	Notifications      []Webhook `json:"notifications"`
+	MinFreeSpaceMB     int64     `json:"min_free_space_mb"`
+
+	// TrashChecksums records a checksum of everything moved to the trash so
+	// undo can verify it. It reads every trashed file, so it is off by default.
+	TrashChecksums bool `json:"trash_checksums"`
+
+	// TrashCap is the most the trash may hold, e.g. "20GB"; 0 means no
+	// cap. Before a cleanup would pass it, TrashCapAction "ask" (the
+	// default) offers to purge the oldest sessions and "purge" does so
+	// without asking.
+	TrashCap       units.Size `json:"trash_cap,omitempty"`
+	TrashCapAction string     `json:"trash_cap_action,omitempty"`
+
+	// UndoWindow is how long cleanups stay undoable, e.g. "72h". Sessions
+	// older than that are purged automatically; 0 keeps them until purged.
+	UndoWindow units.Duration `json:"undo_window,omitempty"`
+
+	// SizeThreshold and MinFreeSpace accept sizes such as "1.5GB" and take
+	// precedence over their *_mb counterparts.
+	SizeThreshold units.Size `json:"size_threshold,omitempty"`
+	MinFreeSpace  units.Size `json:"min_free_space,omitempty"`
+
+	// ExcludeExternalVolumes skips rule paths and large-file roots that live on
+	// removable or network volumes.
+	ExcludeExternalVolumes bool `json:"exclude_external_volumes"`
+
+	LargeFiles LargeFilesConfig `json:"large_files"`
+
+	// Scan limits how hard scans hit the machine.
+	Scan ScanConfig `json:"scan"`
+
+	// Power decides when scheduled scans are deferred on laptops.
+	Power PowerConfig `json:"power"`
+
+	// Watch schedules `burrow watch`; its flags take precedence.
+	Watch WatchConfig `json:"watch"`
+
+	// ProtectedPaths are never deleted. ForceAllowPaths override the default
+	// Git and user-directory guards and require extra confirmation.
+	ProtectedPaths  []string `json:"protected_paths"`
+	ForceAllowPaths []string `json:"force_allow_paths"`
+
+	// DataDir relocates trash, history, and caches (default: the platform state directory).
+	DataDir string `json:"data_dir,omitempty"`
+
+	// Locale selects the language of rule descriptions and explanations,
+	// e.g. "de" (default: the system locale).
+	Locale string `json:"locale,omitempty"`
+
+	// QuietTips turns off the milestones and tips shown after cleanups.
+	QuietTips bool `json:"quiet_tips"`
+
+	envOverrides []string
 }
 
-// Load loads the configuration from ~/.config/burrow/config.json.
-func Load() (*Config, error) {
-	home, _ := os.UserHomeDir()
-	configDir := filepath.Join(home, ".config", "burrow")
-	configPath := filepath.Join(configDir, "config.json")
+// LargeFilesConfig customizes 'scan --large'.
+type LargeFilesConfig struct {
+	Roots      []LargeFileRoot `json:"roots"`
+	Extensions []string        `json:"extensions"`
+	MinSizeMB  int64           `json:"min_size_mb"`
+	MinSize    units.Size      `json:"min_size,omitempty"` // e.g. "500MB"; overrides min_size_mb
+	MinAgeDays int             `json:"min_age_days"`
+	MinAge     units.Duration  `json:"min_age,omitempty"` // e.g. "2w"; overrides min_age_days
+	Backend    string          `json:"backend,omitempty"` // "walk" (default) or "spotlight"
+}
 
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return &Config{}, nil // Return default empty config
+// SizeThresholdBytes returns the smallest result size worth reporting.
+func (c *Config) SizeThresholdBytes() int64 {
+	return sizeOr(c.SizeThreshold, c.SizeThresholdMB)
+}
+
+// MinFreeSpaceBytes returns the free space to keep on the trash volume.
+func (c *Config) MinFreeSpaceBytes() int64 {
+	return sizeOr(c.MinFreeSpace, c.MinFreeSpaceMB)
+}
+
+// MinSizeBytes returns the smallest file 'scan --large' reports.
+func (c LargeFilesConfig) MinSizeBytes() int64 {
+	return sizeOr(c.MinSize, c.MinSizeMB)
+}
+
+// sizeOr prefers a size setting over its legacy megabyte counterpart.
+func sizeOr(size units.Size, mb int64) int64 {
+	if size > 0 {
+		return int64(size)
 	}
+	return mb * units.MB
+}
+
+// ScanConfig throttles scans; zero values mean no limit.
+type ScanConfig struct {
+	MaxConcurrency int  `json:"max_concurrency"`  // Rules walked at once
+	FilesPerSecond int  `json:"files_per_second"` // Across all walks
+	IdlePriority   bool `json:"idle_priority"`    // Run at nice 19 with idle/throttled I/O
 
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return nil, err
+	// CacheTTLMinutes is how long --cached may reuse the last scan (default 60).
+	CacheTTLMinutes int `json:"cache_ttl_minutes"`
+
+	// SizeMode is the default --size-mode: "apparent" or "disk".
+	SizeMode string `json:"size_mode,omitempty"`
+
+	// RuleTimeout is the longest one rule may walk its paths, e.g. "2m"
+	// (default 5m); rules that take longer are reported as timed out.
+	RuleTimeout units.Duration `json:"rule_timeout,omitempty"`
+}
+
+// DefaultRuleTimeout bounds each rule's walk when scan.rule_timeout is unset.
+const DefaultRuleTimeout = 5 * time.Minute
+
+// RuleTimeoutDuration returns how long one rule may walk its paths.
+func (c ScanConfig) RuleTimeoutDuration() time.Duration {
+	if c.RuleTimeout > 0 {
+		return time.Duration(c.RuleTimeout)
 	}
+	return DefaultRuleTimeout
+}
+
+// PowerConfig controls battery and thermal awareness of `burrow watch`.
+type PowerConfig struct {
+	MinBatteryPercent int  `json:"min_battery_percent"` // Defer on battery below this charge (default 50)
+	RunOnBattery      bool `json:"run_on_battery"`      // Never defer because of the battery
+	IgnoreThermal     bool `json:"ignore_thermal"`      // Never defer because of thermal pressure
+}
+
+// WatchConfig schedules `burrow watch`. A running watch picks up changes
+// without a restart.
+type WatchConfig struct {
+	Interval  units.Duration `json:"interval,omitempty"` // Time between scans (default 6h)
+	AutoClean bool           `json:"auto_clean"`         // Trash Safe-risk results after each scan
+
+	// GrowthAlert is how much a rule's data must grow between scans to be
+	// reported (default 1GB).
+	GrowthAlert units.Size `json:"growth_alert,omitempty"`
+}
+
+// LargeFileRoot is a directory searched for large files. MaxDepth limits
+// recursion below it; zero means unlimited.
+type LargeFileRoot struct {
+	Path     string `json:"path"`
+	MaxDepth int    `json:"max_depth"`
+}
+
+// Webhook is a notification endpoint that receives cleanup summaries.
+// Type is one of "slack", "discord", or "generic" and is inferred from the URL when empty.
+type Webhook struct {
+	URL  string `json:"url"`
+	Type string `json:"type,omitempty"`
+}
+
+// Load loads config.json from the config directory (see paths.ConfigDir), or
+// the file selected by --config / BURROW_CONFIG, then applies BURROW_<KEY>
+// environment overrides, which take precedence over the file. Invalid
+// overrides are ignored and reported in the error alongside the config.
+func Load() (*Config, error) {
+	configPath := paths.ConfigFile()
 
 	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		return nil, err
+	if _, err := os.Stat(configPath); err == nil {
+		data, err := os.ReadFile(configPath)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, err
+		}
 	}
 
+	if err := applyEnv(&cfg, os.LookupEnv); err != nil {
+		return &cfg, fmt.Errorf("ignoring invalid environment overrides: %w", err)
+	}
 	return &cfg, nil
 }
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix starts the environment variables that override config keys.
+const EnvPrefix = "BURROW_"
+
+// EnvName returns the variable that overrides a config key: the key
+// upper-cased, with nested keys joined by underscores, e.g. scan.max_concurrency
+// becomes BURROW_SCAN_MAX_CONCURRENCY.
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// EnvOverrides returns the variables that overrode the config file, in
+// field order.
+func (c *Config) EnvOverrides() []string {
+	return c.envOverrides
+}
+
+// applyEnv overrides fields of cfg from the environment. Lists are
+// comma-separated, and lists of objects such as notifications are JSON.
+// Invalid values are left out and reported together.
+func applyEnv(cfg *Config, lookup func(string) (string, bool)) error {
+	var errs []error
+	applyEnvStruct(reflect.ValueOf(cfg).Elem(), "", lookup, &cfg.envOverrides, &errs)
+	return errors.Join(errs...)
+}
+
+// setter is implemented by units.Size and units.Duration.
+type setter interface {
+	Set(string) error
+}
+
+func applyEnvStruct(v reflect.Value, prefix string, lookup func(string) (string, bool), applied *[]string, errs *[]error) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if key == "" || key == "-" || !t.Field(i).IsExported() {
+			continue
+		}
+		key = prefix + key
+		field := v.Field(i)
+
+		if _, ok := field.Addr().Interface().(setter); !ok && field.Kind() == reflect.Struct {
+			applyEnvStruct(field, key+".", lookup, applied, errs)
+			continue
+		}
+		name := EnvName(key)
+		raw, ok := lookup(name)
+		if !ok {
+			continue
+		}
+		if err := setField(field, strings.TrimSpace(raw)); err != nil {
+			*errs = append(*errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		*applied = append(*applied, name)
+	}
+}
+
+func setField(field reflect.Value, raw string) error {
+	if s, ok := field.Addr().Interface().(setter); ok {
+		return s.Set(raw)
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("expected true or false, got %q", raw)
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("expected a whole number, got %q", raw)
+		}
+		field.SetInt(n)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() == reflect.String {
+			var list []string
+			for _, item := range strings.Split(raw, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					list = append(list, item)
+				}
+			}
+			field.Set(reflect.ValueOf(list))
+			return nil
+		}
+		return json.Unmarshal([]byte(raw), field.Addr().Interface())
+	default:
+		return fmt.Errorf("unsupported setting")
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ismailtsdln/burrow/internal/units"
+)
+
+func TestApplyEnv(t *testing.T) {
+	env := map[string]string{
+		"BURROW_SIZE_THRESHOLD_MB":        "250",
+		"BURROW_SIZE_THRESHOLD":           "1.5GB",
+		"BURROW_EXCLUDED_PATHS":           " ~/work, ,/tmp/cache ",
+		"BURROW_ENABLE_AUTH":              "true",
+		"BURROW_SCAN_MAX_CONCURRENCY":     "2",
+		"BURROW_LARGE_FILES_MIN_SIZE":     "500MB",
+		"BURROW_LARGE_FILES_MIN_AGE_DAYS": "30",
+		"BURROW_NOTIFICATIONS":            `[{"url":"https://example.com/hook"}]`,
+		"BURROW_DATA_DIR":                 "/Volumes/Scratch/burrow",
+	}
+	lookup := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+
+	cfg := Config{ExcludedPaths: []string{"~/from-file"}, EnableAuth: false}
+	if err := applyEnv(&cfg, lookup); err != nil {
+		t.Fatalf("applyEnv: %v", err)
+	}
+
+	if cfg.SizeThresholdMB != 250 || cfg.SizeThreshold != units.Size(1.5*float64(units.GB)) {
+		t.Errorf("size threshold = %d MB / %d, want 250 MB / 1.5GB", cfg.SizeThresholdMB, cfg.SizeThreshold)
+	}
+	if !slices.Equal(cfg.ExcludedPaths, []string{"~/work", "/tmp/cache"}) {
+		t.Errorf("excluded paths = %q, want the environment list to replace the file's", cfg.ExcludedPaths)
+	}
+	if !cfg.EnableAuth || cfg.Scan.MaxConcurrency != 2 || cfg.LargeFiles.MinAgeDays != 30 {
+		t.Errorf("enable_auth=%v max_concurrency=%d min_age_days=%d", cfg.EnableAuth, cfg.Scan.MaxConcurrency, cfg.LargeFiles.MinAgeDays)
+	}
+	if cfg.LargeFiles.MinSize != units.Size(500*units.MB) {
+		t.Errorf("large_files.min_size = %d, want 500MB", cfg.LargeFiles.MinSize)
+	}
+	if len(cfg.Notifications) != 1 || cfg.Notifications[0].URL != "https://example.com/hook" {
+		t.Errorf("notifications = %+v", cfg.Notifications)
+	}
+	if cfg.DataDir != "/Volumes/Scratch/burrow" {
+		t.Errorf("data_dir = %q", cfg.DataDir)
+	}
+	if got := cfg.EnvOverrides(); len(got) != len(env) {
+		t.Errorf("EnvOverrides() = %q, want all %d variables", got, len(env))
+	}
+}
+
+func TestApplyEnv_InvalidValuesAreSkipped(t *testing.T) {
+	env := map[string]string{
+		"BURROW_ENABLE_AUTH":       "maybe",
+		"BURROW_SIZE_THRESHOLD_MB": "lots",
+		"BURROW_USE_SYSTEM_TRASH":  "1",
+	}
+	lookup := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+
+	cfg := Config{EnableAuth: true, SizeThresholdMB: 100}
+	err := applyEnv(&cfg, lookup)
+	if err == nil {
+		t.Fatal("expected an error for invalid values")
+	}
+	for _, name := range []string{"BURROW_ENABLE_AUTH", "BURROW_SIZE_THRESHOLD_MB"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error %q does not name %s", err, name)
+		}
+	}
+	if !cfg.EnableAuth || cfg.SizeThresholdMB != 100 {
+		t.Errorf("invalid values changed the config: enable_auth=%v size_threshold_mb=%d", cfg.EnableAuth, cfg.SizeThresholdMB)
+	}
+	if !cfg.UseSystemTrash || !slices.Equal(cfg.EnvOverrides(), []string{"BURROW_USE_SYSTEM_TRASH"}) {
+		t.Errorf("valid override not applied: %v %q", cfg.UseSystemTrash, cfg.EnvOverrides())
+	}
+}
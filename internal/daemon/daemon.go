@@ -0,0 +1,231 @@
+package daemon
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/cleaner"
+	"github.com/ismailtsdln/burrow/internal/config"
+	"github.com/ismailtsdln/burrow/internal/dismiss"
+	"github.com/ismailtsdln/burrow/internal/notify"
+	"github.com/ismailtsdln/burrow/internal/paths"
+	"github.com/ismailtsdln/burrow/internal/power"
+	"github.com/ismailtsdln/burrow/internal/priority"
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/safety"
+	"github.com/ismailtsdln/burrow/internal/scanner"
+	"github.com/ismailtsdln/burrow/internal/snapshot"
+	"github.com/ismailtsdln/burrow/internal/usage"
+)
+
+// Options controls the behaviour of the watch loop. Zero values defer to
+// the watch block of the config.
+type Options struct {
+	Interval  time.Duration
+	AutoClean bool // Clean Safe-risk rules after each scan
+}
+
+// defaultInterval is the time between scans when neither a flag nor the
+// config sets one.
+const defaultInterval = 6 * time.Hour
+
+// Run scans on every interval until the context is cancelled. Each pass
+// refreshes the scan cache and, with AutoClean, trashes Safe-risk results.
+// On macOS, FSEvents keeps the cache current between passes by rescanning
+// only the rules whose paths changed, and rules that grow quickly are
+// reported.
+// Changes to the config file are applied without a restart: a new interval
+// reschedules the next scan, and thresholds and exclusions apply from the
+// next pass.
+func Run(ctx context.Context, opts Options, logger *log.Logger) error {
+	cfg := loadConfig(nil, logger)
+	interval, autoClean := opts.schedule(cfg)
+	logger.Printf("watch started (interval %s, auto-clean %v)", interval, autoClean)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	watcher := newConfigWatcher(paths.ConfigFile())
+	configEvents, poll, stopConfigWatch := configChanges(watcher.path)
+	defer stopConfigWatch()
+	refresh := time.NewTicker(growthRefresh)
+	defer refresh.Stop()
+	monitor := newGrowthMonitor(logger)
+	defer monitor.close()
+
+	var settle <-chan time.Time
+	reload := func() {
+		if !watcher.changed() {
+			return
+		}
+		reloaded := loadConfig(cfg, logger)
+		if reloaded == cfg {
+			return
+		}
+		cfg = reloaded
+		next, nextAutoClean := opts.schedule(cfg)
+		if next != interval {
+			ticker.Reset(next)
+		}
+		interval, autoClean = next, nextAutoClean
+		logger.Printf("config reloaded from %s (interval %s, auto-clean %v)", watcher.path, interval, autoClean)
+	}
+
+	for {
+		// A deferred pass is retried soon instead of waiting a full interval
+		var retry <-chan time.Time
+		results, ran := runOnce(cfg, autoClean, logger)
+		if !ran {
+			retry = time.After(deferRetry)
+		}
+		if results != nil {
+			monitor.reset(results)
+		}
+
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				logger.Printf("watch stopped")
+				return nil
+			case <-ticker.C:
+				break wait
+			case <-retry:
+				break wait
+			case changed := <-monitor.events():
+				monitor.mark(changed)
+			case <-refresh.C:
+				for _, g := range monitor.refresh(cfg) {
+					logger.Print(g.Text())
+					for _, err := range notify.SendGrowth(cfg.Notifications, g) {
+						logger.Printf("notification failed: %v", err)
+					}
+				}
+			case <-configEvents:
+				if settle == nil {
+					settle = time.After(reloadSettle)
+				}
+			case <-settle:
+				settle = nil
+				reload()
+			case <-poll:
+				reload()
+			}
+		}
+	}
+}
+
+// deferRetry is how long a pass deferred for power reasons waits before
+// checking again.
+const deferRetry = 15 * time.Minute
+
+// runOnce finalizes the cleanups whose undo window closed, performs one
+// scan (and cleanup) pass, and returns the scan, dismissals included. It
+// returns false when the pass was deferred because of the battery or thermal
+// state.
+func runOnce(cfg *config.Config, autoClean bool, logger *log.Logger) (*scanner.ScanResults, bool) {
+	finalizeExpired(cfg, logger)
+	if reason := deferReason(cfg.Power); reason != "" {
+		logger.Printf("deferring scan: %s", reason)
+		return nil, false
+	}
+
+	safety.SetPolicy(safety.Policy{ProtectedPaths: cfg.ProtectedPaths, ForceAllowPaths: cfg.ForceAllowPaths})
+	if cfg.Scan.IdlePriority {
+		if err := priority.Idle(); err != nil {
+			logger.Printf("failed to lower priority: %v", err)
+		}
+	}
+
+	// The scan is cached with dismissed suggestions, like the CLI's, and they
+	// are left out only of what is reported and cleaned
+	registry := rules.NewRegistry()
+	full, err := scanner.NewScanner(registry, scanOptions(cfg).WithoutDismissals()).Scan()
+	if err != nil {
+		logger.Printf("scan failed: %v", err)
+		return nil, true
+	}
+	scanner.SaveCache(full, registry)
+	snapshot.NewManager().Save(snapshot.FromResults(full.Results))
+	usage.NewManager().RecordScan()
+	results := scanner.NewScanner(registry, scanOptions(cfg)).Filter(full)
+	logger.Printf("scan complete: %d candidates, %d bytes reclaimable", len(results.Results), results.TotalSize)
+
+	if !autoClean {
+		return full, true
+	}
+
+	var safe []rules.Result
+	for _, res := range results.Results {
+		// Force-allowed paths need interactive confirmation, so never auto-clean them
+		if res.Rule.RiskLevel == rules.RiskSafe && len(safety.OverriddenPaths(res.FoundPaths)) == 0 {
+			safe = append(safe, res)
+		}
+	}
+	if len(safe) == 0 {
+		return full, true
+	}
+
+	c := cleaner.NewCleaner()
+	c.UseSystemTrash = cfg.UseSystemTrash
+	c.TrashChecksums = cfg.TrashChecksums
+	// Nobody is there to ask, so a cleanup that would run the disk low or
+	// take the trash over its cap is skipped, unless trash_cap_action is
+	// "purge"
+	purged, err := c.PrepareUnattended(safe, false, cleaner.Unattended{
+		MinFreeSpace: cfg.MinFreeSpaceBytes(),
+		TrashCap:     int64(cfg.TrashCap),
+		PurgeOverCap: cfg.TrashCapAction == "purge",
+	})
+	if err != nil {
+		logger.Printf("scheduled cleanup skipped: %v", err)
+		return full, true
+	}
+	if purged != nil {
+		logger.Printf("purged %d old trash session(s) (%d bytes) to stay under the trash cap", len(purged.Purge), purged.Freed)
+	}
+	res, err := c.Clean(safe, false, false)
+	if err != nil {
+		logger.Printf("scheduled cleanup failed: %v", err)
+		return full, true
+	}
+	logger.Printf("scheduled cleanup reclaimed %d bytes (%d items, session %s)", res.ReclaimedSpace, res.FileCount, res.TrashSession)
+
+	for _, err := range notify.Send(cfg.Notifications, notify.NewSummary("scheduled", res.TrashSession, res.ReclaimedSpace, res.FileCount, res.CategoryStats)) {
+		logger.Printf("notification failed: %v", err)
+	}
+	return full, true
+}
+
+// finalizeExpired purges the cleanups whose undo window closed.
+func finalizeExpired(cfg *config.Config, logger *log.Logger) {
+	if cfg.UndoWindow <= 0 {
+		return
+	}
+	purged, err := cleaner.NewCleaner().FinalizeExpired(time.Duration(cfg.UndoWindow), time.Now())
+	if err != nil {
+		logger.Printf("failed to finalize expired cleanups: %v", err)
+	}
+	for _, id := range purged {
+		logger.Printf("finalized session %s: its undo window closed", id)
+	}
+}
+
+// scanOptions applies the config and dismissals to a scan. Dismissals that
+// can't be read are ignored, as there is nobody to tell.
+func scanOptions(cfg *config.Config) scanner.ScanOptions {
+	opts, _ := dismiss.ScanOptions(cfg)
+	return opts
+}
+
+// deferReason checks the power state against the config; see power.State.
+func deferReason(cfg config.PowerConfig) string {
+	state, err := power.Current()
+	if err != nil {
+		return "" // Unknown power state never blocks a scan
+	}
+	if cfg.RunOnBattery {
+		state.OnBattery = false
+	}
+	return state.DeferReason(cfg.MinBatteryPercent, cfg.IgnoreThermal)
+}
@@ -0,0 +1,159 @@
+package daemon
+
+import (
+	"errors"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/config"
+	"github.com/ismailtsdln/burrow/internal/fsevents"
+	"github.com/ismailtsdln/burrow/internal/notify"
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/scanner"
+	"github.com/ismailtsdln/burrow/internal/units"
+)
+
+// growthRefresh is how often rules with reported changes are measured again
+// between full scans.
+const growthRefresh = time.Minute
+
+// defaultGrowthAlert is the growth reported when watch.growth_alert is unset.
+const defaultGrowthAlert = units.GB
+
+// growthMonitor keeps the last scan current between full passes. File system
+// events mark the rules whose paths changed, and only those are rescanned.
+// Without events (outside macOS) it stays idle and the full passes do the work.
+type growthMonitor struct {
+	logger   *log.Logger
+	stream   *fsevents.Stream
+	results  *scanner.ScanResults
+	roots    map[string]string // Found path -> rule name
+	dirty    map[string]bool
+	baseline map[string]int64 // Rule size at the last full scan or alert
+}
+
+func newGrowthMonitor(logger *log.Logger) *growthMonitor {
+	return &growthMonitor{logger: logger}
+}
+
+// reset watches the paths found by a full scan.
+func (m *growthMonitor) reset(results *scanner.ScanResults) {
+	m.close()
+	m.results = results
+	m.roots = make(map[string]string)
+	m.dirty = make(map[string]bool)
+	m.baseline = make(map[string]int64)
+
+	var watch []string
+	for _, res := range results.Results {
+		for _, p := range res.FoundPaths {
+			m.roots[filepath.Clean(p)] = res.Rule.Name
+			watch = append(watch, p)
+		}
+		m.baseline[res.Rule.Name] = res.TotalSize
+	}
+	if len(watch) == 0 {
+		return
+	}
+
+	stream, err := fsevents.Watch(watch)
+	if err != nil {
+		if !errors.Is(err, fsevents.ErrUnsupported) {
+			m.logger.Printf("growth monitoring unavailable: %v", err)
+		}
+		return
+	}
+	m.stream = stream
+}
+
+// events delivers changed directories; it is nil, and so never ready,
+// while nothing is watched.
+func (m *growthMonitor) events() <-chan []string {
+	if m.stream == nil {
+		return nil
+	}
+	return m.stream.Events
+}
+
+// mark flags the rules with a path at, below, or above a changed directory.
+func (m *growthMonitor) mark(changed []string) {
+	sep := string(filepath.Separator)
+	for _, c := range changed {
+		c = filepath.Clean(c)
+		for root, rule := range m.roots {
+			if c == root || strings.HasPrefix(c, root+sep) || strings.HasPrefix(root, c+sep) {
+				m.dirty[rule] = true
+			}
+		}
+	}
+}
+
+// refresh rescans the marked rules, updates the scan cache, and returns the
+// rules that grew by at least the configured alert size.
+func (m *growthMonitor) refresh(cfg *config.Config) []notify.Growth {
+	if len(m.dirty) == 0 || m.results == nil {
+		return nil
+	}
+	names := make([]string, 0, len(m.dirty))
+	for name := range m.dirty {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	m.dirty = make(map[string]bool)
+
+	registry := rules.NewRegistry()
+	fresh, err := scanner.NewScanner(registry, scanOptions(cfg).WithoutDismissals()).Refresh(m.results, names)
+	if err != nil {
+		m.logger.Printf("refresh failed: %v", err)
+		return nil
+	}
+	m.results = fresh
+	if err := scanner.SaveCache(fresh, registry); err != nil {
+		m.logger.Printf("failed to save scan cache: %v", err)
+	}
+
+	threshold := int64(cfg.Watch.GrowthAlert)
+	if threshold <= 0 {
+		threshold = defaultGrowthAlert
+	}
+	// Dismissed suggestions stay cached but never raise an alert
+	view := scanner.NewScanner(registry, scanOptions(cfg)).Filter(fresh)
+	return growthAlerts(m.baseline, view.Results, names, threshold)
+}
+
+// growthAlerts compares the refreshed rules against baseline. A rule that
+// grew by threshold is reported and becomes the new baseline, as does one
+// that shrank, so growth is always measured from the low point.
+func growthAlerts(baseline map[string]int64, results []rules.Result, names []string, threshold int64) []notify.Growth {
+	refreshed := make(map[string]bool, len(names))
+	for _, n := range names {
+		refreshed[n] = true
+	}
+
+	var alerts []notify.Growth
+	for _, res := range results {
+		name := res.Rule.Name
+		if !refreshed[name] {
+			continue
+		}
+		grew := res.TotalSize - baseline[name]
+		switch {
+		case grew >= threshold:
+			alerts = append(alerts, notify.NewGrowth(name, res.Rule.Category, grew, res.TotalSize))
+			baseline[name] = res.TotalSize
+		case grew < 0:
+			baseline[name] = res.TotalSize
+		}
+	}
+	return alerts
+}
+
+func (m *growthMonitor) close() {
+	if m.stream != nil {
+		m.stream.Close()
+		m.stream = nil
+	}
+}
@@ -0,0 +1,52 @@
+package daemon
+
+import (
+	"io"
+	"log"
+	"testing"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+func TestGrowthMonitor_Mark(t *testing.T) {
+	m := newGrowthMonitor(log.New(io.Discard, "", 0))
+	m.roots = map[string]string{
+		"/Users/me/Library/Developer/Xcode/DerivedData": "Xcode DerivedData",
+		"/Users/me/.npm/_cacache":                       "npm Cache",
+		"/Users/me/.npm-global":                         "npm Global",
+	}
+	m.dirty = map[string]bool{}
+
+	m.mark([]string{
+		"/Users/me/Library/Developer/Xcode/DerivedData/App-abc/Build/", // Below a root
+		"/Users/me/.npm",   // Above a root, e.g. when it was replaced
+		"/Users/me/.npmrc", // Neither, despite the shared prefix
+	})
+	if !m.dirty["Xcode DerivedData"] || !m.dirty["npm Cache"] || m.dirty["npm Global"] || len(m.dirty) != 2 {
+		t.Errorf("dirty = %v, want Xcode DerivedData and npm Cache", m.dirty)
+	}
+}
+
+func TestGrowthAlerts(t *testing.T) {
+	result := func(name string, size int64) rules.Result {
+		return rules.Result{Rule: rules.CleanupRule{Name: name, Category: "Developer Tools"}, TotalSize: size}
+	}
+	baseline := map[string]int64{"DerivedData": 10 << 30, "npm": 5 << 30, "Gradle": 2 << 30, "Idle": 0}
+	results := []rules.Result{
+		result("DerivedData", 13<<30), // Grew 3 GB
+		result("npm", 5<<30+100),      // Grew, but not by enough
+		result("Gradle", 1<<30),       // Shrank
+		result("Idle", 4<<30),         // Grew, but was not refreshed
+	}
+
+	alerts := growthAlerts(baseline, results, []string{"DerivedData", "npm", "Gradle"}, 1<<30)
+	if len(alerts) != 1 || alerts[0].Rule != "DerivedData" || alerts[0].Grew != 3<<30 || alerts[0].Size != 13<<30 {
+		t.Fatalf("alerts = %+v, want DerivedData growing 3 GB", alerts)
+	}
+	if alerts[0].Text() == "" || alerts[0].Event != "growth" {
+		t.Errorf("alert = %+v", alerts[0])
+	}
+	if baseline["DerivedData"] != 13<<30 || baseline["npm"] != 5<<30 || baseline["Gradle"] != 1<<30 || baseline["Idle"] != 0 {
+		t.Errorf("baseline = %v", baseline)
+	}
+}
@@ -0,0 +1,94 @@
+package daemon
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/config"
+	"github.com/ismailtsdln/burrow/internal/fsevents"
+)
+
+// reloadPoll is how often a running watch checks the config file for
+// changes where the platform has no file events to tell it.
+const reloadPoll = 5 * time.Second
+
+// reloadSettle is how long a running watch waits after a file event before
+// reading the config, so an editor's save is complete.
+const reloadSettle = 500 * time.Millisecond
+
+// configChanges signals when the config file at path may have changed:
+// events receives file events for its directory where the platform has them
+// (FSEvents on macOS, inotify on Linux), and poll ticks every reloadPoll
+// otherwise. Exactly one is non-nil; stop releases it.
+func configChanges(path string) (events <-chan []string, poll <-chan time.Time, stop func()) {
+	if stream, err := fsevents.WatchDir(filepath.Dir(path)); err == nil {
+		return stream.Events, nil, stream.Close
+	}
+	// The directory may not exist yet, or events are unsupported
+	ticker := time.NewTicker(reloadPoll)
+	return nil, ticker.C, ticker.Stop
+}
+
+// configWatcher notices when the config file is written, created, or
+// removed.
+type configWatcher struct {
+	path    string
+	modTime time.Time
+	size    int64
+	exists  bool
+}
+
+func newConfigWatcher(path string) *configWatcher {
+	w := &configWatcher{path: path}
+	w.changed()
+	return w
+}
+
+// changed reports whether the file differs from the last call.
+func (w *configWatcher) changed() bool {
+	info, err := os.Stat(w.path)
+	exists := err == nil
+	var modTime time.Time
+	var size int64
+	if exists {
+		modTime, size = info.ModTime(), info.Size()
+	}
+	if exists == w.exists && modTime.Equal(w.modTime) && size == w.size {
+		return false
+	}
+	w.exists, w.modTime, w.size = exists, modTime, size
+	return true
+}
+
+// loadConfig loads the config, falling back to prev (or the defaults) when
+// the file can't be parsed, e.g. while it is half-written.
+func loadConfig(prev *config.Config, logger *log.Logger) *config.Config {
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Printf("failed to load config: %v", err)
+	}
+	switch {
+	case cfg != nil:
+		return cfg
+	case prev != nil:
+		logger.Printf("keeping the previous settings")
+		return prev
+	default:
+		return &config.Config{}
+	}
+}
+
+// schedule resolves the interval and auto-clean setting; flags win over the
+// config file.
+func (o Options) schedule(cfg *config.Config) (time.Duration, bool) {
+	interval := o.Interval
+	if interval <= 0 {
+		interval = time.Duration(cfg.Watch.Interval)
+	}
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	return interval, o.AutoClean || cfg.Watch.AutoClean
+}
@@ -0,0 +1,68 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/config"
+	"github.com/ismailtsdln/burrow/internal/units"
+)
+
+func TestConfigWatcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	w := newConfigWatcher(path)
+	if w.changed() {
+		t.Fatal("missing file reported as changed")
+	}
+
+	if err := os.WriteFile(path, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !w.changed() {
+		t.Error("created file not reported")
+	}
+	if w.changed() {
+		t.Error("unchanged file reported twice")
+	}
+
+	// Same size, later modification time
+	if err := os.WriteFile(path, []byte(`[]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if !w.changed() {
+		t.Error("rewritten file not reported")
+	}
+
+	os.Remove(path)
+	if !w.changed() {
+		t.Error("removed file not reported")
+	}
+}
+
+func TestOptionsSchedule(t *testing.T) {
+	fromConfig := &config.Config{Watch: config.WatchConfig{Interval: units.Duration(12 * time.Hour), AutoClean: true}}
+	tests := []struct {
+		name          string
+		opts          Options
+		cfg           *config.Config
+		wantInterval  time.Duration
+		wantAutoClean bool
+	}{
+		{"defaults", Options{}, &config.Config{}, defaultInterval, false},
+		{"config", Options{}, fromConfig, 12 * time.Hour, true},
+		{"flags win", Options{Interval: time.Hour}, fromConfig, time.Hour, true},
+		{"flag auto-clean", Options{AutoClean: true}, &config.Config{}, defaultInterval, true},
+	}
+	for _, tt := range tests {
+		interval, autoClean := tt.opts.schedule(tt.cfg)
+		if interval != tt.wantInterval || autoClean != tt.wantAutoClean {
+			t.Errorf("%s: schedule() = %s, %v; want %s, %v", tt.name, interval, autoClean, tt.wantInterval, tt.wantAutoClean)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package disk
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Usage describes the capacity of the volume containing a path.
+type Usage struct {
+	Total int64 `json:"total"`
+	Free  int64 `json:"free"` // Available to the current user
+	Used  int64 `json:"used"`
+}
+
+// UsageOf returns the usage of the volume holding path. Missing paths are
+// resolved to their nearest existing parent so callers can query locations
+// that have not been created yet (like a fresh trash directory).
+func UsageOf(path string) (Usage, error) {
+	return usage(existingParent(path))
+}
+
+// SameVolume reports whether two paths live on the same volume.
+func SameVolume(a, b string) bool {
+	return sameVolume(existingParent(a), existingParent(b))
+}
+
+func existingParent(path string) string {
+	p := filepath.Clean(path)
+	for {
+		if _, err := os.Lstat(p); err == nil {
+			return p
+		}
+		parent := filepath.Dir(p)
+		if parent == p {
+			return p
+		}
+		p = parent
+	}
+}
@@ -0,0 +1,35 @@
+package disk
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUsageOf_MissingPath(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "burrow-disk-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	u, err := UsageOf(filepath.Join(tempDir, "does", "not", "exist"))
+	if err != nil {
+		t.Fatalf("UsageOf failed: %v", err)
+	}
+	if u.Total <= 0 || u.Free < 0 || u.Free > u.Total {
+		t.Errorf("implausible usage: %+v", u)
+	}
+}
+
+func TestSameVolume(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "burrow-disk-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if !SameVolume(tempDir, filepath.Join(tempDir, "child")) {
+		t.Errorf("a directory and its child should share a volume")
+	}
+}
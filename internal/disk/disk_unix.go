@@ -0,0 +1,36 @@
+//go:build !windows
+
+package disk
+
+import (
+	"os"
+	"syscall"
+)
+
+func usage(path string) (Usage, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return Usage{}, err
+	}
+	bsize := int64(st.Bsize)
+	total := int64(st.Blocks) * bsize
+	return Usage{
+		Total: total,
+		Free:  int64(st.Bavail) * bsize,
+		Used:  total - int64(st.Bfree)*bsize,
+	}, nil
+}
+
+func sameVolume(a, b string) bool {
+	ia, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	ib, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	sa, okA := ia.Sys().(*syscall.Stat_t)
+	sb, okB := ib.Sys().(*syscall.Stat_t)
+	return okA && okB && sa.Dev == sb.Dev
+}
@@ -0,0 +1,31 @@
+package disk
+
+// Volume describes the filesystem a path lives on.
+type Volume struct {
+	MountPoint string `json:"mount_point"`
+	FSType     string `json:"fs_type,omitempty"`
+	External   bool   `json:"external"` // Removable or secondary local drive
+	Network    bool   `json:"network"`  // NFS, SMB, AFP, WebDAV, ...
+}
+
+// Removable reports whether the volume is anything other than a local internal disk.
+func (v Volume) Removable() bool {
+	return v.External || v.Network
+}
+
+// VolumeOf returns volume information for path (or its nearest existing parent).
+func VolumeOf(path string) (Volume, error) {
+	return volumeOf(existingParent(path))
+}
+
+func cString(b []int8) string {
+	n := 0
+	for n < len(b) && b[n] != 0 {
+		n++
+	}
+	out := make([]byte, n)
+	for i := 0; i < n; i++ {
+		out[i] = byte(b[i])
+	}
+	return string(out)
+}
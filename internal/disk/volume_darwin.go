@@ -0,0 +1,30 @@
+//go:build darwin
+
+package disk
+
+import (
+	"strings"
+	"syscall"
+)
+
+const mntLocal = 0x1000 // MNT_LOCAL from <sys/mount.h>
+
+var networkFSTypes = map[string]bool{
+	"smbfs": true, "nfs": true, "afpfs": true, "webdav": true, "cifs": true, "ftp": true,
+}
+
+func volumeOf(path string) (Volume, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return Volume{}, err
+	}
+
+	v := Volume{
+		MountPoint: cString(st.Mntonname[:]),
+		FSType:     cString(st.Fstypename[:]),
+	}
+	v.Network = st.Flags&mntLocal == 0 || networkFSTypes[v.FSType]
+	// Everything mounted under /Volumes is a secondary disk, except the data volume of the boot disk
+	v.External = !v.Network && strings.HasPrefix(v.MountPoint, "/Volumes/")
+	return v, nil
+}
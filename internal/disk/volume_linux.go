@@ -0,0 +1,65 @@
+//go:build linux
+
+package disk
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// Filesystem magic numbers from statfs(2) for network filesystems.
+var networkMagic = map[int64]string{
+	0x6969:     "nfs",
+	0x517B:     "smb",
+	0xFF534D42: "cifs",
+	0xFE534D42: "smb2",
+	0x564C:     "ncp",
+	0x65735546: "fuse",
+}
+
+func volumeOf(path string) (Volume, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return Volume{}, err
+	}
+
+	v := Volume{MountPoint: mountPoint(path)}
+	if fs, ok := networkMagic[int64(st.Type)]; ok {
+		v.FSType = fs
+		v.Network = fs != "fuse"
+	}
+	for _, prefix := range []string{"/media/", "/mnt/", "/run/media/"} {
+		if strings.HasPrefix(v.MountPoint+"/", prefix) {
+			v.External = true
+		}
+	}
+	return v, nil
+}
+
+// mountPoint walks up from path until the device number changes.
+func mountPoint(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return abs
+	}
+	dev := info.Sys().(*syscall.Stat_t).Dev
+
+	curr := abs
+	for {
+		parent := filepath.Dir(curr)
+		if parent == curr {
+			return curr
+		}
+		pinfo, err := os.Stat(parent)
+		if err != nil || pinfo.Sys().(*syscall.Stat_t).Dev != dev {
+			return curr
+		}
+		curr = parent
+	}
+}
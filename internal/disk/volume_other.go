@@ -0,0 +1,7 @@
+//go:build !darwin && !linux && !windows
+
+package disk
+
+func volumeOf(path string) (Volume, error) {
+	return Volume{MountPoint: "/"}, nil
+}
@@ -0,0 +1,32 @@
+//go:build windows
+
+package disk
+
+import (
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	driveRemovable = 2
+	driveRemote    = 4
+	driveCDROM     = 5
+)
+
+var procGetDriveTypeW = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDriveTypeW")
+
+func volumeOf(path string) (Volume, error) {
+	root := filepath.VolumeName(path) + `\`
+	p, err := syscall.UTF16PtrFromString(root)
+	if err != nil {
+		return Volume{}, err
+	}
+	driveType, _, _ := procGetDriveTypeW.Call(uintptr(unsafe.Pointer(p)))
+
+	return Volume{
+		MountPoint: root,
+		External:   driveType == driveRemovable || driveType == driveCDROM,
+		Network:    driveType == driveRemote || len(root) > 2 && root[:2] == `\\`,
+	}, nil
+}
@@ -0,0 +1,153 @@
+// Package dismiss stores suggestions the user asked Burrow to stop making,
+// either for good ("never suggest") or until a deadline.
+package dismiss
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/config"
+	"github.com/ismailtsdln/burrow/internal/paths"
+	"github.com/ismailtsdln/burrow/internal/safety"
+	"github.com/ismailtsdln/burrow/internal/scanner"
+)
+
+// Dismissal suppresses a whole rule or a path and everything below it.
+type Dismissal struct {
+	Rule    string    `json:"rule,omitempty"`
+	Path    string    `json:"path,omitempty"`
+	Until   time.Time `json:"until,omitzero"` // Zero means forever
+	Created time.Time `json:"created"`
+}
+
+// Target returns the rule name or path the dismissal applies to.
+func (d Dismissal) Target() string {
+	if d.Rule != "" {
+		return d.Rule
+	}
+	return d.Path
+}
+
+// Matches reports whether target names the dismissal's rule
+// (case-insensitively) or its path.
+func (d Dismissal) Matches(target string) bool {
+	if d.Rule != "" {
+		return strings.EqualFold(d.Rule, target)
+	}
+	return filepath.Clean(safety.ExpandPath(d.Path)) == filepath.Clean(safety.ExpandPath(target))
+}
+
+// Active reports whether the dismissal still applies at now.
+func (d Dismissal) Active(now time.Time) bool {
+	return d.Until.IsZero() || now.Before(d.Until)
+}
+
+// Manager persists dismissals in the data directory.
+type Manager struct {
+	path string
+}
+
+// NewManager creates a manager for the default dismissals file.
+func NewManager() *Manager {
+	return &Manager{path: paths.DismissalsFile()}
+}
+
+// Load returns every stored dismissal, including expired ones.
+func (m *Manager) Load() ([]Dismissal, error) {
+	data, err := os.ReadFile(m.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Dismissal
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", m.path, err)
+	}
+	return list, nil
+}
+
+// Add stores d, replacing an earlier dismissal of the same target. Expired
+// entries are dropped on the way.
+func (m *Manager) Add(d Dismissal) error {
+	list, err := m.Load()
+	if err != nil {
+		return err
+	}
+	if d.Created.IsZero() {
+		d.Created = time.Now()
+	}
+
+	kept := list[:0]
+	for _, e := range list {
+		if e.Active(d.Created) && (e.Rule != d.Rule || e.Path != d.Path) {
+			kept = append(kept, e)
+		}
+	}
+	return m.save(append(kept, d))
+}
+
+// Remove deletes the dismissals of target, a rule name (case-insensitive)
+// or a path, and returns how many were removed. An empty target removes all.
+func (m *Manager) Remove(target string) (int, error) {
+	list, err := m.Load()
+	if err != nil {
+		return 0, err
+	}
+
+	kept := list[:0]
+	for _, d := range list {
+		if target != "" && !d.Matches(target) {
+			kept = append(kept, d)
+		}
+	}
+	removed := len(list) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, m.save(kept)
+}
+
+func (m *Manager) save(list []Dismissal) error {
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(m.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(m.path, data, 0644)
+}
+
+// Apply adds the active dismissals to opts, so the scan leaves those rules
+// and paths out.
+func Apply(opts *scanner.ScanOptions, list []Dismissal) {
+	now := time.Now()
+	for _, d := range list {
+		if !d.Active(now) {
+			continue
+		}
+		if d.Rule != "" {
+			opts.DismissedRules = append(opts.DismissedRules, d.Rule)
+		}
+		if d.Path != "" {
+			opts.DismissedPaths = append(opts.DismissedPaths, d.Path)
+		}
+	}
+}
+
+// ScanOptions returns the scan options implied by cfg and the active
+// dismissals. When the dismissals can't be read, the options are still
+// usable and the error says why none were applied.
+func ScanOptions(cfg *config.Config) (scanner.ScanOptions, error) {
+	opts := scanner.OptionsFromConfig(cfg)
+	list, err := NewManager().Load()
+	Apply(&opts, list)
+	return opts, err
+}
@@ -0,0 +1,113 @@
+package dismiss
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/config"
+	"github.com/ismailtsdln/burrow/internal/paths"
+	"github.com/ismailtsdln/burrow/internal/scanner"
+)
+
+func TestManagerAdd(t *testing.T) {
+	m := &Manager{path: filepath.Join(t.TempDir(), "dismissals.json")}
+	now := time.Now()
+
+	adds := []Dismissal{
+		{Rule: "npm Cache", Until: now.Add(time.Hour)},
+		{Path: "/tmp/old.dmg", Until: now.Add(-time.Hour)}, // Already expired
+		{Rule: "pip Cache"},
+		{Rule: "npm Cache"}, // Replaces the timed dismissal
+	}
+	for _, d := range adds {
+		if err := m.Add(d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	list, err := m.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var targets []string
+	for _, d := range list {
+		targets = append(targets, d.Target())
+		if d.Rule == "npm Cache" && !d.Until.IsZero() {
+			t.Errorf("npm Cache should now be dismissed forever, got until %v", d.Until)
+		}
+	}
+	if want := []string{"pip Cache", "npm Cache"}; !reflect.DeepEqual(targets, want) {
+		t.Errorf("targets = %v, want %v", targets, want)
+	}
+}
+
+func TestApply(t *testing.T) {
+	now := time.Now()
+	var opts scanner.ScanOptions
+	Apply(&opts, []Dismissal{
+		{Rule: "npm Cache"},
+		{Path: "~/Downloads/big.iso", Until: now.Add(time.Hour)},
+		{Rule: "pip Cache", Until: now.Add(-time.Minute)},
+	})
+	if !reflect.DeepEqual(opts.DismissedRules, []string{"npm Cache"}) {
+		t.Errorf("DismissedRules = %v", opts.DismissedRules)
+	}
+	if !reflect.DeepEqual(opts.DismissedPaths, []string{"~/Downloads/big.iso"}) {
+		t.Errorf("DismissedPaths = %v", opts.DismissedPaths)
+	}
+}
+
+func TestScanOptions(t *testing.T) {
+	t.Setenv(paths.DataEnv, t.TempDir())
+	if err := NewManager().Add(Dismissal{Rule: "npm Cache"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{ExcludedPaths: []string{"~/keep"}, DisabledCategories: []string{"Logs"}}
+	opts, err := ScanOptions(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(opts.ExcludedPaths, cfg.ExcludedPaths) || !reflect.DeepEqual(opts.DisabledCategories, cfg.DisabledCategories) {
+		t.Errorf("config not applied: %+v", opts)
+	}
+	if !reflect.DeepEqual(opts.DismissedRules, []string{"npm Cache"}) {
+		t.Errorf("DismissedRules = %v", opts.DismissedRules)
+	}
+}
+
+func TestManagerRemove(t *testing.T) {
+	m := &Manager{path: filepath.Join(t.TempDir(), "dismissals.json")}
+	for _, d := range []Dismissal{{Rule: "npm Cache"}, {Path: "/tmp/a"}, {Path: "/tmp/b"}} {
+		if err := m.Add(d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n, err := m.Remove("NPM cache"); err != nil || n != 1 {
+		t.Errorf("Remove(rule) = %d, %v; want 1", n, err)
+	}
+	if n, err := m.Remove("/tmp/a/"); err != nil || n != 1 {
+		t.Errorf("Remove(path) = %d, %v; want 1", n, err)
+	}
+	if n, err := m.Remove(""); err != nil || n != 1 {
+		t.Errorf("Remove(all) = %d, %v; want 1", n, err)
+	}
+	if list, _ := m.Load(); len(list) != 0 {
+		t.Errorf("expected no dismissals left, got %+v", list)
+	}
+}
+
+func TestDismissalForeverOmitsUntil(t *testing.T) {
+	data, err := json.Marshal(Dismissal{Rule: "npm Cache", Created: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "until") {
+		t.Errorf("a dismissal without an end wrote %s", data)
+	}
+}
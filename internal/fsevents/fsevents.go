@@ -0,0 +1,48 @@
+// Package fsevents reports changes below a set of directories as they
+// happen, using FSEvents on macOS. Changes in a single directory can also be
+// watched on Linux, using inotify.
+package fsevents
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrUnsupported is returned by Watch where no change notification API is
+// available; callers fall back to rescanning.
+var ErrUnsupported = errors.New("file system events are not supported on this platform")
+
+// Latency is how long FSEvents coalesces changes before delivering them.
+const Latency = 2 * time.Second
+
+// Stream delivers the directories that changed below the watched paths.
+type Stream struct {
+	// Events receives batches of changed directories. Batches are dropped
+	// while the receiver falls behind, so a stream is a hint to rescan, not
+	// a complete log.
+	Events <-chan []string
+
+	events chan []string
+	stop   func()
+}
+
+func newStream() *Stream {
+	events := make(chan []string, 64)
+	return &Stream{Events: events, events: events}
+}
+
+// deliver hands a batch to the receiver without blocking the event thread.
+func (s *Stream) deliver(paths []string) {
+	select {
+	case s.events <- paths:
+	default:
+	}
+}
+
+// Close stops the stream. Events is not closed.
+func (s *Stream) Close() {
+	if s.stop != nil {
+		s.stop()
+		s.stop = nil
+	}
+}
@@ -0,0 +1,112 @@
+//go:build darwin && cgo
+
+package fsevents
+
+/*
+#cgo LDFLAGS: -framework CoreServices
+#include <CoreServices/CoreServices.h>
+#include <dispatch/dispatch.h>
+#include <stdint.h>
+#include <stdlib.h>
+
+extern void fseventsCallback(uintptr_t handle, size_t count, char **paths);
+
+static void streamCallback(ConstFSEventStreamRef stream, void *info, size_t count, void *paths,
+                           const FSEventStreamEventFlags flags[], const FSEventStreamEventId ids[]) {
+    fseventsCallback((uintptr_t)info, count, (char **)paths);
+}
+
+static void noop(void *ctx) {}
+
+static FSEventStreamRef startStream(uintptr_t handle, char **paths, int count, double latency, dispatch_queue_t queue) {
+    CFMutableArrayRef roots = CFArrayCreateMutable(NULL, count, &kCFTypeArrayCallBacks);
+    for (int i = 0; i < count; i++) {
+        CFStringRef s = CFStringCreateWithCString(NULL, paths[i], kCFStringEncodingUTF8);
+        if (s) {
+            CFArrayAppendValue(roots, s);
+            CFRelease(s);
+        }
+    }
+
+    FSEventStreamContext ctx = {0, (void *)handle, NULL, NULL, NULL};
+    FSEventStreamRef stream = FSEventStreamCreate(NULL, streamCallback, &ctx, roots,
+        kFSEventStreamEventIdSinceNow, latency, kFSEventStreamCreateFlagNoDefer);
+    CFRelease(roots);
+    if (!stream) {
+        return NULL;
+    }
+
+    FSEventStreamSetDispatchQueue(stream, queue);
+    if (!FSEventStreamStart(stream)) {
+        FSEventStreamInvalidate(stream);
+        FSEventStreamRelease(stream);
+        return NULL;
+    }
+    return stream;
+}
+
+static dispatch_queue_t newQueue(void) {
+    return dispatch_queue_create("burrow.fsevents", DISPATCH_QUEUE_SERIAL);
+}
+
+static void releaseQueue(dispatch_queue_t queue) {
+    dispatch_release(queue);
+}
+
+// stopStream returns once no callback is running or queued.
+static void stopStream(FSEventStreamRef stream, dispatch_queue_t queue) {
+    FSEventStreamStop(stream);
+    FSEventStreamInvalidate(stream);
+    FSEventStreamRelease(stream);
+    dispatch_sync_f(queue, NULL, noop);
+    releaseQueue(queue);
+}
+*/
+import "C"
+import (
+	"errors"
+	"runtime/cgo"
+	"unsafe"
+)
+
+//export fseventsCallback
+func fseventsCallback(handle C.uintptr_t, count C.size_t, paths **C.char) {
+	raw := unsafe.Slice(paths, int(count))
+	batch := make([]string, len(raw))
+	for i, p := range raw {
+		batch[i] = C.GoString(p)
+	}
+	cgo.Handle(handle).Value().(*Stream).deliver(batch)
+}
+
+// Watch starts an FSEvents stream over paths.
+func Watch(paths []string) (*Stream, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("no paths to watch")
+	}
+
+	cpaths := make([]*C.char, len(paths))
+	for i, p := range paths {
+		cpaths[i] = C.CString(p)
+	}
+	defer func() {
+		for _, p := range cpaths {
+			C.free(unsafe.Pointer(p))
+		}
+	}()
+
+	s := newStream()
+	h := cgo.NewHandle(s)
+	queue := C.newQueue()
+	ref := C.startStream(C.uintptr_t(h), &cpaths[0], C.int(len(paths)), C.double(Latency.Seconds()), queue)
+	if ref == nil {
+		C.releaseQueue(queue)
+		h.Delete()
+		return nil, errors.New("failed to start FSEvents stream")
+	}
+	s.stop = func() {
+		C.stopStream(ref, queue)
+		h.Delete()
+	}
+	return s, nil
+}
@@ -0,0 +1,8 @@
+//go:build !darwin || !cgo
+
+package fsevents
+
+// Watch returns ErrUnsupported.
+func Watch(paths []string) (*Stream, error) {
+	return nil, ErrUnsupported
+}
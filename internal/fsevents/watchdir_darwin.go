@@ -0,0 +1,9 @@
+//go:build darwin && cgo
+
+package fsevents
+
+// WatchDir starts an FSEvents stream over dir. Unlike inotify, FSEvents
+// also reports changes in its subdirectories.
+func WatchDir(dir string) (*Stream, error) {
+	return Watch([]string{dir})
+}
@@ -0,0 +1,44 @@
+//go:build linux
+
+package fsevents
+
+import (
+	"os"
+	"syscall"
+)
+
+// dirEvents are the inotify events that add, replace, or change an entry.
+const dirEvents = syscall.IN_CREATE | syscall.IN_CLOSE_WRITE | syscall.IN_MODIFY | syscall.IN_ATTRIB |
+	syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO | syscall.IN_DELETE
+
+// WatchDir starts an inotify watch on dir. Each batch of events names dir
+// itself; the entries that changed are not reported.
+func WatchDir(dir string) (*Stream, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+	if _, err := syscall.InotifyAddWatch(fd, dir, dirEvents); err != nil {
+		syscall.Close(fd)
+		return nil, &os.PathError{Op: "inotify_add_watch", Path: dir, Err: err}
+	}
+
+	// A non-blocking descriptor goes through the runtime poller, so Close
+	// wakes the pending Read
+	f := os.NewFile(uintptr(fd), "inotify")
+	s := newStream()
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			n, err := f.Read(buf)
+			if err != nil {
+				return
+			}
+			if n > 0 {
+				s.deliver([]string{dir})
+			}
+		}
+	}()
+	s.stop = func() { f.Close() }
+	return s, nil
+}
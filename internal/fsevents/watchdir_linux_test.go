@@ -0,0 +1,41 @@
+package fsevents
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchDir(t *testing.T) {
+	dir := t.TempDir()
+	s, err := WatchDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// Saved the way editors do: written aside, then renamed over
+	tmp := filepath.Join(dir, "config.json.tmp")
+	if err := os.WriteFile(tmp, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, "config.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case batch := <-s.Events:
+		if len(batch) != 1 || batch[0] != dir {
+			t.Errorf("batch = %v, want [%s]", batch, dir)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event for a file saved in the watched directory")
+	}
+}
+
+func TestWatchDirMissing(t *testing.T) {
+	if _, err := WatchDir(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("watching a missing directory succeeded")
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !linux && (!darwin || !cgo)
+
+package fsevents
+
+// WatchDir returns ErrUnsupported.
+func WatchDir(dir string) (*Stream, error) {
+	return nil, ErrUnsupported
+}
@@ -2,10 +2,14 @@ package history
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"time"
+
+	"github.com/ismailtsdln/burrow/internal/paths"
 )
 
 // Entry represents a single cleanup session record.
@@ -15,6 +19,14 @@ type Entry struct {
 	ReclaimedBytes int64            `json:"reclaimed_bytes"`
 	FileCount      int              `json:"file_count"`
 	CategoryStats  map[string]int64 `json:"category_stats"`
+	Rules          []string         `json:"rules,omitempty"`
+	Paths          []string         `json:"paths,omitempty"`
+	// Restored counts the items that undo has put back since.
+	Restored int `json:"restored,omitempty"`
+	// Note is the user's description of the cleanup, from 'clean --note'.
+	Note string `json:"note,omitempty"`
+	// Finalized is when the undo window closed and the session was purged.
+	Finalized time.Time `json:"finalized,omitzero"`
 }
 
 // Manager handles history operations.
@@ -24,9 +36,8 @@ type Manager struct {
 
 // NewManager creates a new history manager.
 func NewManager() *Manager {
-	home, _ := os.UserHomeDir()
 	return &Manager{
-		historyPath: filepath.Join(home, ".burrow", "history.json"),
+		historyPath: paths.HistoryFile(),
 	}
 }
 
@@ -39,7 +50,86 @@ func (m *Manager) Save(entry Entry) error {
 	if len(entries) > 50 {
 		entries = entries[len(entries)-50:]
 	}
+	return m.write(entries)
+}
+
+// Find returns the entry with the given session ID. Older versions recorded
+// every permanent or system-trash cleanup under the same marker, and such an
+// ID names no single entry.
+func (m *Manager) Find(id string) (*Entry, error) {
+	entries, err := m.Load()
+	if err != nil {
+		return nil, err
+	}
+	var found *Entry
+	for i := range entries {
+		if entries[i].ID != id {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("several cleanups share the ID %s, which older versions used for every cleanup that bypassed the trash; 'burrow history' lists them by date", id)
+		}
+		found = &entries[i]
+	}
+	if found == nil {
+		return nil, fmt.Errorf("no cleanup session %s in history (see 'burrow history')", id)
+	}
+	return found, nil
+}
+
+// MarkRestored records that undo restored n items of session id. Sessions
+// without a history entry are ignored.
+func (m *Manager) MarkRestored(id string, n int) error {
+	if n == 0 {
+		return nil
+	}
+	entries, err := m.Load()
+	if err != nil {
+		return err
+	}
+	for i := range entries {
+		if entries[i].ID == id {
+			entries[i].Restored += n
+			return m.write(entries)
+		}
+	}
+	return nil
+}
+
+// MarkFinalized records that the sessions in ids were purged at the given
+// time because their undo window closed.
+func (m *Manager) MarkFinalized(ids []string, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	entries, err := m.Load()
+	if err != nil {
+		return err
+	}
+	for i := range entries {
+		if slices.Contains(ids, entries[i].ID) {
+			entries[i].Finalized = at
+		}
+	}
+	return m.write(entries)
+}
+
+// Remove deletes every entry with the given session ID.
+func (m *Manager) Remove(id string) error {
+	entries, err := m.Load()
+	if err != nil {
+		return err
+	}
+	kept := entries[:0]
+	for _, e := range entries {
+		if e.ID != id {
+			kept = append(kept, e)
+		}
+	}
+	return m.write(kept)
+}
 
+func (m *Manager) write(entries []Entry) error {
 	data, err := json.MarshalIndent(entries, "", "  ")
 	if err != nil {
 		return err
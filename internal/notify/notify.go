@@ -0,0 +1,146 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/config"
+	"github.com/ismailtsdln/burrow/internal/units"
+)
+
+// Summary describes a finished cleanup for notification purposes.
+type Summary struct {
+	Machine        string           `json:"machine"`
+	Session        string           `json:"session"`
+	Trigger        string           `json:"trigger"`
+	Timestamp      time.Time        `json:"timestamp"`
+	ReclaimedBytes int64            `json:"reclaimed_bytes"`
+	FileCount      int              `json:"file_count"`
+	CategoryStats  map[string]int64 `json:"category_stats"`
+}
+
+var client = &http.Client{Timeout: 10 * time.Second}
+
+// NewSummary builds a summary stamped with the local machine name and current time.
+func NewSummary(trigger, session string, reclaimed int64, files int, categories map[string]int64) Summary {
+	machine, _ := os.Hostname()
+	return Summary{
+		Machine:        machine,
+		Session:        session,
+		Trigger:        trigger,
+		Timestamp:      time.Now(),
+		ReclaimedBytes: reclaimed,
+		FileCount:      files,
+		CategoryStats:  categories,
+	}
+}
+
+// Send posts the summary to every configured webhook and returns the errors
+// for hooks that failed. A failing hook never prevents the others from firing.
+func Send(hooks []config.Webhook, summary Summary) []error {
+	return sendAll(hooks, Text(summary), summary)
+}
+
+// Growth describes a rule whose data grew quickly while 'burrow watch' was
+// running.
+type Growth struct {
+	Event     string    `json:"event"` // Always "growth"
+	Machine   string    `json:"machine"`
+	Rule      string    `json:"rule"`
+	Category  string    `json:"category"`
+	Timestamp time.Time `json:"timestamp"`
+	Grew      int64     `json:"grew_bytes"`
+	Size      int64     `json:"size_bytes"`
+}
+
+// NewGrowth builds a growth alert stamped with the local machine name and
+// current time.
+func NewGrowth(rule, category string, grew, size int64) Growth {
+	machine, _ := os.Hostname()
+	return Growth{Event: "growth", Machine: machine, Rule: rule, Category: category, Timestamp: time.Now(), Grew: grew, Size: size}
+}
+
+// Text renders the alert for chat webhooks and logs.
+func (g Growth) Text() string {
+	return fmt.Sprintf("%s just grew %s on %s (now %s).", g.Rule, units.FormatSize(g.Grew), g.Machine, units.FormatSize(g.Size))
+}
+
+// SendGrowth posts a growth alert to every configured webhook, like Send.
+func SendGrowth(hooks []config.Webhook, g Growth) []error {
+	return sendAll(hooks, g.Text(), g)
+}
+
+// sendAll posts text to chat webhooks and payload to generic ones.
+func sendAll(hooks []config.Webhook, text string, payload interface{}) []error {
+	var errs []error
+	for _, hook := range hooks {
+		if err := post(hook, text, payload); err != nil {
+			errs = append(errs, fmt.Errorf("%s webhook: %w", hookType(hook), err))
+		}
+	}
+	return errs
+}
+
+func post(hook config.Webhook, text string, payload interface{}) error {
+	switch hookType(hook) {
+	case "slack":
+		payload = map[string]string{"text": text}
+	case "discord":
+		payload = map[string]string{"content": text}
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Post(hook.URL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// Text renders a human-readable one-message summary for chat webhooks.
+func Text(s Summary) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Burrow %s cleanup on %s reclaimed %s (%d items).", s.Trigger, s.Machine, units.FormatSize(s.ReclaimedBytes), s.FileCount)
+
+	cats := make([]string, 0, len(s.CategoryStats))
+	for cat := range s.CategoryStats {
+		cats = append(cats, cat)
+	}
+	sort.Slice(cats, func(i, j int) bool {
+		return s.CategoryStats[cats[i]] > s.CategoryStats[cats[j]]
+	})
+	for _, cat := range cats {
+		fmt.Fprintf(&b, "\n• %s: %s", cat, units.FormatSize(s.CategoryStats[cat]))
+	}
+	return b.String()
+}
+
+// hookType returns the webhook flavour, inferring it from the URL when unset.
+func hookType(hook config.Webhook) string {
+	if hook.Type != "" {
+		return strings.ToLower(hook.Type)
+	}
+	switch {
+	case strings.Contains(hook.URL, "hooks.slack.com"):
+		return "slack"
+	case strings.Contains(hook.URL, "discord.com/api/webhooks"):
+		return "discord"
+	default:
+		return "generic"
+	}
+}
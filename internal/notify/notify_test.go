@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ismailtsdln/burrow/internal/config"
+)
+
+func TestSend_Payloads(t *testing.T) {
+	var bodies []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+	}))
+	defer srv.Close()
+
+	summary := NewSummary("manual", "20240101_120000", 2048, 3, map[string]int64{"Package Managers": 2048})
+	errs := Send([]config.Webhook{
+		{URL: srv.URL, Type: "slack"},
+		{URL: srv.URL, Type: "discord"},
+		{URL: srv.URL},
+	}, summary)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	if len(bodies) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(bodies))
+	}
+	if text, _ := bodies[0]["text"].(string); !strings.Contains(text, "2.00 KB") {
+		t.Errorf("slack payload missing size: %v", bodies[0])
+	}
+	if _, ok := bodies[1]["content"]; !ok {
+		t.Errorf("discord payload missing content: %v", bodies[1])
+	}
+	if bodies[2]["reclaimed_bytes"] != float64(2048) {
+		t.Errorf("generic payload mismatch: %v", bodies[2])
+	}
+}
+
+func TestSend_ReportsFailures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	errs := Send([]config.Webhook{{URL: srv.URL}}, NewSummary("manual", "x", 0, 0, nil))
+	if len(errs) != 1 {
+		t.Errorf("expected 1 error, got %v", errs)
+	}
+}
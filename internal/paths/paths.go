@@ -0,0 +1,260 @@
+// Package paths resolves every file and directory Burrow reads or writes, so
+// that overrides such as BURROW_CONFIG apply consistently.
+package paths
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// ConfigEnv names the environment variable pointing at an alternate config
+// file or directory. The global --config flag sets it as well.
+const ConfigEnv = "BURROW_CONFIG"
+
+// DataEnv names the environment variable that relocates the data directory.
+// It takes precedence over data_dir in the config file.
+const DataEnv = "BURROW_DATA_DIR"
+
+// configuredDataDir is the data_dir config value, installed by SetDataDir.
+var configuredDataDir string
+
+// SetDataDir relocates the data directory unless BURROW_DATA_DIR is set.
+func SetDataDir(dir string) {
+	configuredDataDir = dir
+}
+
+// home returns the home directory of the user running Burrow. Under sudo that
+// is the invoking user, so an elevated cleanup keeps its trash, history, and
+// audit log where a later 'burrow undo' looks for them.
+func home() string {
+	if h := sudoHome(); h != "" {
+		return h
+	}
+	h, _ := os.UserHomeDir()
+	return h
+}
+
+// sudoHome returns SUDO_USER's home directory when running as root through
+// sudo, or "" otherwise.
+func sudoHome() string {
+	name := os.Getenv("SUDO_USER")
+	if os.Geteuid() != 0 || name == "" || name == "root" {
+		return ""
+	}
+	u, err := user.Lookup(name)
+	if err != nil {
+		return ""
+	}
+	return u.HomeDir
+}
+
+// ConfigDir returns the directory holding config.json, custom_rules.json, and
+// rules.d: the directory named by BURROW_CONFIG (the parent directory when it
+// names a .json file), or else the platform config directory. On macOS an
+// existing ~/.config/burrow keeps being used until the platform directory
+// holds configuration of its own.
+func ConfigDir() string {
+	override, isFile := configOverride()
+	switch {
+	case override == "":
+		def := DefaultConfigDir()
+		if xdg := xdgConfigDir(); xdg != def && !hasConfig(def) && hasConfig(xdg) {
+			return xdg
+		}
+		return def
+	case isFile:
+		return filepath.Dir(override)
+	default:
+		return override
+	}
+}
+
+// DefaultConfigDir returns the platform config directory: ~/Library/Application
+// Support/burrow on macOS, which also holds the data, and
+// $XDG_CONFIG_HOME/burrow (default ~/.config/burrow) elsewhere.
+func DefaultConfigDir() string {
+	if runtime.GOOS == "darwin" {
+		return filepath.Join(home(), "Library", "Application Support", "burrow")
+	}
+	return xdgConfigDir()
+}
+
+func xdgConfigDir() string {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" && filepath.IsAbs(xdg) {
+		return filepath.Join(xdg, "burrow")
+	}
+	return filepath.Join(home(), ".config", "burrow")
+}
+
+// hasConfig reports whether dir holds any of the files ConfigDir names.
+func hasConfig(dir string) bool {
+	for _, name := range []string{"config.json", "custom_rules.json", "rules.d", "locales"} {
+		if exists(filepath.Join(dir, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ConfigOverridden reports whether BURROW_CONFIG (or --config) chose the
+// config file or directory.
+func ConfigOverridden() bool {
+	override, _ := configOverride()
+	return override != ""
+}
+
+// ConfigFile returns the config file path, honoring BURROW_CONFIG.
+func ConfigFile() string {
+	if override, isFile := configOverride(); isFile {
+		return override
+	}
+	return filepath.Join(ConfigDir(), "config.json")
+}
+
+// CustomRulesFile returns the path of custom_rules.json.
+func CustomRulesFile() string {
+	return filepath.Join(ConfigDir(), "custom_rules.json")
+}
+
+// RulesDir returns the rules.d directory of drop-in rule files.
+func RulesDir() string {
+	return filepath.Join(ConfigDir(), "rules.d")
+}
+
+// LocalesDir returns the directory of user message files, one
+// <language>.json per locale.
+func LocalesDir() string {
+	return filepath.Join(ConfigDir(), "locales")
+}
+
+// DataDir returns the directory for trash, history, caches, and logs:
+// BURROW_DATA_DIR, then data_dir from the config, then the platform state
+// directory. A legacy ~/.burrow is used until it has been migrated.
+func DataDir() string {
+	if v := strings.TrimSpace(os.Getenv(DataEnv)); v != "" {
+		return absolute(v)
+	}
+	if configuredDataDir != "" {
+		return absolute(configuredDataDir)
+	}
+	def := DefaultDataDir()
+	if !exists(def) && exists(LegacyDataDir()) {
+		return LegacyDataDir()
+	}
+	return def
+}
+
+// DefaultDataDir returns the platform state directory: ~/Library/Application
+// Support/burrow on macOS, %LOCALAPPDATA%\burrow on Windows, and
+// $XDG_STATE_HOME/burrow (default ~/.local/state/burrow) elsewhere.
+func DefaultDataDir() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return filepath.Join(home(), "Library", "Application Support", "burrow")
+	case "windows":
+		if local := os.Getenv("LOCALAPPDATA"); local != "" {
+			return filepath.Join(local, "burrow")
+		}
+		return filepath.Join(home(), "AppData", "Local", "burrow")
+	default:
+		if xdg := os.Getenv("XDG_STATE_HOME"); xdg != "" && filepath.IsAbs(xdg) {
+			return filepath.Join(xdg, "burrow")
+		}
+		return filepath.Join(home(), ".local", "state", "burrow")
+	}
+}
+
+// LegacyDataDir returns the pre-XDG data directory, ~/.burrow.
+func LegacyDataDir() string {
+	return filepath.Join(home(), ".burrow")
+}
+
+// Migrate moves a legacy ~/.burrow to the platform state directory once. It
+// does nothing when the data directory is overridden or already migrated, and
+// reports the destination when it moved something.
+func Migrate() (string, error) {
+	if os.Getenv(DataEnv) != "" || configuredDataDir != "" {
+		return "", nil
+	}
+	legacy, def := LegacyDataDir(), DefaultDataDir()
+	if !exists(legacy) || exists(def) {
+		return "", nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(def), 0755); err != nil {
+		return "", err
+	}
+	if err := os.Rename(legacy, def); err != nil {
+		return "", fmt.Errorf("failed to migrate %s to %s: %w", legacy, def, err)
+	}
+	return def, nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// TrashDir returns the root of Burrow's trash sessions.
+func TrashDir() string { return filepath.Join(DataDir(), "trash") }
+
+// HistoryFile returns the cleanup history file.
+func HistoryFile() string { return filepath.Join(DataDir(), "history.json") }
+
+// SnapshotsFile returns the growth snapshot file.
+func SnapshotsFile() string { return filepath.Join(DataDir(), "snapshots.json") }
+
+// ScanCacheFile returns the cached result of the last unfiltered scan.
+func ScanCacheFile() string { return filepath.Join(DataDir(), "last_scan.json") }
+
+// ScanListingFile returns the results last listed by 'burrow scan', which
+// 'clean --ids' refers to.
+func ScanListingFile() string { return filepath.Join(DataDir(), "last_listing.json") }
+
+// AuditLog returns the privileged-operation audit log.
+func AuditLog() string { return filepath.Join(DataDir(), "audit.log") }
+
+// LogsDir returns the directory for background service logs.
+func LogsDir() string { return filepath.Join(DataDir(), "logs") }
+
+// RuleSourcesDir returns the directory of cloned rule sources.
+func RuleSourcesDir() string { return filepath.Join(DataDir(), "rule-sources") }
+
+// RuleSourcesFile returns the manifest of registered rule sources.
+func RuleSourcesFile() string { return filepath.Join(DataDir(), "rule_sources.json") }
+
+// DismissalsFile returns the list of suggestions the user dismissed or snoozed.
+func DismissalsFile() string { return filepath.Join(DataDir(), "dismissals.json") }
+
+// UsageFile returns the local usage statistics.
+func UsageFile() string { return filepath.Join(DataDir(), "usage.json") }
+
+// configOverride returns the absolute BURROW_CONFIG path and whether it names
+// a config file rather than a directory.
+func configOverride() (string, bool) {
+	v := strings.TrimSpace(os.Getenv(ConfigEnv))
+	if v == "" {
+		return "", false
+	}
+	v = absolute(v)
+
+	if info, err := os.Stat(v); err == nil {
+		return v, !info.IsDir()
+	}
+	return v, strings.EqualFold(filepath.Ext(v), ".json")
+}
+
+// absolute expands a leading ~/ and makes path absolute.
+func absolute(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		path = filepath.Join(home(), path[2:])
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
@@ -0,0 +1,156 @@
+package paths
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigOverride(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "work.json")
+	if err := os.WriteFile(file, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	def := DefaultConfigDir()
+
+	tests := []struct {
+		name     string
+		env      string
+		wantDir  string
+		wantFile string
+	}{
+		{"Default", "", def, filepath.Join(def, "config.json")},
+		{"Directory", dir, dir, filepath.Join(dir, "config.json")},
+		{"File", file, dir, file},
+		{"Missing directory", filepath.Join(dir, "profile"), filepath.Join(dir, "profile"), filepath.Join(dir, "profile", "config.json")},
+		{"Missing file", filepath.Join(dir, "new.json"), dir, filepath.Join(dir, "new.json")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(ConfigEnv, tt.env)
+			if got := ConfigDir(); got != tt.wantDir {
+				t.Errorf("ConfigDir() = %q, want %q", got, tt.wantDir)
+			}
+			if got := ConfigFile(); got != tt.wantFile {
+				t.Errorf("ConfigFile() = %q, want %q", got, tt.wantFile)
+			}
+		})
+	}
+}
+
+func TestConfigDirKeepsXDGConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv(ConfigEnv, "")
+
+	xdg := filepath.Join(home, ".config", "burrow")
+	if err := os.MkdirAll(xdg, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(xdg, "config.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := ConfigDir(); got != xdg {
+		t.Errorf("ConfigDir() = %q, want the existing %q", got, xdg)
+	}
+
+	// Configuration in the platform directory wins
+	def := DefaultConfigDir()
+	if err := os.MkdirAll(filepath.Join(def, "rules.d"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := ConfigDir(); got != def {
+		t.Errorf("ConfigDir() = %q, want %q", got, def)
+	}
+}
+
+func TestDataDirPrecedence(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_STATE_HOME", "")
+	defer SetDataDir("")
+
+	t.Setenv(DataEnv, "")
+	SetDataDir("")
+	if got := DataDir(); got != DefaultDataDir() {
+		t.Errorf("default DataDir() = %q, want %q", got, DefaultDataDir())
+	}
+
+	SetDataDir("~/burrow-data")
+	if got := DataDir(); got != filepath.Join(home, "burrow-data") {
+		t.Errorf("configured DataDir() = %q", got)
+	}
+
+	t.Setenv(DataEnv, "/Volumes/Scratch/burrow")
+	if got := DataDir(); got != filepath.FromSlash("/Volumes/Scratch/burrow") {
+		t.Errorf("env DataDir() = %q, want the environment to win", got)
+	}
+	if got := TrashDir(); got != filepath.Join(DataDir(), "trash") {
+		t.Errorf("TrashDir() = %q, not below DataDir", got)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv(DataEnv, "")
+	SetDataDir("")
+
+	legacy := filepath.Join(home, ".burrow")
+	if err := os.MkdirAll(filepath.Join(legacy, "trash"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(legacy, "history.json"), []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := DataDir(); got != legacy {
+		t.Errorf("before migration DataDir() = %q, want legacy %q", got, legacy)
+	}
+
+	moved, err := Migrate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved != DefaultDataDir() || DataDir() != moved {
+		t.Errorf("Migrate() = %q, DataDir() = %q, want %q", moved, DataDir(), DefaultDataDir())
+	}
+	if _, err := os.Stat(HistoryFile()); err != nil {
+		t.Errorf("history not migrated: %v", err)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Error("legacy directory still present after migration")
+	}
+
+	if again, err := Migrate(); err != nil || again != "" {
+		t.Errorf("second Migrate() = %q, %v; want no-op", again, err)
+	}
+}
+
+func TestSudoHome(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("requires root")
+	}
+	u, err := user.Lookup("nobody")
+	if err != nil {
+		t.Skip("no nobody user")
+	}
+	t.Setenv(DataEnv, "")
+	t.Setenv("XDG_STATE_HOME", "")
+
+	t.Setenv("SUDO_USER", "nobody")
+	if got := home(); got != u.HomeDir {
+		t.Errorf("home() under sudo = %q, want %q", got, u.HomeDir)
+	}
+	t.Setenv("SUDO_USER", "root")
+	if got, want := home(), os.Getenv("HOME"); got != want {
+		t.Errorf("home() as root = %q, want %q", got, want)
+	}
+}
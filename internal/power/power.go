@@ -0,0 +1,71 @@
+// Package power reports battery and thermal state so scheduled scans can be
+// deferred on laptops running low on battery or under thermal pressure.
+package power
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultMinBatteryPercent is the charge below which scans are deferred while
+// running on battery.
+const DefaultMinBatteryPercent = 50
+
+// State is a snapshot of the machine's power source.
+type State struct {
+	OnBattery bool
+	Percent   int  // Battery charge; 0 when there is no battery or it is unknown
+	Throttled bool // The CPU is being slowed down because of heat
+}
+
+// Current returns the power state. Platforms without support report a machine
+// on AC power.
+func Current() (State, error) {
+	return current()
+}
+
+// DeferReason explains why a heavy scan should wait, or returns "" when it
+// can run now. minPercent <= 0 uses DefaultMinBatteryPercent.
+func (s State) DeferReason(minPercent int, ignoreThermal bool) string {
+	if minPercent <= 0 {
+		minPercent = DefaultMinBatteryPercent
+	}
+	if s.OnBattery && s.Percent > 0 && s.Percent < minPercent {
+		return fmt.Sprintf("on battery at %d%% (below %d%%)", s.Percent, minPercent)
+	}
+	if s.Throttled && !ignoreThermal {
+		return "under thermal pressure"
+	}
+	return ""
+}
+
+var battPercent = regexp.MustCompile(`(\d+)%`)
+
+// parsePmsetBatt parses `pmset -g batt`, e.g.
+//
+//	Now drawing from 'Battery Power'
+//	 -InternalBattery-0 (id=1234)	42%; discharging; 2:10 remaining present: true
+func parsePmsetBatt(out string) State {
+	var s State
+	s.OnBattery = strings.Contains(out, "'Battery Power'")
+	if m := battPercent.FindStringSubmatch(out); m != nil {
+		s.Percent, _ = strconv.Atoi(m[1])
+	}
+	return s
+}
+
+// parsePmsetTherm parses `pmset -g therm`, which lists CPU_Speed_Limit below
+// 100 while the CPU is thermally throttled.
+func parsePmsetTherm(out string) bool {
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) != "CPU_Speed_Limit" {
+			continue
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(value))
+		return err == nil && limit < 100
+	}
+	return false
+}
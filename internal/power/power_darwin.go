@@ -0,0 +1,16 @@
+package power
+
+import "os/exec"
+
+func current() (State, error) {
+	out, err := exec.Command("pmset", "-g", "batt").Output()
+	if err != nil {
+		return State{}, err
+	}
+	s := parsePmsetBatt(string(out))
+
+	if out, err := exec.Command("pmset", "-g", "therm").Output(); err == nil {
+		s.Throttled = parsePmsetTherm(string(out))
+	}
+	return s, nil
+}
@@ -0,0 +1,50 @@
+package power
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const sysPowerSupply = "/sys/class/power_supply"
+
+func current() (State, error) {
+	return readPowerSupply(sysPowerSupply), nil
+}
+
+// readPowerSupply inspects the batteries and AC adapters under dir. The
+// machine is on battery when a battery is discharging and no adapter is online.
+func readPowerSupply(dir string) State {
+	var s State
+	entries, _ := os.ReadDir(dir)
+	acOnline := false
+	for _, e := range entries {
+		base := filepath.Join(dir, e.Name())
+		switch readSysfs(base, "type") {
+		case "Mains", "USB":
+			if readSysfs(base, "online") == "1" {
+				acOnline = true
+			}
+		case "Battery":
+			if readSysfs(base, "status") == "Discharging" {
+				s.OnBattery = true
+			}
+			if p, err := strconv.Atoi(readSysfs(base, "capacity")); err == nil {
+				s.Percent = p
+			}
+		}
+	}
+	if acOnline {
+		s.OnBattery = false
+	}
+	return s
+}
+
+func readSysfs(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,33 @@
+package power
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadPowerSupply(t *testing.T) {
+	dir := t.TempDir()
+	write := func(dev, name, value string) {
+		if err := os.MkdirAll(filepath.Join(dir, dev), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, dev, name), []byte(value+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("BAT0", "type", "Battery")
+	write("BAT0", "status", "Discharging")
+	write("BAT0", "capacity", "35")
+	write("AC", "type", "Mains")
+	write("AC", "online", "0")
+
+	if s := readPowerSupply(dir); !s.OnBattery || s.Percent != 35 {
+		t.Errorf("unexpected state on battery: %+v", s)
+	}
+
+	write("AC", "online", "1")
+	if s := readPowerSupply(dir); s.OnBattery {
+		t.Errorf("expected AC power once the adapter is online: %+v", s)
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !darwin && !linux
+
+package power
+
+func current() (State, error) {
+	return State{}, nil
+}
@@ -0,0 +1,46 @@
+package power
+
+import "testing"
+
+func TestParsePmset(t *testing.T) {
+	batt := parsePmsetBatt("Now drawing from 'Battery Power'\n -InternalBattery-0 (id=4653155)\t42%; discharging; 2:10 remaining present: true\n")
+	if !batt.OnBattery || batt.Percent != 42 {
+		t.Errorf("unexpected battery state: %+v", batt)
+	}
+
+	ac := parsePmsetBatt("Now drawing from 'AC Power'\n -InternalBattery-0 (id=4653155)\t100%; charged; 0:00 remaining present: true\n")
+	if ac.OnBattery || ac.Percent != 100 {
+		t.Errorf("unexpected AC state: %+v", ac)
+	}
+
+	if parsePmsetTherm("Note: No thermal warning level has been recorded\n") {
+		t.Error("no warning should not be throttled")
+	}
+	if !parsePmsetTherm("CPU Power notify\n\tCPU_Scheduler_Limit \t= 100\n\tCPU_Available_CPUs \t= 8\n\tCPU_Speed_Limit \t= 70\n") {
+		t.Error("a speed limit below 100 should be throttled")
+	}
+}
+
+func TestDeferReason(t *testing.T) {
+	tests := []struct {
+		name          string
+		state         State
+		min           int
+		ignoreThermal bool
+		wantDefer     bool
+	}{
+		{"AC power", State{Percent: 20}, 0, false, false},
+		{"Battery above default", State{OnBattery: true, Percent: 80}, 0, false, false},
+		{"Battery below default", State{OnBattery: true, Percent: 30}, 0, false, true},
+		{"Battery below custom", State{OnBattery: true, Percent: 30}, 20, false, false},
+		{"Throttled", State{Throttled: true}, 0, false, true},
+		{"Throttled but ignored", State{Throttled: true}, 0, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.state.DeferReason(tt.min, tt.ignoreThermal) != ""; got != tt.wantDefer {
+				t.Errorf("DeferReason() defers = %v, want %v", got, tt.wantDefer)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+// Package priority lowers the scheduling and I/O priority of the current
+// process so background scans stay out of the way of interactive work.
+package priority
+
+// Idle moves the process to the lowest CPU priority and, where the platform
+// supports it, to background/idle I/O scheduling. It affects the whole
+// process and cannot be undone.
+func Idle() error {
+	return idle()
+}
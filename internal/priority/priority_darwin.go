@@ -0,0 +1,33 @@
+package priority
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// Constants from <sys/resource.h>.
+const (
+	iopolCmdSet       = 1
+	iopolTypeDisk     = 0
+	iopolScopeProcess = 0
+	iopolThrottle     = 3
+)
+
+type iopolParam struct {
+	iotype int32
+	scope  int32
+	policy int32
+}
+
+// idle is the equivalent of `nice -n 19` plus `taskpolicy -d throttle`.
+func idle() error {
+	if err := syscall.Setpriority(syscall.PRIO_PROCESS, 0, 19); err != nil {
+		return fmt.Errorf("setpriority: %w", err)
+	}
+	p := iopolParam{iotype: iopolTypeDisk, scope: iopolScopeProcess, policy: iopolThrottle}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOPOLICYSYS, iopolCmdSet, uintptr(unsafe.Pointer(&p)), 0); errno != 0 {
+		return fmt.Errorf("setiopolicy: %w", errno)
+	}
+	return nil
+}
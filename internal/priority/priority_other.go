@@ -0,0 +1,9 @@
+//go:build !linux && !darwin && !windows
+
+package priority
+
+import "syscall"
+
+func idle() error {
+	return syscall.Setpriority(syscall.PRIO_PROCESS, 0, 19)
+}
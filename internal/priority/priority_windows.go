@@ -0,0 +1,22 @@
+package priority
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// processModeBackgroundBegin lowers both CPU and I/O priority (SetPriorityClass).
+const processModeBackgroundBegin = 0x00100000
+
+var procSetPriorityClass = syscall.NewLazyDLL("kernel32.dll").NewProc("SetPriorityClass")
+
+func idle() error {
+	h, err := syscall.GetCurrentProcess()
+	if err != nil {
+		return err
+	}
+	if r, _, err := procSetPriorityClass.Call(uintptr(h), processModeBackgroundBegin); r == 0 {
+		return fmt.Errorf("SetPriorityClass: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package privilege
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/cleaner"
+	"github.com/ismailtsdln/burrow/internal/paths"
+)
+
+// Whitelist contains the only system-owned locations Burrow will ever clean as root.
+var Whitelist = []string{
+	"/Library/Caches",
+	"/private/var/folders",
+}
+
+// IsRoot reports whether the current process runs with root privileges.
+func IsRoot() bool {
+	return os.Geteuid() == 0
+}
+
+// IsWhitelisted returns true if path is one of the whitelisted system paths or lives below one.
+func IsWhitelisted(path string) bool {
+	clean := filepath.Clean(path)
+	if !filepath.IsAbs(clean) {
+		return false
+	}
+	for _, allowed := range Whitelist {
+		if clean == allowed || strings.HasPrefix(clean, allowed+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedEnv lists the variables that must survive sudo's environment
+// reset, so the elevated run uses the same config and data directory.
+var forwardedEnv = []string{paths.ConfigEnv, paths.DataEnv}
+
+// Reexec runs the current executable again through sudo with the given arguments,
+// wiring the child to the current terminal and waiting for it to finish.
+func Reexec(args []string) error {
+	if runtime.GOOS == "windows" {
+		return errors.New("privilege escalation is not supported on Windows; run burrow from an elevated prompt")
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate burrow executable: %w", err)
+	}
+
+	sudoArgs := append(sudoEnv(), exe)
+	sudoArgs = append(sudoArgs, args...)
+	cmd := exec.Command("sudo", sudoArgs...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("elevated cleanup failed: %w", err)
+	}
+	return nil
+}
+
+// sudoEnv returns the env(1) prefix that passes forwardedEnv through sudo,
+// or nothing when none of them is set.
+func sudoEnv() []string {
+	var env []string
+	for _, name := range forwardedEnv {
+		if v := os.Getenv(name); v != "" {
+			env = append(env, name+"="+v)
+		}
+	}
+	if len(env) == 0 {
+		return nil
+	}
+	return append([]string{"env"}, env...)
+}
+
+// lchown changes an owner without following symlinks; tests replace it.
+var lchown = os.Lchown
+
+// HandBack gives the data directory back to the user who ran sudo, so that
+// history, caches, the audit log, and trash sessions an elevated run wrote
+// stay usable without sudo. What a cleanup moved into the trash keeps its
+// owner, so undo puts system files back as they were. It does nothing unless
+// running as root through sudo.
+func HandBack() error {
+	uid, gid, ok := invoker()
+	if !ok {
+		return nil
+	}
+	trash := paths.TrashDir()
+	err := filepath.WalkDir(paths.DataDir(), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if filepath.Dir(filepath.Dir(path)) == trash && !cleaner.IsSessionFile(d.Name()) {
+			// An entry a cleanup moved into a session
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		return lchown(path, uid, gid)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to hand %s back to %s: %w", paths.DataDir(), os.Getenv("SUDO_USER"), err)
+	}
+	return nil
+}
+
+// invoker returns the IDs of the user who ran sudo, or false when not running
+// as root through sudo.
+func invoker() (int, int, bool) {
+	if !IsRoot() {
+		return 0, 0, false
+	}
+	uid, err := strconv.Atoi(os.Getenv("SUDO_UID"))
+	if err != nil || uid == 0 {
+		return 0, 0, false
+	}
+	gid, err := strconv.Atoi(os.Getenv("SUDO_GID"))
+	if err != nil {
+		return 0, 0, false
+	}
+	return uid, gid, true
+}
+
+// AuditEvent is a single record in the privileged operation audit log.
+type AuditEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Action    string    `json:"action"`
+	User      string    `json:"user"`
+	UID       int       `json:"uid"`
+	Paths     []string  `json:"paths,omitempty"`
+	Session   string    `json:"session,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// AuditLogPath returns the location of the audit log.
+func AuditLogPath() string {
+	return paths.AuditLog()
+}
+
+// Audit appends an event to the audit log as a JSON line.
+func Audit(action string, paths []string, session string, cause error) error {
+	user := os.Getenv("SUDO_USER")
+	if user == "" {
+		user = os.Getenv("USER")
+	}
+
+	event := AuditEvent{
+		Timestamp: time.Now(),
+		Action:    action,
+		User:      user,
+		UID:       os.Geteuid(),
+		Paths:     paths,
+		Session:   session,
+	}
+	if cause != nil {
+		event.Error = cause.Error()
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	logPath := AuditLogPath()
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,99 @@
+package privilege
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ismailtsdln/burrow/internal/paths"
+)
+
+func TestIsWhitelisted(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/Library/Caches", true},
+		{"/Library/Caches/com.apple.iconservices.store", true},
+		{"/private/var/folders/xy/abc/C", true},
+		{"/Library/CachesEvil", false},
+		{"/Library/Caches/../Preferences", false},
+		{"/Library", false},
+		{"/System/Library/Caches", false},
+		{"Library/Caches", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := IsWhitelisted(tt.path); got != tt.want {
+				t.Errorf("IsWhitelisted(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandBack(t *testing.T) {
+	if !IsRoot() {
+		t.Skip("HandBack only acts as root")
+	}
+	data := t.TempDir()
+	t.Setenv(paths.DataEnv, data)
+	t.Setenv("SUDO_UID", "501")
+	t.Setenv("SUDO_GID", "20")
+
+	session := filepath.Join(data, "trash", "20260105_093000")
+	moved := filepath.Join(session, "Caches")
+	for _, dir := range []string{moved, filepath.Join(data, "logs")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range []string{
+		filepath.Join(data, "history.json"),
+		filepath.Join(session, "manifest.json"),
+		filepath.Join(session, "Cache.db"),
+		filepath.Join(moved, "blob"),
+	} {
+		if err := os.WriteFile(f, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	chowned := map[string]bool{}
+	lchown = func(path string, uid, gid int) error {
+		if uid != 501 || gid != 20 {
+			t.Errorf("lchown(%s) to %d:%d", path, uid, gid)
+		}
+		chowned[path] = true
+		return nil
+	}
+	defer func() { lchown = os.Lchown }()
+
+	if err := HandBack(); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{data, filepath.Join(data, "history.json"), filepath.Join(data, "logs"), filepath.Join(data, "trash"), session, filepath.Join(session, "manifest.json")} {
+		if !chowned[p] {
+			t.Errorf("%s was not handed back", p)
+		}
+	}
+	for _, p := range []string{moved, filepath.Join(moved, "blob"), filepath.Join(session, "Cache.db")} {
+		if chowned[p] {
+			t.Errorf("trashed entry %s changed owner", p)
+		}
+	}
+}
+
+func TestSudoEnv(t *testing.T) {
+	t.Setenv(paths.ConfigEnv, "")
+	t.Setenv(paths.DataEnv, "")
+	if env := sudoEnv(); env != nil {
+		t.Errorf("sudoEnv() = %v, want nothing", env)
+	}
+	t.Setenv(paths.DataEnv, "/Volumes/Data/burrow")
+	want := []string{"env", paths.DataEnv + "=/Volumes/Data/burrow"}
+	if env := sudoEnv(); !slices.Equal(env, want) {
+		t.Errorf("sudoEnv() = %v, want %v", env, want)
+	}
+}
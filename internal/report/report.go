@@ -0,0 +1,116 @@
+// Package report builds periodic digests of what Burrow reclaimed and what
+// is waiting to be cleaned, for the terminal, notifications, or email.
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/cleaner"
+	"github.com/ismailtsdln/burrow/internal/history"
+	"github.com/ismailtsdln/burrow/internal/snapshot"
+	"github.com/ismailtsdln/burrow/internal/units"
+)
+
+// maxGrowth is how many of the fastest-growing categories a digest lists.
+const maxGrowth = 3
+
+// Digest summarizes one period.
+type Digest struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	Cleanups  int   `json:"cleanups"`
+	Reclaimed int64 `json:"reclaimed"`
+
+	// Reclaimable is the total of the latest scan snapshot, taken at ScannedAt.
+	Reclaimable int64     `json:"reclaimable"`
+	ScannedAt   time.Time `json:"scanned_at,omitzero"`
+
+	Growth []snapshot.Growth  `json:"growth"`
+	Trash  cleaner.TrashUsage `json:"trash"`
+}
+
+// Build summarizes the period ending at now from cleanup history, scan
+// snapshots, and the current trash.
+func Build(now time.Time, period time.Duration, entries []history.Entry, snapshots []snapshot.Snapshot, trash cleaner.TrashUsage) Digest {
+	d := Digest{From: now.Add(-period), To: now, Trash: trash}
+
+	for _, e := range entries {
+		if e.Timestamp.After(d.From) && !e.Timestamp.After(now) {
+			d.Cleanups++
+			d.Reclaimed += e.ReclaimedBytes
+		}
+	}
+
+	var inPeriod []snapshot.Snapshot
+	for _, s := range snapshots {
+		if s.Timestamp.After(d.From) && !s.Timestamp.After(now) {
+			inPeriod = append(inPeriod, s)
+		}
+	}
+	if len(snapshots) > 0 {
+		latest := snapshots[len(snapshots)-1]
+		d.Reclaimable, d.ScannedAt = latest.TotalBytes, latest.Timestamp
+	}
+	for _, g := range snapshot.CategoryGrowth(inPeriod) {
+		if g.Delta <= 0 || len(d.Growth) == maxGrowth {
+			break
+		}
+		d.Growth = append(d.Growth, g)
+	}
+	return d
+}
+
+// Write renders the digest as plain text, or as Markdown when markdown is set.
+func (d Digest) Write(w io.Writer, markdown bool) {
+	title := fmt.Sprintf("Burrow report: %s to %s", d.From.Format("Jan 2"), d.To.Format("Jan 2, 2006"))
+	section := func(name string) {
+		if markdown {
+			fmt.Fprintf(w, "\n## %s\n\n", name)
+		} else {
+			fmt.Fprintf(w, "\n%s\n%s\n", name, strings.Repeat("-", len(name)))
+		}
+	}
+	item := func(format string, args ...interface{}) {
+		fmt.Fprintf(w, "- "+format+"\n", args...)
+	}
+
+	if markdown {
+		fmt.Fprintf(w, "# %s\n", title)
+	} else {
+		fmt.Fprintf(w, "%s\n%s\n", title, strings.Repeat("=", len(title)))
+	}
+
+	section("Reclaimed")
+	if d.Cleanups == 0 {
+		item("No cleanups this period.")
+	} else {
+		item("%s in %d cleanup(s)", units.FormatSize(d.Reclaimed), d.Cleanups)
+	}
+
+	section("Reclaimable now")
+	if d.ScannedAt.IsZero() {
+		item("No scans yet; run 'burrow scan'.")
+	} else {
+		item("%s (scanned %s)", units.FormatSize(d.Reclaimable), d.ScannedAt.Format("Jan 2 15:04"))
+	}
+
+	section("Fastest-growing categories")
+	if len(d.Growth) == 0 {
+		item("Nothing grew this period.")
+	}
+	for _, g := range d.Growth {
+		item("%s: +%s (now %s)", g.Category, units.FormatSize(g.Delta), units.FormatSize(g.Last))
+	}
+
+	section("Trash pending purge")
+	if d.Trash.Sessions == 0 {
+		item("Trash is empty.")
+	} else {
+		item("%s in %d session(s), the oldest from %s", units.FormatSize(d.Trash.Bytes), d.Trash.Sessions, d.Trash.Oldest.Format("Jan 2"))
+		item("Run 'burrow trash purge <id>' to free it for good.")
+	}
+}
@@ -0,0 +1,45 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/cleaner"
+	"github.com/ismailtsdln/burrow/internal/history"
+	"github.com/ismailtsdln/burrow/internal/snapshot"
+)
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2026, 3, 15, 9, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return now.Add(-time.Duration(n) * 24 * time.Hour) }
+	entries := []history.Entry{
+		{Timestamp: day(1), ReclaimedBytes: 300},
+		{Timestamp: day(3), ReclaimedBytes: 200},
+		{Timestamp: day(10), ReclaimedBytes: 5000}, // Before the period
+	}
+	snapshots := []snapshot.Snapshot{
+		{Timestamp: day(20), TotalBytes: 10, Categories: map[string]int64{"System": 10}},
+		{Timestamp: day(6), TotalBytes: 150, Categories: map[string]int64{"Developer Tools": 100, "System": 50}},
+		{Timestamp: day(0), TotalBytes: 900, Categories: map[string]int64{"Developer Tools": 800, "System": 40, "Package Managers": 60}},
+	}
+
+	d := Build(now, 7*24*time.Hour, entries, snapshots, cleaner.TrashUsage{Sessions: 2, Bytes: 4096, Oldest: day(5)})
+	if d.Cleanups != 2 || d.Reclaimed != 500 {
+		t.Errorf("reclaimed %d in %d cleanups, want 500 in 2", d.Reclaimed, d.Cleanups)
+	}
+	if d.Reclaimable != 900 {
+		t.Errorf("reclaimable = %d, want 900", d.Reclaimable)
+	}
+	if len(d.Growth) != 2 || d.Growth[0].Category != "Developer Tools" || d.Growth[0].Delta != 700 {
+		t.Errorf("growth = %+v, want Developer Tools +700 then Package Managers", d.Growth)
+	}
+
+	var md strings.Builder
+	d.Write(&md, true)
+	for _, want := range []string{"# Burrow report: Mar 8 to Mar 15, 2026", "## Trash pending purge", "- Developer Tools: +700 B"} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("markdown is missing %q:\n%s", want, md.String())
+		}
+	}
+}
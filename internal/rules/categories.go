@@ -0,0 +1,87 @@
+package rules
+
+import (
+	"sort"
+	"strings"
+)
+
+// Category describes a group of related rules.
+type Category struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Icon        string `json:"icon,omitempty"`
+	// Risk is the usual risk of the category's rules; each rule still sets
+	// its own.
+	Risk RiskLevel `json:"default_risk,omitempty"`
+}
+
+// DefaultIcon marks categories without an icon of their own.
+const DefaultIcon = "📁"
+
+// knownCategories are the categories used by the built-in rules, followed by
+// those of scan modes that discover their results instead of using rules.
+var knownCategories = []Category{
+	{Name: "Package Managers", Icon: "📦", Risk: RiskSafe, Description: "Download caches of language package managers (npm, pip, Cargo, Go modules, ...)."},
+	{Name: "Developer Tools", Icon: "🔨", Risk: RiskSafe, Description: "Build outputs, indexes, and simulators created by IDEs and SDKs."},
+	{Name: "Language Servers", Icon: "💬", Risk: RiskSafe, Description: "Indexes and downloads of editor language servers (gopls, rust-analyzer, clangd, tsserver, Metals)."},
+	{Name: "Machine Learning", Icon: "🤖", Risk: RiskCaution, Description: "Downloaded models, checkpoints, and GPU libraries (Hugging Face, Ollama, PyTorch, CUDA)."},
+	{Name: "System", Icon: "💻", Risk: RiskSafe, Description: "General user and system caches, app caches, and temporary files."},
+	{Name: "Browsers", Icon: "🌐", Risk: RiskSafe, Description: "Per-profile GPU and service worker caches of Chrome, Edge, and Arc."},
+	{Name: "Downloads", Icon: "📥", Risk: RiskCaution, Description: "Old installers and disk images left in ~/Downloads."},
+	{Name: "Containers", Icon: "🐳", Risk: RiskManual, Description: "Docker, Colima, and Lima usage, reported with the commands that prune it."},
+	{Name: "Virtual Machines", Icon: "💽", Risk: RiskManual, Description: "Virtual machine bundles and disk images, reported with the tools that shrink them."},
+	{Name: "Custom", Icon: "🧩", Risk: RiskManual, Description: "Rules from custom_rules.json, rules.d, and rule sources without a category."},
+	{Name: "App Caches", Icon: "🧊", Risk: RiskCaution, Description: "Per-application caches found by scan --apps."},
+	{Name: "App Leftovers", Icon: "👻", Risk: RiskManual, Description: "Data of uninstalled apps found by scan --leftovers."},
+	{Name: "Large Files", Icon: "🐘", Risk: RiskManual, Description: "Large files found by scan --large, for review."},
+}
+
+// LookupCategory returns the metadata of a known category, or a category
+// with just the name and the default icon.
+func LookupCategory(name string) Category {
+	for _, c := range knownCategories {
+		if strings.EqualFold(c.Name, name) {
+			return c
+		}
+	}
+	return Category{Name: name, Icon: DefaultIcon}
+}
+
+// Categories returns every category used by the registered rules, with
+// descriptions for the known ones, in display order.
+func (r *Registry) Categories() []Category {
+	seen := make(map[string]bool)
+	var out []Category
+	for _, c := range knownCategories {
+		for _, rule := range r.rules {
+			if strings.EqualFold(rule.Category, c.Name) {
+				out = append(out, c)
+				seen[strings.ToLower(c.Name)] = true
+				break
+			}
+		}
+	}
+
+	var extra []Category
+	for _, rule := range r.rules {
+		key := strings.ToLower(rule.Category)
+		if rule.Category == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		extra = append(extra, Category{Name: rule.Category, Icon: DefaultIcon})
+	}
+	sort.Slice(extra, func(i, j int) bool { return extra[i].Name < extra[j].Name })
+
+	return append(out, extra...)
+}
+
+// FindCategory returns the registered category matching name case-insensitively.
+func (r *Registry) FindCategory(name string) (Category, bool) {
+	for _, c := range r.Categories() {
+		if strings.EqualFold(c.Name, name) {
+			return c, true
+		}
+	}
+	return Category{}, false
+}
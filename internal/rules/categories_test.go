@@ -0,0 +1,48 @@
+package rules
+
+import "testing"
+
+func TestCategories(t *testing.T) {
+	r := NewRegistryFromRules([]CleanupRule{
+		{Name: "a", Category: "Scratch"},
+		{Name: "b", Category: "System"},
+		{Name: "c", Category: "Package Managers"},
+		{Name: "d", Category: "scratch"},
+	})
+
+	got := r.Categories()
+	want := []string{"Package Managers", "System", "Scratch"}
+	if len(got) != len(want) {
+		t.Fatalf("Categories() = %+v, want %v", got, want)
+	}
+	for i, name := range want {
+		if got[i].Name != name {
+			t.Errorf("Categories()[%d] = %q, want %q", i, got[i].Name, name)
+		}
+	}
+	if got[0].Description == "" {
+		t.Error("known category is missing its description")
+	}
+
+	if c, ok := r.FindCategory("package managers"); !ok || c.Name != "Package Managers" {
+		t.Errorf("FindCategory() = %+v, %v", c, ok)
+	}
+	if _, ok := r.FindCategory("Nope"); ok {
+		t.Error("FindCategory() found an unknown category")
+	}
+}
+
+func TestLookupCategory(t *testing.T) {
+	c := LookupCategory("package managers")
+	if c.Name != "Package Managers" || c.Icon == "" || c.Risk != RiskSafe {
+		t.Errorf("LookupCategory(known) = %+v", c)
+	}
+	if c := LookupCategory("Scratch"); c.Name != "Scratch" || c.Icon != DefaultIcon {
+		t.Errorf("LookupCategory(unknown) = %+v", c)
+	}
+	for _, c := range knownCategories {
+		if c.Icon == "" || c.Risk == "" || c.Description == "" {
+			t.Errorf("known category %q is missing metadata", c.Name)
+		}
+	}
+}
@@ -2,26 +2,132 @@ package rules
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
-	"github.com/ismailtsdln/burrow/internal/safety"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ismailtsdln/burrow/internal/paths"
+	"github.com/ismailtsdln/burrow/internal/sources"
 )
 
-// LoadCustomRules loads rules from ~/.config/burrow/custom_rules.json
+// LoadCustomRules loads rules from custom_rules.json in the config directory,
+// every JSON or YAML file in its rules.d/, and the top level of each
+// rule source cloned by `burrow rules add-source`, capped at Caution. Files
+// that fail to parse
+// are reported in the returned error; rules from the others are still
+// returned.
 func LoadCustomRules() ([]CleanupRule, error) {
-	path := safety.ExpandPath("~/.config/burrow/custom_rules.json")
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return nil, nil // No custom rules file, which is fine
+	var all []CleanupRule
+	var errs []error
+
+	path := paths.CustomRulesFile()
+	if _, err := os.Stat(path); err == nil {
+		loaded, err := loadRulesFile(path)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		all = append(all, loaded...)
+	}
+
+	loaded, err := LoadRulesDir(paths.RulesDir())
+	if err != nil {
+		errs = append(errs, err)
+	}
+	all = append(all, loaded...)
+
+	clones, _ := os.ReadDir(sources.Dir())
+	for _, c := range clones {
+		if !c.IsDir() {
+			continue
+		}
+		loaded, err := loadSourceRules(filepath.Join(sources.Dir(), c.Name()))
+		if err != nil {
+			errs = append(errs, err)
+		}
+		all = append(all, loaded...)
 	}
 
+	return all, errors.Join(errs...)
+}
+
+// loadSourceRules loads the rules of a cloned rule source. They come from
+// someone else's repository, so they are never Safe, which would let the
+// watch loop clean them unattended, and never skip the Git guard.
+func loadSourceRules(dir string) ([]CleanupRule, error) {
+	loaded, err := LoadRulesDir(dir)
+	for i := range loaded {
+		if loaded[i].RiskLevel == RiskSafe {
+			loaded[i].RiskLevel = RiskCaution
+		}
+		loaded[i].BypassGitCheck = false
+	}
+	return loaded, err
+}
+
+// LoadRulesDir loads every .json, .yaml, and .yml file in dir in lexical
+// order. A missing directory yields no rules and no error.
+func LoadRulesDir(dir string) ([]CleanupRule, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".json", ".yaml", ".yml":
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var all []CleanupRule
+	var errs []error
+	for _, name := range names {
+		loaded, err := loadRulesFile(filepath.Join(dir, name))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		all = append(all, loaded...)
+	}
+	return all, errors.Join(errs...)
+}
+
+// loadRulesFile parses a JSON or YAML list of rules and applies the custom
+// rule defaults. YAML uses the same keys as JSON.
+func loadRulesFile(path string) ([]CleanupRule, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
 
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		// Round-trip through JSON so both formats share the json struct tags
+		var doc interface{}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if data, err = json.Marshal(doc); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
 	var customRules []CleanupRule
 	if err := json.Unmarshal(data, &customRules); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%s: %w", path, err)
 	}
 
 	// Set defaults for custom rules
@@ -32,8 +138,12 @@ func LoadCustomRules() ([]CleanupRule, error) {
 		if customRules[i].RiskLevel == "" {
 			customRules[i].RiskLevel = RiskManual // Default to manual/caution for safety
 		}
+		if customRules[i].GitCheck == "" {
+			customRules[i].GitCheck = "deep" // Refuse any embedded repository unless the rule opts out
+		}
 		customRules[i].IntroducedIn = "custom"
 		customRules[i].RuleVersion = "1.0.0"
+		customRules[i].Source = path
 	}
 
 	return customRules, nil
@@ -0,0 +1,70 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ismailtsdln/burrow/internal/units"
+)
+
+func TestLoadRulesDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"10-node.json": `[{"name": "Node Logs", "paths": ["~/logs"], "include_patterns": ["*.log"]}]`,
+		"20-java.yaml": "- name: Maven Repo\n  category: Java\n  paths: [\"~/.m2/repository\"]\n  risk_level: Caution\n  min_age: 2w\n",
+		"30-bad.yml":   "- name: [unterminated\n",
+		"README.md":    "not a rules file",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	loaded, err := LoadRulesDir(dir)
+	if err == nil {
+		t.Error("expected an error for the malformed YAML file")
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("expected 2 rules, got %d", len(loaded))
+	}
+
+	node, java := loaded[0], loaded[1]
+	if node.Name != "Node Logs" || node.Category != "Custom" || node.RiskLevel != RiskManual || len(node.IncludePatterns) != 1 {
+		t.Errorf("unexpected JSON rule: %+v", node)
+	}
+	if java.Name != "Maven Repo" || java.Category != "Java" || java.RiskLevel != RiskCaution || java.MinAgeDuration() != 2*units.Week {
+		t.Errorf("unexpected YAML rule: %+v", java)
+	}
+	if java.Source != filepath.Join(dir, "20-java.yaml") {
+		t.Errorf("Source = %q, want the YAML file path", java.Source)
+	}
+}
+
+func TestLoadRulesDirMissing(t *testing.T) {
+	loaded, err := LoadRulesDir(filepath.Join(t.TempDir(), "absent"))
+	if err != nil || len(loaded) != 0 {
+		t.Errorf("LoadRulesDir(missing) = %v, %v; want no rules and no error", loaded, err)
+	}
+}
+
+func TestLoadSourceRules(t *testing.T) {
+	dir := t.TempDir()
+	rules := `[{"name": "Team Cache", "paths": ["~/team"], "risk_level": "Safe", "bypass_git_check": true},
+		{"name": "Team Logs", "paths": ["~/logs"], "risk_level": "Manual"}]`
+	if err := os.WriteFile(filepath.Join(dir, "team.json"), []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := loadSourceRules(dir)
+	if err != nil || len(loaded) != 2 {
+		t.Fatalf("loadSourceRules = %v, %v", loaded, err)
+	}
+	if loaded[0].RiskLevel != RiskCaution || loaded[0].BypassGitCheck {
+		t.Errorf("Safe source rule = %+v, want Caution with the Git guard", loaded[0])
+	}
+	if loaded[1].RiskLevel != RiskManual {
+		t.Errorf("Manual source rule became %s", loaded[1].RiskLevel)
+	}
+}
@@ -0,0 +1,45 @@
+package rules
+
+// Explanation is the structured form of a rule's explanation, for
+// documentation sites and GUIs.
+type Explanation struct {
+	Rule          string      `json:"rule"`
+	Category      string      `json:"category"`
+	Risk          RiskLevel   `json:"risk_level"`
+	Summary       string      `json:"summary"`
+	Stores        string      `json:"stores,omitempty"`
+	AfterDeletion string      `json:"after_deletion,omitempty"`
+	RebuildCost   RebuildCost `json:"rebuild_cost,omitzero"`
+	Details       string      `json:"details,omitempty"`
+	References    []string    `json:"references,omitempty"`
+	Paths         []string    `json:"paths"`
+	RequiresRoot  bool        `json:"requires_root,omitempty"`
+	ReportOnly    bool        `json:"report_only,omitempty"`
+}
+
+// Explain returns the rule's explanation. What happens after deletion is
+// the rebuild cost description; report-only rules are never deleted, so
+// their details name the tool to use instead.
+func (r CleanupRule) Explain() Explanation {
+	e := Explanation{
+		Rule:         r.Name,
+		Category:     r.Category,
+		Risk:         r.RiskLevel,
+		Summary:      r.Description,
+		Stores:       r.Stores,
+		RebuildCost:  r.RebuildCost,
+		Details:      r.Explanation,
+		References:   r.References,
+		Paths:        r.Paths,
+		RequiresRoot: r.RequiresRoot,
+		ReportOnly:   r.ReportOnly,
+	}
+	switch {
+	case r.ReportOnly:
+	case r.RebuildCost.Description != "":
+		e.AfterDeletion = r.RebuildCost.Description
+	case r.RebuildCost.Level == RebuildNone:
+		e.AfterDeletion = "Nothing needs to be rebuilt or downloaded again."
+	}
+	return e
+}
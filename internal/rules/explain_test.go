@@ -0,0 +1,43 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	r := CleanupRule{
+		Name:        "Cache",
+		RiskLevel:   RiskSafe,
+		Description: "Delete the cache.",
+		Stores:      "Downloads.",
+		Explanation: "Safe to delete.",
+		RebuildCost: RebuildCost{Level: RebuildLow, Description: "Downloaded again."},
+		References:  []string{"https://example.com/cache"},
+	}
+	e := r.Explain()
+	if e.Rule != "Cache" || e.Summary != r.Description || e.Stores != r.Stores || e.Details != r.Explanation {
+		t.Errorf("Explain() = %+v", e)
+	}
+	if e.AfterDeletion != "Downloaded again." {
+		t.Errorf("AfterDeletion = %q, want the rebuild cost description", e.AfterDeletion)
+	}
+
+	r.ReportOnly = true
+	if e := r.Explain(); e.AfterDeletion != "" {
+		t.Errorf("report-only rule has AfterDeletion %q", e.AfterDeletion)
+	}
+
+	// Every built-in rule says what it stores, and rules that say Burrow
+	// only reports them are never cleaned
+	builtin := &Registry{}
+	builtin.registerDefaultRules()
+	for _, rule := range append(builtin.rules, windowsRules()...) {
+		if rule.Stores == "" {
+			t.Errorf("built-in rule %q has no Stores", rule.Name)
+		}
+		if strings.Contains(rule.Explanation, "only reports") && !rule.ReportOnly {
+			t.Errorf("built-in rule %q says it is only reported but is not ReportOnly", rule.Name)
+		}
+	}
+}
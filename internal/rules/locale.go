@@ -0,0 +1,134 @@
+package rules
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ismailtsdln/burrow/internal/paths"
+)
+
+// DefaultLocale is the language the rules are written in.
+const DefaultLocale = "en"
+
+//go:embed locales/*.json
+var bundledLocales embed.FS
+
+// Messages are the translated texts of one rule. Texts left empty fall back
+// to English.
+type Messages struct {
+	Description string `json:"description,omitempty"`
+	Explanation string `json:"explanation,omitempty"`
+	Stores      string `json:"stores,omitempty"`
+	RebuildCost string `json:"rebuild_cost,omitempty"`
+}
+
+// messageFile is the format of a locale file: translations keyed by rule name.
+type messageFile struct {
+	Rules map[string]Messages `json:"rules"`
+}
+
+// locale is the language NewRegistry translates rules into.
+var locale = DefaultLocale
+
+// SetLocale selects the language of rule texts, e.g. "de" or "pt_BR.UTF-8".
+func SetLocale(name string) {
+	locale = NormalizeLocale(name)
+}
+
+// Locale returns the language selected with SetLocale.
+func Locale() string {
+	return locale
+}
+
+// NormalizeLocale reduces a locale name such as "de_DE.UTF-8" to its
+// language, "de". The C and POSIX locales are English.
+func NormalizeLocale(name string) string {
+	name, _, _ = strings.Cut(name, ".")
+	name, _, _ = strings.Cut(name, "@")
+	name, _, _ = strings.Cut(strings.ReplaceAll(name, "-", "_"), "_")
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == "c" || name == "posix" {
+		return DefaultLocale
+	}
+	return name
+}
+
+// SystemLocale returns the language of the user's environment from
+// LC_ALL, LC_MESSAGES, or LANG.
+func SystemLocale() string {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(key); v != "" {
+			return NormalizeLocale(v)
+		}
+	}
+	return DefaultLocale
+}
+
+// LoadMessages returns the translations for lang: those bundled with Burrow,
+// overridden rule by rule by <config dir>/locales/<lang>.json.
+func LoadMessages(lang string) (map[string]Messages, error) {
+	messages := make(map[string]Messages)
+	if lang == DefaultLocale || lang == "" || filepath.Base(lang) != lang {
+		return messages, nil
+	}
+
+	if data, err := bundledLocales.ReadFile("locales/" + lang + ".json"); err == nil {
+		if err := mergeMessages(messages, data); err != nil {
+			return messages, fmt.Errorf("bundled %s messages: %w", lang, err)
+		}
+	}
+	path := filepath.Join(paths.LocalesDir(), lang+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return messages, nil
+		}
+		return messages, err
+	}
+	if err := mergeMessages(messages, data); err != nil {
+		return messages, fmt.Errorf("%s: %w", path, err)
+	}
+	return messages, nil
+}
+
+func mergeMessages(messages map[string]Messages, data []byte) error {
+	var file messageFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return err
+	}
+	for name, m := range file.Rules {
+		messages[strings.ToLower(name)] = m
+	}
+	return nil
+}
+
+// Localize replaces the texts of each rule with their translation, where
+// there is one. Rules are matched by name, case-insensitively.
+func Localize(list []CleanupRule, messages map[string]Messages) []CleanupRule {
+	if len(messages) == 0 {
+		return list
+	}
+	out := make([]CleanupRule, len(list))
+	for i, r := range list {
+		if m, ok := messages[strings.ToLower(r.Name)]; ok {
+			r.Description = pick(m.Description, r.Description)
+			r.Explanation = pick(m.Explanation, r.Explanation)
+			r.Stores = pick(m.Stores, r.Stores)
+			r.RebuildCost.Description = pick(m.RebuildCost, r.RebuildCost.Description)
+		}
+		out[i] = r
+	}
+	return out
+}
+
+func pick(translated, original string) string {
+	if translated != "" {
+		return translated
+	}
+	return original
+}
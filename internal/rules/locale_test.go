@@ -0,0 +1,98 @@
+package rules
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ismailtsdln/burrow/internal/paths"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	tests := map[string]string{
+		"de_DE.UTF-8":     "de",
+		"pt-BR":           "pt",
+		"sr_RS@latin":     "sr",
+		"FR":              "fr",
+		"C":               "en",
+		"POSIX":           "en",
+		"":                "en",
+		"en_US.ISO8859-1": "en",
+	}
+	for in, want := range tests {
+		if got := NormalizeLocale(in); got != want {
+			t.Errorf("NormalizeLocale(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(paths.ConfigEnv, dir)
+
+	// A user file overrides the bundled translation of one rule and adds another
+	if err := os.MkdirAll(filepath.Join(dir, "locales"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	user := `{"rules": {"npm cache": {"description": "npm weg"}, "My Rule": {"explanation": "Meine Erklärung"}}}`
+	if err := os.WriteFile(filepath.Join(dir, "locales", "de.json"), []byte(user), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	messages, err := LoadMessages("de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := Localize([]CleanupRule{
+		{Name: "npm Cache", Description: "Delete npm cache.", Explanation: "English"},
+		{Name: "Go Build Cache", Description: "Delete Go build cache.", RebuildCost: RebuildCost{Level: RebuildLow, Description: "Recompiles."}},
+		{Name: "My Rule", Description: "Mine", Explanation: "Mine explained"},
+		{Name: "Untranslated", Description: "Stays"},
+	}, messages)
+
+	if list[0].Description != "npm weg" || list[0].Explanation != "English" {
+		t.Errorf("user override = %+v", list[0])
+	}
+	if list[1].Description == "Delete Go build cache." || list[1].RebuildCost.Description == "Recompiles." {
+		t.Errorf("bundled translation not applied: %+v", list[1])
+	}
+	if list[2].Description != "Mine" || list[2].Explanation != "Meine Erklärung" {
+		t.Errorf("custom rule = %+v", list[2])
+	}
+	if list[3].Description != "Stays" {
+		t.Errorf("untranslated rule changed: %+v", list[3])
+	}
+
+	if messages, _ := LoadMessages("en"); len(messages) != 0 {
+		t.Errorf("English has %d translations, want none", len(messages))
+	}
+}
+
+// Every bundled translation must name a built-in rule.
+func TestBundledLocales(t *testing.T) {
+	builtin := &Registry{}
+	builtin.registerDefaultRules()
+	names := make(map[string]bool)
+	for _, r := range append(builtin.rules, windowsRules()...) {
+		names[r.Name] = true
+	}
+
+	files, err := bundledLocales.ReadDir("locales")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		data, _ := bundledLocales.ReadFile("locales/" + f.Name())
+		var file messageFile
+		if err := json.Unmarshal(data, &file); err != nil {
+			t.Errorf("%s: %v", f.Name(), err)
+			continue
+		}
+		for name := range file.Rules {
+			if !names[name] {
+				t.Errorf("%s translates unknown rule %q", f.Name(), name)
+			}
+		}
+	}
+}
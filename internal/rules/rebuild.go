@@ -0,0 +1,67 @@
+package rules
+
+import "sort"
+
+// RebuildLevel grades how costly it is to get cleaned data back.
+type RebuildLevel string
+
+const (
+	RebuildNone RebuildLevel = "None" // Nothing is rebuilt or re-downloaded
+	RebuildLow  RebuildLevel = "Low"  // Refilled quickly, or only when needed
+	RebuildHigh RebuildLevel = "High" // Large re-downloads or long rebuilds
+)
+
+// RebuildCost describes what it takes to get a rule's data back after
+// cleaning, e.g. "Gradle will re-download all dependencies".
+type RebuildCost struct {
+	Level       RebuildLevel `json:"level"`
+	Description string       `json:"description,omitempty"`
+}
+
+// weight discounts the value of cleaning by the rebuild cost. Rules that
+// don't say are treated as Low.
+func (c RebuildCost) weight() float64 {
+	switch c.Level {
+	case RebuildNone:
+		return 1
+	case RebuildHigh:
+		return 6
+	default:
+		return 2
+	}
+}
+
+// Value ranks a result for recommendation: its size, discounted by how
+// costly the data is to get back.
+func (r Result) Value() float64 {
+	return float64(r.TotalSize) / r.Rule.RebuildCost.weight()
+}
+
+// SortBySize orders results by size, largest first. This is the order scans
+// keep their results in and number them by, so 'clean --ids' picks the
+// results a listing showed no matter how it was displayed.
+func SortBySize(results []Result) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].TotalSize != results[j].TotalSize {
+			return results[i].TotalSize > results[j].TotalSize
+		}
+		return results[i].Rule.Name < results[j].Rule.Name
+	})
+}
+
+// ByValue returns the indices of results in display order: large results
+// that are cheap to rebuild first. results itself is left as it is.
+func ByValue(results []Result) []int {
+	order := make([]int, len(results))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ri, rj := results[order[a]], results[order[b]]
+		if vi, vj := ri.Value(), rj.Value(); vi != vj {
+			return vi > vj
+		}
+		return ri.Rule.Name < rj.Rule.Name
+	})
+	return order
+}
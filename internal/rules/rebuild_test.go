@@ -0,0 +1,53 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestByValue(t *testing.T) {
+	const gb = 1 << 30
+	results := []Result{
+		{Rule: CleanupRule{Name: "Gradle", RebuildCost: RebuildCost{Level: RebuildHigh}}, TotalSize: 10 * gb},
+		{Rule: CleanupRule{Name: "npm", RebuildCost: RebuildCost{Level: RebuildLow}}, TotalSize: 4 * gb},
+		{Rule: CleanupRule{Name: "Logs", RebuildCost: RebuildCost{Level: RebuildNone}}, TotalSize: 3 * gb},
+		{Rule: CleanupRule{Name: "Custom"}, TotalSize: 4 * gb},
+	}
+
+	var got []string
+	for _, i := range ByValue(results) {
+		got = append(got, results[i].Rule.Name)
+	}
+	want := []string{"Logs", "Custom", "npm", "Gradle"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("order = %v, want %v", got, want)
+	}
+	if results[0].Rule.Name != "Gradle" {
+		t.Error("ByValue must not reorder the results")
+	}
+
+	// The order results are kept and numbered in ignores rebuild cost
+	SortBySize(results)
+	want = []string{"Gradle", "Custom", "npm", "Logs"}
+	if strings.Join(names(results), ",") != strings.Join(want, ",") {
+		t.Errorf("order = %v, want %v", names(results), want)
+	}
+}
+
+func TestBuiltinRulesHaveRebuildCost(t *testing.T) {
+	r := &Registry{}
+	r.registerDefaultRules()
+	for _, rule := range append(r.All(), windowsRules()...) {
+		if rule.RebuildCost.Level == "" {
+			t.Errorf("%s has no rebuild cost", rule.Name)
+		}
+	}
+}
+
+func names(results []Result) []string {
+	var out []string
+	for _, r := range results {
+		out = append(out, r.Rule.Name)
+	}
+	return out
+}
@@ -1,5 +1,10 @@
 package rules
 
+import (
+	"runtime"
+	"strings"
+)
+
 // Registry manages the collection of cleanup rules.
 type Registry struct {
 	rules []CleanupRule
@@ -10,21 +15,45 @@ func NewRegistry() *Registry {
 	r := &Registry{}
 	r.registerDefaultRules()
 
-	// Load custom rules
-	if custom, err := LoadCustomRules(); err == nil && len(custom) > 0 {
-		r.rules = append(r.rules, custom...)
-	}
+	// Load custom rules; files that fail to parse are skipped
+	custom, _ := LoadCustomRules()
+	r.rules = append(r.rules, custom...)
+
+	// Translate rule texts; a user message file that fails to parse is
+	// skipped like a custom rule file
+	messages, _ := LoadMessages(locale)
+	r.rules = Localize(r.rules, messages)
 
 	return r
 }
 
+// NewRegistryFromRules creates a registry containing only the given rules.
+func NewRegistryFromRules(rules []CleanupRule) *Registry {
+	return &Registry{rules: rules}
+}
+
 // All returns all registered cleanup rules.
 func (r *Registry) All() []CleanupRule {
 	return r.rules
 }
 
+// Find returns the rule named name, matched case-insensitively.
+func (r *Registry) Find(name string) (CleanupRule, bool) {
+	for _, rule := range r.rules {
+		if strings.EqualFold(rule.Name, name) {
+			return rule, true
+		}
+	}
+	return CleanupRule{}, false
+}
+
 // registerDefaultRules populates the registry with built-in rules.
 func (r *Registry) registerDefaultRules() {
+	if runtime.GOOS == "windows" {
+		r.rules = windowsRules()
+		return
+	}
+
 	r.rules = []CleanupRule{
 		// Package Managers
 		{
@@ -33,7 +62,10 @@ func (r *Registry) registerDefaultRules() {
 			Paths:        []string{"~/Library/Caches/Homebrew"},
 			RiskLevel:    RiskSafe,
 			Description:  "Delete downloaded Homebrew formulae and bottles.",
+			Stores:       "Downloaded source archives and pre-built bottles of Homebrew formulae and casks.",
+			References:   []string{"https://docs.brew.sh/Manpage"},
 			Explanation:  "Homebrew caches downloaded source code and pre-compiled binaries (bottles). Deleting this will reclaim space without affecting installed software. Homebrew will simply re-download what it needs during the next update or install.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "Homebrew re-downloads bottles only when installing or upgrading."},
 			RuleVersion:  "1.0.0",
 			IntroducedIn: "0.1.0",
 		},
@@ -43,7 +75,10 @@ func (r *Registry) registerDefaultRules() {
 			Paths:        []string{"~/.npm/_cacache", "~/.npm/_logs"},
 			RiskLevel:    RiskSafe,
 			Description:  "Delete npm global cache and logs.",
+			Stores:       "Package tarballs and registry metadata fetched by npm, plus debug logs of past installs.",
+			References:   []string{"https://docs.npmjs.com/cli/commands/npm-cache"},
 			Explanation:  "The npm cache stores package data to avoid redundant network requests. Deleting it is safe because npm handles missing cache entries by fetching them from the registry. It also removes debug logs which are only useful for troubleshooting failed installs.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "npm re-downloads packages on the next install."},
 			RuleVersion:  "1.0.0",
 			IntroducedIn: "0.1.0",
 		},
@@ -53,7 +88,10 @@ func (r *Registry) registerDefaultRules() {
 			Paths:        []string{"~/Library/Caches/pip"},
 			RiskLevel:    RiskSafe,
 			Description:  "Delete pip package cache.",
+			Stores:       "Wheels and source distributions downloaded or built by pip.",
+			References:   []string{"https://pip.pypa.io/en/stable/topics/caching/"},
 			Explanation:  "Python's pip tool caches wheels and source distributions to speed up re-installation of the same versions. Deleting this cache is safe; pip will re-download the packages from PyPI as needed.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "pip re-downloads packages on the next install."},
 			RuleVersion:  "1.0.0",
 			IntroducedIn: "0.1.0",
 		},
@@ -63,19 +101,26 @@ func (r *Registry) registerDefaultRules() {
 			Paths:        []string{"~/.cargo/registry/cache", "~/.cargo/registry/index"},
 			RiskLevel:    RiskSafe,
 			Description:  "Delete Rust cargo registry cache.",
+			Stores:       "The crates.io index and downloaded crate archives and sources.",
+			References:   []string{"https://doc.rust-lang.org/cargo/guide/cargo-home.html"},
 			Explanation:  "Cargo caches registry index metadata and downloaded crate source files. While deleting this saves space, the next 'cargo build' will involve a 'Updating crates.io index' step followed by re-downloading dependencies. It has zero impact on compiled binaries.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "The next cargo build re-downloads the crates.io index and dependencies."},
 			RuleVersion:  "1.0.0",
 			IntroducedIn: "0.1.0",
 		},
 		{
-			Name:         "Go Module Cache",
-			Category:     "Package Managers",
-			Paths:        []string{"~/go/pkg/mod"},
-			RiskLevel:    RiskSafe,
-			Description:  "Delete Go module cache.",
-			Explanation:  "Determined by GOMODCACHE, this directory holds downloaded modules. Deleting it forces a redownload of dependencies on the next build, which is safe but consumes bandwidth.",
-			RuleVersion:  "1.1.0",
-			IntroducedIn: "0.2.0",
+			Name:           "Go Module Cache",
+			Category:       "Package Managers",
+			Paths:          []string{"~/go/pkg/mod"},
+			RiskLevel:      RiskSafe,
+			Description:    "Delete Go module cache.",
+			Stores:         "Downloaded Go module sources and their checksums (GOMODCACHE).",
+			References:     []string{"https://go.dev/ref/mod#module-cache"},
+			Explanation:    "Determined by GOMODCACHE, this directory holds downloaded modules. Deleting it forces a redownload of dependencies on the next build, which is safe but consumes bandwidth.",
+			RebuildCost:    RebuildCost{Level: RebuildLow, Description: "Go re-downloads each project's modules on its next build."},
+			RuleVersion:    "1.1.0",
+			IntroducedIn:   "0.2.0",
+			BypassGitCheck: true,
 		},
 		{
 			Name:         "Yarn Cache",
@@ -83,7 +128,10 @@ func (r *Registry) registerDefaultRules() {
 			Paths:        []string{"~/Library/Caches/Yarn"},
 			RiskLevel:    RiskSafe,
 			Description:  "Delete Yarn package cache.",
+			Stores:       "Every package version Yarn has downloaded.",
+			References:   []string{"https://classic.yarnpkg.com/en/docs/cli/cache"},
 			Explanation:  "Yarn stores every downloaded package in a global cache. Deleting this frees up space but will make future yarn installs slower until the cache is repopulated.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "Yarn re-downloads packages on the next install."},
 			RuleVersion:  "1.1.0",
 			IntroducedIn: "0.2.0",
 		},
@@ -93,7 +141,9 @@ func (r *Registry) registerDefaultRules() {
 			Paths:        []string{"~/Library/Caches/CocoaPods"},
 			RiskLevel:    RiskSafe,
 			Description:  "Delete CocoaPods cache.",
+			Stores:       "Downloaded pod specs and pod sources.",
 			Explanation:  "CocoaPods caches pod specs and sources. Cleaning this directory is safe and useful for resolving pod installation issues.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "CocoaPods re-downloads pods on the next pod install."},
 			RuleVersion:  "1.1.0",
 			IntroducedIn: "0.2.0",
 		},
@@ -103,7 +153,9 @@ func (r *Registry) registerDefaultRules() {
 			Paths:        []string{"~/.composer/cache"},
 			RiskLevel:    RiskSafe,
 			Description:  "Delete PHP Composer cache.",
+			Stores:       "Downloaded PHP package archives and repository metadata.",
 			Explanation:  "Composer caches downloaded PHP packages. Safe to delete; packages will be re-downloaded as needed.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "Composer re-downloads packages on the next install."},
 			RuleVersion:  "1.1.0",
 			IntroducedIn: "0.2.0",
 		},
@@ -113,8 +165,11 @@ func (r *Registry) registerDefaultRules() {
 			Paths:        []string{"~/.gem/specs"},
 			RiskLevel:    RiskSafe,
 			Description:  "Delete Ruby Gem specs cache.",
+			Stores:       "Gem specification files fetched from RubyGems.",
 			Explanation:  "Caches spec files for RubyGems. Safe to delete.",
-			RuleVersion:  "1.1.0",
+			MinSizeMB:    1,
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "RubyGems re-fetches specs on the next install."},
+			RuleVersion:  "1.2.0",
 			IntroducedIn: "0.2.0",
 		},
 
@@ -122,11 +177,14 @@ func (r *Registry) registerDefaultRules() {
 		{
 			Name:         "Xcode DerivedData",
 			Category:     "Developer Tools",
-			Paths:        []string{"~/Library/Developer/Xcode/DerivedData"},
+			Paths:        []string{"~/Library/Developer/Xcode/DerivedData/*"},
 			RiskLevel:    RiskSafe,
 			Description:  "Delete Xcode build artifacts and indexes.",
-			Explanation:  "DerivedData contains intermediate build products, debug symbols, and module caches. It is the most common source of 'ghost bugs' in Xcode. Deleting it is safe and often recommended; Xcode will rebuild everything from scratch and re-index your projects.",
-			RuleVersion:  "1.0.0",
+			Stores:       "Intermediate build products, indexes, logs, and module caches of Xcode projects.",
+			Explanation:  "DerivedData contains intermediate build products, debug symbols, and module caches. It is the most common source of 'ghost bugs' in Xcode. Deleting it is safe and often recommended; Xcode will rebuild everything from scratch and re-index your projects. Each project is listed separately with when Xcode last used it, so stale ones can be cleaned alone.",
+			RebuildCost:  RebuildCost{Level: RebuildHigh, Description: "Xcode rebuilds and re-indexes every project from scratch."},
+			Items:        ItemsXcodeProject,
+			RuleVersion:  "1.1.0",
 			IntroducedIn: "0.1.0",
 		},
 		{
@@ -135,29 +193,108 @@ func (r *Registry) registerDefaultRules() {
 			Paths:        []string{"~/Library/Developer/CoreSimulator/Devices"},
 			RiskLevel:    RiskCaution,
 			Description:  "Delete all Xcode Simulator devices.",
+			Stores:       "Simulated iOS, watchOS, and tvOS devices with the apps and data installed on them.",
 			Explanation:  "This deletes all simulated iOS/watchOS/tvOS devices. You will lose any apps installed on them and their data. Xcode will recreate fresh, empty simulators the next time you launch it or run a test.",
+			RebuildCost:  RebuildCost{Level: RebuildHigh, Description: "Simulators are recreated empty; apps and data on them are gone."},
 			RuleVersion:  "1.1.0",
 			IntroducedIn: "0.2.0",
 		},
+		{
+			Name:         "Simulator dyld Cache",
+			Category:     "Developer Tools",
+			Paths:        []string{"~/Library/Developer/CoreSimulator/Caches/dyld"},
+			RiskLevel:    RiskSafe,
+			Description:  "Delete the shared library caches built for simulator runtimes.",
+			Stores:       "dyld shared caches that CoreSimulator builds for each simulator runtime and Xcode version.",
+			Explanation:  "CoreSimulator builds a dyld shared cache for every runtime it boots, and caches for runtimes and Xcode versions you no longer use are never removed. They are often several gigabytes each. Deleting them is safe; the next simulator boot rebuilds the cache it needs.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "The first boot of each simulator runtime is slower while its cache is rebuilt."},
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.5.0",
+		},
+		{
+			Name:         "Simulator Runtimes",
+			Category:     "Developer Tools",
+			Paths:        []string{"/Library/Developer/CoreSimulator/Profiles/Runtimes/*.simruntime"},
+			RiskLevel:    RiskManual,
+			Description:  "Report the space used by downloaded simulator runtimes.",
+			Stores:       "iOS, watchOS, tvOS, and visionOS runtimes downloaded for the simulator.",
+			Explanation:  "Each simulator runtime takes several gigabytes, and runtimes of old Xcode versions stay installed until removed. They are owned by root and registered with CoreSimulator, so Burrow only reports them, even with 'clean --system'. List them with 'xcrun simctl runtime list' and remove one with 'sudo xcrun simctl runtime delete <id>', or under Xcode > Settings > Platforms.",
+			RebuildCost:  RebuildCost{Level: RebuildHigh, Description: "A removed runtime is downloaded again, several GB, before its simulators can run."},
+			Items:        ItemsSimulatorRuntime,
+			ReportOnly:   true,
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.5.0",
+		},
 		{
 			Name:         "Android Build Cache",
 			Category:     "Developer Tools",
 			Paths:        []string{"~/.android/build-cache"},
 			RiskLevel:    RiskSafe,
 			Description:  "Delete Android SDK build cache.",
+			Stores:       "Pre-dexed libraries and other outputs of the Android Gradle plugin.",
 			Explanation:  "The Android build cache stores pre-dexed libraries and other build artifacts. Deleting it is safe; the Android Gradle plugin will re-populate it during subsequent builds.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "The next Android build is slower while the cache refills."},
 			RuleVersion:  "1.0.0",
 			IntroducedIn: "0.1.0",
 		},
 		{
-			Name:         "Gradle Cache",
+			Name:         "Android Virtual Devices",
 			Category:     "Developer Tools",
-			Paths:        []string{"~/.gradle/caches"},
-			RiskLevel:    RiskCaution,
-			Description:  "Delete Gradle dependency caches.",
-			Explanation:  "This directory contains all JARs and artifacts downloaded by Gradle. While safe from a data integrity perspective, deleting it will force every project to re-download all dependencies, which can be extremely slow and consume significant bandwidth.",
+			Paths:        []string{"~/.android/avd/*.avd"},
+			RiskLevel:    RiskManual,
+			Description:  "Inspect the space used by Android emulator devices.",
+			Stores:       "The disk images, SD cards, and snapshots of Android emulator devices.",
+			References:   []string{"https://developer.android.com/studio/command-line/avdmanager"},
+			Explanation:  "Each virtual device keeps its user data, SD card, and snapshots, often 5 to 15 GB. Devices are listed one by one with when they last booted. Deleting one loses the apps and data installed on it. Prefer Android Studio's Device Manager or 'avdmanager delete avd -n <name>', which also removes the .ini file next to the folder; cleaning the folder alone leaves a broken entry that Device Manager offers to delete.",
+			RebuildCost:  RebuildCost{Level: RebuildHigh, Description: "The device has to be created again, and the apps and data on it are gone."},
+			Items:        ItemsAndroidAVD,
 			RuleVersion:  "1.0.0",
-			IntroducedIn: "0.1.0",
+			IntroducedIn: "0.5.0",
+		},
+		{
+			Name:     "Android System Images",
+			Category: "Developer Tools",
+			Paths: []string{
+				"~/Library/Android/sdk/system-images/*/*/*",
+				"~/Android/Sdk/system-images/*/*/*",
+			},
+			RiskLevel:    RiskManual,
+			Description:  "Inspect SDK system images that no virtual device uses.",
+			Stores:       "Android system images downloaded for the emulator, one per API level, variant, and ABI.",
+			References:   []string{"https://developer.android.com/tools/sdkmanager"},
+			Explanation:  "The SDK keeps every system image ever downloaded, 1 to 5 GB each, long after the devices using them are gone. Only images no virtual device boots from are listed. Remove one with 'sdkmanager --uninstall \"system-images;android-34;google_apis;arm64-v8a\"' or in Android Studio's SDK Manager, which keeps its package list in sync.",
+			RebuildCost:  RebuildCost{Level: RebuildHigh, Description: "The image is downloaded again before a device can use it."},
+			Items:        ItemsAndroidSystemImage,
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.5.0",
+		},
+		{
+			Name:         "Android Emulator Snapshots",
+			Category:     "Developer Tools",
+			Paths:        []string{"~/.android/avd/*.avd/snapshots/*"},
+			RiskLevel:    RiskManual,
+			Description:  "Inspect the space used by Android emulator snapshots.",
+			Stores:       "Saved emulator states, including the Quick Boot snapshot taken when a device closes.",
+			References:   []string{"https://developer.android.com/studio/run/emulator-snapshots"},
+			Explanation:  "Each snapshot saves a device's full memory and disk state, often 1 to 4 GB. Deleting one is safe for the device itself: a missing Quick Boot snapshot (default_boot) only means the next start is a cold boot, but named snapshots you saved are gone for good. You can also delete them in the emulator's Extended Controls > Snapshots.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "The next start is a cold boot; named snapshots cannot be recreated."},
+			Items:        ItemsAndroidSnapshot,
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.5.0",
+		},
+		{
+			Name:           "Gradle Cache",
+			Category:       "Developer Tools",
+			Paths:          []string{"~/.gradle/caches"},
+			RiskLevel:      RiskCaution,
+			Description:    "Delete Gradle dependency caches.",
+			Stores:         "Dependency JARs and artifacts downloaded by Gradle, and its build caches.",
+			References:     []string{"https://docs.gradle.org/current/userguide/directory_layout.html"},
+			Explanation:    "This directory contains all JARs and artifacts downloaded by Gradle. While safe from a data integrity perspective, deleting it will force every project to re-download all dependencies, which can be extremely slow and consume significant bandwidth.",
+			RebuildCost:    RebuildCost{Level: RebuildHigh, Description: "Gradle will re-download all dependencies, often several GB, on the next build."},
+			RuleVersion:    "1.0.0",
+			IntroducedIn:   "0.1.0",
+			BypassGitCheck: true,
 		},
 		{
 			Name:         "Go Build Cache",
@@ -165,19 +302,43 @@ func (r *Registry) registerDefaultRules() {
 			Paths:        []string{"~/.cache/go-build"},
 			RiskLevel:    RiskSafe,
 			Description:  "Delete Go build cache.",
+			Stores:       "Compiled Go packages and test results (GOCACHE).",
+			References:   []string{"https://pkg.go.dev/cmd/go#hdr-Build_and_test_caching"},
 			Explanation:  "Go caches compiled packages to speed up builds. Deleting this is safe but will make the next build roughly as slow as a fresh build.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "The next go build or go test recompiles packages."},
 			RuleVersion:  "1.1.0",
 			IntroducedIn: "0.2.0",
 		},
 		{
-			Name:         "JetBrains IDE Caches",
-			Category:     "Developer Tools",
-			Paths:        []string{"~/Library/Caches/JetBrains"},
-			RiskLevel:    RiskCaution,
-			Description:  "Delete IntelliJ/WebStorm/Goland caches.",
-			Explanation:  "JetBrains IDEs store indexes and caches here. Deleting this will force the IDE to re-index all projects upon next launch, which can take significant time.",
-			RuleVersion:  "1.1.0",
-			IntroducedIn: "0.2.0",
+			Name:           "JetBrains IDE Caches",
+			Category:       "Developer Tools",
+			Paths:          []string{"~/Library/Caches/JetBrains"},
+			RiskLevel:      RiskCaution,
+			Description:    "Delete IntelliJ/WebStorm/Goland caches.",
+			Stores:         "Indexes and caches of IntelliJ-based IDEs.",
+			Explanation:    "JetBrains IDEs store indexes and caches here. Deleting this will force the IDE to re-index all projects upon next launch, which can take significant time.",
+			RebuildCost:    RebuildCost{Level: RebuildLow, Description: "IDEs re-index open projects on the next start."},
+			RuleVersion:    "1.1.0",
+			IntroducedIn:   "0.2.0",
+			BypassGitCheck: true,
+		},
+		{
+			Name:     "JetBrains Old Versions",
+			Category: "Developer Tools",
+			Paths: []string{
+				"~/Library/Caches/JetBrains/*",
+				"~/Library/Application Support/JetBrains/*",
+				"~/Library/Logs/JetBrains/*",
+			},
+			RiskLevel:      RiskCaution,
+			Description:    "Delete the caches, settings, and plugins of outdated JetBrains IDE versions.",
+			Stores:         "Per-version caches, settings, plugins, and logs of IntelliJ-based IDEs, such as GoLand2022.3.",
+			Explanation:    "Every JetBrains IDE release gets its own directories, and upgrades import the old settings without removing them, so versions pile up at a few GB each. Only versions older than the newest one of the same IDE are listed; the newest is always kept. Settings and plugins of an old version are gone once cleaned, so keep any version you still run.",
+			RebuildCost:    RebuildCost{Level: RebuildLow, Description: "Nothing, unless you go back to an old version, which then starts with default settings."},
+			Items:          ItemsJetBrainsVersion,
+			RuleVersion:    "1.0.0",
+			IntroducedIn:   "0.5.0",
+			BypassGitCheck: true,
 		},
 		{
 			Name:     "VS Code Cache",
@@ -190,11 +351,144 @@ func (r *Registry) registerDefaultRules() {
 			},
 			RiskLevel:    RiskCaution,
 			Description:  "Delete VS Code caches and workspace storage.",
+			Stores:       "VS Code's web caches and per-workspace state such as UI layout and opened files.",
 			Explanation:  "Deletes generic caches and cached workspace data. Deleting workspaceStorage will not delete your code, but may reset local workspace state (UI layout, opened files history) for projects. Useful if VS Code is acting buggy.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "VS Code refills its caches as you use it."},
 			RuleVersion:  "1.1.0",
 			IntroducedIn: "0.2.0",
 		},
 
+		// Language Servers
+		{
+			Name:         "gopls Cache",
+			Category:     "Language Servers",
+			Paths:        []string{"~/Library/Caches/gopls", "~/.cache/gopls"},
+			RiskLevel:    RiskSafe,
+			Description:  "Delete the Go language server's index cache.",
+			Stores:       "Type-checking and cross-reference data of the Go language server.",
+			Explanation:  "gopls caches type-checking and cross-reference data for every module and Go version it has opened. Old entries are never pruned, so the cache grows with each Go release. Deleting it is safe; gopls rebuilds what it needs the next time an editor opens a Go file.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "gopls re-indexes on the next editor start."},
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.4.0",
+		},
+		{
+			Name:         "rust-analyzer Cache",
+			Category:     "Language Servers",
+			Paths:        []string{"~/Library/Caches/rust-analyzer", "~/.cache/rust-analyzer"},
+			RiskLevel:    RiskSafe,
+			Description:  "Delete rust-analyzer's proc-macro and sysroot caches.",
+			Stores:       "Build script and proc-macro outputs and sysroot metadata of rust-analyzer.",
+			Explanation:  "rust-analyzer keeps build script and proc-macro outputs and sysroot metadata here. Deleting it only makes the first analysis of each workspace slower; projects and their target directories are untouched.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "rust-analyzer re-indexes on the next editor start."},
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.4.0",
+		},
+		{
+			Name:         "clangd Index",
+			Category:     "Language Servers",
+			Paths:        []string{"~/Library/Caches/clangd", "~/.cache/clangd"},
+			RiskLevel:    RiskSafe,
+			Description:  "Delete clangd's background index for files outside a project.",
+			Stores:       "clangd's background index of headers and sources outside any project.",
+			Explanation:  "clangd stores the background index of headers and sources that don't belong to a project (such as SDK and system headers) in its user cache. Project indexes in each project's .cache/clangd folder are not affected. clangd re-indexes in the background after deletion.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "clangd re-indexes on the next editor start."},
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.4.0",
+		},
+		{
+			Name:         "TypeScript Server Cache",
+			Category:     "Language Servers",
+			Paths:        []string{"~/Library/Caches/typescript", "~/.cache/typescript"},
+			RiskLevel:    RiskSafe,
+			Description:  "Delete type definitions downloaded by tsserver.",
+			Stores:       "@types packages downloaded by tsserver for JavaScript projects, per TypeScript version.",
+			Explanation:  "The TypeScript server downloads @types packages for JavaScript projects (automatic type acquisition) into one folder per TypeScript version, and never removes versions that are no longer used. Deleting it is safe; editors fetch the types again when needed.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "Type definitions are re-downloaded as projects are opened."},
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.4.0",
+		},
+		{
+			Name:     "Metals & Bloop Caches",
+			Category: "Language Servers",
+			Paths: []string{
+				"~/Library/Caches/org.scalameta.metals",
+				"~/Library/Caches/bloop",
+				"~/.cache/metals",
+				"~/.cache/bloop",
+			},
+			RiskLevel:    RiskSafe,
+			Description:  "Delete caches of the Scala language server and its build server.",
+			Stores:       "Downloaded Metals server versions, indexes, and Bloop compiler bridges.",
+			Explanation:  "Metals caches downloaded server versions and indexes, and Bloop caches compiler bridges and downloaded artifacts. Project .metals and .bloop folders are not affected. Both download and rebuild what they need on the next import.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "Metals and Bloop re-index and recompile on the next import."},
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.4.0",
+		},
+
+		// Machine Learning
+		{
+			Name:     "Hugging Face Models",
+			Category: "Machine Learning",
+			Paths: []string{
+				"~/.cache/huggingface/hub/models--*",
+				"~/.cache/huggingface/hub/datasets--*",
+			},
+			RiskLevel:    RiskCaution,
+			Description:  "Delete models and datasets downloaded from the Hugging Face Hub.",
+			Stores:       "Model and dataset revisions downloaded from the Hugging Face Hub.",
+			References:   []string{"https://huggingface.co/docs/huggingface_hub/guides/manage-cache"},
+			Explanation:  "transformers, diffusers, and the huggingface_hub library keep every model and dataset revision they download, often several gigabytes each. Each one is listed separately so you can see which are worth keeping. Deleting them is safe, but the next run that needs a model downloads it again in full.",
+			RebuildCost:  RebuildCost{Level: RebuildHigh, Description: "Models are re-downloaded, often several GB each, the next time they are loaded."},
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.4.0",
+		},
+		{
+			Name:         "Ollama Models",
+			Category:     "Machine Learning",
+			Paths:        []string{"~/.ollama/models"},
+			RiskLevel:    RiskManual,
+			Description:  "Inspect the space used by local Ollama models.",
+			Stores:       "Model manifests and the layer blobs they share.",
+			References:   []string{"https://github.com/ollama/ollama/blob/main/docs/faq.md"},
+			Explanation:  "Ollama stores model layers as shared blobs, so deleting files by hand can break other models. Burrow only reports the total; run 'ollama list' to see each model's size and 'ollama rm <model>' to remove the ones you no longer use.",
+			RebuildCost:  RebuildCost{Level: RebuildHigh, Description: "Each model has to be pulled again with 'ollama pull'."},
+			ReportOnly:   true,
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.4.0",
+		},
+		{
+			Name:     "PyTorch Hub Checkpoints",
+			Category: "Machine Learning",
+			Paths: []string{
+				"~/Library/Caches/torch/hub/checkpoints/*",
+				"~/.cache/torch/hub/checkpoints/*",
+			},
+			RiskLevel:    RiskCaution,
+			Description:  "Delete pretrained weights downloaded by torch.hub and torchvision.",
+			Stores:       "Pretrained weights downloaded by torch.hub and torchvision.",
+			References:   []string{"https://pytorch.org/docs/stable/hub.html"},
+			Explanation:  "torch.hub and torchvision download pretrained weights into the checkpoints folder on first use and never remove them. Each file is listed separately. Deleting them is safe; they are downloaded again the next time a model is loaded with pretrained weights.",
+			RebuildCost:  RebuildCost{Level: RebuildHigh, Description: "Checkpoints are re-downloaded the next time a model is loaded."},
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.4.0",
+		},
+		{
+			Name:     "CUDA Wheels (pip)",
+			Category: "Machine Learning",
+			Paths: []string{
+				"~/.local/lib/python3.*/site-packages/nvidia",
+				"~/.local/lib/python3.*/site-packages/nvidia_*",
+			},
+			RiskLevel:    RiskManual,
+			Description:  "Inspect NVIDIA CUDA libraries installed with pip --user.",
+			Stores:       "Installed nvidia-* packages with the CUDA libraries that PyTorch and TensorFlow use.",
+			Explanation:  "PyTorch and TensorFlow pull in nvidia-* wheels (cuDNN, cuBLAS, NCCL, ...) that can take several gigabytes per Python version. They are installed packages, not caches, so Burrow only reports them; remove them with 'pip uninstall' once no framework needs them.",
+			RebuildCost:  RebuildCost{Level: RebuildHigh, Description: "pip re-downloads several GB of CUDA wheels on the next install."},
+			ReportOnly:   true,
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.4.0",
+		},
+
 		// System
 		{
 			Name:         "User Caches",
@@ -202,7 +496,9 @@ func (r *Registry) registerDefaultRules() {
 			Paths:        []string{"~/Library/Caches"},
 			RiskLevel:    RiskCaution,
 			Description:  "Delete general application caches.",
+			Stores:       "Caches that applications keep for the current user.",
 			Explanation:  "General macOS application caches. While most apps handle missing caches gracefully, some may experience temporary performance degradation or lose local-only state (like unsynced drafts or transient UI preferences). Use with caution.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "Apps refill their caches as they run; some may sign you out."},
 			RuleVersion:  "1.0.0",
 			IntroducedIn: "0.1.0",
 		},
@@ -210,28 +506,98 @@ func (r *Registry) registerDefaultRules() {
 			Name:     "Electron App Caches",
 			Category: "System",
 			Paths: []string{
-				"~/Library/Application Support/Slack/Cache",
-				"~/Library/Application Support/Slack/Code Cache",
-				"~/Library/Application Support/Discord/Cache",
-				"~/Library/Application Support/Discord/Code Cache",
+				"~/Library/Application Support/*/Cache",
+				"~/Library/Application Support/*/Code Cache",
+				"~/Library/Application Support/*/GPUCache",
+				"~/Library/Application Support/*/*/Cache",
+				"~/Library/Application Support/*/*/Code Cache",
+				"~/Library/Application Support/*/*/GPUCache",
 			},
 			RiskLevel:    RiskSafe,
-			Description:  "Delete caches for Slack and Discord.",
-			Explanation:  "Electron apps (Slack, Discord) tend to accumulate large amounts of cache data over time. Deleting these is generally safe and forces the apps to fetch fresh data, often resolving UI glitches.",
-			RuleVersion:  "1.1.0",
+			Description:  "Delete the web caches of Electron apps such as Slack, Teams, Notion, and Figma.",
+			Stores:       "Web, V8 code, and GPU shader caches of Electron apps.",
+			Explanation:  "Electron apps (Slack, Discord, Teams, Notion, Figma, Spotify, and many more) each embed Chromium and accumulate large caches over time. Burrow finds them by their Chromium cache folders (Code Cache alongside Cache or GPUCache) and lists each app separately. Deleting these is generally safe and forces the apps to fetch fresh data, often resolving UI glitches.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "Apps re-fetch their data on the next start."},
+			Items:        ItemsElectronApp,
+			RuleVersion:  "2.0.0",
 			IntroducedIn: "0.2.0",
 		},
+		{
+			Name:     "Browser GPU Caches",
+			Category: "Browsers",
+			Paths: []string{
+				"~/Library/Application Support/Google/Chrome/*/GPUCache",
+				"~/Library/Application Support/Microsoft Edge/*/GPUCache",
+				"~/Library/Application Support/Arc/User Data/*/GPUCache",
+			},
+			RiskLevel:    RiskSafe,
+			Description:  "Delete the GPU shader caches of Chrome, Edge, and Arc profiles.",
+			Stores:       "Compiled GPU shaders of each browser profile.",
+			Explanation:  "Chromium browsers keep a GPU shader cache in every profile, which grows with each site and installed web app. Burrow lists each profile separately. The browser recompiles shaders as pages need them; quit it first so it does not write to the cache while it is cleaned.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "Shaders are recompiled as pages are drawn."},
+			Items:        ItemsBrowserProfile,
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.5.0",
+		},
+		{
+			Name:     "Browser Service Worker Caches",
+			Category: "Browsers",
+			Paths: []string{
+				"~/Library/Application Support/Google/Chrome/*/Service Worker/CacheStorage",
+				"~/Library/Application Support/Microsoft Edge/*/Service Worker/CacheStorage",
+				"~/Library/Application Support/Arc/User Data/*/Service Worker/CacheStorage",
+			},
+			RiskLevel:    RiskCaution,
+			Description:  "Delete the service worker caches of Chrome, Edge, and Arc profiles.",
+			Stores:       "Responses that sites and installed web apps (PWAs) cache for offline use.",
+			Explanation:  "Service workers cache whole sites so web apps load fast and work offline, and with a few PWAs installed this storage often reaches gigabytes per profile. It is separate from the browser's HTTP cache. Burrow lists each profile separately. Deleting it keeps your logins and settings, but web apps lose their offline content until they are opened online again. Quit the browser first.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "Web apps re-download their offline content the next time they are opened."},
+			Items:        ItemsBrowserProfile,
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.5.0",
+		},
+		{
+			Name:         "System Caches",
+			Category:     "System",
+			Paths:        []string{"/Library/Caches"},
+			RiskLevel:    RiskCaution,
+			Description:  "Delete system-wide application caches (requires root).",
+			Stores:       "Caches shared by system daemons and installers.",
+			Explanation:  "Shared caches written by system daemons and installers. They are rebuilt on demand, but cleaning them requires administrator privileges, so Burrow only touches them through 'burrow clean --system', which asks for sudo and records every path in the audit log.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "System daemons rebuild their caches on demand."},
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.3.0",
+			RequiresRoot: true,
+		},
 		{
 			Name:         "Temporary Files",
 			Category:     "System",
 			Paths:        []string{"/tmp", "/var/tmp"},
 			RiskLevel:    RiskSafe,
 			Description:  "Delete system temporary files.",
+			Stores:       "Short-lived files that programs write to the temporary directory.",
 			Explanation:  "Safe to delete. These files are typically temporary and short-lived.",
+			RebuildCost:  RebuildCost{Level: RebuildNone},
 			RuleVersion:  "1.0.0",
 			IntroducedIn: "0.1.0",
 		},
 
+		// Downloads
+		{
+			Name:            "Old Installers & Disk Images",
+			Category:        "Downloads",
+			Paths:           []string{"~/Downloads"},
+			IncludePatterns: []string{"*.dmg", "*.pkg", "*.iso"},
+			MinAgeDays:      30,
+			RiskLevel:       RiskSafe,
+			Description:     "Delete installers and disk images downloaded more than 30 days ago.",
+			Stores:          "Disk images and installer packages in ~/Downloads.",
+			Explanation:     "Disk images (.dmg, .iso) and installer packages (.pkg) are only needed while installing software. Once the app is installed they can be re-downloaded from the vendor at any time. Only files untouched for 30 days are selected, and nothing else in Downloads is affected.",
+			RebuildCost:     RebuildCost{Level: RebuildNone, Description: "Nothing needs them; download an installer again if you ever do."},
+			RuleVersion:     "1.0.0",
+			IntroducedIn:    "0.3.0",
+		},
+
 		// Containers (INSPECTION ONLY for MVP)
 		{
 			Name:         "Docker System Usage",
@@ -239,9 +605,91 @@ func (r *Registry) registerDefaultRules() {
 			Paths:        []string{"~/.docker"},
 			RiskLevel:    RiskManual,
 			Description:  "Inspect Docker configuration and context.",
+			Stores:       "Docker's configuration and client data; images and containers live in the VM disk.",
+			References:   []string{"https://docs.docker.com/reference/cli/docker/system/prune/"},
 			Explanation:  "Burrow tracks the configuration size. To clean actual containers and images, run 'docker system prune'. Burrow does not directly delete Docker artifacts to prevent accidental data loss of persistent volumes.",
+			RebuildCost:  RebuildCost{Level: RebuildHigh, Description: "Images are pulled and build caches rebuilt on the next docker build."},
 			RuleVersion:  "1.0.0",
 			IntroducedIn: "0.1.0",
 		},
+		{
+			Name:         "Docker Desktop Disk",
+			Category:     "Containers",
+			Paths:        []string{"~/Library/Containers/com.docker.docker/Data/vms/0/data/Docker.raw"},
+			RiskLevel:    RiskManual,
+			Description:  "Report the space used by Docker Desktop's VM disk.",
+			Stores:       "The virtual disk of Docker Desktop with every image, container, and volume.",
+			References:   []string{"https://docs.docker.com/reference/cli/docker/system/prune/"},
+			Explanation:  "Docker.raw holds every image, container, and volume. It is a sparse file, so only the space it really occupies is counted. Run 'docker system prune' (add --volumes to include unused volumes) and 'docker builder prune' to shrink it, or lower the disk limit under Settings > Resources.",
+			RebuildCost:  RebuildCost{Level: RebuildHigh, Description: "Images, containers, and volumes in the VM have to be pulled or recreated."},
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.4.0",
+			ReportOnly:   true,
+			SparseFiles:  true,
+		},
+		{
+			Name:     "Colima VM Disks",
+			Category: "Containers",
+			Paths: []string{
+				"~/.colima/_lima/*/diffdisk",
+				"~/.colima/_lima/_disks/*/datadisk",
+			},
+			RiskLevel:    RiskManual,
+			Description:  "Report the space used by Colima virtual machine disks.",
+			Stores:       "The virtual disks of Colima profiles with their images and containers.",
+			Explanation:  "Each Colima profile runs a VM whose sparse disk holds its images and containers. Run 'docker system prune' inside the profile to free space, 'colima prune' to remove cached downloads, or 'colima delete <profile>' to remove a profile you no longer use.",
+			RebuildCost:  RebuildCost{Level: RebuildHigh, Description: "Images, containers, and volumes in the VM have to be pulled or recreated."},
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.4.0",
+			ReportOnly:   true,
+			SparseFiles:  true,
+		},
+		{
+			Name:     "Lima VM Disks",
+			Category: "Containers",
+			Paths: []string{
+				"~/.lima/*/diffdisk",
+				"~/.lima/_disks/*/datadisk",
+			},
+			RiskLevel:    RiskManual,
+			Description:  "Report the space used by Lima virtual machine disks.",
+			Stores:       "The virtual disks of Lima instances.",
+			Explanation:  "Lima keeps a sparse disk per instance. Run 'limactl prune' to remove cached images, and 'limactl delete <instance>' to remove instances you no longer use.",
+			RebuildCost:  RebuildCost{Level: RebuildHigh, Description: "The VM and everything installed in it has to be recreated."},
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.4.0",
+			ReportOnly:   true,
+			SparseFiles:  true,
+		},
+
+		// Virtual Machines
+		{
+			Name:         "UTM Virtual Machines",
+			Category:     "Virtual Machines",
+			Paths:        []string{"~/Library/Containers/com.utmapp.UTM/Data/Documents/*.utm"},
+			RiskLevel:    RiskManual,
+			Description:  "Report the size of each UTM virtual machine.",
+			Stores:       "Whole UTM virtual machines, including their disks.",
+			Explanation:  "A .utm bundle holds a whole virtual machine, including its sparse disk images. Remove machines you no longer need from UTM itself. To shrink one, use 'Reclaim Space' on its drive in the VM settings, or compact the image with 'qemu-img convert -O qcow2'.",
+			RebuildCost:  RebuildCost{Level: RebuildHigh, Description: "A deleted virtual machine can only be rebuilt from scratch."},
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.4.0",
+			ReportOnly:   true,
+			SparseFiles:  true,
+		},
+		{
+			Name:         "Parallels Virtual Machines",
+			Category:     "Virtual Machines",
+			Paths:        []string{"~/Parallels/*.pvm"},
+			RiskLevel:    RiskManual,
+			Description:  "Report the size of each Parallels Desktop virtual machine.",
+			Stores:       "Whole Parallels virtual machines with their disks and snapshots.",
+			Explanation:  "A .pvm bundle holds a whole virtual machine with its disks and snapshots. Remove machines from the Parallels Control Center. To shrink one, use 'Free Up Disk Space' in its configuration, or run 'prl_disk_tool compact --hdd <disk.hdd>' while it is shut down.",
+			RebuildCost:  RebuildCost{Level: RebuildHigh, Description: "A deleted virtual machine can only be rebuilt from scratch."},
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.4.0",
+			ReportOnly:   true,
+			SparseFiles:  true,
+		},
 	}
 }
@@ -1,5 +1,15 @@
 package rules
 
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/safety"
+	"github.com/ismailtsdln/burrow/internal/units"
+)
+
 // RiskLevel represents the safety level of a cleanup rule.
 type RiskLevel string
 
@@ -9,6 +19,16 @@ const (
 	RiskManual  RiskLevel = "Manual"
 )
 
+// ParseRiskLevel matches name against the risk levels case-insensitively.
+func ParseRiskLevel(name string) (RiskLevel, error) {
+	for _, l := range []RiskLevel{RiskSafe, RiskCaution, RiskManual} {
+		if strings.EqualFold(string(l), strings.TrimSpace(name)) {
+			return l, nil
+		}
+	}
+	return "", fmt.Errorf("unknown risk level: %s (use Safe, Caution, or Manual)", name)
+}
+
 // CleanupRule defines a single cleanup operation.
 type CleanupRule struct {
 	Name         string    `json:"name"`
@@ -19,11 +39,181 @@ type CleanupRule struct {
 	Explanation  string    `json:"explanation"`
 	RuleVersion  string    `json:"rule_version"`
 	IntroducedIn string    `json:"introduced_in"`
+	RequiresRoot bool      `json:"requires_root,omitempty"`
+
+	// RebuildCost is what cleaning costs later in downloads and rebuilds;
+	// recommendations favor large results that are cheap to rebuild.
+	RebuildCost RebuildCost `json:"rebuild_cost,omitzero"`
+
+	// Stores says in a sentence what the rule's paths hold, and References
+	// link to the owning tool's documentation of that data.
+	Stores     string   `json:"stores,omitempty"`
+	References []string `json:"references,omitempty"`
+
+	// IncludePatterns turns the rule into a file-level rule: instead of the
+	// whole directory, only files whose names match one of the globs are selected.
+	IncludePatterns []string `json:"include_patterns,omitempty"`
+	// ExcludePatterns also makes the rule file-level: files whose names match
+	// one of the globs are kept, and matching directories are not descended.
+	ExcludePatterns []string `json:"exclude_patterns,omitempty"`
+	// MinAgeDays only selects files not modified for at least this many days.
+	MinAgeDays int `json:"min_age_days,omitempty"`
+	// MinAge is the same limit written as a duration such as "2w" or "3mo";
+	// it takes precedence over MinAgeDays.
+	MinAge units.Duration `json:"min_age,omitempty"`
+	// MinSizeMB hides the rule's result when its total is smaller, so trivial
+	// finds don't clutter the output; MinSize is the same limit as a size
+	// such as "50MB" and takes precedence.
+	MinSizeMB int64      `json:"min_size_mb,omitempty"`
+	MinSize   units.Size `json:"min_size,omitempty"`
+	// GitCheck is "scoped" (default: refuse paths inside a repository) or
+	// "deep" (also refuse paths that contain a repository anywhere below).
+	GitCheck string `json:"git_check,omitempty"`
+	// BypassGitCheck skips the Git guard entirely, for well-known caches whose
+	// paths may sit below a repository (e.g. a dotfiles repo in $HOME).
+	BypassGitCheck bool `json:"bypass_git_check,omitempty"`
+
+	// ReportOnly rules are listed by scans but never cleaned; their
+	// Explanation names the tool that reclaims the space safely.
+	ReportOnly bool `json:"report_only,omitempty"`
+	// SparseFiles counts the blocks allocated on disk instead of apparent
+	// file sizes, for sparse disk images that claim far more than they use.
+	SparseFiles bool `json:"sparse_files,omitempty"`
+	// Items says what each path of the rule holds when its paths are items
+	// of one kind, such as the per-project folders of Xcode's DerivedData,
+	// so scans can name each item and tell when it was last used.
+	Items string `json:"items,omitempty"`
+
+	// Source is the file a custom rule was loaded from; empty for built-ins.
+	Source string `json:"source,omitempty"`
+}
+
+// FileLevel reports whether the rule selects individual files rather than
+// its whole paths.
+func (r CleanupRule) FileLevel() bool {
+	return len(r.IncludePatterns) > 0 || len(r.ExcludePatterns) > 0
+}
+
+// MinAgeDuration returns the rule's file age limit, or 0 when it has none.
+func (r CleanupRule) MinAgeDuration() time.Duration {
+	if r.MinAge > 0 {
+		return time.Duration(r.MinAge)
+	}
+	return time.Duration(r.MinAgeDays) * units.Day
+}
+
+// SafetyOptions maps the rule's safety settings onto the safety package.
+func (r CleanupRule) SafetyOptions() safety.Options {
+	return safety.Options{Git: safety.GitCheck(r.GitCheck), BypassGit: r.BypassGitCheck}
 }
 
+// MinSizeBytes returns the smallest total the rule reports, or 0.
+func (r CleanupRule) MinSizeBytes() int64 {
+	if r.MinSize > 0 {
+		return int64(r.MinSize)
+	}
+	return r.MinSizeMB * units.MB
+}
+
+// Item kinds for CleanupRule.Items.
+const (
+	// ItemsXcodeProject is a DerivedData folder, named after its project.
+	ItemsXcodeProject = "xcode-project"
+	// ItemsSimulatorRuntime is a simulator runtime bundle, named after its
+	// platform and version.
+	ItemsSimulatorRuntime = "simulator-runtime"
+	// ItemsAndroidAVD is an Android virtual device, aged by its last boot.
+	ItemsAndroidAVD = "android-avd"
+	// ItemsAndroidSnapshot is an emulator snapshot of a virtual device.
+	ItemsAndroidSnapshot = "android-snapshot"
+	// ItemsAndroidSystemImage is an SDK system image; images a virtual
+	// device boots from are never listed.
+	ItemsAndroidSystemImage = "android-system-image"
+	// ItemsJetBrainsVersion is a versioned JetBrains IDE directory, such as
+	// GoLand2022.3; the newest version of each IDE is never listed.
+	ItemsJetBrainsVersion = "jetbrains-version"
+	// ItemsElectronApp is a Chromium cache folder of an Electron app, named
+	// after the app; folders of apps without the Electron cache layout are
+	// never listed.
+	ItemsElectronApp = "electron-app"
+	// ItemsBrowserProfile is a cache folder of a Chromium browser profile,
+	// named after the browser and the profile.
+	ItemsBrowserProfile = "browser-profile"
+)
+
 // Result represents the outcome of a scan for a specific rule.
 type Result struct {
 	Rule       CleanupRule `json:"rule"`
 	FoundPaths []string    `json:"found_paths"`
 	TotalSize  int64       `json:"total_size"`
+	Volume     string      `json:"volume,omitempty"` // Mount point, set when a path is on an external or network volume
+	Stats      []PathStats `json:"path_stats,omitempty"`
+}
+
+// PathStats summarizes the files counted under one scanned rule path. For
+// pattern rules it covers only the matching files.
+type PathStats struct {
+	Path      string    `json:"path"`
+	Size      int64     `json:"size"`
+	Allocated int64     `json:"allocated"` // Bytes allocated on disk
+	Files     int       `json:"files"`
+	Newest    time.Time `json:"newest,omitzero"`
+	Oldest    time.Time `json:"oldest,omitzero"`
+	// Kind is what the path itself was when it was scanned; see PathKind.
+	Kind string `json:"kind,omitempty"`
+	// Item names what the path holds when its rule lists items, and LastUsed
+	// is when the item was last used, if known.
+	Item     string    `json:"item,omitempty"`
+	LastUsed time.Time `json:"last_used,omitzero"`
+}
+
+// Kinds of scanned paths for PathStats.Kind.
+const (
+	KindDirectory = "directory"
+	KindFile      = "file"
+	KindSymlink   = "symlink"
+	KindOther     = "special file"
+)
+
+// PathKind returns the kind of a path with the given mode, as reported by
+// os.Lstat.
+func PathKind(mode fs.FileMode) string {
+	switch {
+	case mode.IsDir():
+		return KindDirectory
+	case mode.IsRegular():
+		return KindFile
+	case mode&fs.ModeSymlink != 0:
+		return KindSymlink
+	}
+	return KindOther
+}
+
+// Add accounts one file with the given size and modification time.
+func (s *PathStats) Add(size int64, modTime time.Time) {
+	s.Size += size
+	s.Files++
+	if s.Newest.IsZero() || modTime.After(s.Newest) {
+		s.Newest = modTime
+	}
+	if s.Oldest.IsZero() || modTime.Before(s.Oldest) {
+		s.Oldest = modTime
+	}
+}
+
+// Summary merges the stats of all paths of the result.
+func (r Result) Summary() PathStats {
+	var sum PathStats
+	for _, st := range r.Stats {
+		sum.Size += st.Size
+		sum.Allocated += st.Allocated
+		sum.Files += st.Files
+		if !st.Newest.IsZero() && (sum.Newest.IsZero() || st.Newest.After(sum.Newest)) {
+			sum.Newest = st.Newest
+		}
+		if !st.Oldest.IsZero() && (sum.Oldest.IsZero() || st.Oldest.Before(sum.Oldest)) {
+			sum.Oldest = st.Oldest
+		}
+	}
+	return sum
 }
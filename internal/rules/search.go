@@ -0,0 +1,66 @@
+package rules
+
+import (
+	"sort"
+	"strings"
+)
+
+// Match is a rule found by Search and the field that matched it best.
+type Match struct {
+	Rule  CleanupRule `json:"rule"`
+	Field string      `json:"matched"`
+	rank  int
+}
+
+// searchFields are the fields Search looks at, best match first.
+var searchFields = []string{"name", "category", "description", "path"}
+
+// Search returns the rules whose name, category, description, or paths
+// contain every word of query, ignoring case. Rules matched by name come
+// first, then by category, description, and path; otherwise rules keep
+// registry order.
+func (r *Registry) Search(query string) []Match {
+	words := strings.Fields(strings.ToLower(query))
+	if len(words) == 0 {
+		return nil
+	}
+
+	var matches []Match
+	for _, rule := range r.rules {
+		fields := [][]string{
+			{rule.Name},
+			{rule.Category},
+			{rule.Description},
+			rule.Paths,
+		}
+		best := -1
+		for _, w := range words {
+			found := -1
+			for i, values := range fields {
+				if containsWord(values, w) {
+					found = i
+					break
+				}
+			}
+			if found < 0 {
+				best = -1
+				break
+			}
+			best = max(best, found)
+		}
+		if best >= 0 {
+			matches = append(matches, Match{Rule: rule, Field: searchFields[best], rank: best})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].rank < matches[j].rank })
+	return matches
+}
+
+func containsWord(values []string, word string) bool {
+	for _, v := range values {
+		if strings.Contains(strings.ToLower(v), word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package rules
+
+import "testing"
+
+func TestSearch(t *testing.T) {
+	r := NewRegistryFromRules([]CleanupRule{
+		{Name: "Docker Images", Category: "Containers", Description: "Unused images", Paths: []string{"~/Library/Containers/com.docker.docker"}},
+		{Name: "npm Cache", Category: "Package Managers", Description: "Downloaded packages", Paths: []string{"~/.npm/_cacache"}},
+		{Name: "Xcode DerivedData", Category: "Developer Tools", Description: "Build output", Paths: []string{"~/Library/Developer/Xcode/DerivedData"}},
+		{Name: "Colima", Category: "Containers", Description: "VM disk of the Docker runtime", Paths: []string{"~/.colima"}},
+	})
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"docker", []string{"Docker Images", "Colima"}},
+		{"XCODE", []string{"Xcode DerivedData"}},
+		{"cacache", []string{"npm Cache"}},
+		{"containers", []string{"Docker Images", "Colima"}},
+		{"docker vm", []string{"Colima"}},
+		{"nothing", nil},
+		{"  ", nil},
+	}
+	for _, tt := range tests {
+		got := r.Search(tt.query)
+		if len(got) != len(tt.want) {
+			t.Errorf("Search(%q) = %d matches, want %v", tt.query, len(got), tt.want)
+			continue
+		}
+		for i, name := range tt.want {
+			if got[i].Rule.Name != name {
+				t.Errorf("Search(%q)[%d] = %q, want %q", tt.query, i, got[i].Rule.Name, name)
+			}
+		}
+	}
+
+	if got := r.Search("docker"); got[0].Field != "name" || got[1].Field != "description" {
+		t.Errorf("matched fields = %q, %q; want name, description", got[0].Field, got[1].Field)
+	}
+}
@@ -0,0 +1,189 @@
+package rules
+
+// windowsRules returns the built-in rules used on Windows, where caches live
+// under %LOCALAPPDATA% and %USERPROFILE% instead of ~/Library.
+func windowsRules() []CleanupRule {
+	return []CleanupRule{
+		// Package Managers
+		{
+			Name:         "npm Cache",
+			Category:     "Package Managers",
+			Paths:        []string{"%LOCALAPPDATA%/npm-cache/_cacache", "%LOCALAPPDATA%/npm-cache/_logs"},
+			RiskLevel:    RiskSafe,
+			Description:  "Delete npm global cache and logs.",
+			Stores:       "Package tarballs and registry metadata fetched by npm, plus debug logs of past installs.",
+			References:   []string{"https://docs.npmjs.com/cli/commands/npm-cache"},
+			Explanation:  "The npm cache stores package data to avoid redundant network requests. Deleting it is safe because npm handles missing cache entries by fetching them from the registry. It also removes debug logs which are only useful for troubleshooting failed installs.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "npm re-downloads packages on the next install."},
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.3.0",
+		},
+		{
+			Name:         "NuGet Cache",
+			Category:     "Package Managers",
+			Paths:        []string{"~/.nuget/packages", "%LOCALAPPDATA%/NuGet/v3-cache", "%LOCALAPPDATA%/NuGet/plugins-cache"},
+			RiskLevel:    RiskSafe,
+			Description:  "Delete NuGet global packages and HTTP cache.",
+			Stores:       "Restored NuGet package versions and cached feed responses.",
+			References:   []string{"https://learn.microsoft.com/en-us/nuget/consume-packages/managing-the-global-packages-and-cache-folders"},
+			Explanation:  "NuGet keeps every restored package version in the global packages folder and caches feed responses in v3-cache. Deleting them is safe; the next 'dotnet restore' or Visual Studio build downloads what it needs again.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "NuGet re-downloads packages on the next restore."},
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.3.0",
+		},
+		{
+			Name:         "pip Cache",
+			Category:     "Package Managers",
+			Paths:        []string{"%LOCALAPPDATA%/pip/Cache"},
+			RiskLevel:    RiskSafe,
+			Description:  "Delete pip package cache.",
+			Stores:       "Wheels and source distributions downloaded or built by pip.",
+			References:   []string{"https://pip.pypa.io/en/stable/topics/caching/"},
+			Explanation:  "Python's pip tool caches wheels and source distributions to speed up re-installation of the same versions. Deleting this cache is safe; pip will re-download the packages from PyPI as needed.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "pip re-downloads packages on the next install."},
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.3.0",
+		},
+		{
+			Name:         "Yarn Cache",
+			Category:     "Package Managers",
+			Paths:        []string{"%LOCALAPPDATA%/Yarn/Cache"},
+			RiskLevel:    RiskSafe,
+			Description:  "Delete Yarn package cache.",
+			Stores:       "Every package version Yarn has downloaded.",
+			References:   []string{"https://classic.yarnpkg.com/en/docs/cli/cache"},
+			Explanation:  "Yarn stores every downloaded package in a global cache. Deleting this frees up space but will make future yarn installs slower until the cache is repopulated.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "Yarn re-downloads packages on the next install."},
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.3.0",
+		},
+		{
+			Name:           "Go Module Cache",
+			Category:       "Package Managers",
+			Paths:          []string{"~/go/pkg/mod"},
+			RiskLevel:      RiskSafe,
+			Description:    "Delete Go module cache.",
+			Stores:         "Downloaded Go module sources and their checksums (GOMODCACHE).",
+			References:     []string{"https://go.dev/ref/mod#module-cache"},
+			Explanation:    "Determined by GOMODCACHE, this directory holds downloaded modules. Deleting it forces a redownload of dependencies on the next build, which is safe but consumes bandwidth.",
+			RebuildCost:    RebuildCost{Level: RebuildLow, Description: "Go re-downloads each project's modules on its next build."},
+			RuleVersion:    "1.0.0",
+			IntroducedIn:   "0.3.0",
+			BypassGitCheck: true,
+		},
+
+		// Developer Tools
+		{
+			Name:           "Gradle Cache",
+			Category:       "Developer Tools",
+			Paths:          []string{"~/.gradle/caches"},
+			RiskLevel:      RiskCaution,
+			Description:    "Delete Gradle dependency caches.",
+			Stores:         "Dependency JARs and artifacts downloaded by Gradle, and its build caches.",
+			References:     []string{"https://docs.gradle.org/current/userguide/directory_layout.html"},
+			Explanation:    "This directory contains all JARs and artifacts downloaded by Gradle. While safe from a data integrity perspective, deleting it will force every project to re-download all dependencies, which can be extremely slow and consume significant bandwidth.",
+			RebuildCost:    RebuildCost{Level: RebuildHigh, Description: "Gradle will re-download all dependencies, often several GB, on the next build."},
+			RuleVersion:    "1.0.0",
+			IntroducedIn:   "0.3.0",
+			BypassGitCheck: true,
+		},
+		{
+			Name:         "Go Build Cache",
+			Category:     "Developer Tools",
+			Paths:        []string{"%LOCALAPPDATA%/go-build"},
+			RiskLevel:    RiskSafe,
+			Description:  "Delete Go build cache.",
+			Stores:       "Compiled Go packages and test results (GOCACHE).",
+			References:   []string{"https://pkg.go.dev/cmd/go#hdr-Build_and_test_caching"},
+			Explanation:  "Go caches compiled packages to speed up builds. Deleting this is safe but will make the next build roughly as slow as a fresh build.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "The next go build or go test recompiles packages."},
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.3.0",
+		},
+		{
+			Name:     "VS Code Cache",
+			Category: "Developer Tools",
+			Paths: []string{
+				"%APPDATA%/Code/Cache",
+				"%APPDATA%/Code/CachedData",
+				"%APPDATA%/Code/User/workspaceStorage",
+			},
+			RiskLevel:    RiskCaution,
+			Description:  "Delete VS Code caches and workspace storage.",
+			Stores:       "VS Code's web caches and per-workspace state such as UI layout and opened files.",
+			Explanation:  "Deletes generic caches and cached workspace data. Deleting workspaceStorage will not delete your code, but may reset local workspace state (UI layout, opened files history) for projects. Useful if VS Code is acting buggy.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "VS Code refills its caches as you use it."},
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.3.0",
+		},
+
+		// Language Servers
+		{
+			Name:         "gopls Cache",
+			Category:     "Language Servers",
+			Paths:        []string{"%LOCALAPPDATA%/gopls"},
+			RiskLevel:    RiskSafe,
+			Description:  "Delete the Go language server's index cache.",
+			Stores:       "Type-checking and cross-reference data of the Go language server.",
+			Explanation:  "gopls caches type-checking and cross-reference data for every module and Go version it has opened. Old entries are never pruned, so the cache grows with each Go release. Deleting it is safe; gopls rebuilds what it needs the next time an editor opens a Go file.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "gopls re-indexes on the next editor start."},
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.4.0",
+		},
+		{
+			Name:         "TypeScript Server Cache",
+			Category:     "Language Servers",
+			Paths:        []string{"%LOCALAPPDATA%/Microsoft/TypeScript"},
+			RiskLevel:    RiskSafe,
+			Description:  "Delete type definitions downloaded by tsserver.",
+			Stores:       "@types packages downloaded by tsserver for JavaScript projects, per TypeScript version.",
+			Explanation:  "The TypeScript server downloads @types packages for JavaScript projects (automatic type acquisition) into one folder per TypeScript version, and never removes versions that are no longer used. Deleting it is safe; editors fetch the types again when needed.",
+			RebuildCost:  RebuildCost{Level: RebuildLow, Description: "Type definitions are re-downloaded as projects are opened."},
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.4.0",
+		},
+
+		// Machine Learning
+		{
+			Name:         "Hugging Face Models",
+			Category:     "Machine Learning",
+			Paths:        []string{"~/.cache/huggingface/hub/models--*", "~/.cache/huggingface/hub/datasets--*"},
+			RiskLevel:    RiskCaution,
+			Description:  "Delete models and datasets downloaded from the Hugging Face Hub.",
+			Stores:       "Model and dataset revisions downloaded from the Hugging Face Hub.",
+			References:   []string{"https://huggingface.co/docs/huggingface_hub/guides/manage-cache"},
+			Explanation:  "transformers, diffusers, and the huggingface_hub library keep every model and dataset revision they download, often several gigabytes each. Each one is listed separately so you can see which are worth keeping. Deleting them is safe, but the next run that needs a model downloads it again in full.",
+			RebuildCost:  RebuildCost{Level: RebuildHigh, Description: "Models are re-downloaded, often several GB each, the next time they are loaded."},
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.4.0",
+		},
+		{
+			Name:         "Ollama Models",
+			Category:     "Machine Learning",
+			Paths:        []string{"~/.ollama/models"},
+			RiskLevel:    RiskManual,
+			Description:  "Inspect the space used by local Ollama models.",
+			Stores:       "Model manifests and the layer blobs they share.",
+			References:   []string{"https://github.com/ollama/ollama/blob/main/docs/faq.md"},
+			Explanation:  "Ollama stores model layers as shared blobs, so deleting files by hand can break other models. Burrow only reports the total; run 'ollama list' to see each model's size and 'ollama rm <model>' to remove the ones you no longer use.",
+			RebuildCost:  RebuildCost{Level: RebuildHigh, Description: "Each model has to be pulled again with 'ollama pull'."},
+			ReportOnly:   true,
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.4.0",
+		},
+
+		// System
+		{
+			Name:         "Temporary Files",
+			Category:     "System",
+			Paths:        []string{"%LOCALAPPDATA%/Temp"},
+			RiskLevel:    RiskSafe,
+			Description:  "Delete user temporary files.",
+			Stores:       "Short-lived files that programs write to the temporary directory.",
+			Explanation:  "Safe to delete. Files still held open by running programs are skipped by Windows.",
+			RebuildCost:  RebuildCost{Level: RebuildNone},
+			RuleVersion:  "1.0.0",
+			IntroducedIn: "0.3.0",
+		},
+	}
+}
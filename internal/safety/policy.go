@@ -0,0 +1,42 @@
+package safety
+
+import "sync"
+
+// Policy holds the user-configured additions to the built-in guards.
+type Policy struct {
+	// ProtectedPaths are never deleted, in addition to the built-in guards.
+	ProtectedPaths []string
+	// ForceAllowPaths bypass the soft guards (Git metadata, user document
+	// directories). Hard guards such as home, root, and SIP always apply.
+	ForceAllowPaths []string
+}
+
+var (
+	policyMu sync.RWMutex
+	policy   Policy
+)
+
+// SetPolicy replaces the active user policy.
+func SetPolicy(p Policy) {
+	policyMu.Lock()
+	defer policyMu.Unlock()
+	policy = p
+}
+
+func currentPolicy() Policy {
+	policyMu.RLock()
+	defer policyMu.RUnlock()
+	return policy
+}
+
+// OverriddenPaths returns the paths that are only deletable because a
+// force_allow_paths entry bypassed a default guard.
+func OverriddenPaths(paths []string) []string {
+	var out []string
+	for _, p := range paths {
+		if Check(p).Overridden {
+			out = append(out, p)
+		}
+	}
+	return out
+}
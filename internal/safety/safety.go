@@ -4,86 +4,199 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/ismailtsdln/burrow/internal/paths"
 )
 
 // IsSafe returns true if the path is safe to delete.
 func IsSafe(path string) (bool, string) {
+	v := Check(path)
+	return v.Safe, v.Reason
+}
+
+// Verdict is the detailed outcome of a safety check.
+type Verdict struct {
+	Safe   bool
+	Reason string
+	// Overridden is set when the path is only safe because a force_allow_paths
+	// entry bypassed a default guard; such deletions need extra confirmation.
+	Overridden bool
+}
+
+// GitCheck selects how thoroughly a candidate is checked for Git metadata.
+type GitCheck string
+
+const (
+	// GitScoped refuses a candidate that is a working tree root, a .git
+	// directory, or lies inside a repository. This is the default.
+	GitScoped GitCheck = "scoped"
+	// GitDeep additionally refuses a candidate that contains a repository
+	// anywhere below it, at the cost of walking the whole tree.
+	GitDeep GitCheck = "deep"
+)
+
+// Options tunes the guards applied by CheckWith for a particular rule.
+type Options struct {
+	Git GitCheck
+	// BypassGit disables the Git guard for rules that opt out of it.
+	BypassGit bool
+}
+
+// Check runs all safety guards against path with default options.
+func Check(path string) Verdict {
+	return CheckWith(path, Options{})
+}
+
+// CheckWith runs all safety guards against path. Hard guards (home, root,
+// system paths, Burrow's own state, user-protected paths) can never be
+// bypassed; the Git and user document guards can be overridden with
+// force_allow_paths.
+func CheckWith(path string, opts Options) Verdict {
 	absPath, err := filepath.Abs(ExpandPath(path))
 	if err != nil {
-		return false, "Invalid path"
+		return Verdict{Reason: "Invalid path"}
 	}
 
 	// 1. Guard against home directory and root
 	home, _ := os.UserHomeDir()
-	if absPath == home || absPath == "/" {
-		return false, "Cannot delete home or root directory"
-	}
-
-	// 2. Guard against System Integrity Protection (SIP) and system paths
-	systemPaths := []string{
-		"/System",
-		"/Library/Apple",
-		"/usr/bin",
-		"/usr/sbin",
-		"/bin",
-		"/sbin",
-	}
-	for _, p := range systemPaths {
-		if strings.HasPrefix(absPath, p) {
-			return false, "Path is protected by System Integrity Protection (SIP)"
+	if absPath == home || filepath.Dir(absPath) == absPath {
+		return Verdict{Reason: "Cannot delete home or root directory"}
+	}
+
+	// 2. Guard against platform-protected system paths (SIP on macOS)
+	if isProtectedSystemPath(absPath) {
+		return Verdict{Reason: protectedSystemMessage}
+	}
+
+	// 3. Guard against Burrow's own config, history, trash, and caches,
+	// which only its dedicated commands may change
+	for _, p := range stateDirs() {
+		if MatchPath(absPath, p) || MatchPath(p, absPath) {
+			return Verdict{Reason: "Path holds Burrow's own state (config, history, trash, or caches)"}
+		}
+	}
+	for _, p := range configEntries() {
+		if MatchPath(absPath, p) {
+			return Verdict{Reason: "Path holds Burrow's own state (config, history, trash, or caches)"}
+		}
+	}
+
+	// 4. Guard against user-configured protected paths, including any
+	// candidate that would take a protected path down with it
+	pol := currentPolicy()
+	for _, p := range pol.ProtectedPaths {
+		pattern := filepath.Clean(ExpandPath(p))
+		if MatchPath(absPath, pattern) || MatchPath(pattern, absPath) {
+			return Verdict{Reason: "Path is protected by protected_paths in your configuration"}
+		}
+	}
+
+	var softReason string
+
+	// 5. Guard against Git repositories
+	switch {
+	case opts.BypassGit:
+		// The rule explicitly opted out of the Git guard
+	case isGitRepo(absPath):
+		softReason = "Path is inside a Git repository"
+	case opts.Git == GitDeep && containsGitRepo(absPath):
+		softReason = "Path contains Git metadata (.git)"
+	}
+
+	// 6. Guard against common user directories
+	if softReason == "" {
+		userDocPaths := []string{
+			filepath.Join(home, "Documents"),
+			filepath.Join(home, "Desktop"),
+			filepath.Join(home, "Downloads"),
+		}
+		for _, p := range userDocPaths {
+			if absPath == p {
+				softReason = "Path is a common user document directory"
+				break
+			}
+		}
+	}
+
+	if softReason == "" {
+		return Verdict{Safe: true}
+	}
+
+	for _, p := range pol.ForceAllowPaths {
+		if MatchPath(absPath, filepath.Clean(ExpandPath(p))) {
+			return Verdict{Safe: true, Reason: softReason, Overridden: true}
 		}
 	}
+	return Verdict{Reason: softReason}
+}
 
-	// 3. Guard against Git repositories
-	if isGitRepo(absPath) {
-		return false, "Path contains Git metadata (.git)"
+// stateDirs returns the directories that are Burrow's alone: the data
+// directory, the legacy ~/.burrow, and the config directory unless --config
+// chose another one. Neither they nor a directory holding them may be removed.
+func stateDirs() []string {
+	dirs := []string{paths.DataDir(), paths.LegacyDataDir()}
+	if !paths.ConfigOverridden() {
+		dirs = append(dirs, paths.ConfigDir())
 	}
+	for i, d := range dirs {
+		dirs[i] = filepath.Clean(d)
+	}
+	return dirs
+}
 
-	// 4. Guard against common user directories
-	userDocPaths := []string{
-		filepath.Join(home, "Documents"),
-		filepath.Join(home, "Desktop"),
-		filepath.Join(home, "Downloads"),
+// configEntries returns Burrow's own config files and directories. A config
+// picked with --config may sit in any directory, such as the home directory,
+// so only the entries themselves are guarded, never the directory around them.
+func configEntries() []string {
+	entries := []string{paths.ConfigFile(), paths.CustomRulesFile(), paths.RulesDir(), paths.LocalesDir()}
+	for i, e := range entries {
+		entries[i] = filepath.Clean(e)
 	}
-	for _, p := range userDocPaths {
-		if absPath == p {
-			return false, "Path is a common user document directory"
+	return entries
+}
+
+// MatchPath reports whether path equals pattern or lies below it, comparing
+// whole path segments. Patterns containing globs match path or any of its
+// parent directories.
+func MatchPath(path, pattern string) bool {
+	path = filepath.Clean(path)
+	pattern = filepath.Clean(pattern)
+
+	if strings.ContainsAny(pattern, "*?[") {
+		for curr := path; ; {
+			if ok, _ := filepath.Match(pattern, curr); ok {
+				return true
+			}
+			parent := filepath.Dir(curr)
+			if parent == curr {
+				return false
+			}
+			curr = parent
 		}
 	}
 
-	return true, ""
+	return path == pattern || strings.HasPrefix(path, strings.TrimSuffix(pattern, string(filepath.Separator))+string(filepath.Separator))
 }
 
-// ExpandPath replaces ~ with the user's home directory.
+// ExpandPath replaces ~ with the user's home directory and expands
+// platform-specific variables such as %LOCALAPPDATA% on Windows.
 func ExpandPath(path string) string {
-	if strings.HasPrefix(path, "~/") {
+	path = expandPlatformVars(path)
+	if strings.HasPrefix(path, "~/") || strings.HasPrefix(path, "~"+string(filepath.Separator)) {
 		home, _ := os.UserHomeDir()
 		return filepath.Join(home, path[2:])
 	}
 	return path
 }
 
-// isGitRepo checks if the path or any of its parents/children contain .git.
+// isGitRepo reports whether path is a .git directory, a working tree root, or
+// lies inside a repository. It never descends into path, so caches that embed
+// vendored repositories (e.g. ~/go/pkg/mod) are not blocked.
 func isGitRepo(path string) bool {
-	// Check if the path itself is .git
 	if filepath.Base(path) == ".git" {
 		return true
 	}
 
-	// Check for .git in the current directory or any subdirectory
-	found := false
-	filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
-		if err == nil && info.IsDir() && info.Name() == ".git" {
-			found = true
-			return filepath.SkipAll
-		}
-		return nil
-	})
-	if found {
-		return true
-	}
-
-	// Traverse up to check if we are inside a git repo
 	curr := path
 	for {
 		if _, err := os.Stat(filepath.Join(curr, ".git")); err == nil {
@@ -98,3 +211,16 @@ func isGitRepo(path string) bool {
 
 	return false
 }
+
+// containsGitRepo reports whether any directory below path is named .git.
+func containsGitRepo(path string) bool {
+	found := false
+	filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err == nil && info.IsDir() && info.Name() == ".git" {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/ismailtsdln/burrow/internal/paths"
 )
 
 func TestIsSafe(t *testing.T) {
@@ -72,3 +74,110 @@ func TestIsGitRepo(t *testing.T) {
 		})
 	}
 }
+
+func TestPolicy(t *testing.T) {
+	home, _ := os.UserHomeDir()
+	SetPolicy(Policy{
+		ProtectedPaths:  []string{"~/Library/Caches/Important", "~/.npm/keep"},
+		ForceAllowPaths: []string{"~/Downloads"},
+	})
+	defer SetPolicy(Policy{})
+
+	tests := []struct {
+		name           string
+		path           string
+		wantSafe       bool
+		wantOverridden bool
+	}{
+		{"Protected path", filepath.Join(home, "Library/Caches/Important"), false, false},
+		{"Inside protected path", filepath.Join(home, "Library/Caches/Important/db"), false, false},
+		{"Parent of protected path", filepath.Join(home, ".npm"), false, false},
+		{"Sibling of protected path", filepath.Join(home, "Library/Caches/ImportantOld"), true, false},
+		{"Force-allowed Downloads", filepath.Join(home, "Downloads"), true, true},
+		{"Home is never overridable", home, false, false},
+		{"Documents still guarded", filepath.Join(home, "Documents"), false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := Check(tt.path)
+			if v.Safe != tt.wantSafe || v.Overridden != tt.wantOverridden {
+				t.Errorf("Check(%v) = %+v, want safe=%v overridden=%v", tt.path, v, tt.wantSafe, tt.wantOverridden)
+			}
+		})
+	}
+}
+
+func TestGitCheckScope(t *testing.T) {
+	cache := t.TempDir()
+	vendored := filepath.Join(cache, "github.com", "foo", "bar@v1.0.0", ".git")
+	if err := os.MkdirAll(vendored, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := CheckWith(cache, Options{Git: GitScoped}); !v.Safe {
+		t.Errorf("scoped check refused cache with vendored repo: %s", v.Reason)
+	}
+	if v := CheckWith(cache, Options{Git: GitDeep}); v.Safe {
+		t.Errorf("deep check allowed cache with vendored repo")
+	}
+	if v := Check(filepath.Dir(vendored)); v.Safe {
+		t.Errorf("scoped check allowed a working tree root")
+	}
+	if v := CheckWith(filepath.Dir(vendored), Options{BypassGit: true}); !v.Safe {
+		t.Errorf("bypass still refused a working tree root: %s", v.Reason)
+	}
+}
+
+func TestBurrowStateIsProtected(t *testing.T) {
+	data := t.TempDir()
+	config := t.TempDir()
+	t.Setenv(paths.DataEnv, data)
+	t.Setenv(paths.ConfigEnv, config)
+	home, _ := os.UserHomeDir()
+
+	tests := []struct {
+		name     string
+		path     string
+		wantSafe bool
+	}{
+		{"Data directory", data, false},
+		{"Trash session", filepath.Join(data, "trash", "20240101_000000"), false},
+		{"History", filepath.Join(data, "history.json"), false},
+		{"Config directory", filepath.Join(config, "rules.d"), false},
+		{"Parent of the data directory", filepath.Dir(data), false},
+		{"Legacy data directory", filepath.Join(home, ".burrow"), false},
+		{"Sibling", filepath.Join(filepath.Dir(data), filepath.Base(data)+"-other"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotSafe, msg := IsSafe(tt.path); gotSafe != tt.wantSafe {
+				t.Errorf("IsSafe(%v) = %v (msg: %v), want %v", tt.path, gotSafe, msg, tt.wantSafe)
+			}
+		})
+	}
+
+	// A config file passed with --config guards itself, not its directory
+	dir := t.TempDir()
+	file := filepath.Join(dir, "burrow.json")
+	if err := os.WriteFile(file, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(paths.ConfigEnv, file)
+	for path, wantSafe := range map[string]bool{
+		file:                                    false,
+		filepath.Join(dir, "custom_rules.json"): false,
+		filepath.Join(dir, "Caches", "app"):     true,
+	} {
+		if v := Check(path); v.Safe != wantSafe {
+			t.Errorf("with --config %s, Check(%s) = %+v, want safe %v", file, path, v, wantSafe)
+		}
+	}
+
+	// force_allow_paths cannot lift the guard
+	SetPolicy(Policy{ForceAllowPaths: []string{data}})
+	defer SetPolicy(Policy{})
+	if v := Check(data); v.Safe {
+		t.Errorf("force-allowed data directory is safe: %+v", v)
+	}
+}
@@ -0,0 +1,29 @@
+//go:build !windows
+
+package safety
+
+import "strings"
+
+const protectedSystemMessage = "Path is protected by System Integrity Protection (SIP)"
+
+var protectedSystemPaths = []string{
+	"/System",
+	"/Library/Apple",
+	"/usr/bin",
+	"/usr/sbin",
+	"/bin",
+	"/sbin",
+}
+
+func isProtectedSystemPath(absPath string) bool {
+	for _, p := range protectedSystemPaths {
+		if strings.HasPrefix(absPath, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func expandPlatformVars(path string) string {
+	return path
+}
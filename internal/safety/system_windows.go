@@ -0,0 +1,44 @@
+//go:build windows
+
+package safety
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const protectedSystemMessage = "Path is a protected Windows system directory"
+
+var envVarPattern = regexp.MustCompile(`%([A-Za-z0-9_()]+)%`)
+
+func isProtectedSystemPath(absPath string) bool {
+	var protected []string
+	for _, env := range []string{"SystemRoot", "ProgramFiles", "ProgramFiles(x86)", "ProgramW6432"} {
+		if v := os.Getenv(env); v != "" {
+			protected = append(protected, filepath.Clean(v))
+		}
+	}
+
+	// Windows paths are case-insensitive
+	lower := strings.ToLower(absPath)
+	for _, p := range protected {
+		if strings.HasPrefix(lower, strings.ToLower(p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandPlatformVars expands %VAR% references (e.g. %LOCALAPPDATA%) and
+// normalizes slashes so rule paths can be written portably.
+func expandPlatformVars(path string) string {
+	expanded := envVarPattern.ReplaceAllStringFunc(path, func(m string) string {
+		if v, ok := os.LookupEnv(m[1 : len(m)-1]); ok {
+			return v
+		}
+		return m
+	})
+	return filepath.FromSlash(expanded)
+}
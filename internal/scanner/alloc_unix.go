@@ -0,0 +1,17 @@
+//go:build !windows
+
+package scanner
+
+import (
+	"os"
+	"syscall"
+)
+
+// allocatedSize returns the bytes actually allocated on disk for a file, which
+// is less than its size for sparse files such as VM disk images.
+func allocatedSize(info os.FileInfo) int64 {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return int64(st.Blocks) * 512
+	}
+	return info.Size()
+}
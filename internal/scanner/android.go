@@ -0,0 +1,88 @@
+package scanner
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/safety"
+)
+
+// androidAVDHome returns the directory holding the Android virtual devices.
+func androidAVDHome() string {
+	if dir := os.Getenv("ANDROID_AVD_HOME"); dir != "" {
+		return dir
+	}
+	return safety.ExpandPath("~/.android/avd")
+}
+
+// readINI returns the key=value pairs of an Android .ini file.
+func readINI(path string) map[string]string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	values := make(map[string]string)
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if k, v, ok := strings.Cut(sc.Text(), "="); ok {
+			values[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return values
+}
+
+// androidAVD describes a virtual device folder. The emulator rewrites
+// hardware-qemu.ini on every boot, so its age is the time since the device
+// last booted.
+func androidAVD(path string) (string, time.Time) {
+	name := strings.TrimSuffix(filepath.Base(path), ".avd")
+	if display := readINI(filepath.Join(path, "config.ini"))["avd.ini.displayname"]; display != "" {
+		name = display
+	}
+	var booted time.Time
+	if info, err := os.Stat(filepath.Join(path, "hardware-qemu.ini")); err == nil {
+		booted = info.ModTime()
+	}
+	return name, booted
+}
+
+// androidSnapshot names an emulator snapshot after its device.
+func androidSnapshot(path string) string {
+	avd := strings.TrimSuffix(filepath.Base(filepath.Dir(filepath.Dir(path))), ".avd")
+	return avd + "/" + filepath.Base(path)
+}
+
+// systemImageID returns the SDK package path of a system image, such as
+// system-images/android-34/google_apis/arm64-v8a, or "".
+func systemImageID(path string) string {
+	slashed := filepath.ToSlash(filepath.Clean(path))
+	i := strings.LastIndex(slashed, "system-images/")
+	if i < 0 {
+		return ""
+	}
+	return slashed[i:]
+}
+
+// systemImageName names a system image by API level, tag, and ABI.
+func systemImageName(path string) string {
+	return strings.ReplaceAll(strings.TrimPrefix(systemImageID(path), "system-images/"), "/", " ")
+}
+
+// systemImageUsers maps the system images the virtual devices in avdHome
+// boot from to the device using each.
+func systemImageUsers(avdHome string) map[string]string {
+	users := make(map[string]string)
+	configs, _ := filepath.Glob(filepath.Join(avdHome, "*.avd", "config.ini"))
+	for _, c := range configs {
+		values := readINI(c)
+		if sysdir := values["image.sysdir.1"]; sysdir != "" {
+			id := strings.TrimSuffix(filepath.ToSlash(sysdir), "/")
+			users[id] = strings.TrimSuffix(filepath.Base(filepath.Dir(c)), ".avd")
+		}
+	}
+	return users
+}
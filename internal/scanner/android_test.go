@@ -0,0 +1,50 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+func TestAndroidItems(t *testing.T) {
+	avdHome := t.TempDir()
+	t.Setenv("ANDROID_AVD_HOME", avdHome)
+	avd := filepath.Join(avdHome, "Pixel_7_API_34.avd")
+	if err := os.MkdirAll(filepath.Join(avd, "snapshots", "default_boot"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := "avd.ini.displayname=Pixel 7 API 34\nimage.sysdir.1=system-images/android-34/google_apis/arm64-v8a/\n"
+	if err := os.WriteFile(filepath.Join(avd, "config.ini"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	name, booted := describeItem(rules.ItemsAndroidAVD, avd)
+	if name != "Pixel 7 API 34" || !booted.IsZero() {
+		t.Errorf("avd = %q, %v; want the display name and no boot yet", name, booted)
+	}
+	if err := os.WriteFile(filepath.Join(avd, "hardware-qemu.ini"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, booted := describeItem(rules.ItemsAndroidAVD, avd); booted.IsZero() {
+		t.Error("boot time not read from hardware-qemu.ini")
+	}
+
+	if name, _ := describeItem(rules.ItemsAndroidSnapshot, filepath.Join(avd, "snapshots", "default_boot")); name != "Pixel_7_API_34/default_boot" {
+		t.Errorf("snapshot = %q", name)
+	}
+
+	sdk := filepath.Join(t.TempDir(), "sdk", "system-images")
+	used := filepath.Join(sdk, "android-34", "google_apis", "arm64-v8a")
+	unused := filepath.Join(sdk, "android-30", "google_apis", "x86_64")
+	if reason := itemInUse(rules.ItemsAndroidSystemImage, used); reason != "used by virtual device Pixel_7_API_34" {
+		t.Errorf("used image: reason %q", reason)
+	}
+	if reason := itemInUse(rules.ItemsAndroidSystemImage, unused); reason != "" {
+		t.Errorf("unused image: reason %q", reason)
+	}
+	if name, _ := describeItem(rules.ItemsAndroidSystemImage, unused); name != "android-30 google_apis x86_64" {
+		t.Errorf("image = %q", name)
+	}
+}
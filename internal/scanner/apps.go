@@ -0,0 +1,178 @@
+package scanner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/safety"
+)
+
+// AppCacheCategory is the category of the per-application results of
+// scan --apps.
+const AppCacheCategory = "App Caches"
+
+// appCacheGlobs are searched in app cache mode. The path element matched by
+// the first wildcard names the application: a bundle ID such as
+// com.spotify.client, or a folder such as "Slack".
+var appCacheGlobs = defaultAppCacheGlobs()
+
+func defaultAppCacheGlobs() []string {
+	switch runtime.GOOS {
+	case "darwin":
+		return []string{"~/Library/Caches/*", "~/Library/Application Support/*/Cache*"}
+	case "windows":
+		return []string{"%LOCALAPPDATA%/*/Cache*", "%APPDATA%/*/Cache*"}
+	default:
+		return []string{"~/.cache/*", "~/.config/*/Cache*"}
+	}
+}
+
+// appCaches groups the cache directories of one application.
+type appCaches struct {
+	key   string // Bundle ID or folder name
+	paths []string
+}
+
+// scanAppCaches discovers cache directories for every application, with or
+// without an explicit rule, and reports one result per application.
+func (s *Scanner) scanAppCaches() (*ScanResults, error) {
+	apps := make(map[string]*appCaches)
+	var order []string
+	for _, pattern := range appCacheGlobs {
+		expanded := safety.ExpandPath(pattern)
+		matches, _ := filepath.Glob(expanded)
+		for _, m := range matches {
+			if info, err := os.Stat(m); err != nil || !info.IsDir() {
+				continue
+			}
+			key := appKey(expanded, m)
+			if apps[key] == nil {
+				apps[key] = &appCaches{key: key}
+				order = append(order, key)
+			}
+			apps[key].paths = append(apps[key].paths, m)
+		}
+	}
+
+	res := &ScanResults{Results: make([]rules.Result, 0)}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := newSemaphore(s.options.MaxConcurrency)
+	for _, key := range order {
+		wg.Add(1)
+		go func(app *appCaches) {
+			defer wg.Done()
+			sem.acquire()
+			defer sem.release()
+
+			result, skipped := s.scanApp(app)
+			mu.Lock()
+			defer mu.Unlock()
+			res.Skipped = append(res.Skipped, skipped...)
+			if result != nil {
+				res.Results = append(res.Results, *result)
+				res.TotalSize += result.TotalSize
+			}
+		}(apps[key])
+	}
+	wg.Wait()
+
+	rules.SortBySize(res.Results)
+	sort.Slice(res.Skipped, func(i, j int) bool { return res.Skipped[i].Path < res.Skipped[j].Path })
+	s.hideSmall(res)
+	res.Disk = diskSummary(res.TotalSize)
+	return res, nil
+}
+
+// scanApp sizes one application's cache directories.
+func (s *Scanner) scanApp(app *appCaches) (*rules.Result, []SkippedPath) {
+	name := app.key
+	if resolved := appName(app.key); resolved != "" {
+		name = resolved
+	}
+	rule := rules.CleanupRule{
+		Name:        name,
+		Category:    AppCacheCategory,
+		Paths:       app.paths,
+		RiskLevel:   rules.RiskCaution,
+		Description: "Caches of " + name,
+		Explanation: "Discovered by scan --apps. Applications rebuild their caches, but may be slower or sign you out until they do.",
+	}
+	if name != app.key {
+		rule.Description += " (" + app.key + ")"
+	}
+
+	result := rules.Result{Rule: rule}
+	var skipped []SkippedPath
+	for _, p := range app.paths {
+		size, stats, skip := s.measure(name, p, s.options.OlderThan)
+		if skip != nil {
+			skipped = append(skipped, *skip)
+		}
+		if size == 0 {
+			continue
+		}
+		result.FoundPaths = append(result.FoundPaths, p)
+		result.TotalSize += size
+		result.Stats = append(result.Stats, stats)
+	}
+
+	if len(result.FoundPaths) == 0 || (s.options.SizeThreshold > 0 && result.TotalSize < s.options.SizeThreshold) {
+		return nil, skipped
+	}
+	return &result, skipped
+}
+
+// measure sizes one discovered path, applying the same exclusion, age, and
+// safety filters as rule scans. It returns a zero size for paths that are
+// filtered out, along with the reason when the user should be told.
+func (s *Scanner) measure(name, p string, minAge time.Duration) (int64, rules.PathStats, *SkippedPath) {
+	if isExcluded(p, s.options.ExcludedPaths) || s.dismissed(p) {
+		return 0, rules.PathStats{}, nil
+	}
+	if minAge > 0 {
+		if info, err := os.Lstat(p); err != nil || time.Since(info.ModTime()) < minAge {
+			return 0, rules.PathStats{}, nil
+		}
+	}
+	if v := safety.CheckWith(p, safety.Options{}); !v.Safe {
+		return 0, rules.PathStats{}, &SkippedPath{Rule: name, Path: p, Reason: skipReason("unsafe", v.Reason)}
+	}
+	size, stats, err := dirStats(p, s.limiter)
+	var partial *partialError
+	if err != nil && !errors.As(err, &partial) {
+		status, detail := classifyAccessError(err)
+		return 0, rules.PathStats{}, &SkippedPath{Rule: name, Path: p, Reason: skipReason(string(status), detail)}
+	}
+	return size, stats, nil
+}
+
+// appKey returns the path element of match that the first wildcard of
+// pattern matched.
+func appKey(pattern, match string) string {
+	patternParts := strings.Split(filepath.ToSlash(pattern), "/")
+	matchParts := strings.Split(filepath.ToSlash(match), "/")
+	for i, part := range patternParts {
+		if strings.ContainsAny(part, "*?[") && i < len(matchParts) {
+			return matchParts[i]
+		}
+	}
+	return filepath.Base(match)
+}
+
+// looksLikeBundleID reports whether key is a reverse-DNS identifier such as
+// com.spotify.client rather than a plain folder name.
+func looksLikeBundleID(key string) bool {
+	return strings.Count(key, ".") >= 2 && !strings.ContainsAny(key, " '\"\\")
+}
+
+// appName resolves a bundle ID to the application's display name, or returns
+// "" when it is unknown. Tests replace it.
+var appName = lookupAppName
@@ -0,0 +1,34 @@
+package scanner
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// lookupAppName asks LaunchServices, through Spotlight, for the application
+// registered under bundle ID key.
+func lookupAppName(key string) string {
+	if !looksLikeBundleID(key) {
+		return ""
+	}
+	out, err := exec.Command("mdfind", "kMDItemCFBundleIdentifier == '"+key+"'").Output()
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasSuffix(line, ".app") {
+			return strings.TrimSuffix(filepath.Base(line), ".app")
+		}
+	}
+	return ""
+}
+
+// binaryPlistString reads key from a binary property list with plutil.
+func binaryPlistString(plist, key string) string {
+	out, err := exec.Command("plutil", "-extract", key, "raw", "-o", "-", plist).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
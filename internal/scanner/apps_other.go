@@ -0,0 +1,10 @@
+//go:build !darwin
+
+package scanner
+
+// lookupAppName has no bundle registry to ask outside macOS; folder names are
+// shown as they are.
+func lookupAppName(string) string { return "" }
+
+// binaryPlistString can't read binary property lists without plutil.
+func binaryPlistString(string, string) string { return "" }
@@ -0,0 +1,79 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScanner_AppCaches(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]int{
+		"Caches/com.example.editor/blob":              4096,
+		"Support/com.example.editor/Cache/data":       1024,
+		"Support/com.example.editor/Preferences/keep": 1024,
+		"Caches/Slack/index":                          2048,
+		"Caches/com.example.empty/.keep":              0,
+		"Support/Chat/Code Cache/js":                  512,
+	}
+	for name, size := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	oldGlobs, oldName := appCacheGlobs, appName
+	defer func() { appCacheGlobs, appName = oldGlobs, oldName }()
+	appCacheGlobs = []string{filepath.Join(tmpDir, "Caches", "*"), filepath.Join(tmpDir, "Support", "*", "*Cache*")}
+	appName = func(key string) string {
+		if key == "com.example.editor" {
+			return "Editor"
+		}
+		return ""
+	}
+
+	s := NewScanner(nil, ScanOptions{AppCacheMode: true})
+	res, err := s.Scan()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]int64)
+	for _, r := range res.Results {
+		got[r.Rule.Name] = r.TotalSize
+		if r.Rule.Category != AppCacheCategory {
+			t.Errorf("%s: category %q", r.Rule.Name, r.Rule.Category)
+		}
+	}
+	want := map[string]int64{"Editor": 5120, "Slack": 2048, "Chat": 512}
+	if len(got) != len(want) {
+		t.Fatalf("results = %v, want %v", got, want)
+	}
+	for name, size := range want {
+		if got[name] != size {
+			t.Errorf("%s = %d, want %d", name, got[name], size)
+		}
+	}
+	if res.Results[0].Rule.Name != "Editor" {
+		t.Errorf("largest app should come first, got %s", res.Results[0].Rule.Name)
+	}
+}
+
+func TestAppKey(t *testing.T) {
+	tests := []struct {
+		pattern, match, want string
+	}{
+		{"/home/u/Library/Caches/*", "/home/u/Library/Caches/com.apple.Safari", "com.apple.Safari"},
+		{"/home/u/Support/*/Cache*", "/home/u/Support/Slack/Cache", "Slack"},
+		{"/home/u/Support/*/Cache*", "/home/u/Support/Code/CachedData", "Code"},
+	}
+	for _, tt := range tests {
+		if got := appKey(tt.pattern, tt.match); got != tt.want {
+			t.Errorf("appKey(%q, %q) = %q, want %q", tt.pattern, tt.match, got, tt.want)
+		}
+	}
+}
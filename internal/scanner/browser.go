@@ -0,0 +1,71 @@
+package scanner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// chromiumBrowsers maps the tail of a Chromium browser's user data
+// directory to the browser's name.
+var chromiumBrowsers = []struct{ dir, name string }{
+	{"Google/Chrome", "Chrome"},
+	{"Microsoft Edge", "Edge"},
+	{"Arc/User Data", "Arc"},
+}
+
+// browserProfileDir returns the profile directory holding the cache folder
+// at path, found as the directory whose parent has the browser's Local State
+// file, or "" when path is not inside a Chromium profile.
+func browserProfileDir(path string) string {
+	dir := filepath.Dir(path)
+	for range 2 {
+		if isFile(filepath.Join(filepath.Dir(dir), "Local State")) {
+			return dir
+		}
+		dir = filepath.Dir(dir)
+	}
+	return ""
+}
+
+// browserProfile names the profile holding the cache folder at path, such
+// as "Chrome: Work", using the display name the browser records in Local
+// State and falling back to the profile directory's name.
+func browserProfile(path string) string {
+	profile := browserProfileDir(path)
+	if profile == "" {
+		return filepath.Base(filepath.Dir(path))
+	}
+	userData := filepath.Dir(profile)
+	dir := filepath.Base(profile)
+
+	browser := filepath.Base(userData)
+	slashed := filepath.ToSlash(userData)
+	for _, b := range chromiumBrowsers {
+		if strings.HasSuffix(slashed, "/"+b.dir) {
+			browser = b.name
+			break
+		}
+	}
+
+	name := dir
+	var state struct {
+		Profile struct {
+			InfoCache map[string]struct {
+				Name string `json:"name"`
+			} `json:"info_cache"`
+		} `json:"profile"`
+	}
+	if data, err := os.ReadFile(filepath.Join(userData, "Local State")); err == nil && json.Unmarshal(data, &state) == nil {
+		if n := state.Profile.InfoCache[dir].Name; n != "" {
+			name = n
+		}
+	}
+	return browser + ": " + name
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
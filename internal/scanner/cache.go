@@ -0,0 +1,203 @@
+package scanner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/paths"
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+// CachedScan is a scan result persisted to disk for quick reuse.
+type CachedScan struct {
+	Timestamp time.Time    `json:"timestamp"`
+	Results   *ScanResults `json:"results"`
+	// RuleHashes fingerprints every rule definition the scan ran with, so
+	// rules added, edited, or removed since are scanned again.
+	RuleHashes map[string]string `json:"rule_hashes,omitempty"`
+}
+
+// Age returns how long ago the cached scan was taken.
+func (c *CachedScan) Age() time.Duration {
+	return time.Since(c.Timestamp)
+}
+
+// Validate reports why the cached scan can no longer stand in for a fresh one
+// at all: it is older than ttl (0 disables the check) or the configuration
+// changed since. Changes to individual paths are found by StaleRules, and
+// dismissals are applied when reading it; see Scanner.Filter.
+func (c *CachedScan) Validate(ttl time.Duration) error {
+	if c.Results == nil {
+		return fmt.Errorf("cached scan is empty")
+	}
+	if c.RuleHashes == nil {
+		return fmt.Errorf("cached scan does not record its rules")
+	}
+	if ttl > 0 && c.Age() > ttl {
+		return fmt.Errorf("cached scan is %s old (limit %s)", c.Age().Round(time.Second), ttl)
+	}
+	if info, err := os.Stat(paths.ConfigFile()); err == nil && info.ModTime().After(c.Timestamp) {
+		return fmt.Errorf("configuration changed since the scan")
+	}
+	return nil
+}
+
+// StaleRules returns the rules that need to be scanned again: rules of
+// registry that were added or whose definition changed since the scan, rules
+// that were removed, and rules with a path that vanished or was modified
+// after the scan.
+func (c *CachedScan) StaleRules(registry *rules.Registry) []string {
+	stale := make(map[string]bool)
+	current := ruleHashes(registry)
+	for name, hash := range current {
+		if c.RuleHashes[name] != hash {
+			stale[name] = true
+		}
+	}
+	for name := range c.RuleHashes {
+		if _, ok := current[name]; !ok {
+			stale[name] = true
+		}
+	}
+	for _, res := range c.Results.Results {
+		for _, p := range res.FoundPaths {
+			info, err := os.Lstat(p)
+			if err != nil || info.ModTime().After(c.Timestamp) {
+				stale[res.Rule.Name] = true
+				break
+			}
+		}
+	}
+
+	names := make([]string, 0, len(stale))
+	for name := range stale {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ruleHashes fingerprints each rule of registry by its whole definition.
+func ruleHashes(registry *rules.Registry) map[string]string {
+	hashes := make(map[string]string)
+	for _, r := range registry.All() {
+		data, _ := json.Marshal(r)
+		sum := sha256.Sum256(data)
+		hashes[r.Name] = hex.EncodeToString(sum[:8])
+	}
+	return hashes
+}
+
+// Refresh rescans only the named rules and merges them into prev, which is
+// left untouched.
+func (s *Scanner) Refresh(prev *ScanResults, names []string) (*ScanResults, error) {
+	stale := make(map[string]bool, len(names))
+	for _, n := range names {
+		stale[n] = true
+	}
+
+	var subset []rules.CleanupRule
+	for _, r := range s.registry.All() {
+		if stale[r.Name] {
+			subset = append(subset, r)
+		}
+	}
+	fresh, err := NewScanner(rules.NewRegistryFromRules(subset), s.options).Scan()
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &ScanResults{Results: make([]rules.Result, 0, len(prev.Results)), Duration: fresh.Duration}
+	for _, res := range prev.Results {
+		if !stale[res.Rule.Name] {
+			merged.Results = append(merged.Results, res)
+			merged.TotalSize += res.TotalSize
+		}
+	}
+	merged.Results = append(merged.Results, fresh.Results...)
+	merged.TotalSize += fresh.TotalSize
+	rules.SortBySize(merged.Results)
+
+	for _, sp := range prev.Skipped {
+		if !stale[sp.Rule] {
+			merged.Skipped = append(merged.Skipped, sp)
+		}
+	}
+	merged.Skipped = append(merged.Skipped, fresh.Skipped...)
+	for _, e := range prev.Errors {
+		if !stale[e.Rule] {
+			merged.Errors = append(merged.Errors, e)
+		}
+	}
+	merged.Errors = append(merged.Errors, fresh.Errors...)
+	for _, h := range prev.Hidden {
+		if !stale[h.Rule] {
+			merged.Hidden = append(merged.Hidden, h)
+		}
+	}
+	merged.Hidden = append(merged.Hidden, fresh.Hidden...)
+	merged.Timings = fresh.Timings
+	merged.Disk = diskSummary(merged.TotalSize)
+	return merged, nil
+}
+
+func cachePath() string {
+	return paths.ScanCacheFile()
+}
+
+// SaveCache stores the results of an unfiltered scan with registry's rules,
+// including what the user dismissed.
+func SaveCache(results *ScanResults, registry *rules.Registry) error {
+	return saveScan(cachePath(), results, registry)
+}
+
+// LoadCache returns the most recent cached scan, or an error if none exists.
+func LoadCache() (*CachedScan, error) {
+	return loadScan(cachePath())
+}
+
+// SaveListing stores the results as 'burrow scan' listed them, filters and
+// all, so a later 'clean --ids' can refer to them by number.
+func SaveListing(results *ScanResults, registry *rules.Registry) error {
+	return saveScan(paths.ScanListingFile(), results, registry)
+}
+
+// LoadListing returns the results last listed by 'burrow scan'.
+func LoadListing() (*CachedScan, error) {
+	return loadScan(paths.ScanListingFile())
+}
+
+func saveScan(path string, results *ScanResults, registry *rules.Registry) error {
+	data, err := json.Marshal(CachedScan{
+		Timestamp:  time.Now(),
+		Results:    results,
+		RuleHashes: ruleHashes(registry),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func loadScan(path string) (*CachedScan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cached CachedScan
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, err
+	}
+	return &cached, nil
+}
@@ -0,0 +1,164 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/paths"
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+func TestCachedScanValidate(t *testing.T) {
+	t.Setenv(paths.ConfigEnv, t.TempDir())
+	dir := t.TempDir()
+	stable := filepath.Join(dir, "stable")
+	busy := filepath.Join(dir, "busy")
+	past := time.Now().Add(-time.Hour)
+	for _, p := range []string{stable, busy} {
+		if err := os.Mkdir(p, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(p, "data"), make([]byte, 100), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(p, past, past); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stableRule := rules.CleanupRule{Name: "Stable", Paths: []string{stable}}
+	busyRule := rules.CleanupRule{Name: "Busy", Paths: []string{busy}}
+	registry := rules.NewRegistryFromRules([]rules.CleanupRule{stableRule, busyRule})
+	cached := &CachedScan{
+		Timestamp: time.Now().Add(-time.Minute),
+		Results: &ScanResults{TotalSize: 200, Results: []rules.Result{
+			{Rule: stableRule, FoundPaths: []string{stable}, TotalSize: 100},
+			{Rule: busyRule, FoundPaths: []string{busy}, TotalSize: 100},
+		}},
+		RuleHashes: ruleHashes(registry),
+	}
+
+	if err := cached.Validate(time.Hour); err != nil {
+		t.Errorf("expected a valid cache, got %v", err)
+	}
+	if err := cached.Validate(time.Second); err == nil {
+		t.Error("expected the TTL to expire the cache")
+	}
+	if stale := cached.StaleRules(registry); len(stale) != 0 {
+		t.Errorf("expected no stale rules, got %v", stale)
+	}
+
+	// Adding an entry bumps the directory's mtime past the scan
+	if err := os.WriteFile(filepath.Join(busy, "new"), make([]byte, 50), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stale := cached.StaleRules(registry)
+	if len(stale) != 1 || stale[0] != "Busy" {
+		t.Fatalf("expected only Busy to be stale, got %v", stale)
+	}
+
+	s := NewScanner(registry, ScanOptions{})
+	refreshed, err := s.Refresh(cached.Results, stale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(refreshed.Results) != 2 || refreshed.TotalSize != 250 {
+		t.Errorf("expected the busy rule to be rescanned (total 250), got %d results, %d bytes", len(refreshed.Results), refreshed.TotalSize)
+	}
+	if refreshed.Results[0].Rule.Name != "Busy" {
+		t.Error("expected the refreshed results to stay in size order")
+	}
+	if cached.Results.TotalSize != 200 {
+		t.Error("Refresh must not modify the cached results")
+	}
+}
+
+func TestStaleRulesComparesDefinitions(t *testing.T) {
+	dir := t.TempDir()
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(dir, past, past); err != nil {
+		t.Fatal(err)
+	}
+	kept := rules.CleanupRule{Name: "Kept", Paths: []string{dir}}
+	edited := rules.CleanupRule{Name: "Edited", Paths: []string{"~/a"}}
+	removed := rules.CleanupRule{Name: "Removed", Paths: []string{"~/b"}}
+	cached := &CachedScan{
+		Timestamp:  time.Now(),
+		Results:    &ScanResults{Results: []rules.Result{{Rule: kept, FoundPaths: []string{dir}}}},
+		RuleHashes: ruleHashes(rules.NewRegistryFromRules([]rules.CleanupRule{kept, edited, removed})),
+	}
+
+	// A pattern added below the top level of a rule changes its definition
+	edited.ExcludePatterns = []string{"*.keep"}
+	added := rules.CleanupRule{Name: "Added", Paths: []string{"~/c"}}
+	stale := cached.StaleRules(rules.NewRegistryFromRules([]rules.CleanupRule{kept, edited, added}))
+	if want := []string{"Added", "Edited", "Removed"}; !slices.Equal(stale, want) {
+		t.Errorf("stale = %v, want %v", stale, want)
+	}
+
+	if err := (&CachedScan{Results: cached.Results, Timestamp: time.Now()}).Validate(time.Hour); err == nil {
+		t.Error("expected a cache without rule hashes to be invalid")
+	}
+}
+
+func TestFilterAppliesDismissals(t *testing.T) {
+	dir := t.TempDir()
+	kept := filepath.Join(dir, "kept")
+	gone := filepath.Join(dir, "gone")
+	logs := filepath.Join(dir, "logs")
+	dismissedLog := filepath.Join(logs, "old.log")
+	if err := os.Mkdir(logs, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for p, size := range map[string]int{dismissedLog: 30, filepath.Join(logs, "new.log"): 20} {
+		if err := os.WriteFile(p, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cacheRule := rules.CleanupRule{Name: "Cache", Paths: []string{kept, gone}}
+	logRule := rules.CleanupRule{Name: "Logs", Paths: []string{logs}}
+	otherRule := rules.CleanupRule{Name: "Other", Paths: []string{dir}}
+	cached := &ScanResults{TotalSize: 460, Results: []rules.Result{
+		{Rule: cacheRule, FoundPaths: []string{kept, gone}, TotalSize: 300, Stats: []rules.PathStats{
+			{Path: kept, Size: 100, Files: 1},
+			{Path: gone, Size: 200, Files: 2},
+		}},
+		{Rule: logRule, FoundPaths: []string{dismissedLog, filepath.Join(logs, "new.log")}, TotalSize: 50, Stats: []rules.PathStats{
+			{Path: logs, Size: 50, Files: 2},
+		}},
+		{Rule: otherRule, FoundPaths: []string{dir}, TotalSize: 110},
+	}}
+
+	registry := rules.NewRegistryFromRules([]rules.CleanupRule{cacheRule, logRule, otherRule})
+	s := NewScanner(registry, ScanOptions{DismissedRules: []string{"Other"}, DismissedPaths: []string{gone, dismissedLog}})
+	res := s.Filter(cached)
+
+	if len(res.Results) != 2 {
+		t.Fatalf("expected the dismissed rule to be dropped, got %d results", len(res.Results))
+	}
+	cache, logResult := res.Results[0], res.Results[1]
+	if len(cache.FoundPaths) != 1 || cache.FoundPaths[0] != kept || cache.TotalSize != 100 || len(cache.Stats) != 1 {
+		t.Errorf("expected only %s (100 bytes) to remain, got %v (%d bytes, %d stats)", kept, cache.FoundPaths, cache.TotalSize, len(cache.Stats))
+	}
+	if len(logResult.FoundPaths) != 1 || logResult.TotalSize != 20 || logResult.Stats[0].Size != 20 || logResult.Stats[0].Files != 1 {
+		t.Errorf("expected the dismissed log to leave 20 bytes in 1 file, got %v (%d bytes, stats %+v)", logResult.FoundPaths, logResult.TotalSize, logResult.Stats[0])
+	}
+	if res.TotalSize != 120 {
+		t.Errorf("expected 120 bytes in total, got %d", res.TotalSize)
+	}
+	if len(res.Skipped) != 2 || res.Skipped[0].Reason != "dismissed" {
+		t.Errorf("expected both dismissed paths to be reported, got %+v", res.Skipped)
+	}
+	if len(cached.Results[0].FoundPaths) != 2 || cached.Results[1].Stats[0].Size != 50 {
+		t.Error("Filter must not modify the cached results")
+	}
+
+	// Without dismissals the cached scan is kept whole
+	if all := NewScanner(registry, s.options.WithoutDismissals()).Filter(cached); all.TotalSize != 460 {
+		t.Errorf("expected 460 bytes without dismissals, got %d", all.TotalSize)
+	}
+}
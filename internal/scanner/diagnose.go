@@ -0,0 +1,130 @@
+package scanner
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/safety"
+)
+
+// PathStatus describes why a rule path would or would not produce scan results.
+type PathStatus string
+
+const (
+	PathOK          PathStatus = "OK"
+	PathMissing     PathStatus = "Missing"
+	PathExcluded    PathStatus = "Excluded"
+	PathDisabled    PathStatus = "Category Disabled"
+	PathNoAccess    PathStatus = "Permission Denied"
+	PathTCCBlocked  PathStatus = "Blocked by TCC"
+	PathUnsafe      PathStatus = "Unsafe"
+	PathNeedsRoot   PathStatus = "Requires Root"
+	PathUnreachable PathStatus = "Error"
+)
+
+// PathDiagnosis is the accessibility report for a single rule path.
+type PathDiagnosis struct {
+	Path   string     `json:"path"`
+	Status PathStatus `json:"status"`
+	Detail string     `json:"detail,omitempty"`
+}
+
+// RuleDiagnosis groups the path reports of one rule.
+type RuleDiagnosis struct {
+	Rule  rules.CleanupRule `json:"rule"`
+	Paths []PathDiagnosis   `json:"paths"`
+}
+
+// Diagnose checks every path of every registered rule and reports whether it
+// exists, is readable, and would pass the exclusion and safety filters.
+func Diagnose(registry *rules.Registry, excludedPaths, disabledCategories []string) []RuleDiagnosis {
+	var report []RuleDiagnosis
+	for _, rule := range registry.All() {
+		diag := RuleDiagnosis{Rule: rule}
+		disabled := false
+		for _, cat := range disabledCategories {
+			if strings.EqualFold(cat, rule.Category) {
+				disabled = true
+				break
+			}
+		}
+
+		for _, pathPattern := range rule.Paths {
+			for _, expanded := range expandRulePath(pathPattern) {
+				diag.Paths = append(diag.Paths, diagnosePath(expanded, rule, disabled, excludedPaths))
+			}
+		}
+		report = append(report, diag)
+	}
+	return report
+}
+
+func diagnosePath(path string, rule rules.CleanupRule, disabled bool, excludedPaths []string) PathDiagnosis {
+	d := PathDiagnosis{Path: path}
+
+	if disabled {
+		d.Status = PathDisabled
+		return d
+	}
+	if isExcluded(path, excludedPaths) {
+		d.Status = PathExcluded
+		return d
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		d.Status, d.Detail = classifyAccessError(err)
+		return d
+	}
+
+	f, err := os.Open(path)
+	if err == nil {
+		_, err = f.Readdirnames(1)
+		f.Close()
+		// An empty directory yields io.EOF, and plain files can't list entries; both are readable
+		if err != nil && !errors.Is(err, os.ErrPermission) {
+			err = nil
+		}
+	}
+	if err != nil {
+		d.Status, d.Detail = classifyAccessError(err)
+		if rule.RequiresRoot && d.Status == PathNoAccess {
+			d.Status = PathNeedsRoot
+			d.Detail = "run 'burrow clean --system'"
+		}
+		return d
+	}
+
+	if v := safety.CheckWith(path, rule.SafetyOptions()); !v.Safe {
+		d.Status = PathUnsafe
+		d.Detail = v.Reason
+		return d
+	}
+
+	d.Status = PathOK
+	return d
+}
+
+// classifyAccessError distinguishes plain permission errors from macOS privacy
+// (TCC) denials, which surface as EPERM rather than EACCES.
+func classifyAccessError(err error) (PathStatus, string) {
+	if os.IsNotExist(err) {
+		return PathMissing, ""
+	}
+
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		switch errno {
+		case syscall.EPERM:
+			return PathTCCBlocked, "grant Full Disk Access to your terminal"
+		case syscall.EACCES:
+			return PathNoAccess, "check ownership and permissions"
+		}
+	}
+	if os.IsPermission(err) {
+		return PathNoAccess, "check ownership and permissions"
+	}
+	return PathUnreachable, err.Error()
+}
@@ -0,0 +1,56 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+func TestDiagnosePath(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "burrow_diag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cacheDir := filepath.Join(tmpDir, "cache")
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	rule := rules.CleanupRule{Name: "Test", Category: "Test"}
+
+	tests := []struct {
+		name     string
+		path     string
+		disabled bool
+		excluded []string
+		want     PathStatus
+	}{
+		{"Existing directory", cacheDir, false, nil, PathOK},
+		{"Missing directory", filepath.Join(tmpDir, "missing"), false, nil, PathMissing},
+		{"Excluded directory", cacheDir, false, []string{cacheDir}, PathExcluded},
+		{"Disabled category", cacheDir, true, nil, PathDisabled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diagnosePath(tt.path, rule, tt.disabled, tt.excluded)
+			if got.Status != tt.want {
+				t.Errorf("diagnosePath(%v) = %v (%s), want %v", tt.path, got.Status, got.Detail, tt.want)
+			}
+		})
+	}
+}
+
+func TestIncludesDisabledCategory(t *testing.T) {
+	opts := ScanOptions{DisabledCategories: []string{"developer tools"}}
+	if opts.Includes(rules.CleanupRule{Name: "npm", Category: "Developer Tools"}) {
+		t.Error("rule in a disabled category included")
+	}
+	if !opts.Includes(rules.CleanupRule{Name: "Safari", Category: "Browsers"}) {
+		t.Error("rule in an enabled category excluded")
+	}
+}
@@ -0,0 +1,95 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"syscall"
+)
+
+// ErrorKind classifies why a rule path could not be scanned.
+type ErrorKind string
+
+const (
+	KindPermissionDenied ErrorKind = "PermissionDenied"
+	KindNotFound         ErrorKind = "NotFound" // Vanished while being scanned
+	KindUnsafe           ErrorKind = "Unsafe"   // Refused by a safety guard
+	KindIO               ErrorKind = "IO"
+	KindTimedOut         ErrorKind = "TimedOut" // Walk ran past the rule timeout
+)
+
+// Sentinel errors matched by PathError via errors.Is.
+var (
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrNotFound         = errors.New("not found")
+	ErrUnsafe           = errors.New("unsafe path")
+	ErrTimedOut         = errors.New("timed out")
+)
+
+// PathError is a rule path that exists but could not be (fully) scanned.
+// Results may still include data from the readable part of the path.
+type PathError struct {
+	Kind   ErrorKind `json:"kind"`
+	Rule   string    `json:"rule"`
+	Path   string    `json:"path"`
+	Detail string    `json:"detail,omitempty"`
+}
+
+func (e *PathError) Error() string {
+	if e.Detail == "" {
+		return fmt.Sprintf("%s: %s (%s)", e.Path, e.Kind, e.Rule)
+	}
+	return fmt.Sprintf("%s: %s: %s (%s)", e.Path, e.Kind, e.Detail, e.Rule)
+}
+
+// Is lets errors.Is match a PathError against the sentinel for its kind.
+func (e *PathError) Is(target error) bool {
+	switch target {
+	case ErrPermissionDenied:
+		return e.Kind == KindPermissionDenied
+	case ErrNotFound:
+		return e.Kind == KindNotFound
+	case ErrUnsafe:
+		return e.Kind == KindUnsafe
+	case ErrTimedOut:
+		return e.Kind == KindTimedOut
+	}
+	return false
+}
+
+// newPathError classifies a filesystem error encountered under path.
+func newPathError(rule, path string, err error) *PathError {
+	e := &PathError{Kind: KindIO, Rule: rule, Path: path, Detail: err.Error()}
+	var errno syscall.Errno
+	switch {
+	case os.IsNotExist(err):
+		e.Kind, e.Detail = KindNotFound, "removed during the scan"
+	case os.IsPermission(err), errors.As(err, &errno) && errno == syscall.EPERM:
+		_, e.Detail = classifyAccessError(err)
+		e.Kind = KindPermissionDenied
+	}
+	return e
+}
+
+// Err joins the path errors of the scan, or returns nil when every existing
+// rule path was read in full.
+func (r *ScanResults) Err() error {
+	errs := make([]error, len(r.Errors))
+	for i, e := range r.Errors {
+		errs[i] = e
+	}
+	return errors.Join(errs...)
+}
+
+// TimedOut returns the rules with paths whose walk ran past the rule
+// timeout, so their sizes are unknown or incomplete.
+func (r *ScanResults) TimedOut() []string {
+	var names []string
+	for _, e := range r.Errors {
+		if e.Kind == KindTimedOut && !slices.Contains(names, e.Rule) {
+			names = append(names, e.Rule)
+		}
+	}
+	return names
+}
@@ -0,0 +1,100 @@
+package scanner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+func TestScanTypedErrors(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// A cache inside a Git working tree is refused by the safety guard
+	repo := filepath.Join(tmpDir, "repo")
+	unsafe := filepath.Join(repo, "cache")
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(unsafe, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	registry := rules.NewRegistryFromRules([]rules.CleanupRule{
+		{Name: "Unsafe", Paths: []string{unsafe}},
+		{Name: "Missing", Paths: []string{filepath.Join(tmpDir, "missing")}},
+	})
+	results, err := NewScanner(registry, ScanOptions{}).Scan()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results.Results) != 0 {
+		t.Fatalf("expected no results, got %d", len(results.Results))
+	}
+	if len(results.Errors) != 1 {
+		t.Fatalf("expected 1 error (missing rule paths are not errors), got %v", results.Errors)
+	}
+	if e := results.Errors[0]; e.Kind != KindUnsafe || e.Rule != "Unsafe" || e.Path != unsafe {
+		t.Errorf("unexpected error: %+v", e)
+	}
+	if !errors.Is(results.Err(), ErrUnsafe) || errors.Is(results.Err(), ErrPermissionDenied) {
+		t.Errorf("Err() should match ErrUnsafe only: %v", results.Err())
+	}
+}
+
+func TestNewPathError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want ErrorKind
+	}{
+		{os.ErrNotExist, KindNotFound},
+		{&os.PathError{Op: "open", Path: "/x", Err: os.ErrPermission}, KindPermissionDenied},
+		{errors.New("input/output error"), KindIO},
+	}
+	for _, tt := range tests {
+		if got := newPathError("Rule", "/x", tt.err).Kind; got != tt.want {
+			t.Errorf("newPathError(%v) kind = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestScanPartlyUnreadable(t *testing.T) {
+	root := t.TempDir()
+	cache := filepath.Join(root, "cache")
+	for _, name := range []string{"a/data", "locked/data", "z/data"} {
+		path := filepath.Join(cache, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, 100), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Report the locked directory as unreadable, as a walk as root can't
+	locked := filepath.Join(cache, "locked")
+	defer func(w func(string, filepath.WalkFunc) error) { walk = w }(walk)
+	walk = func(path string, fn filepath.WalkFunc) error {
+		return filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+			if p == locked {
+				return fn(p, info, &os.PathError{Op: "open", Path: p, Err: os.ErrPermission})
+			}
+			return fn(p, info, err)
+		})
+	}
+
+	registry := rules.NewRegistryFromRules([]rules.CleanupRule{{Name: "Cache", Paths: []string{cache}}})
+	results, err := NewScanner(registry, ScanOptions{}).Scan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.Results) != 1 || results.Results[0].TotalSize != 200 {
+		t.Fatalf("results = %+v, want the readable 200 bytes", results.Results)
+	}
+	if len(results.Errors) != 1 || results.Errors[0].Kind != KindPermissionDenied || results.Errors[0].Path != locked {
+		t.Errorf("errors = %+v, want one permission error for %s", results.Errors, locked)
+	}
+}
@@ -0,0 +1,99 @@
+package scanner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/units"
+)
+
+// PathEvaluation is the step-by-step outcome of one rule path, as used by
+// `burrow rules test`.
+type PathEvaluation struct {
+	Pattern  string        `json:"pattern"`
+	Path     string        `json:"path"`
+	Status   PathStatus    `json:"status"`
+	Detail   string        `json:"detail,omitempty"`
+	Size     int64         `json:"size"`
+	Files    int           `json:"files,omitempty"` // Matching files for pattern rules
+	Age      time.Duration `json:"age"`
+	Selected bool          `json:"selected"`
+	Filter   string        `json:"filter,omitempty"` // Why an otherwise valid path was filtered out
+}
+
+// Evaluate runs a single rule against the filesystem and reports what the
+// scanner would do with each of its paths, without caching or side effects.
+func (s *Scanner) Evaluate(r rules.CleanupRule) []PathEvaluation {
+	var evals []PathEvaluation
+	for _, pattern := range r.Paths {
+		for _, expanded := range expandRulePath(pattern) {
+			evals = append(evals, s.evaluatePath(r, pattern, expanded))
+		}
+	}
+	return evals
+}
+
+// evaluatePath evaluates one expanded rule path.
+func (s *Scanner) evaluatePath(r rules.CleanupRule, pattern, expanded string) PathEvaluation {
+	eval := PathEvaluation{Pattern: pattern, Path: expanded, Status: PathOK}
+
+	if d := diagnosePath(expanded, r, false, s.options.ExcludedPaths); d.Status != PathOK {
+		eval.Status, eval.Detail = d.Status, d.Detail
+		return eval
+	}
+
+	info, err := os.Stat(expanded)
+	if err != nil {
+		eval.Status, eval.Detail = classifyAccessError(err)
+		return eval
+	}
+	eval.Age = time.Since(info.ModTime())
+	if _, lastUsed := describeItem(r.Items, expanded); !lastUsed.IsZero() {
+		eval.Age = time.Since(lastUsed)
+	}
+
+	if r.FileLevel() {
+		paths, size, _, _ := s.matchFiles(expanded, r, time.Time{})
+		eval.Files, eval.Size = len(paths), size
+		switch {
+		case len(paths) == 0:
+			eval.Filter = "no files match the include/exclude patterns and age limit"
+		case s.options.SizeThreshold > 0 && size < s.options.SizeThreshold:
+			eval.Filter = "below size threshold " + units.FormatSize(s.options.SizeThreshold)
+		default:
+			eval.Selected = true
+		}
+		return eval
+	}
+
+	size, err := dirSize(expanded)
+	var partial *partialError
+	if err != nil && !errors.As(err, &partial) {
+		eval.Status, eval.Detail = classifyAccessError(err)
+		return eval
+	}
+	eval.Size = size
+	if partial != nil {
+		eval.Detail = "size leaves out unreadable " + partial.path
+	}
+
+	inUse := itemInUse(r.Items, expanded)
+	switch {
+	case inUse != "":
+		eval.Filter = inUse
+	case newIgnorer(filepath.Dir(expanded)).ignored(expanded, true):
+		eval.Filter = "listed in " + filepath.Join(filepath.Dir(expanded), IgnoreFile)
+	case hasIgnoreFile(expanded):
+		eval.Filter = "contains " + filepath.Join(expanded, IgnoreFile)
+	case s.options.OlderThan > 0 && eval.Age < s.options.OlderThan:
+		eval.Filter = "modified more recently than the age filter"
+	case s.options.SizeThreshold > 0 && size < s.options.SizeThreshold:
+		eval.Filter = "below size threshold " + units.FormatSize(s.options.SizeThreshold)
+	default:
+		eval.Selected = true
+	}
+	return eval
+}
@@ -0,0 +1,34 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+func TestEvaluate(t *testing.T) {
+	base := t.TempDir()
+	small := filepath.Join(base, "small")
+	if err := os.MkdirAll(small, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(small, "data"), []byte("cache"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rule := rules.CleanupRule{Name: "Test", Paths: []string{small, filepath.Join(base, "missing")}}
+	s := NewScanner(rules.NewRegistryFromRules(nil), ScanOptions{SizeThreshold: 1024})
+	evals := s.Evaluate(rule)
+
+	if len(evals) != 2 {
+		t.Fatalf("expected 2 evaluations, got %d", len(evals))
+	}
+	if evals[0].Status != PathOK || evals[0].Selected || evals[0].Size != 5 || evals[0].Filter == "" {
+		t.Errorf("small path: got %+v, want OK but filtered by size", evals[0])
+	}
+	if evals[1].Status != PathMissing {
+		t.Errorf("missing path: got status %s, want %s", evals[1].Status, PathMissing)
+	}
+}
@@ -0,0 +1,176 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/units"
+)
+
+func TestIsExcluded(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		excluded []string
+		want     bool
+	}{
+		{"Exact match", "/cache/Foo", []string{"/cache/Foo"}, true},
+		{"Child of exclusion", "/cache/Foo/sub", []string{"/cache/Foo"}, true},
+		{"Prefix collision", "/cache/FooBar", []string{"/cache/Foo"}, false},
+		{"Trailing slash", "/cache/Foo", []string{"/cache/Foo/"}, true},
+		{"Traversal in exclusion", "/cache/Foo", []string{"/cache/Bar/../Foo"}, true},
+		{"Glob match", "/cache/com.mycompany.app", []string{"/cache/com.mycompany.*"}, true},
+		{"Glob child", "/cache/com.mycompany.app/data", []string{"/cache/com.mycompany.*"}, true},
+		{"Glob miss", "/cache/com.other.app", []string{"/cache/com.mycompany.*"}, false},
+		{"No exclusions", "/cache/Foo", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isExcluded(tt.path, tt.excluded); got != tt.want {
+				t.Errorf("isExcluded(%q, %v) = %v, want %v", tt.path, tt.excluded, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanReportsSkippedPaths(t *testing.T) {
+	base := t.TempDir()
+	kept := filepath.Join(base, "kept")
+	excluded := filepath.Join(base, "excluded")
+	for _, dir := range []string{kept, excluded} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "data"), []byte("cache"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	registry := rules.NewRegistryFromRules([]rules.CleanupRule{{
+		Name:  "Temp Rule",
+		Paths: []string{kept, excluded, filepath.Join(base, "missing")},
+	}})
+	results, err := NewScanner(registry, ScanOptions{ExcludedPaths: []string{excluded}}).Scan()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results.Results) != 1 || len(results.Results[0].FoundPaths) != 1 {
+		t.Fatalf("expected only %s in results, got %+v", kept, results.Results)
+	}
+	if len(results.Skipped) != 1 || results.Skipped[0].Path != excluded {
+		t.Fatalf("expected %s to be reported as skipped, got %+v", excluded, results.Skipped)
+	}
+}
+
+func TestScanHidesSmallResults(t *testing.T) {
+	base := t.TempDir()
+	small := filepath.Join(base, "small")
+	large := filepath.Join(base, "large")
+	for dir, size := range map[string]int{small: 2 << 10, large: 64 << 10} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "data"), make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	registry := rules.NewRegistryFromRules([]rules.CleanupRule{
+		{Name: "Small", Paths: []string{small}},
+		{Name: "Large", Paths: []string{large}},
+		{Name: "Picky", Paths: []string{large}, MinSize: units.Size(units.MB)},
+	})
+	results, err := NewScanner(registry, ScanOptions{MinSize: 16 << 10}).Scan()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results.Results) != 1 || results.Results[0].Rule.Name != "Large" {
+		t.Fatalf("expected only Large in results, got %+v", results.Results)
+	}
+	if results.TotalSize != 64<<10 {
+		t.Errorf("TotalSize = %d, want only the visible result", results.TotalSize)
+	}
+	want := []HiddenResult{
+		{Rule: "Picky", Size: 64 << 10, MinSize: units.MB},
+		{Rule: "Small", Size: 2 << 10, MinSize: 16 << 10},
+	}
+	if !reflect.DeepEqual(results.Hidden, want) {
+		t.Errorf("Hidden = %+v, want %+v", results.Hidden, want)
+	}
+}
+
+func TestScanExpandsGlobPaths(t *testing.T) {
+	base := t.TempDir()
+	for name, size := range map[string]int{"models--a/blob": 3 << 10, "models--b/blob": 5 << 10, "other/blob": 7 << 10} {
+		path := filepath.Join(base, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	registry := rules.NewRegistryFromRules([]rules.CleanupRule{
+		{Name: "Models", Paths: []string{filepath.Join(base, "models--*"), filepath.Join(base, "none--*")}},
+	})
+	results, err := NewScanner(registry, ScanOptions{}).Scan()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results.Results) != 1 {
+		t.Fatalf("expected one result, got %+v", results.Results)
+	}
+	res := results.Results[0]
+	want := []string{filepath.Join(base, "models--a"), filepath.Join(base, "models--b")}
+	if !reflect.DeepEqual(res.FoundPaths, want) || res.TotalSize != 8<<10 {
+		t.Errorf("FoundPaths = %v (%d bytes), want %v (8 KB)", res.FoundPaths, res.TotalSize, want)
+	}
+	if len(res.Stats) != 2 || res.Stats[1].Size != 5<<10 {
+		t.Errorf("Stats = %+v, want a size per match", res.Stats)
+	}
+}
+
+func TestScanCountsAllocatedBlocksOfSparseFiles(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("allocated size is not tracked on Windows")
+	}
+	disk := filepath.Join(t.TempDir(), "Docker.raw")
+	f, err := os.Create(disk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write(make([]byte, 64<<10)); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Truncate(1 << 30); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	registry := rules.NewRegistryFromRules([]rules.CleanupRule{
+		{Name: "VM", Paths: []string{disk}, SparseFiles: true, ReportOnly: true},
+	})
+	results, err := NewScanner(registry, ScanOptions{}).Scan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.Results) != 1 {
+		t.Fatalf("expected one result, got %+v", results.Results)
+	}
+	res := results.Results[0]
+	if res.TotalSize >= 1<<30 || res.TotalSize < 64<<10 {
+		t.Errorf("TotalSize = %d, want the allocated size of the sparse file", res.TotalSize)
+	}
+	if res.Stats[0].Size != 1<<30 {
+		t.Errorf("apparent size = %d, want %d", res.Stats[0].Size, 1<<30)
+	}
+}
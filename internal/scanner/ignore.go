@@ -0,0 +1,169 @@
+package scanner
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFile marks files and folders inside a scanned directory that Burrow
+// must never suggest, using gitignore-style patterns.
+const IgnoreFile = ".burrowignore"
+
+// hasIgnoreFile reports whether dir holds an ignore file of its own. Such a
+// directory is never suggested as a whole: removing it would take the ignore
+// file and everything it protects along.
+func hasIgnoreFile(dir string) bool {
+	info, err := os.Lstat(filepath.Join(dir, IgnoreFile))
+	return err == nil && info.Mode().IsRegular()
+}
+
+// ignorePattern is one line of an ignore file.
+type ignorePattern struct {
+	glob     string // Slash-separated
+	negate   bool   // "!pattern" re-includes what an earlier pattern ignored
+	dirOnly  bool   // "pattern/" only matches directories
+	anchored bool   // Patterns containing a slash match from the file's directory
+}
+
+// ignoreList holds the patterns of the ignore file in dir.
+type ignoreList struct {
+	dir      string
+	patterns []ignorePattern
+}
+
+// loadIgnoreList parses dir's ignore file, returning nil when there is none.
+func loadIgnoreList(dir string) *ignoreList {
+	f, err := os.Open(filepath.Join(dir, IgnoreFile))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	list := &ignoreList{dir: dir}
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var p ignorePattern
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		}
+		line = strings.TrimPrefix(line, `\`) // "\#file" and "\!file" are literal
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			p.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		p.glob = line
+		list.patterns = append(list.patterns, p)
+	}
+	return list
+}
+
+// match reports whether any pattern applies to target and, if so, whether
+// the last applicable one ignores it.
+func (l *ignoreList) match(target string, isDir bool) (matched, ignored bool) {
+	rel, err := filepath.Rel(l.dir, target)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false, false
+	}
+	rel = filepath.ToSlash(rel)
+
+	for _, p := range l.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		subject := rel
+		if !p.anchored {
+			subject = path.Base(rel)
+		}
+		if globMatch(p.glob, subject) {
+			matched, ignored = true, !p.negate
+		}
+	}
+	return matched, ignored
+}
+
+// globMatch matches a slash-separated glob where "**" spans any number of
+// path segments, including none.
+func globMatch(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// ignorer applies every ignore file between a walk's root and the visited
+// path, so nested files refine their parents like .gitignore does. It is not
+// safe for concurrent use; each walk gets its own.
+type ignorer struct {
+	root  string
+	lists map[string]*ignoreList // Keyed by directory; nil when it has no file
+}
+
+func newIgnorer(root string) *ignorer {
+	return &ignorer{root: filepath.Clean(root), lists: make(map[string]*ignoreList)}
+}
+
+// ignored reports whether target is excluded by an ignore file in the root
+// or any directory between it and target. The ignore files themselves are
+// always ignored so they never end up in the trash.
+func (ig *ignorer) ignored(target string, isDir bool) bool {
+	if !isDir && filepath.Base(target) == IgnoreFile {
+		return true
+	}
+
+	var dirs []string
+	for d := filepath.Dir(target); ; d = filepath.Dir(d) {
+		dirs = append(dirs, d)
+		if d == ig.root || filepath.Dir(d) == d {
+			break
+		}
+	}
+
+	ignored := false
+	for i := len(dirs) - 1; i >= 0; i-- {
+		list, seen := ig.lists[dirs[i]]
+		if !seen {
+			list = loadIgnoreList(dirs[i])
+			ig.lists[dirs[i]] = list
+		}
+		if list == nil {
+			continue
+		}
+		if matched, ign := list.match(target, isDir); matched {
+			ignored = ign
+		}
+	}
+	return ignored
+}
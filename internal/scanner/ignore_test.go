@@ -0,0 +1,109 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+func TestIgnorer(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		IgnoreFile:               "# keep these\n*.iso\n/renders/\nkeep/**/final.mov\n",
+		"projects/" + IgnoreFile: "!important.iso\nscratch/\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"movie.mov", false, false},
+		{"disk.iso", false, true},
+		{"deep/disk.iso", false, true},
+		{"renders", true, true},
+		{"deep/renders", true, false}, // Anchored to the ignore file's directory
+		{"keep/final.mov", false, true},
+		{"keep/a/b/final.mov", false, true},
+		{"projects/important.iso", false, false}, // Re-included by the nested file
+		{"projects/other.iso", false, true},
+		{"projects/scratch", true, true},
+		{"projects/scratch", false, false}, // Directory-only pattern
+		{IgnoreFile, false, true},
+	}
+	ig := newIgnorer(root)
+	for _, tt := range tests {
+		if got := ig.ignored(filepath.Join(root, tt.path), tt.isDir); got != tt.want {
+			t.Errorf("ignored(%q, dir=%v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+}
+
+func TestScanner_LargeFilesHonorIgnoreFile(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		IgnoreFile:        "keep.dmg\narchive/\n",
+		"keep.dmg":        "",
+		"drop.dmg":        "",
+		"archive/old.dmg": "",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if content == "" {
+			content = string(make([]byte, 2048))
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewScanner(nil, ScanOptions{SizeThreshold: 1024})
+	found, _ := s.walkLargeFiles(root, 0, 1024)
+	sort.Strings(found)
+	if len(found) != 1 || found[0] != filepath.Join(root, "drop.dmg") {
+		t.Errorf("found %v, want only drop.dmg", found)
+	}
+}
+
+func TestScanner_SkipsTargetWithOwnIgnoreFile(t *testing.T) {
+	root := t.TempDir()
+	kept, dropped := filepath.Join(root, "kept"), filepath.Join(root, "dropped")
+	for _, dir := range []string{kept, dropped} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "data"), []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(kept, IgnoreFile), []byte("data\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	registry := rules.NewRegistryFromRules([]rules.CleanupRule{{Name: "Caches", Paths: []string{kept, dropped}}})
+	results, err := NewScanner(registry, ScanOptions{}).Scan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.Results) != 1 || len(results.Results[0].FoundPaths) != 1 || results.Results[0].FoundPaths[0] != dropped {
+		t.Fatalf("results = %+v, want only %s", results.Results, dropped)
+	}
+	if len(results.Skipped) != 1 || results.Skipped[0].Path != kept {
+		t.Errorf("skipped = %+v, want %s", results.Skipped, kept)
+	}
+}
@@ -0,0 +1,98 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+// describeItem names the item at path for rules that list items of a kind,
+// and returns when it was last used if the item records that.
+func describeItem(kind, path string) (string, time.Time) {
+	switch kind {
+	case rules.ItemsXcodeProject:
+		return xcodeProject(path)
+	case rules.ItemsSimulatorRuntime:
+		return strings.TrimSuffix(filepath.Base(path), ".simruntime"), time.Time{}
+	case rules.ItemsAndroidAVD:
+		return androidAVD(path)
+	case rules.ItemsAndroidSnapshot:
+		return androidSnapshot(path), time.Time{}
+	case rules.ItemsAndroidSystemImage:
+		return systemImageName(path), time.Time{}
+	case rules.ItemsJetBrainsVersion:
+		return jetbrainsVersionName(path), time.Time{}
+	case rules.ItemsElectronApp:
+		return filepath.Base(filepath.Dir(path)), time.Time{}
+	case rules.ItemsBrowserProfile:
+		return browserProfile(path), time.Time{}
+	}
+	return "", time.Time{}
+}
+
+// itemInUse returns why the item at path must be kept, or "" when it can
+// be cleaned: a system image a virtual device boots from, the newest
+// version of a JetBrains IDE, or a cache folder of an app that is not
+// Electron or of a browser profile, which the browser rules list.
+func itemInUse(kind, path string) string {
+	switch kind {
+	case rules.ItemsAndroidSystemImage:
+		if avd, ok := systemImageUsers(androidAVDHome())[systemImageID(path)]; ok {
+			return "used by virtual device " + avd
+		}
+	case rules.ItemsJetBrainsVersion:
+		return jetbrainsKeep(path)
+	case rules.ItemsElectronApp:
+		if browserProfileDir(path) != "" {
+			return "browser profile"
+		}
+		if !isElectronApp(filepath.Dir(path)) {
+			return "not an Electron app"
+		}
+	}
+	return ""
+}
+
+// derivedDataName matches a DerivedData folder name: the project name and
+// the 28-letter hash of its path.
+var derivedDataName = regexp.MustCompile(`^(.+)-[a-z]{28}$`)
+
+// xcodeProject describes a DerivedData folder. Its info.plist records when
+// Xcode last opened or built the project.
+func xcodeProject(path string) (string, time.Time) {
+	name := filepath.Base(path)
+	if m := derivedDataName.FindStringSubmatch(name); m != nil {
+		name = m[1]
+	}
+	data, err := os.ReadFile(filepath.Join(path, "info.plist"))
+	if err != nil {
+		return name, time.Time{}
+	}
+	return name, plistDate(data, "LastAccessedDate")
+}
+
+// plistDate returns the date stored under key in an XML property list, or
+// the zero time.
+func plistDate(data []byte, key string) time.Time {
+	re := regexp.MustCompile(`<key>` + regexp.QuoteMeta(key) + `</key>\s*<date>([^<]+)</date>`)
+	m := re.FindSubmatch(data)
+	if m == nil {
+		return time.Time{}
+	}
+	t, _ := time.Parse(time.RFC3339, string(m[1]))
+	return t
+}
+
+// isElectronApp reports whether an app's support directory has the cache
+// layout of Chromium: a V8 Code Cache next to a web Cache or GPUCache.
+func isElectronApp(dir string) bool {
+	exists := func(name string) bool {
+		info, err := os.Stat(filepath.Join(dir, name))
+		return err == nil && info.IsDir()
+	}
+	return exists("Code Cache") && (exists("Cache") || exists("GPUCache"))
+}
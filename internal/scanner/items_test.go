@@ -0,0 +1,98 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+func TestDescribeXcodeProject(t *testing.T) {
+	root := t.TempDir()
+	project := filepath.Join(root, "MyApp-bxyzfkqjhdlqmtaowpcnrsgvieuz")
+	if err := os.MkdirAll(project, 0755); err != nil {
+		t.Fatal(err)
+	}
+	plist := `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>LastAccessedDate</key>
+	<date>2024-03-05T10:20:30Z</date>
+	<key>WorkspacePath</key>
+	<string>/Users/me/MyApp/MyApp.xcodeproj</string>
+</dict>
+</plist>`
+	if err := os.WriteFile(filepath.Join(project, "info.plist"), []byte(plist), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	name, lastUsed := describeItem(rules.ItemsXcodeProject, project)
+	if name != "MyApp" || !lastUsed.Equal(time.Date(2024, 3, 5, 10, 20, 30, 0, time.UTC)) {
+		t.Errorf("got %q, %v; want MyApp last used 2024-03-05", name, lastUsed)
+	}
+
+	// Shared folders keep their name and have no recorded use
+	name, lastUsed = describeItem(rules.ItemsXcodeProject, filepath.Join(root, "ModuleCache.noindex"))
+	if name != "ModuleCache.noindex" || !lastUsed.IsZero() {
+		t.Errorf("got %q, %v; want ModuleCache.noindex without a date", name, lastUsed)
+	}
+
+	if name, _ := describeItem(rules.ItemsSimulatorRuntime, "/Library/Developer/CoreSimulator/Profiles/Runtimes/iOS 17.2.simruntime"); name != "iOS 17.2" {
+		t.Errorf("runtime named %q, want iOS 17.2", name)
+	}
+
+	if name, _ := describeItem("", project); name != "" {
+		t.Errorf("rule without items named %q", name)
+	}
+}
+
+func TestElectronAppItems(t *testing.T) {
+	support := t.TempDir()
+	for _, dir := range []string{"Slack/Cache", "Slack/Code Cache", "Notion/GPUCache", "Notion/Code Cache", "AddressBook/Cache"} {
+		if err := os.MkdirAll(filepath.Join(support, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, path := range []string{"Slack/Cache", "Notion/GPUCache"} {
+		if reason := itemInUse(rules.ItemsElectronApp, filepath.Join(support, path)); reason != "" {
+			t.Errorf("%s kept: %s", path, reason)
+		}
+	}
+	if reason := itemInUse(rules.ItemsElectronApp, filepath.Join(support, "AddressBook", "Cache")); reason == "" {
+		t.Error("cache of an app without Code Cache was listed")
+	}
+	if name, _ := describeItem(rules.ItemsElectronApp, filepath.Join(support, "Slack", "Code Cache")); name != "Slack" {
+		t.Errorf("name = %q, want Slack", name)
+	}
+}
+
+func TestBrowserProfileItems(t *testing.T) {
+	support := t.TempDir()
+	chrome := filepath.Join(support, "Google", "Chrome")
+	for _, dir := range []string{"Default/GPUCache", "Default/Code Cache", "Profile 1/Service Worker/CacheStorage"} {
+		if err := os.MkdirAll(filepath.Join(chrome, filepath.FromSlash(dir)), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	state := `{"profile": {"info_cache": {"Default": {"name": "Personal"}, "Profile 1": {"name": "Work"}}}}`
+	if err := os.WriteFile(filepath.Join(chrome, "Local State"), []byte(state), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string]string{
+		"Default/GPUCache":                      "Chrome: Personal",
+		"Profile 1/Service Worker/CacheStorage": "Chrome: Work",
+	} {
+		if name, _ := describeItem(rules.ItemsBrowserProfile, filepath.Join(chrome, filepath.FromSlash(path))); name != want {
+			t.Errorf("%s: name = %q, want %q", path, name, want)
+		}
+	}
+
+	// The Electron rule leaves browser profiles to the browser rules
+	if reason := itemInUse(rules.ItemsElectronApp, filepath.Join(chrome, "Default", "GPUCache")); reason == "" {
+		t.Error("browser profile listed as an Electron app")
+	}
+}
@@ -0,0 +1,63 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+)
+
+// jetbrainsDir matches the versioned directories of JetBrains IDEs, such as
+// GoLand2024.1 or IntelliJIdea2023.3.
+var jetbrainsDir = regexp.MustCompile(`^([A-Za-z][A-Za-z-]*?)(\d{4})\.(\d+)$`)
+
+// ideVersion is the product and version of a JetBrains IDE directory.
+type ideVersion struct {
+	product     string
+	year, minor int
+}
+
+func parseIDEVersion(name string) (ideVersion, bool) {
+	m := jetbrainsDir.FindStringSubmatch(name)
+	if m == nil {
+		return ideVersion{}, false
+	}
+	year, _ := strconv.Atoi(m[2])
+	minor, _ := strconv.Atoi(m[3])
+	return ideVersion{product: m[1], year: year, minor: minor}, true
+}
+
+func (v ideVersion) newer(o ideVersion) bool {
+	if v.year != o.year {
+		return v.year > o.year
+	}
+	return v.minor > o.minor
+}
+
+func (v ideVersion) String() string {
+	return v.product + " " + strconv.Itoa(v.year) + "." + strconv.Itoa(v.minor)
+}
+
+// jetbrainsVersionName names a JetBrains IDE directory by product and version.
+func jetbrainsVersionName(path string) string {
+	if v, ok := parseIDEVersion(filepath.Base(path)); ok {
+		return v.String()
+	}
+	return filepath.Base(path)
+}
+
+// jetbrainsKeep returns why a JetBrains IDE directory is kept: it is not
+// versioned, or no newer version of the same product sits next to it.
+func jetbrainsKeep(path string) string {
+	v, ok := parseIDEVersion(filepath.Base(path))
+	if !ok {
+		return "not a versioned JetBrains IDE directory"
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	for _, e := range entries {
+		if o, ok := parseIDEVersion(e.Name()); ok && e.IsDir() && o.product == v.product && o.newer(v) {
+			return ""
+		}
+	}
+	return "newest " + v.product + " version"
+}
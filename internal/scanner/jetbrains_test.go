@@ -0,0 +1,38 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+func TestJetBrainsKeepsNewest(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"GoLand2022.3", "GoLand2024.1", "GoLand2023.10", "IntelliJIdea2023.2", "consentOptions"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		keep string
+	}{
+		{"GoLand2022.3", ""},
+		{"GoLand2023.10", ""},
+		{"GoLand2024.1", "newest GoLand version"},
+		{"IntelliJIdea2023.2", "newest IntelliJIdea version"},
+		{"consentOptions", "not a versioned JetBrains IDE directory"},
+	}
+	for _, tt := range tests {
+		if got := itemInUse(rules.ItemsJetBrainsVersion, filepath.Join(dir, tt.name)); got != tt.keep {
+			t.Errorf("%s: kept for %q, want %q", tt.name, got, tt.keep)
+		}
+	}
+
+	if name, _ := describeItem(rules.ItemsJetBrainsVersion, filepath.Join(dir, "GoLand2022.3")); name != "GoLand 2022.3" {
+		t.Errorf("name = %q, want GoLand 2022.3", name)
+	}
+}
@@ -0,0 +1,230 @@
+package scanner
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/config"
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/safety"
+	"github.com/ismailtsdln/burrow/internal/units"
+)
+
+// DefaultLargeFileRoots are searched when no roots are configured.
+var DefaultLargeFileRoots = []config.LargeFileRoot{
+	{Path: "~/Downloads"},
+	{Path: "~/Desktop"},
+	{Path: "~/Documents"},
+	{Path: "~/Movies"},
+	{Path: "~/Pictures"},
+}
+
+// Large file discovery backends.
+const (
+	BackendWalk      = "walk"      // Walk every directory below the roots
+	BackendSpotlight = "spotlight" // Ask the Spotlight index (macOS), walking where it is unavailable
+)
+
+// defaultLargeFileThreshold applies when no size threshold is set.
+const defaultLargeFileThreshold = 100 * 1024 * 1024
+
+// scanLargeFiles finds files above the size threshold in the configured roots,
+// honouring per-root depth limits, extension filters, and the minimum age.
+func (s *Scanner) scanLargeFiles() (*ScanResults, error) {
+	results := make([]rules.Result, 0)
+	var warnings []string
+	var totalSize int64
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	roots := s.options.LargeFileRoots
+	if len(roots) == 0 {
+		roots = DefaultLargeFileRoots
+	}
+
+	threshold := s.options.SizeThreshold
+	if threshold == 0 {
+		threshold = defaultLargeFileThreshold
+	}
+	sem := newSemaphore(s.options.MaxConcurrency)
+
+	for _, root := range roots {
+		expanded := safety.ExpandPath(root.Path)
+		volume, skip := s.externalVolume(expanded)
+		if skip {
+			continue
+		}
+
+		wg.Add(1)
+		go func(root config.LargeFileRoot, dir string) {
+			defer wg.Done()
+			sem.acquire()
+			defer sem.release()
+			foundPaths, ruleSize, warning := s.findLargeFiles(dir, root.MaxDepth, threshold)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if warning != "" {
+				warnings = append(warnings, warning)
+			}
+			if len(foundPaths) == 0 {
+				return
+			}
+
+			results = append(results, rules.Result{
+				Rule: rules.CleanupRule{
+					Name:        fmt.Sprintf("Large Files (>%s)", units.FormatSize(threshold)),
+					Category:    "Large Files",
+					Description: fmt.Sprintf("Files larger than %s in %s", units.FormatSize(threshold), dir),
+					RiskLevel:   rules.RiskManual,
+				},
+				FoundPaths: foundPaths,
+				TotalSize:  ruleSize,
+				Volume:     volume,
+			})
+			totalSize += ruleSize
+		}(root, expanded)
+	}
+	wg.Wait()
+
+	sort.Strings(warnings)
+	return &ScanResults{Results: results, TotalSize: totalSize, Disk: diskSummary(totalSize), Warnings: warnings}, nil
+}
+
+// findLargeFiles searches one root with the configured backend. When
+// Spotlight can't answer, it walks the root instead and says why.
+func (s *Scanner) findLargeFiles(root string, maxDepth int, threshold int64) ([]string, int64, string) {
+	if s.options.LargeFileBackend != BackendSpotlight {
+		paths, size := s.walkLargeFiles(root, maxDepth, threshold)
+		return paths, size, ""
+	}
+	paths, size, err := s.spotlightLargeFiles(root, maxDepth, threshold)
+	if err != nil {
+		paths, size = s.walkLargeFiles(root, maxDepth, threshold)
+		return paths, size, fmt.Sprintf("Spotlight unavailable for %s (%v); walked it instead", root, err)
+	}
+	return paths, size, ""
+}
+
+// spotlightLargeFiles asks Spotlight for the files above threshold and
+// applies the same filters as walkLargeFiles. Spotlight does not index
+// hidden folders or ones excluded in System Settings, so it may find less.
+func (s *Scanner) spotlightLargeFiles(root string, maxDepth int, threshold int64) ([]string, int64, error) {
+	candidates, err := spotlightQuery(root, threshold)
+	if err != nil {
+		return nil, 0, err
+	}
+	sort.Strings(candidates)
+
+	var foundPaths []string
+	var size int64
+	ig := newIgnorer(root)
+	for _, path := range candidates {
+		if maxDepth > 0 && depth(root, path) > maxDepth {
+			continue
+		}
+		if s.inTrash(path) || s.ignoredDir(ig, root, path) {
+			continue
+		}
+		info, err := os.Lstat(path)
+		if err != nil || !info.Mode().IsRegular() || !s.largeFile(ig, path, info, threshold) {
+			continue
+		}
+		foundPaths = append(foundPaths, path)
+		size += info.Size()
+	}
+	return foundPaths, size, nil
+}
+
+// ignoredDir reports whether a directory between root and path is ignored
+// or dismissed, which a walk would have skipped.
+func (s *Scanner) ignoredDir(ig *ignorer, root, path string) bool {
+	for d := filepath.Dir(path); d != root && len(d) > len(root); d = filepath.Dir(d) {
+		if ig.ignored(d, true) || s.dismissed(d) {
+			return true
+		}
+	}
+	return false
+}
+
+// largeFile applies the ignore files, dismissals, extension, size, and age
+// filters to a regular file.
+func (s *Scanner) largeFile(ig *ignorer, path string, info os.FileInfo, threshold int64) bool {
+	if ig.ignored(path, false) || s.dismissed(path) || !matchesExtension(path, s.options.Extensions) {
+		return false
+	}
+	if info.Size() <= threshold {
+		return false
+	}
+	return s.options.OlderThan <= 0 || time.Since(info.ModTime()) >= s.options.OlderThan
+}
+
+// spotlightQuery returns the files below root larger than threshold
+// according to the Spotlight index. Tests replace it.
+var spotlightQuery = querySpotlight
+
+func (s *Scanner) walkLargeFiles(root string, maxDepth int, threshold int64) ([]string, int64) {
+	var foundPaths []string
+	var size int64
+	ig := newIgnorer(root)
+
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		s.limiter.wait()
+		if d.IsDir() {
+			if maxDepth > 0 && path != root && depth(root, path) >= maxDepth {
+				return filepath.SkipDir
+			}
+			if s.inTrash(path) || path != root && (ig.ignored(path, true) || s.dismissed(path)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || !s.largeFile(ig, path, info, threshold) {
+			return nil
+		}
+
+		foundPaths = append(foundPaths, path)
+		size += info.Size()
+		return nil
+	})
+
+	return foundPaths, size
+}
+
+// depth returns how many directory levels path is below root.
+func depth(root, path string) int {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return 0
+	}
+	return strings.Count(rel, string(filepath.Separator)) + 1
+}
+
+// matchesExtension reports whether path has one of the given extensions.
+// An empty list matches every file.
+func matchesExtension(path string, extensions []string) bool {
+	if len(extensions) == 0 {
+		return true
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range extensions {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if !strings.HasPrefix(e, ".") {
+			e = "." + e
+		}
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
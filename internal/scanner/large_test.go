@@ -0,0 +1,147 @@
+package scanner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ismailtsdln/burrow/internal/config"
+)
+
+func TestScanner_LargeFileRoots(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "burrow_large")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	files := map[string]int{
+		"top.dmg":             2048,
+		"top.txt":             2048,
+		"small.dmg":           10,
+		"a/nested.iso":        2048,
+		"a/b/deep.dmg":        2048,
+		"a/b/c/very_deep.dmg": 2048,
+	}
+	for name, size := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		maxDepth   int
+		extensions []string
+		want       int
+	}{
+		{"Unlimited, all types", 0, nil, 5},
+		{"Only disk images", 0, []string{".dmg", "iso"}, 4},
+		{"Depth 1", 1, nil, 2},
+		{"Depth 2 disk images", 2, []string{"dmg"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScanner(nil, ScanOptions{
+				LargeFileMode:  true,
+				SizeThreshold:  1024,
+				LargeFileRoots: []config.LargeFileRoot{{Path: tmpDir, MaxDepth: tt.maxDepth}},
+				Extensions:     tt.extensions,
+			})
+			results, err := s.Scan()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := 0
+			for _, res := range results.Results {
+				got += len(res.FoundPaths)
+			}
+			if got != tt.want {
+				t.Errorf("found %d files, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanner_LargeFilesSpotlight(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]int{
+		"movie.mov":         2048,
+		"small.mov":         10,
+		"ignored/big.iso":   2048,
+		"a/b/deep.dmg":      2048,
+		"gone/vanished.dmg": 0, // Reported by the index but no longer on disk
+	}
+	var indexed []string
+	for name, size := range files {
+		path := filepath.Join(root, name)
+		indexed = append(indexed, path)
+		if size == 0 {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, IgnoreFile), []byte("ignored/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	orig := spotlightQuery
+	defer func() { spotlightQuery = orig }()
+
+	scan := func(maxDepth int) *ScanResults {
+		t.Helper()
+		s := NewScanner(nil, ScanOptions{
+			LargeFileMode:    true,
+			LargeFileBackend: BackendSpotlight,
+			SizeThreshold:    1024,
+			LargeFileRoots:   []config.LargeFileRoot{{Path: root, MaxDepth: maxDepth}},
+		})
+		results, err := s.Scan()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return results
+	}
+	found := func(results *ScanResults) []string {
+		var paths []string
+		for _, res := range results.Results {
+			for _, p := range res.FoundPaths {
+				rel, _ := filepath.Rel(root, p)
+				paths = append(paths, filepath.ToSlash(rel))
+			}
+		}
+		return paths
+	}
+
+	spotlightQuery = func(string, int64) ([]string, error) { return indexed, nil }
+	if got := found(scan(0)); !slices.Equal(got, []string{"a/b/deep.dmg", "movie.mov"}) {
+		t.Errorf("spotlight found %q, want the indexed files that pass the filters", got)
+	}
+	if got := found(scan(1)); !slices.Equal(got, []string{"movie.mov"}) {
+		t.Errorf("spotlight with depth 1 found %q", got)
+	}
+
+	// Without an index the root is walked, which the results must say
+	spotlightQuery = func(string, int64) ([]string, error) { return nil, errors.New("indexing is disabled") }
+	results := scan(0)
+	if got := found(results); !slices.Equal(got, []string{"a/b/deep.dmg", "movie.mov"}) {
+		t.Errorf("fallback walk found %q", got)
+	}
+	if len(results.Warnings) != 1 || !strings.Contains(results.Warnings[0], "indexing is disabled") {
+		t.Errorf("warnings = %q, want the fallback reason", results.Warnings)
+	}
+}
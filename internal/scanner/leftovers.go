@@ -0,0 +1,354 @@
+package scanner
+
+import (
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/safety"
+	"github.com/ismailtsdln/burrow/internal/units"
+)
+
+// LeftoverCategory is the category of data left behind by uninstalled apps.
+const LeftoverCategory = "App Leftovers"
+
+// leftoverMinAge keeps data that is still being written out of the results,
+// e.g. from an app run straight from a disk image or a build folder.
+const leftoverMinAge = 30 * units.Day
+
+// leftoverLocation is a place where applications keep per-app data, and how
+// risky removing that data is once the app is gone.
+type leftoverLocation struct {
+	glob string
+	kind string
+	risk rules.RiskLevel
+	// plainNames also considers folders that are not named after a bundle
+	// ID, which is common in Application Support but not in Caches, where
+	// command-line tools keep plain-named folders.
+	plainNames bool
+}
+
+var leftoverLocations = defaultLeftoverLocations()
+
+func defaultLeftoverLocations() []leftoverLocation {
+	if runtime.GOOS != "darwin" {
+		return nil
+	}
+	return []leftoverLocation{
+		{glob: "~/Library/Caches/*", kind: "caches", risk: rules.RiskSafe},
+		{glob: "~/Library/Saved Application State/*.savedState", kind: "saved state", risk: rules.RiskSafe},
+		{glob: "~/Library/HTTPStorages/*", kind: "web storage", risk: rules.RiskSafe},
+		{glob: "~/Library/WebKit/*", kind: "web storage", risk: rules.RiskSafe},
+		{glob: "~/Library/Logs/*", kind: "logs", risk: rules.RiskSafe, plainNames: true},
+		{glob: "~/Library/Preferences/*.plist", kind: "preferences", risk: rules.RiskCaution},
+		{glob: "~/Library/Application Support/*", kind: "app data", risk: rules.RiskManual, plainNames: true},
+		{glob: "~/Library/Containers/*", kind: "container", risk: rules.RiskManual},
+		{glob: "~/Library/Group Containers/*", kind: "group container", risk: rules.RiskManual},
+	}
+}
+
+// applicationGlobs locate installed application bundles.
+var applicationGlobs = []string{
+	"/Applications/*.app",
+	"/Applications/*/*.app",
+	"~/Applications/*.app",
+	"~/Applications/*/*.app",
+	"/System/Applications/*.app",
+	"/System/Applications/*/*.app",
+}
+
+// leftoverKeep lists folders in the leftover locations that belong to macOS
+// or are shared between apps, so they are never reported.
+var leftoverKeep = []string{
+	"apple", "addressbook", "callhistorydb", "callhistorytransactions", "clouddocs",
+	"crashreporter", "diagnosticreports", "dock", "fileprovider", "icloud",
+	"knowledge", "mobilesync", "syncservices", "networkserviceproxy", "familycircle",
+	"cloudkit", "identityservices", "accounts", "homekit", "icdd",
+}
+
+// errNoApplications guards against reporting every app's data as a leftover
+// when installed applications can't be listed.
+var errNoApplications = errors.New("no installed applications found; leftover detection needs macOS")
+
+// installedApps is what is installed, lower-cased for matching.
+type installedApps struct {
+	ids     map[string]bool // Bundle IDs
+	names   map[string]bool // Bundle names without ".app"
+	vendors map[string]bool // Reverse-DNS vendor prefixes, e.g. "com.google"
+}
+
+// findInstalledApps reads the bundle ID of every application bundle.
+func findInstalledApps() *installedApps {
+	apps := &installedApps{ids: map[string]bool{}, names: map[string]bool{}, vendors: map[string]bool{}}
+	for _, pattern := range applicationGlobs {
+		matches, _ := filepath.Glob(safety.ExpandPath(pattern))
+		for _, app := range matches {
+			apps.names[strings.ToLower(strings.TrimSuffix(filepath.Base(app), ".app"))] = true
+			id := strings.ToLower(bundleID(app))
+			if id == "" {
+				continue
+			}
+			apps.ids[id] = true
+			if parts := strings.Split(id, "."); len(parts) >= 3 {
+				apps.vendors[parts[0]+"."+parts[1]] = true
+			}
+		}
+	}
+	return apps
+}
+
+// bundleID returns the CFBundleIdentifier of the application bundle at app.
+func bundleID(app string) string {
+	plist := filepath.Join(app, "Contents", "Info.plist")
+	data, err := os.ReadFile(plist)
+	if err != nil {
+		return ""
+	}
+	if strings.HasPrefix(string(data), "bplist") {
+		return binaryPlistString(plist, "CFBundleIdentifier")
+	}
+	return xmlPlistString(data, "CFBundleIdentifier")
+}
+
+// xmlPlistString returns the string value of key in an XML property list.
+func xmlPlistString(data []byte, key string) string {
+	dec := xml.NewDecoder(strings.NewReader(string(data)))
+	dec.Strict = false
+	var element, lastKey string
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return ""
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			element = t.Name.Local
+		case xml.EndElement:
+			element = ""
+		case xml.CharData:
+			switch element {
+			case "key":
+				lastKey = string(t)
+			case "string":
+				if lastKey == key {
+					return strings.TrimSpace(string(t))
+				}
+				lastKey = ""
+			}
+		}
+	}
+}
+
+// leftoverKey reduces a file or folder name in a leftover location to the
+// bundle ID or app name it belongs to.
+func leftoverKey(name string) string {
+	key := strings.ToLower(name)
+	for _, suffix := range []string{".plist", ".savedstate", ".binarycookies"} {
+		key = strings.TrimSuffix(key, suffix)
+	}
+	key = strings.TrimPrefix(key, "group.")
+	// Group containers may be prefixed with a 10-character team ID
+	if i := strings.Index(key, "."); i == 10 && looksLikeBundleID(key[i+1:]) {
+		key = key[i+1:]
+	}
+	return key
+}
+
+// owns reports whether key may belong to an installed app: its own data,
+// a helper's or extension's, or that of its vendor.
+func (a *installedApps) owns(key string) bool {
+	if a.ids[key] || a.names[key] {
+		return true
+	}
+	if looksLikeBundleID(key) {
+		parts := strings.Split(key, ".")
+		if a.vendors[parts[0]+"."+parts[1]] {
+			return true
+		}
+		for id := range a.ids {
+			if strings.HasPrefix(key, id+".") || strings.HasPrefix(id, key+".") {
+				return true
+			}
+		}
+		return false
+	}
+	for name := range a.names {
+		if strings.ReplaceAll(name, " ", "") == key || strings.HasPrefix(name, key+" ") {
+			return true
+		}
+	}
+	for vendor := range a.vendors {
+		if strings.HasSuffix(vendor, "."+key) {
+			return true
+		}
+	}
+	return false
+}
+
+// leftover is the data found for one uninstalled app.
+type leftover struct {
+	key   string
+	name  string // As first found on disk, for display
+	paths []string
+	kinds []string
+	risk  rules.RiskLevel
+}
+
+// scanLeftovers reports data in ~/Library that belongs to no installed app.
+func (s *Scanner) scanLeftovers() (*ScanResults, error) {
+	installed := findInstalledApps()
+	if len(installed.ids) == 0 {
+		return nil, errNoApplications
+	}
+	covered := s.rulePaths()
+
+	found := make(map[string]*leftover)
+	var order []string
+	for _, loc := range leftoverLocations {
+		matches, _ := filepath.Glob(safety.ExpandPath(loc.glob))
+		for _, m := range matches {
+			key := leftoverKey(filepath.Base(m))
+			if !looksLikeBundleID(key) && !loc.plainNames {
+				continue
+			}
+			if strings.HasPrefix(key, "com.apple.") || strings.HasPrefix(key, ".") || keepLeftover(key) || installed.owns(key) || coveredBy(m, covered) || s.inTrash(m) || s.holdsTrash(m) {
+				continue
+			}
+			lo := found[key]
+			if lo == nil {
+				name := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(m), ".plist"), ".savedState")
+				lo = &leftover{key: key, name: name, risk: loc.risk}
+				found[key] = lo
+				order = append(order, key)
+			}
+			lo.paths = append(lo.paths, m)
+			if !slices.Contains(lo.kinds, loc.kind) {
+				lo.kinds = append(lo.kinds, loc.kind)
+			}
+			if riskRank(loc.risk) > riskRank(lo.risk) {
+				lo.risk = loc.risk
+			}
+		}
+	}
+
+	minAge := leftoverMinAge
+	if s.options.OlderThan > 0 {
+		minAge = s.options.OlderThan
+	}
+
+	res := &ScanResults{Results: make([]rules.Result, 0)}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := newSemaphore(s.options.MaxConcurrency)
+	for _, key := range order {
+		wg.Add(1)
+		go func(lo *leftover) {
+			defer wg.Done()
+			sem.acquire()
+			defer sem.release()
+
+			result, skipped := s.scanLeftover(lo, minAge)
+			mu.Lock()
+			defer mu.Unlock()
+			res.Skipped = append(res.Skipped, skipped...)
+			if result != nil {
+				res.Results = append(res.Results, *result)
+				res.TotalSize += result.TotalSize
+			}
+		}(found[key])
+	}
+	wg.Wait()
+
+	rules.SortBySize(res.Results)
+	sort.Slice(res.Skipped, func(i, j int) bool { return res.Skipped[i].Path < res.Skipped[j].Path })
+	s.hideSmall(res)
+	res.Disk = diskSummary(res.TotalSize)
+	return res, nil
+}
+
+func (s *Scanner) scanLeftover(lo *leftover, minAge time.Duration) (*rules.Result, []SkippedPath) {
+	rule := rules.CleanupRule{
+		Name:        lo.name,
+		Category:    LeftoverCategory,
+		Paths:       lo.paths,
+		RiskLevel:   lo.risk,
+		Description: "Leftover " + strings.Join(lo.kinds, ", ") + " of an app that is no longer installed",
+		Explanation: "No application in /Applications or ~/Applications uses this bundle ID or name, and nothing here changed in " +
+			units.FormatDuration(minAge) + ". Reinstalling the app would start from scratch.",
+	}
+	// A plain name like "Docker" may just as well belong to a command-line
+	// tool or an app installed elsewhere, so only a bundle ID is evidence
+	// enough to clean
+	if !looksLikeBundleID(lo.key) {
+		rule.ReportOnly = true
+		rule.Explanation = "No application in /Applications or ~/Applications has this name, and nothing here changed in " +
+			units.FormatDuration(minAge) + ". The name is not a bundle ID, so it may belong to a tool Burrow can't see; Burrow only reports it. Remove it yourself once you are sure."
+	}
+
+	result := rules.Result{Rule: rule}
+	var skipped []SkippedPath
+	for _, p := range lo.paths {
+		size, stats, skip := s.measure(lo.name, p, minAge)
+		if skip != nil {
+			skipped = append(skipped, *skip)
+		}
+		if size == 0 {
+			continue
+		}
+		result.FoundPaths = append(result.FoundPaths, p)
+		result.TotalSize += size
+		result.Stats = append(result.Stats, stats)
+	}
+	if len(result.FoundPaths) == 0 || (s.options.SizeThreshold > 0 && result.TotalSize < s.options.SizeThreshold) {
+		return nil, skipped
+	}
+	return &result, skipped
+}
+
+// rulePaths returns the expanded paths of every registered rule; data that a
+// rule already handles is not a leftover.
+func (s *Scanner) rulePaths() []string {
+	if s.registry == nil {
+		return nil
+	}
+	var paths []string
+	for _, r := range s.registry.All() {
+		for _, p := range r.Paths {
+			paths = append(paths, safety.ExpandPath(p))
+		}
+	}
+	return paths
+}
+
+func coveredBy(path string, patterns []string) bool {
+	for _, p := range patterns {
+		if safety.MatchPath(path, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func keepLeftover(key string) bool {
+	return slices.Contains(leftoverKeep, strings.ReplaceAll(key, " ", ""))
+}
+
+// riskRank orders risk levels from least to most risky.
+func riskRank(r rules.RiskLevel) int {
+	switch r {
+	case rules.RiskSafe:
+		return 0
+	case rules.RiskCaution:
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,113 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+const testInfoPlist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>CFBundleName</key>
+	<string>Foo</string>
+	<key>CFBundleIdentifier</key>
+	<string>com.example.Foo</string>
+</dict>
+</plist>`
+
+func TestScanner_Leftovers(t *testing.T) {
+	tmpDir := t.TempDir()
+	lib := filepath.Join(tmpDir, "Library")
+	files := map[string]string{
+		"Applications/Foo.app/Contents/Info.plist":   testInfoPlist,
+		"Library/Caches/com.example.foo/data":        "installed",
+		"Library/Caches/com.example.foo.helper/data": "installed helper",
+		"Library/Caches/com.example.bar/data":        "same vendor",
+		"Library/Caches/com.gone.app/data":           "leftover cache",
+		"Library/Caches/com.apple.Safari/data":       "system",
+		"Library/Caches/pip/data":                    "command-line tool",
+		"Library/Preferences/com.gone.app.plist":     "prefs",
+		"Library/Support/Foo/data":                   "installed by name",
+		"Library/Support/GoneApp/data":               "leftover data",
+		"Library/Support/CrashReporter/data":         "system",
+		"Library/Caches/com.fresh.app/data":          "recently used",
+		"Library/Caches/com.covered.app/data":        "has a rule",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-60 * 24 * time.Hour)
+	for _, dir := range []string{"Caches/com.gone.app", "Preferences/com.gone.app.plist", "Support/GoneApp", "Caches/com.covered.app"} {
+		if err := os.Chtimes(filepath.Join(lib, dir), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	oldLocs, oldApps := leftoverLocations, applicationGlobs
+	defer func() { leftoverLocations, applicationGlobs = oldLocs, oldApps }()
+	applicationGlobs = []string{filepath.Join(tmpDir, "Applications", "*.app")}
+	leftoverLocations = []leftoverLocation{
+		{glob: filepath.Join(lib, "Caches", "*"), kind: "caches", risk: rules.RiskSafe},
+		{glob: filepath.Join(lib, "Preferences", "*.plist"), kind: "preferences", risk: rules.RiskCaution},
+		{glob: filepath.Join(lib, "Support", "*"), kind: "app data", risk: rules.RiskManual, plainNames: true},
+	}
+
+	registry := rules.NewRegistryFromRules([]rules.CleanupRule{{Name: "Covered", Paths: []string{filepath.Join(lib, "Caches", "com.covered.app")}}})
+	res, err := NewScanner(registry, ScanOptions{LeftoverMode: true}).Scan()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]rules.Result)
+	for _, r := range res.Results {
+		got[r.Rule.Name] = r
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d leftovers, want com.gone.app and GoneApp: %v", len(got), got)
+	}
+	if r := got["com.gone.app"]; len(r.FoundPaths) != 2 || r.Rule.RiskLevel != rules.RiskCaution {
+		t.Errorf("com.gone.app = %d paths, risk %s; want 2 paths, Caution", len(r.FoundPaths), r.Rule.RiskLevel)
+	}
+	if r := got["GoneApp"]; r.Rule.RiskLevel != rules.RiskManual || r.Rule.Category != LeftoverCategory || !r.Rule.ReportOnly {
+		t.Errorf("GoneApp = risk %s, category %s, report-only %v; want a report-only Manual leftover", r.Rule.RiskLevel, r.Rule.Category, r.Rule.ReportOnly)
+	}
+	if got["com.gone.app"].Rule.ReportOnly {
+		t.Error("com.gone.app is named by bundle ID and should be cleanable")
+	}
+}
+
+func TestScanner_LeftoversWithoutApps(t *testing.T) {
+	oldApps := applicationGlobs
+	defer func() { applicationGlobs = oldApps }()
+	applicationGlobs = []string{filepath.Join(t.TempDir(), "*.app")}
+
+	if _, err := NewScanner(nil, ScanOptions{LeftoverMode: true}).Scan(); err != errNoApplications {
+		t.Errorf("err = %v, want errNoApplications", err)
+	}
+}
+
+func TestLeftoverKey(t *testing.T) {
+	tests := map[string]string{
+		"com.Spotify.client.plist":        "com.spotify.client",
+		"com.example.App.savedState":      "com.example.app",
+		"group.com.example.shared":        "com.example.shared",
+		"UBF8T346G9.com.microsoft.office": "com.microsoft.office",
+		"Google":                          "google",
+	}
+	for name, want := range tests {
+		if got := leftoverKey(name); got != want {
+			t.Errorf("leftoverKey(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package scanner
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/safety"
+)
+
+// matchDepth is how many levels below a found directory MatchPaths looks
+// for paths matching a pattern.
+const matchDepth = 4
+
+// MatchPaths narrows results to the found paths matching one of patterns,
+// so one project's artifacts can be cleaned out of a shared cache. A pattern
+// starting with / or ~ matches from the root; any other pattern matches the
+// trailing segments of a path, so "DerivedData/MyApp-*" matches
+// ~/Library/Developer/Xcode/DerivedData/MyApp-1a2b. A match also covers
+// everything below it. A found directory that does not match is replaced by
+// the matching paths inside it, up to matchDepth levels down. Results are
+// resized and those left without paths are dropped.
+func MatchPaths(results *ScanResults, patterns []string) *ScanResults {
+	compiled := make([][]string, len(patterns))
+	for i, p := range patterns {
+		compiled[i] = compileMatch(p)
+	}
+	matches := func(path string) bool {
+		segs := strings.Split(filepath.ToSlash(path), "/")
+		for _, c := range compiled {
+			if matchSegments(c, segs) {
+				return true
+			}
+		}
+		return false
+	}
+
+	out := *results
+	out.Results = nil
+	out.TotalSize = 0
+	for _, res := range results.Results {
+		var found []string
+		for _, p := range res.FoundPaths {
+			if matches(p) {
+				found = append(found, p)
+				continue
+			}
+			found = append(found, matchesBelow(p, matches)...)
+		}
+		if len(found) == 0 {
+			continue
+		}
+
+		kept := make(map[string]rules.PathStats)
+		for _, st := range res.Stats {
+			kept[st.Path] = st
+		}
+		res.FoundPaths = found
+		res.Stats = nil
+		res.TotalSize = 0
+		for _, p := range found {
+			st, ok := kept[p]
+			if !ok {
+				_, st, _ = dirStats(p, nil)
+			}
+			res.Stats = append(res.Stats, st)
+			res.TotalSize += st.Size
+		}
+		out.Results = append(out.Results, res)
+		out.TotalSize += res.TotalSize
+	}
+	rules.SortBySize(out.Results)
+	return &out
+}
+
+// compileMatch splits a --match pattern into segments for matchSegments.
+func compileMatch(pattern string) []string {
+	expanded := safety.ExpandPath(pattern)
+	segs := strings.Split(strings.TrimSuffix(filepath.ToSlash(expanded), "/"), "/")
+	if !filepath.IsAbs(expanded) && !strings.HasPrefix(expanded, "/") {
+		segs = append([]string{"**"}, segs...)
+	}
+	return append(segs, "**")
+}
+
+// matchesBelow returns the paths inside dir, up to matchDepth levels down,
+// that matches accepts, without descending into them.
+func matchesBelow(dir string, matches func(string) bool) []string {
+	var found []string
+	depth := strings.Count(filepath.Clean(dir), string(filepath.Separator))
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == dir {
+			return nil
+		}
+		if matches(path) {
+			found = append(found, path)
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() && strings.Count(path, string(filepath.Separator))-depth >= matchDepth {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return found
+}
@@ -0,0 +1,62 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+func TestMatchPaths(t *testing.T) {
+	root := t.TempDir()
+	derived := filepath.Join(root, "DerivedData")
+	for name, size := range map[string]int{"MyApp-abc/Build/a.o": 100, "MyApp-def/b.o": 50, "Other-xyz/c.o": 400} {
+		path := filepath.Join(derived, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(strings.Repeat("x", size)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	logs := filepath.Join(root, "Logs", "MyApp-abc.log")
+	if err := os.MkdirAll(filepath.Dir(logs), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(logs, []byte("log"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	scan := &ScanResults{Results: []rules.Result{
+		{Rule: rules.CleanupRule{Name: "DerivedData"}, FoundPaths: []string{derived}, TotalSize: 550},
+		{Rule: rules.CleanupRule{Name: "Logs"}, FoundPaths: []string{logs}, TotalSize: 3},
+	}, TotalSize: 553}
+
+	got := MatchPaths(scan, []string{"DerivedData/MyApp-*"})
+	if len(got.Results) != 1 {
+		t.Fatalf("results = %+v, want only DerivedData", got.Results)
+	}
+	res := got.Results[0]
+	want := []string{filepath.Join(derived, "MyApp-abc"), filepath.Join(derived, "MyApp-def")}
+	if len(res.FoundPaths) != 2 || res.FoundPaths[0] != want[0] || res.FoundPaths[1] != want[1] {
+		t.Errorf("found paths = %v, want %v", res.FoundPaths, want)
+	}
+	if res.TotalSize != 150 || got.TotalSize != 150 {
+		t.Errorf("sizes = %d/%d, want 150", res.TotalSize, got.TotalSize)
+	}
+	if scan.Results[0].FoundPaths[0] != derived || scan.TotalSize != 553 {
+		t.Error("MatchPaths modified its input")
+	}
+
+	// Anchored patterns match from the root and cover what lies below
+	got = MatchPaths(scan, []string{filepath.ToSlash(root) + "/Logs"})
+	if len(got.Results) != 1 || got.Results[0].FoundPaths[0] != logs {
+		t.Errorf("anchored match = %+v, want the log file", got.Results)
+	}
+
+	if got := MatchPaths(scan, []string{"MyApp"}); len(got.Results) != 0 {
+		t.Errorf("partial segment matched: %+v", got.Results)
+	}
+}
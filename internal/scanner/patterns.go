@@ -0,0 +1,96 @@
+package scanner
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/safety"
+)
+
+// matchFiles walks dir and returns the files matching the rule's include
+// patterns (all files when there are none) and none of its exclude patterns
+// or .burrowignore entries that also satisfy the rule's and the scan's age filters, along with
+// their total size and stats. Unreadable subdirectories are skipped; the first
+// such error is returned alongside the partial results. Once deadline has
+// passed, the walk gives up with errDeadline.
+func (s *Scanner) matchFiles(dir string, r rules.CleanupRule, deadline time.Time) ([]string, int64, rules.PathStats, error) {
+	minAge := r.MinAgeDuration()
+	if s.options.OlderThan > minAge {
+		minAge = s.options.OlderThan
+	}
+
+	var paths []string
+	var size int64
+	stats := rules.PathStats{Path: dir, Kind: rules.KindDirectory}
+	ig := newIgnorer(dir)
+	var walkErr error
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if walkErr == nil {
+				walkErr = err
+			}
+			return nil
+		}
+		if pastDeadline(deadline) {
+			walkErr = errDeadline
+			return filepath.SkipAll
+		}
+		s.limiter.wait()
+		if d.IsDir() {
+			// Bundles such as Foo.app are opaque and never searched
+			if path != dir && filepath.Ext(d.Name()) == ".app" {
+				return filepath.SkipDir
+			}
+			if path != dir && (matchesAny(d.Name(), r.ExcludePatterns) || ig.ignored(path, true) || s.dismissed(path) || s.inTrash(path)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ig.ignored(path, false) || s.dismissed(path) {
+			return nil
+		}
+		if len(r.IncludePatterns) > 0 && !matchesAny(d.Name(), r.IncludePatterns) {
+			return nil
+		}
+		if matchesAny(d.Name(), r.ExcludePatterns) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if minAge > 0 && time.Since(info.ModTime()) < minAge {
+			return nil
+		}
+		if !safety.CheckWith(path, r.SafetyOptions()).Safe {
+			return nil
+		}
+
+		paths = append(paths, path)
+		size += info.Size()
+		stats.Add(info.Size(), info.ModTime())
+		stats.Allocated += allocatedSize(info)
+		return nil
+	})
+	return paths, size, stats, walkErr
+}
+
+// dismissed reports whether the user dismissed path or a directory above it.
+func (s *Scanner) dismissed(path string) bool {
+	return len(s.options.DismissedPaths) > 0 && isExcluded(path, s.options.DismissedPaths)
+}
+
+// matchesAny reports whether name matches one of the glob patterns (case-insensitive).
+func matchesAny(name string, patterns []string) bool {
+	lower := strings.ToLower(name)
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(strings.ToLower(p), lower); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+func TestScanner_IncludePatterns(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "burrow_patterns")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	old := time.Now().Add(-45 * 24 * time.Hour)
+	files := []struct {
+		name string
+		old  bool
+	}{
+		{"Xcode.dmg", true},
+		{"Tool.PKG", true},
+		{"fresh.iso", false},
+		{"notes.txt", true},
+		{"Foo.app/Contents/payload.dmg", true},
+	}
+	for _, f := range files {
+		path := filepath.Join(tmpDir, f.name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if f.old {
+			if err := os.Chtimes(path, old, old); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	registry := rules.NewRegistryFromRules([]rules.CleanupRule{{
+		Name:            "Installers",
+		Paths:           []string{tmpDir},
+		IncludePatterns: []string{"*.dmg", "*.pkg", "*.iso"},
+		MinAgeDays:      30,
+	}})
+
+	results, err := NewScanner(registry, ScanOptions{}).Scan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.Results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results.Results))
+	}
+
+	got := make(map[string]bool)
+	for _, p := range results.Results[0].FoundPaths {
+		got[filepath.Base(p)] = true
+	}
+	if len(got) != 2 || !got["Xcode.dmg"] || !got["Tool.PKG"] {
+		t.Errorf("unexpected matches: %v", results.Results[0].FoundPaths)
+	}
+
+	if results.Duration <= 0 {
+		t.Error("expected the scan duration to be recorded")
+	}
+
+	stats := results.Results[0].Summary()
+	if stats.Files != 2 {
+		t.Errorf("expected stats for 2 matched files, got %d", stats.Files)
+	}
+	if !stats.Newest.Equal(old) || !stats.Oldest.Equal(old) {
+		t.Errorf("unexpected mtimes: newest %v, oldest %v", stats.Newest, stats.Oldest)
+	}
+}
+
+func TestScanner_ExcludePatterns(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"a.ipa", "keep/b.ipa", "important.db", "notes.txt"} {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		want    []string
+	}{
+		{"Exclude only", nil, []string{"important.db", "keep"}, []string{"a.ipa", "notes.txt"}},
+		{"Include and exclude", []string{"*.ipa"}, []string{"keep"}, []string{"a.ipa"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := rules.NewRegistryFromRules([]rules.CleanupRule{{
+				Name:            "Files",
+				Paths:           []string{tmpDir},
+				IncludePatterns: tt.include,
+				ExcludePatterns: tt.exclude,
+			}})
+			results, err := NewScanner(registry, ScanOptions{}).Scan()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results.Results) != 1 {
+				t.Fatalf("expected 1 result, got %d", len(results.Results))
+			}
+
+			var got []string
+			for _, p := range results.Results[0].FoundPaths {
+				rel, _ := filepath.Rel(tmpDir, p)
+				got = append(got, rel)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
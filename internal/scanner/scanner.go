@@ -1,30 +1,92 @@
 package scanner
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/ismailtsdln/burrow/internal/config"
+	"github.com/ismailtsdln/burrow/internal/disk"
 	"github.com/ismailtsdln/burrow/internal/rules"
 	"github.com/ismailtsdln/burrow/internal/safety"
+	"github.com/ismailtsdln/burrow/internal/units"
 )
 
 // ScanOptions contains filtering and performance settings for a scan.
 type ScanOptions struct {
-	Category      string
-	SizeThreshold int64
-	ExcludedPaths []string
-	OlderThan     time.Duration
-	LargeFileMode bool
+	Category string
+	// DisabledCategories are never scanned, even when named by Category.
+	DisabledCategories []string
+	Risks              []rules.RiskLevel // Only rules with one of these risk levels; all when empty
+	SizeThreshold      int64             // Per path
+	MinSize            int64             // Per result; rules may raise it with their own minimum
+	ExcludedPaths      []string
+	OlderThan          time.Duration
+	LargeFileMode      bool
+	AppCacheMode       bool // Report every application's caches instead of rules
+	LeftoverMode       bool // Report data of uninstalled applications instead of rules
+	IncludeSystem      bool
+	ExcludeExternal    bool
+
+	// DismissedRules and DismissedPaths are suggestions the user asked not
+	// to see again, or not for a while; see package dismiss.
+	DismissedRules []string
+	DismissedPaths []string
+
+	// Large file mode settings; defaults apply when empty
+	LargeFileRoots   []config.LargeFileRoot
+	Extensions       []string
+	LargeFileBackend string // BackendWalk (default) or BackendSpotlight
+
+	// MaxConcurrency caps how many rules (or large-file roots) are walked at
+	// once, and FilesPerSecond paces all walks together; zero means no limit.
+	MaxConcurrency int
+	FilesPerSecond int
+
+	// Rules limits the scan to the rules with these names; all when empty.
+	Rules []string
+	// RuleTimeout bounds how long one rule may walk its paths, so a network
+	// mount or a directory of millions of files can't hang the scan. Paths
+	// it doesn't finish are reported as timed out. Zero means no limit.
+	RuleTimeout time.Duration
+}
+
+// OptionsFromConfig returns the options every scan takes from the
+// configuration, before dismissals (see dismiss.ScanOptions) and filters. A
+// nil config means the defaults.
+func OptionsFromConfig(cfg *config.Config) ScanOptions {
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	return ScanOptions{
+		ExcludedPaths:      cfg.ExcludedPaths,
+		DisabledCategories: cfg.DisabledCategories,
+		ExcludeExternal:    cfg.ExcludeExternalVolumes,
+		MaxConcurrency:     cfg.Scan.MaxConcurrency,
+		FilesPerSecond:     cfg.Scan.FilesPerSecond,
+		RuleTimeout:        cfg.Scan.RuleTimeoutDuration(),
+		SizeThreshold:      cfg.SizeThresholdBytes(),
+	}
+}
+
+// RuleBased reports whether the scan runs the registered rules rather than
+// one of the discovery modes.
+func (o ScanOptions) RuleBased() bool {
+	return !o.LargeFileMode && !o.AppCacheMode && !o.LeftoverMode
 }
 
 // Scanner handles the scanning of the filesystem for cleanup candidates.
 type Scanner struct {
 	registry *rules.Registry
 	options  ScanOptions
+	limiter  *limiter
+	trash    []string // Burrow's trash, never counted; see trashDirs
 }
 
 // NewScanner creates a new scanner instance with options.
@@ -32,6 +94,8 @@ func NewScanner(registry *rules.Registry, options ScanOptions) *Scanner {
 	return &Scanner{
 		registry: registry,
 		options:  options,
+		limiter:  newLimiter(options.FilesPerSecond),
+		trash:    trashDirs(),
 	}
 }
 
@@ -39,147 +103,441 @@ func NewScanner(registry *rules.Registry, options ScanOptions) *Scanner {
 type ScanResults struct {
 	Results   []rules.Result
 	TotalSize int64
+	Disk      *DiskSummary   `json:",omitempty"`
+	Skipped   []SkippedPath  `json:",omitempty"`
+	Errors    []*PathError   `json:",omitempty"` // Typed subset of Skipped; see Err
+	Hidden    []HiddenResult `json:",omitempty"` // Results below their minimum size
+	Duration  time.Duration  `json:",omitempty"` // Wall-clock scan time
+	Timings   []PathTiming   `json:",omitempty"` // Slowest first
+	Warnings  []string       `json:",omitempty"` // Problems not tied to one path, e.g. a backend fallback
+}
+
+// PathTiming is the time spent scanning one rule path. Paths that took less
+// than minTiming (typically ones that do not exist) are not recorded.
+type PathTiming struct {
+	Rule     string        `json:"rule"`
+	Path     string        `json:"path"`
+	Duration time.Duration `json:"duration"`
+}
+
+const minTiming = time.Millisecond
+
+// SkippedPath records a rule path that exists but was left out of the
+// results, and why.
+type SkippedPath struct {
+	Rule   string `json:"rule"`
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// HiddenResult is a rule whose findings were left out of the results because
+// their total was below the scan's or the rule's minimum size.
+type HiddenResult struct {
+	Rule    string `json:"rule"`
+	Size    int64  `json:"size"`
+	MinSize int64  `json:"min_size"`
+}
+
+// DiskSummary describes the home volume and the projected free space after
+// all results are removed.
+type DiskSummary struct {
+	disk.Usage
+	FreeAfterCleanup int64 `json:"free_after_cleanup"`
+}
+
+// diskSummary reports usage of the volume holding the user's home directory.
+func diskSummary(reclaimable int64) *DiskSummary {
+	home, _ := os.UserHomeDir()
+	usage, err := disk.UsageOf(home)
+	if err != nil {
+		return nil
+	}
+	return &DiskSummary{
+		Usage:            usage,
+		FreeAfterCleanup: usage.Free + reclaimable,
+	}
+}
+
+// Includes reports whether rule passes the category, risk, dismissal, and
+// system filters.
+func (o ScanOptions) Includes(rule rules.CleanupRule) bool {
+	if o.Category != "" && !strings.EqualFold(rule.Category, o.Category) {
+		return false
+	}
+	if slices.ContainsFunc(o.DisabledCategories, func(c string) bool { return strings.EqualFold(c, rule.Category) }) {
+		return false
+	}
+	if len(o.Risks) > 0 && !slices.Contains(o.Risks, rule.RiskLevel) {
+		return false
+	}
+	if slices.Contains(o.DismissedRules, rule.Name) {
+		return false
+	}
+	if len(o.Rules) > 0 && !slices.ContainsFunc(o.Rules, func(name string) bool { return strings.EqualFold(name, rule.Name) }) {
+		return false
+	}
+	// Root-owned rules are only scanned when explicitly requested
+	return !rule.RequiresRoot || o.IncludeSystem
+}
+
+// WithoutDismissals returns o without the dismissed rules and paths, for
+// scans that are cached: dismissals are applied when the cache is read, so
+// dismissing or restoring a suggestion needs no rescan.
+func (o ScanOptions) WithoutDismissals() ScanOptions {
+	o.DismissedRules, o.DismissedPaths = nil, nil
+	return o
+}
+
+// Filter narrows res, typically a cached unfiltered scan, down to the rules
+// that pass the scanner's category, risk, rule, dismissal, and system
+// filters, and drops the found paths the user dismissed.
+func (s *Scanner) Filter(res *ScanResults) *ScanResults {
+	included := make(map[string]bool)
+	for _, r := range s.registry.All() {
+		included[r.Name] = s.options.Includes(r)
+	}
+
+	out := &ScanResults{Results: make([]rules.Result, 0, len(res.Results)), Duration: res.Duration}
+	for _, r := range res.Results {
+		if !included[r.Rule.Name] {
+			continue
+		}
+		r, dismissed := s.withoutDismissed(r)
+		for _, p := range dismissed {
+			out.Skipped = append(out.Skipped, SkippedPath{Rule: r.Rule.Name, Path: p, Reason: "dismissed"})
+		}
+		if len(r.FoundPaths) > 0 {
+			out.Results = append(out.Results, r)
+			out.TotalSize += r.TotalSize
+		}
+	}
+	for _, sp := range res.Skipped {
+		if included[sp.Rule] {
+			out.Skipped = append(out.Skipped, sp)
+		}
+	}
+	for _, e := range res.Errors {
+		if included[e.Rule] {
+			out.Errors = append(out.Errors, e)
+		}
+	}
+	for _, t := range res.Timings {
+		if included[t.Rule] {
+			out.Timings = append(out.Timings, t)
+		}
+	}
+	for _, h := range res.Hidden {
+		if included[h.Rule] {
+			out.Hidden = append(out.Hidden, h)
+		}
+	}
+	s.hideSmall(out)
+	out.Disk = diskSummary(out.TotalSize)
+	return out
+}
+
+// withoutDismissed drops the dismissed found paths from r, with their size,
+// and returns them.
+func (s *Scanner) withoutDismissed(r rules.Result) (rules.Result, []string) {
+	if len(s.options.DismissedPaths) == 0 {
+		return r, nil
+	}
+	var kept, dismissed []string
+	stats := slices.Clone(r.Stats)
+	for _, p := range r.FoundPaths {
+		if !s.dismissed(p) {
+			kept = append(kept, p)
+			continue
+		}
+		dismissed = append(dismissed, p)
+		if i := slices.IndexFunc(stats, func(st rules.PathStats) bool { return st.Path == p }); i >= 0 {
+			size := stats[i].Size
+			if r.Rule.SparseFiles {
+				size = stats[i].Allocated
+			}
+			r.TotalSize -= size
+			stats = slices.Delete(stats, i, i+1)
+			continue
+		}
+		// A file picked by a pattern rule, counted in its directory's stats
+		if info, err := os.Lstat(p); err == nil {
+			r.TotalSize -= info.Size()
+			for i := range stats {
+				if strings.HasPrefix(p, stats[i].Path+string(filepath.Separator)) {
+					stats[i].Size -= info.Size()
+					stats[i].Files--
+					break
+				}
+			}
+		}
+	}
+	r.FoundPaths, r.Stats = kept, stats
+	r.TotalSize = max(r.TotalSize, 0)
+	return r, dismissed
+}
+
+// hideSmall moves results whose total is below the larger of the scan's and
+// the rule's minimum size from res.Results to res.Hidden.
+func (s *Scanner) hideSmall(res *ScanResults) {
+	kept := res.Results[:0]
+	for _, r := range res.Results {
+		minSize := max(s.options.MinSize, r.Rule.MinSizeBytes())
+		if minSize > 0 && r.TotalSize < minSize {
+			res.Hidden = append(res.Hidden, HiddenResult{Rule: r.Rule.Name, Size: r.TotalSize, MinSize: minSize})
+			res.TotalSize -= r.TotalSize
+			continue
+		}
+		kept = append(kept, r)
+	}
+	res.Results = kept
+	sort.Slice(res.Hidden, func(i, j int) bool { return res.Hidden[i].Rule < res.Hidden[j].Rule })
 }
 
 // Scan performs a scan based on the registered rules.
 func (s *Scanner) Scan() (*ScanResults, error) {
 	results := make([]rules.Result, 0)
+	var skipped []SkippedPath
+	var timings []PathTiming
+	var scanErrors []*PathError
 	var totalSize int64
+	began := time.Now()
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
 	// Large File Scan Mode
 	if s.options.LargeFileMode {
-		dirsToScan := []string{
-			"~/Downloads",
-			"~/Desktop",
-			"~/Documents",
-			"~/Movies",
-			"~/Pictures",
-		}
-
-		threshold := s.options.SizeThreshold
-		if threshold == 0 {
-			threshold = 100 * 1024 * 1024 // Default 100MB
-		}
-
-		for _, dir := range dirsToScan {
-			expanded := safety.ExpandPath(dir)
-			wg.Add(1)
-			go func(d string) {
-				defer wg.Done()
-				var foundPaths []string
-				var ruleSize int64
-
-				filepath.Walk(d, func(path string, info os.FileInfo, err error) error {
-					if err != nil || info.IsDir() {
-						return nil
-					}
-					if info.Size() > threshold {
-						foundPaths = append(foundPaths, path)
-						ruleSize += info.Size()
-					}
-					return nil
-				})
+		res, err := s.scanLargeFiles()
+		if res != nil {
+			res.Duration = time.Since(began)
+		}
+		return res, err
+	}
 
-				if len(foundPaths) > 0 {
-					mu.Lock()
-					results = append(results, rules.Result{
-						Rule: rules.CleanupRule{
-							Name:        "Large Files (>100MB)",
-							Category:    "Large Files",
-							Description: fmt.Sprintf("Files larger than %s in %s", formatBytes(threshold), d),
-							RiskLevel:   rules.RiskManual,
-						},
-						FoundPaths: foundPaths,
-						TotalSize:  ruleSize,
-					})
-					totalSize += ruleSize
-					mu.Unlock()
-				}
-			}(expanded)
+	if s.options.AppCacheMode {
+		res, err := s.scanAppCaches()
+		if res != nil {
+			res.Duration = time.Since(began)
 		}
-		wg.Wait()
-		return &ScanResults{Results: results, TotalSize: totalSize}, nil
+		return res, err
 	}
 
-	// Regular Rule-Based Scan
-	results = make([]rules.Result, 0)
-	totalSize = 0
+	if s.options.LeftoverMode {
+		res, err := s.scanLeftovers()
+		if res != nil {
+			res.Duration = time.Since(began)
+		}
+		return res, err
+	}
 
+	// Regular Rule-Based Scan
 	allRules := s.registry.All()
-	// Reuse existing variables, reset results for standard scan if not in large mode
+	sem := newSemaphore(s.options.MaxConcurrency)
 
 	for _, rule := range allRules {
-		// Filter by category if specified
-		if s.options.Category != "" && !strings.EqualFold(rule.Category, s.options.Category) {
+		if !s.options.Includes(rule) {
 			continue
 		}
 
 		wg.Add(1)
 		go func(r rules.CleanupRule) {
 			defer wg.Done()
+			sem.acquire()
+			defer sem.release()
+
+			// The timeout runs from when the rule gets its turn
+			var deadline time.Time
+			if s.options.RuleTimeout > 0 {
+				deadline = time.Now().Add(s.options.RuleTimeout)
+			}
 
 			var foundPaths []string
 			var ruleSize int64
+			var ruleVolume string
+			var ruleStats []rules.PathStats
+			var ruleSkipped []SkippedPath
+			var ruleTimings []PathTiming
+			var ruleErrors []*PathError
 
-			for _, pathPattern := range r.Paths {
-				expanded := safety.ExpandPath(pathPattern)
+			// scanPath evaluates one rule path into out; it is a closure so
+			// each path can be timed regardless of where it bails out
+			scanPath := func(expanded string, out *pathScan) {
 
 				// Filter by excluded paths
-				excluded := false
-				for _, ep := range s.options.ExcludedPaths {
-					if strings.HasPrefix(expanded, safety.ExpandPath(ep)) {
-						excluded = true
-						break
+				if isExcluded(expanded, s.options.ExcludedPaths) {
+					if _, err := os.Lstat(expanded); err == nil {
+						out.skip(expanded, "excluded by configuration")
 					}
+					return
 				}
-				if excluded {
-					continue
+				if isExcluded(expanded, s.options.DismissedPaths) {
+					if _, err := os.Lstat(expanded); err == nil {
+						out.skip(expanded, "dismissed")
+					}
+					return
+				}
+				if s.inTrash(expanded) {
+					if _, err := os.Lstat(expanded); err == nil {
+						out.skip(expanded, "in Burrow's trash")
+					}
+					return
 				}
 
 				info, err := os.Stat(expanded)
 				if err != nil {
-					continue
+					if status, detail := classifyAccessError(err); status != PathMissing {
+						out.skip(expanded, skipReason(string(status), detail))
+						out.fail(newPathError(r.Name, expanded, err))
+					}
+					return
+				}
+
+				volume, external := s.externalVolume(expanded)
+				if external {
+					out.skip(expanded, "on external volume "+volume)
+					return
 				}
 
 				// Basic check if path exists (redundant but safe)
 				if os.IsNotExist(err) {
-					continue
+					return
+				}
+				if pastDeadline(deadline) {
+					out.timeOut(expanded, s.options.RuleTimeout)
+					return
 				}
 
-				// Filter by Time (OlderThan)
+				// Pattern rules select individual files inside the directory
+				if r.FileLevel() {
+					paths, size, stats, err := s.matchFiles(expanded, r, deadline)
+					if errors.Is(err, errDeadline) {
+						out.timeOut(expanded, s.options.RuleTimeout)
+						return
+					}
+					if err != nil {
+						out.fail(newPathError(r.Name, expanded, err))
+					}
+					if len(paths) == 0 {
+						return
+					}
+					if s.options.SizeThreshold > 0 && size < s.options.SizeThreshold {
+						out.skip(expanded, belowThreshold(size, s.options.SizeThreshold))
+						return
+					}
+					out.found = append(out.found, paths...)
+					out.size += size
+					out.stats = append(out.stats, stats)
+					out.volume = volume
+					return
+				}
+
+				// Filter by Time (OlderThan); items age from their last use
+				// when they record it
+				if reason := itemInUse(r.Items, expanded); reason != "" {
+					out.skip(expanded, reason)
+					return
+				}
+				item, lastUsed := describeItem(r.Items, expanded)
+				modTime := info.ModTime()
+				if !lastUsed.IsZero() {
+					modTime = lastUsed
+				}
 				if s.options.OlderThan > 0 {
-					if time.Since(info.ModTime()) < s.options.OlderThan {
-						continue
+					if time.Since(modTime) < s.options.OlderThan {
+						return
 					}
 				}
 
+				if newIgnorer(filepath.Dir(expanded)).ignored(expanded, true) {
+					out.skip(expanded, "ignored by "+IgnoreFile)
+					return
+				}
+				if hasIgnoreFile(expanded) {
+					out.skip(expanded, "contains "+IgnoreFile)
+					return
+				}
+
 				// Safety check
-				if safe, _ := safety.IsSafe(expanded); !safe {
-					continue
+				if v := safety.CheckWith(expanded, r.SafetyOptions()); !v.Safe {
+					out.skip(expanded, skipReason("unsafe", v.Reason))
+					out.fail(&PathError{Kind: KindUnsafe, Rule: r.Name, Path: expanded, Detail: v.Reason})
+					return
 				}
 
-				size, err := dirSize(expanded)
-				if err != nil {
-					continue
+				size, stats, err := dirStatsUntil(expanded, s.limiter, deadline)
+				if errors.Is(err, errDeadline) {
+					out.timeOut(expanded, s.options.RuleTimeout)
+					return
 				}
+				// What could be read is still a candidate; the error says
+				// its size is a lower bound
+				var partial *partialError
+				if errors.As(err, &partial) {
+					out.fail(newPathError(r.Name, partial.path, partial.err))
+				} else if err != nil {
+					status, detail := classifyAccessError(err)
+					out.skip(expanded, skipReason(string(status), detail))
+					out.fail(newPathError(r.Name, expanded, err))
+					return
+				}
+				if r.SparseFiles {
+					size = stats.Allocated
+				}
+				stats.Item, stats.LastUsed = item, lastUsed
 
 				// Filter by size threshold
 				if s.options.SizeThreshold > 0 && size < s.options.SizeThreshold {
-					continue
+					out.skip(expanded, belowThreshold(size, s.options.SizeThreshold))
+					return
 				}
 
-				foundPaths = append(foundPaths, expanded)
-				ruleSize += size
+				out.found = append(out.found, expanded)
+				out.size += size
+				out.stats = append(out.stats, stats)
+				out.volume = volume
 			}
 
+			stuck := false
+			for _, pathPattern := range r.Paths {
+				// Past a stuck path, the rest likely sit on the same
+				// unresponsive volume; the rule is reported as timed out
+				if stuck {
+					break
+				}
+				for _, expanded := range expandRulePath(pathPattern) {
+					start := time.Now()
+					var out *pathScan
+					out, stuck = scanUntil(expanded, r.Name, deadline, s.options.RuleTimeout, scanPath)
+					if elapsed := time.Since(start); elapsed >= minTiming {
+						ruleTimings = append(ruleTimings, PathTiming{Rule: r.Name, Path: expanded, Duration: elapsed})
+					}
+					foundPaths = append(foundPaths, out.found...)
+					ruleSize += out.size
+					ruleStats = append(ruleStats, out.stats...)
+					ruleSkipped = append(ruleSkipped, out.skipped...)
+					ruleErrors = append(ruleErrors, out.errors...)
+					if ruleVolume == "" {
+						ruleVolume = out.volume
+					}
+					if stuck {
+						break
+					}
+				}
+			}
+
+			mu.Lock()
+			skipped = append(skipped, ruleSkipped...)
+			scanErrors = append(scanErrors, ruleErrors...)
+			timings = append(timings, ruleTimings...)
+			mu.Unlock()
+
 			if len(foundPaths) > 0 {
 				mu.Lock()
 				results = append(results, rules.Result{
 					Rule:       r,
 					FoundPaths: foundPaths,
 					TotalSize:  ruleSize,
+					Volume:     ruleVolume,
+					Stats:      ruleStats,
 				})
 				totalSize += ruleSize
 				mu.Unlock()
@@ -189,36 +547,221 @@ func (s *Scanner) Scan() (*ScanResults, error) {
 
 	wg.Wait()
 
-	return &ScanResults{
+	sort.Slice(skipped, func(i, j int) bool {
+		if skipped[i].Rule != skipped[j].Rule {
+			return skipped[i].Rule < skipped[j].Rule
+		}
+		return skipped[i].Path < skipped[j].Path
+	})
+
+	sort.Slice(scanErrors, func(i, j int) bool {
+		if scanErrors[i].Rule != scanErrors[j].Rule {
+			return scanErrors[i].Rule < scanErrors[j].Rule
+		}
+		return scanErrors[i].Path < scanErrors[j].Path
+	})
+	sort.Slice(timings, func(i, j int) bool { return timings[i].Duration > timings[j].Duration })
+	rules.SortBySize(results)
+
+	res := &ScanResults{
 		Results:   results,
 		TotalSize: totalSize,
-	}, nil
+		Skipped:   skipped,
+		Errors:    scanErrors,
+		Timings:   timings,
+	}
+	s.hideSmall(res)
+	res.Disk = diskSummary(res.TotalSize)
+	res.Duration = time.Since(began)
+	return res, nil
 }
 
-// dirSize calculates the total size of a directory.
+// pathScan is what scanning one rule path found. Each path gets its own, so
+// one abandoned after the rule's deadline can't touch the rule's results.
+type pathScan struct {
+	rule    string
+	found   []string
+	size    int64
+	volume  string
+	stats   []rules.PathStats
+	skipped []SkippedPath
+	errors  []*PathError
+}
+
+func (p *pathScan) skip(path, reason string) {
+	p.skipped = append(p.skipped, SkippedPath{Rule: p.rule, Path: path, Reason: reason})
+}
+
+func (p *pathScan) fail(e *PathError) {
+	p.errors = append(p.errors, e)
+}
+
+func (p *pathScan) timeOut(path string, limit time.Duration) {
+	p.skip(path, "size unknown (timed out)")
+	p.fail(&PathError{Kind: KindTimedOut, Rule: p.rule, Path: path, Detail: "after " + limit.String()})
+}
+
+// stuckGrace is how long past a rule's deadline scanUntil waits for a walk
+// to notice the deadline itself before giving up on it.
+var stuckGrace = time.Second
+
+// scanUntil runs scan on path and returns what it found. Walks check the
+// deadline between entries, but a call stuck in the kernel, such as a stat
+// on an unresponsive network mount, never returns to check it. Shortly after
+// the deadline scanUntil stops waiting, reports the path as timed out, and
+// returns true; the stuck goroutine is left to finish, or not, on its own.
+func scanUntil(path, rule string, deadline time.Time, limit time.Duration, scan func(string, *pathScan)) (*pathScan, bool) {
+	if deadline.IsZero() {
+		out := &pathScan{rule: rule}
+		scan(path, out)
+		return out, false
+	}
+
+	done := make(chan *pathScan, 1)
+	go func() {
+		out := &pathScan{rule: rule}
+		scan(path, out)
+		done <- out
+	}()
+
+	timer := time.NewTimer(time.Until(deadline) + stuckGrace)
+	defer timer.Stop()
+	select {
+	case out := <-done:
+		return out, false
+	case <-timer.C:
+		out := &pathScan{rule: rule}
+		out.timeOut(path, limit)
+		return out, true
+	}
+}
+
+func belowThreshold(size, threshold int64) string {
+	return fmt.Sprintf("below size threshold: %s < %s", units.FormatSize(size), units.FormatSize(threshold))
+}
+
+func skipReason(status, detail string) string {
+	if detail == "" {
+		return status
+	}
+	return status + ": " + detail
+}
+
+// externalVolume returns the mount point of path when it lives on an external
+// or network volume, and whether the path must be skipped because external
+// volumes are excluded.
+func (s *Scanner) externalVolume(path string) (string, bool) {
+	vol, err := disk.VolumeOf(path)
+	if err != nil || !vol.Removable() {
+		return "", false
+	}
+	return vol.MountPoint, s.options.ExcludeExternal
+}
+
+// expandRulePath expands a rule path. Paths with glob characters (e.g.
+// ~/.cache/huggingface/hub/models--*) become one candidate per match, so each
+// model or version is sized and listed on its own; a glob without matches is
+// returned as is and reported missing like any other path.
+func expandRulePath(pattern string) []string {
+	expanded := safety.ExpandPath(pattern)
+	if !strings.ContainsAny(expanded, "*?[") {
+		return []string{expanded}
+	}
+	matches, err := filepath.Glob(expanded)
+	if err != nil || len(matches) == 0 {
+		return []string{expanded}
+	}
+	return matches
+}
+
+// isExcluded reports whether path matches one of the configured exclusions.
+// Exclusions match whole path segments, so excluding ~/Library/Caches/Foo
+// does not exclude ~/Library/Caches/FooBar. Exclusions may contain globs
+// (e.g. ~/Library/Caches/com.mycompany.*), which also exclude everything below
+// a matching directory.
+func isExcluded(path string, excludedPaths []string) bool {
+	for _, ep := range excludedPaths {
+		if safety.MatchPath(path, safety.ExpandPath(ep)) {
+			return true
+		}
+	}
+	return false
+}
+
+// dirSize calculates the total size of a directory. Symlinks are never
+// followed and count as zero bytes, so data linked from outside the rule path
+// is neither counted nor considered part of the candidate.
 func dirSize(path string) (int64, error) {
+	size, _, err := dirStats(path, nil)
+	return size, err
+}
+
+// dirStats is dirSize that also counts the regular files and tracks their
+// newest and oldest modification times, pacing the walk with lim.
+func dirStats(path string, lim *limiter) (int64, rules.PathStats, error) {
+	return dirStatsUntil(path, lim, time.Time{})
+}
+
+// errDeadline stops a walk that ran past its rule's timeout.
+var errDeadline = errors.New("rule timeout exceeded")
+
+// pastDeadline reports whether deadline has passed; the zero deadline never
+// does.
+func pastDeadline(deadline time.Time) bool {
+	return !deadline.IsZero() && time.Now().After(deadline)
+}
+
+// walk is filepath.Walk, replaced by tests to inject read errors that
+// permissions can't produce when running as root.
+var walk = filepath.Walk
+
+// partialError reports the first entry below a walked path that could not
+// be read. The walk skipped it and the sizes returned alongside cover the
+// rest.
+type partialError struct {
+	path string
+	err  error
+}
+
+func (e *partialError) Error() string { return e.path + ": " + e.err.Error() }
+func (e *partialError) Unwrap() error { return e.err }
+
+// dirStatsUntil is dirStats that gives up with errDeadline once deadline
+// has passed. Unreadable entries below path are skipped and the first is
+// returned as a *partialError with the totals of everything else.
+func dirStatsUntil(path string, lim *limiter, deadline time.Time) (int64, rules.PathStats, error) {
 	var size int64
-	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+	stats := rules.PathStats{Path: path}
+	var partial *partialError
+	err := walk(path, func(p string, info os.FileInfo, err error) error {
 		if err != nil {
-			return err
+			if p == path {
+				return err
+			}
+			if partial == nil {
+				partial = &partialError{path: p, err: err}
+			}
+			if info != nil && info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if pastDeadline(deadline) {
+			return errDeadline
 		}
-		if !info.IsDir() {
+		if p == path {
+			stats.Kind = rules.PathKind(info.Mode())
+		}
+		lim.wait()
+		if info.Mode().IsRegular() {
 			size += info.Size()
+			stats.Add(info.Size(), info.ModTime())
+			stats.Allocated += allocatedSize(info)
 		}
 		return nil
 	})
-	return size, err
-}
-
-func formatBytes(b int64) string {
-	const unit = 1024
-	if b < unit {
-		return fmt.Sprintf("%d B", b)
-	}
-	div, exp := int64(unit), 0
-	for n := b / unit; n >= unit; n /= unit {
-		div *= unit
-		exp++
+	if err == nil && partial != nil {
+		err = partial
 	}
-	return fmt.Sprintf("%.1f %cB", float64(b)/float64(div), "KMGTPE"[exp])
+	return size, stats, err
 }
@@ -0,0 +1,63 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+// Size modes decide which size drives totals and sorting.
+const (
+	SizeApparent = "apparent" // Logical file sizes, as ls and Finder show them
+	SizeDisk     = "disk"     // Blocks allocated on disk, as du shows them
+)
+
+// ParseSizeMode validates a --size-mode value; empty means SizeApparent.
+func ParseSizeMode(mode string) (string, error) {
+	switch mode {
+	case "", SizeApparent:
+		return SizeApparent, nil
+	case SizeDisk:
+		return SizeDisk, nil
+	default:
+		return "", fmt.Errorf("unknown size mode %q (use apparent or disk)", mode)
+	}
+}
+
+// ApplySizeMode returns results sized by mode. Scans measure apparent sizes,
+// except for rules with sparse files, so the scan cache is always in that
+// mode; in SizeDisk every result is resized to its allocated blocks, which
+// are fewer for APFS-compressed and sparse files and more for many small
+// ones, and the results are sorted again.
+func ApplySizeMode(results *ScanResults, mode string) *ScanResults {
+	if mode != SizeDisk {
+		return results
+	}
+	out := *results
+	out.Results = make([]rules.Result, len(results.Results))
+	out.TotalSize = 0
+	for i, res := range results.Results {
+		res.TotalSize = allocatedTotal(res)
+		out.Results[i] = res
+		out.TotalSize += res.TotalSize
+	}
+	rules.SortBySize(out.Results)
+	out.Disk = diskSummary(out.TotalSize)
+	return &out
+}
+
+// allocatedTotal is the on-disk size of a result. Results without path
+// statistics, such as large files, list individual files to stat.
+func allocatedTotal(res rules.Result) int64 {
+	if len(res.Stats) > 0 {
+		return res.Summary().Allocated
+	}
+	var total int64
+	for _, p := range res.FoundPaths {
+		if info, err := os.Lstat(p); err == nil {
+			total += allocatedSize(info)
+		}
+	}
+	return total
+}
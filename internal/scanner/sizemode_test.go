@@ -0,0 +1,41 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+func TestApplySizeMode(t *testing.T) {
+	result := func(name string, stats ...rules.PathStats) rules.Result {
+		res := rules.Result{Rule: rules.CleanupRule{Name: name}, Stats: stats}
+		for _, st := range stats {
+			res.TotalSize += st.Size
+		}
+		return res
+	}
+	scan := &ScanResults{Results: []rules.Result{
+		result("Compressed", rules.PathStats{Size: 900, Allocated: 300}),
+		result("Small files", rules.PathStats{Size: 200, Allocated: 400}, rules.PathStats{Size: 300, Allocated: 400}),
+	}}
+	scan.TotalSize = 1400
+
+	if got := ApplySizeMode(scan, SizeApparent); got != scan {
+		t.Error("apparent mode should return the scan unchanged")
+	}
+
+	disk := ApplySizeMode(scan, SizeDisk)
+	if disk.TotalSize != 1100 {
+		t.Errorf("TotalSize = %d, want 1100", disk.TotalSize)
+	}
+	if disk.Results[0].Rule.Name != "Small files" || disk.Results[0].TotalSize != 800 || disk.Results[1].TotalSize != 300 {
+		t.Errorf("results = %+v, want Small files (800) before Compressed (300)", disk.Results)
+	}
+	if scan.Results[0].TotalSize != 900 || scan.TotalSize != 1400 {
+		t.Error("ApplySizeMode modified its input")
+	}
+
+	if _, err := ParseSizeMode("blocks"); err == nil {
+		t.Error("ParseSizeMode accepted an unknown mode")
+	}
+}
@@ -0,0 +1,33 @@
+package scanner
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// querySpotlight runs mdfind limited to root. mdfind prints nothing, rather
+// than failing, where indexing is off, so mdutil is asked first.
+func querySpotlight(root string, threshold int64) ([]string, error) {
+	status, err := exec.Command("mdutil", "-s", root).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("mdutil: %s", strings.TrimSpace(string(status)))
+	}
+	if bytes.Contains(bytes.ToLower(status), []byte("disabled")) {
+		return nil, errors.New("indexing is disabled")
+	}
+
+	out, err := exec.Command("mdfind", "-0", "-onlyin", root, fmt.Sprintf("kMDItemFSSize > %d", threshold)).Output()
+	if err != nil {
+		return nil, fmt.Errorf("mdfind: %w", err)
+	}
+	var paths []string
+	for _, p := range strings.Split(string(out), "\x00") {
+		if p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths, nil
+}
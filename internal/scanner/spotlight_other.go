@@ -0,0 +1,9 @@
+//go:build !darwin
+
+package scanner
+
+import "errors"
+
+func querySpotlight(root string, threshold int64) ([]string, error) {
+	return nil, errors.New("Spotlight is only available on macOS")
+}
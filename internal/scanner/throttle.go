@@ -0,0 +1,69 @@
+package scanner
+
+import (
+	"sync"
+	"time"
+)
+
+// minSleep batches short delays: sleeping per file at high rates would cost
+// far more than the delay itself, so the debt is paid once it adds up.
+const minSleep = 10 * time.Millisecond
+
+// limiter paces filesystem walks to a fixed number of files per second,
+// shared by all concurrent walks of a scan. A nil limiter never waits.
+type limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// newLimiter returns a limiter for perSecond files, or nil for no limit.
+func newLimiter(perSecond int) *limiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &limiter{interval: time.Second / time.Duration(perSecond)}
+}
+
+// wait blocks until the next file may be visited.
+func (l *limiter) wait() {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if delay >= minSleep {
+		time.Sleep(delay)
+	}
+}
+
+// semaphore bounds the number of concurrent walks. A nil semaphore admits
+// everyone.
+type semaphore chan struct{}
+
+func newSemaphore(n int) semaphore {
+	if n <= 0 {
+		return nil
+	}
+	return make(semaphore, n)
+}
+
+func (s semaphore) acquire() {
+	if s != nil {
+		s <- struct{}{}
+	}
+}
+
+func (s semaphore) release() {
+	if s != nil {
+		<-s
+	}
+}
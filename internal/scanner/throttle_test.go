@@ -0,0 +1,21 @@
+package scanner
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+	var none *limiter
+	none.wait() // nil limiters never block
+
+	lim := newLimiter(1000)
+	start := time.Now()
+	for i := 0; i < 101; i++ {
+		lim.wait()
+	}
+	// The first file is free, the next hundred are paced at 1ms each
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("101 files at 1000/s took %s, want about 100ms", elapsed)
+	}
+}
@@ -0,0 +1,89 @@
+package scanner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+func TestScanRuleTimeout(t *testing.T) {
+	root := t.TempDir()
+	slow := filepath.Join(root, "Slow")
+	for i := range 20 {
+		path := filepath.Join(slow, "dir", string(rune('a'+i)))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, 100), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	registry := rules.NewRegistryFromRules([]rules.CleanupRule{
+		{Name: "Slow", Paths: []string{slow}},
+		{Name: "Sweep", Paths: []string{slow}, IncludePatterns: []string{"*"}},
+	})
+	results, err := NewScanner(registry, ScanOptions{RuleTimeout: time.Nanosecond}).Scan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.Results) != 0 {
+		t.Errorf("results = %+v, want none after timing out", results.Results)
+	}
+	for _, s := range results.Skipped {
+		if s.Reason != "size unknown (timed out)" {
+			t.Errorf("skipped %s (%s): %q", s.Path, s.Rule, s.Reason)
+		}
+	}
+	timedOut := results.TimedOut()
+	slices.Sort(timedOut)
+	if !slices.Equal(timedOut, []string{"Slow", "Sweep"}) {
+		t.Errorf("timed out = %v, want both rules", timedOut)
+	}
+	if !errors.Is(results.Err(), ErrTimedOut) {
+		t.Errorf("err = %v, want ErrTimedOut", results.Err())
+	}
+
+	// Retrying one rule without a limit scans it fully
+	results, err = NewScanner(registry, ScanOptions{Rules: []string{"slow"}}).Scan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.Results) != 1 || results.Results[0].Rule.Name != "Slow" || results.Results[0].TotalSize != 2000 {
+		t.Fatalf("results = %+v, want only Slow at 2000 bytes", results.Results)
+	}
+	if len(results.TimedOut()) != 0 {
+		t.Errorf("timed out = %v, want none", results.TimedOut())
+	}
+}
+
+func TestScanUntilAbandonsStuckPath(t *testing.T) {
+	defer func(g time.Duration) { stuckGrace = g }(stuckGrace)
+	stuckGrace = 10 * time.Millisecond
+
+	release := make(chan struct{})
+	defer close(release)
+	stuck := func(path string, out *pathScan) {
+		<-release // Like a stat on a mount that stopped answering
+		out.found = append(out.found, path)
+	}
+
+	out, abandoned := scanUntil("/mnt/nas", "NAS", time.Now().Add(10*time.Millisecond), time.Minute, stuck)
+	if !abandoned {
+		t.Fatal("stuck path was not abandoned")
+	}
+	if len(out.found) != 0 || len(out.errors) != 1 || out.errors[0].Kind != KindTimedOut {
+		t.Errorf("out = %+v, want only a timeout", out)
+	}
+
+	quick := func(path string, out *pathScan) { out.found = append(out.found, path) }
+	out, abandoned = scanUntil("/tmp/x", "Quick", time.Now().Add(time.Minute), time.Minute, quick)
+	if abandoned || len(out.found) != 1 {
+		t.Errorf("quick path: out = %+v, abandoned = %v", out, abandoned)
+	}
+}
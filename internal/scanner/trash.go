@@ -0,0 +1,41 @@
+package scanner
+
+import (
+	"path/filepath"
+
+	"github.com/ismailtsdln/burrow/internal/paths"
+	"github.com/ismailtsdln/burrow/internal/safety"
+)
+
+// trashDirs returns Burrow's trash directory, and where it resolves to when
+// a symlink leads there, so scans never count trashed data as new
+// reclaimable space.
+func trashDirs() []string {
+	dir := paths.TrashDir()
+	dirs := []string{dir}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil && resolved != dir {
+		dirs = append(dirs, resolved)
+	}
+	return dirs
+}
+
+// inTrash reports whether path is Burrow's trash or lies inside it.
+func (s *Scanner) inTrash(path string) bool {
+	for _, dir := range s.trash {
+		if safety.MatchPath(path, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// holdsTrash reports whether Burrow's trash lies below path, so cleaning
+// path would move the trash along with it.
+func (s *Scanner) holdsTrash(path string) bool {
+	for _, dir := range s.trash {
+		if filepath.Clean(path) != dir && safety.MatchPath(dir, path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ismailtsdln/burrow/internal/config"
+	"github.com/ismailtsdln/burrow/internal/paths"
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+func TestScanSkipsTrash(t *testing.T) {
+	home := t.TempDir()
+	data := filepath.Join(home, "Caches", "burrow")
+	t.Setenv(paths.DataEnv, data)
+	for name, size := range map[string]int{
+		"Caches/app/blob":                          2048,
+		"Caches/burrow/trash/20240101_000000/blob": 4096,
+		"Documents/movie.mov":                      2048,
+	} {
+		path := filepath.Join(home, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	caches := filepath.Join(home, "Caches")
+	trash := paths.TrashDir()
+
+	registry := rules.NewRegistryFromRules([]rules.CleanupRule{
+		{Name: "Caches", Paths: []string{caches}},
+		{Name: "Sweep", Paths: []string{caches}, IncludePatterns: []string{"blob"}},
+		{Name: "Trashed", Paths: []string{filepath.Join(trash, "*")}},
+	})
+	results, err := NewScanner(registry, ScanOptions{}).Scan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.Results) != 1 || results.Results[0].Rule.Name != "Sweep" || results.Results[0].TotalSize != 2048 {
+		t.Fatalf("results = %+v, want only the sweep without the trash", results.Results)
+	}
+	reasons := make(map[string]string)
+	for _, s := range results.Skipped {
+		reasons[s.Rule] = s.Reason
+	}
+	if !strings.Contains(reasons["Caches"], "Burrow's own state") || reasons["Trashed"] != "in Burrow's trash" {
+		t.Errorf("skipped = %+v", results.Skipped)
+	}
+
+	large, err := NewScanner(nil, ScanOptions{
+		LargeFileMode:  true,
+		SizeThreshold:  1024,
+		LargeFileRoots: []config.LargeFileRoot{{Path: home}},
+	}).Scan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(large.Results) != 1 || len(large.Results[0].FoundPaths) != 2 {
+		t.Fatalf("large files = %+v, want two outside the trash", large.Results)
+	}
+	for _, p := range large.Results[0].FoundPaths {
+		if filepath.Dir(filepath.Dir(p)) == trash {
+			t.Errorf("large file mode found %s in the trash", p)
+		}
+	}
+}
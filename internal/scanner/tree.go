@@ -0,0 +1,118 @@
+package scanner
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// TreeNode is a directory or file in a collapsed view of planned deletions.
+// Size covers the whole subtree, even below the depth that was expanded.
+type TreeNode struct {
+	Name     string      `json:"name"`
+	Path     string      `json:"path"`
+	Size     int64       `json:"size"`
+	Children []*TreeNode `json:"children,omitempty"`
+}
+
+// BuildTree summarizes paths as trees expanded to depth levels. Each directory
+// becomes its own root; loose files (as found by pattern rules) are grouped
+// under their common parent directory. Children are sorted largest first.
+func BuildTree(paths []string, depth int) []*TreeNode {
+	var roots []*TreeNode
+	var files []string
+	var fileSizes []int64
+
+	for _, p := range paths {
+		info, err := os.Lstat(p)
+		if err != nil {
+			continue
+		}
+		if !info.IsDir() {
+			files = append(files, p)
+			fileSizes = append(fileSizes, info.Size())
+			continue
+		}
+
+		root := &TreeNode{Name: p, Path: p}
+		filepath.WalkDir(p, func(fp string, d fs.DirEntry, err error) error {
+			if err != nil || !d.Type().IsRegular() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			rel, _ := filepath.Rel(p, fp)
+			root.add(p, strings.Split(rel, string(filepath.Separator)), info.Size(), depth)
+			return nil
+		})
+		roots = append(roots, root)
+	}
+
+	if len(files) > 0 {
+		base := commonDir(files)
+		root := &TreeNode{Name: base, Path: base}
+		for i, f := range files {
+			rel, _ := filepath.Rel(base, f)
+			root.add(base, strings.Split(rel, string(filepath.Separator)), fileSizes[i], depth)
+		}
+		roots = append(roots, root)
+	}
+
+	for _, r := range roots {
+		r.sort()
+	}
+	return roots
+}
+
+// add accounts size to n and to the nodes along parts, creating at most depth
+// levels of children below n.
+func (n *TreeNode) add(base string, parts []string, size int64, depth int) {
+	n.Size += size
+	if depth == 0 || len(parts) == 0 {
+		return
+	}
+
+	var child *TreeNode
+	for _, c := range n.Children {
+		if c.Name == parts[0] {
+			child = c
+			break
+		}
+	}
+	if child == nil {
+		child = &TreeNode{Name: parts[0], Path: filepath.Join(base, parts[0])}
+		n.Children = append(n.Children, child)
+	}
+	child.add(child.Path, parts[1:], size, depth-1)
+}
+
+func (n *TreeNode) sort() {
+	sort.Slice(n.Children, func(i, j int) bool {
+		if n.Children[i].Size != n.Children[j].Size {
+			return n.Children[i].Size > n.Children[j].Size
+		}
+		return n.Children[i].Name < n.Children[j].Name
+	})
+	for _, c := range n.Children {
+		c.sort()
+	}
+}
+
+// commonDir returns the deepest directory containing every path.
+func commonDir(paths []string) string {
+	common := filepath.Dir(paths[0])
+	for _, p := range paths[1:] {
+		for !strings.HasPrefix(p, strings.TrimSuffix(common, string(filepath.Separator))+string(filepath.Separator)) {
+			parent := filepath.Dir(common)
+			if parent == common {
+				return common
+			}
+			common = parent
+		}
+	}
+	return common
+}
@@ -0,0 +1,65 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildTree(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "burrow_tree")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	files := map[string]int{
+		"DerivedData/App-abc/Build/Products/app":    4000,
+		"DerivedData/App-abc/Index/store":           1000,
+		"DerivedData/Lib-def/Build/Intermediates/o": 500,
+		"DerivedData/info.plist":                    10,
+		"logs/a/1.log":                              100,
+		"logs/b/2.log":                              200,
+	}
+	for name, size := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	roots := BuildTree([]string{
+		filepath.Join(tmpDir, "DerivedData"),
+		filepath.Join(tmpDir, "logs/a/1.log"),
+		filepath.Join(tmpDir, "logs/b/2.log"),
+	}, 2)
+	if len(roots) != 2 {
+		t.Fatalf("expected 2 roots, got %d", len(roots))
+	}
+
+	dd := roots[0]
+	if dd.Size != 5510 {
+		t.Errorf("DerivedData size = %d, want 5510", dd.Size)
+	}
+	if len(dd.Children) != 3 || dd.Children[0].Name != "App-abc" || dd.Children[0].Size != 5000 {
+		t.Fatalf("unexpected first level: %+v", dd.Children)
+	}
+	app := dd.Children[0]
+	if len(app.Children) != 2 || app.Children[0].Name != "Build" || app.Children[0].Size != 4000 {
+		t.Fatalf("unexpected second level: %+v", app.Children)
+	}
+	if len(app.Children[0].Children) != 0 {
+		t.Error("tree should be collapsed below two levels")
+	}
+
+	logs := roots[1]
+	if logs.Path != filepath.Join(tmpDir, "logs") || logs.Size != 300 {
+		t.Errorf("loose files grouped under %s (%d bytes), want logs dir with 300", logs.Path, logs.Size)
+	}
+	if len(logs.Children) != 2 || logs.Children[0].Name != "b" {
+		t.Errorf("unexpected loose file children: %+v", logs.Children)
+	}
+}
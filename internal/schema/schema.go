@@ -0,0 +1,259 @@
+// Package schema embeds the JSON Schemas of Burrow's config and custom rule
+// files and checks files against them, reporting problems by line and field.
+package schema
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed config.schema.json
+var configSchema []byte
+
+//go:embed rules.schema.json
+var rulesSchema []byte
+
+// Names lists the embedded schemas.
+var Names = []string{"config", "rules"}
+
+// Source returns the embedded schema called name.
+func Source(name string) ([]byte, error) {
+	switch name {
+	case "config":
+		return configSchema, nil
+	case "rules":
+		return rulesSchema, nil
+	}
+	return nil, fmt.Errorf("unknown schema %q (use %s)", name, strings.Join(Names, " or "))
+}
+
+// Schema is the subset of JSON Schema that Burrow's schemas use.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 typeList           `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+}
+
+// typeList is a "type" keyword, which may be a single type or a list.
+type typeList []string
+
+func (t *typeList) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = typeList{one}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+// Load parses the embedded schema called name.
+func Load(name string) (*Schema, error) {
+	data, err := Source(name)
+	if err != nil {
+		return nil, err
+	}
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("embedded %s schema: %w", name, err)
+	}
+	return &s, nil
+}
+
+// Error is a problem found at a line and column of a file.
+type Error struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Field   string `json:"field,omitempty"` // e.g. notifications[0].url
+	Message string `json:"message"`
+}
+
+func (e Error) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("%d:%d: %s: %s", e.Line, e.Column, e.Field, e.Message)
+}
+
+// Validate checks a JSON or YAML document against the schema called name.
+// A document that cannot be parsed yields a single error at the position of
+// the syntax error.
+func Validate(name string, data []byte, isJSON bool) ([]Error, error) {
+	s, err := Load(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if isJSON {
+		var v interface{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			var syntax *json.SyntaxError
+			if errors.As(err, &syntax) {
+				line, col := position(data, syntax.Offset)
+				return []Error{{Line: line, Column: col, Message: syntax.Error()}}, nil
+			}
+			return []Error{{Line: 1, Column: 1, Message: err.Error()}}, nil
+		}
+	}
+
+	// YAML is a superset of JSON, and its nodes carry their positions
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return []Error{{Line: 1, Column: 1, Message: err.Error()}}, nil
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	v := &validator{root: s}
+	v.check(s, doc.Content[0], "")
+	return v.errs, nil
+}
+
+type validator struct {
+	root *Schema
+	errs []Error
+}
+
+func (v *validator) fail(n *yaml.Node, field, format string, args ...interface{}) {
+	v.errs = append(v.errs, Error{Line: n.Line, Column: n.Column, Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) check(s *Schema, n *yaml.Node, field string) {
+	if s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, "#/$defs/")
+		if def := v.root.Defs[name]; def != nil {
+			s = def
+		}
+	}
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+
+	got := nodeType(n)
+	if len(s.Type) > 0 && !slices.ContainsFunc(s.Type, func(t string) bool { return typeMatches(t, got) }) {
+		v.fail(n, field, "must be %s, got %s", strings.Join(s.Type, " or "), got)
+		return
+	}
+	if len(s.Enum) > 0 && !slices.Contains(s.Enum, n.Value) {
+		v.fail(n, field, "must be one of %s, got %q", strings.Join(s.Enum, ", "), n.Value)
+		return
+	}
+	if s.Pattern != "" && got == "string" {
+		if re, err := regexp.Compile(s.Pattern); err == nil && !re.MatchString(n.Value) {
+			if s.Description != "" {
+				v.fail(n, field, "%q is not valid; expected %s", n.Value, lowerFirst(s.Description))
+			} else {
+				v.fail(n, field, "%q does not match %s", n.Value, s.Pattern)
+			}
+		}
+	}
+	if s.Minimum != nil && (got == "integer" || got == "number") {
+		if f, err := strconv.ParseFloat(n.Value, 64); err == nil && f < *s.Minimum {
+			v.fail(n, field, "must be at least %v", *s.Minimum)
+		}
+	}
+
+	switch n.Kind {
+	case yaml.MappingNode:
+		seen := make(map[string]bool)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i], n.Content[i+1]
+			seen[key.Value] = true
+			child := joinField(field, key.Value)
+			if prop := s.Properties[key.Value]; prop != nil {
+				v.check(prop, value, child)
+			} else if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				v.fail(key, child, "unknown field%s", suggest(key.Value, s.Properties))
+			}
+		}
+		for _, req := range s.Required {
+			if !seen[req] {
+				v.fail(n, field, "missing required field %q", req)
+			}
+		}
+	case yaml.SequenceNode:
+		if s.Items != nil {
+			for i, item := range n.Content {
+				v.check(s.Items, item, fmt.Sprintf("%s[%d]", field, i))
+			}
+		}
+	}
+}
+
+// nodeType names the JSON type of a node.
+func nodeType(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	switch n.Tag {
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	case "!!bool":
+		return "boolean"
+	case "!!null":
+		return "null"
+	}
+	return "string"
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+func typeMatches(want, got string) bool {
+	return want == got || (want == "number" && got == "integer")
+}
+
+func joinField(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// suggest names a known field that differs from key only by case or
+// separators, the most common way to misspell one.
+func suggest(key string, known map[string]*Schema) string {
+	norm := func(s string) string {
+		return strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(s))
+	}
+	for name := range known {
+		if norm(name) == norm(key) {
+			return fmt.Sprintf(" (did you mean %q?)", name)
+		}
+	}
+	return ""
+}
+
+// position converts a byte offset into a 1-based line and column.
+func position(data []byte, offset int64) (int, int) {
+	offset = min(offset, int64(len(data)))
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := int(offset) - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
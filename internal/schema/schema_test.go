@@ -0,0 +1,116 @@
+package schema
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ismailtsdln/burrow/internal/config"
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+func TestValidateConfig(t *testing.T) {
+	data := `{
+  "excluded_paths": ["~/keep", 42],
+  "size_threshold": "lots",
+  "Enable_Auth": true,
+  "notifications": [{"type": "email"}],
+  "scan": {"max_concurrency": -1}
+}`
+	errs, err := Validate("config", []byte(data), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"2:32: excluded_paths[1]: must be string, got integer",
+		`3:21: size_threshold: "lots" is not valid`,
+		`4:3: Enable_Auth: unknown field (did you mean "enable_auth"?)`,
+		`5:30: notifications[0].type: must be one of slack, discord, generic, got "email"`,
+		`5:21: notifications[0]: missing required field "url"`,
+		"6:31: scan.max_concurrency: must be at least 0",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("got %d errors, want %d: %v", len(errs), len(want), errs)
+	}
+	for i, w := range want {
+		if !strings.HasPrefix(errs[i].Error(), w) {
+			t.Errorf("error %d = %q, want prefix %q", i, errs[i].Error(), w)
+		}
+	}
+}
+
+func TestValidateSyntaxError(t *testing.T) {
+	errs, err := Validate("config", []byte("{\n  \"enable_auth\": true,\n}"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 || errs[0].Line != 3 {
+		t.Fatalf("errs = %v, want one error on line 3", errs)
+	}
+}
+
+func TestValidateRulesYAML(t *testing.T) {
+	data := `- name: Maven Repo
+  paths: ["~/.m2/repository"]
+  risk_level: Caution
+  min_age: 2w
+- name: Broken
+  risk_level: risky
+  min_age: 14
+`
+	errs, err := Validate("rules", []byte(data), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`6:15: [1].risk_level: must be one of Safe, Caution, Manual, got "risky"`,
+		"7:12: [1].min_age: must be string, got integer",
+		`5:3: [1]: missing required field "paths"`,
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("got %v, want %v", errs, want)
+	}
+	for i, w := range want {
+		if errs[i].Error() != w {
+			t.Errorf("error %d = %q, want %q", i, errs[i].Error(), w)
+		}
+	}
+}
+
+// TestSchemasMatchStructs keeps the schemas in step with the fields the
+// loaders accept.
+func TestSchemasMatchStructs(t *testing.T) {
+	cfg, _ := Load("config")
+	compareFields(t, "config", cfg, reflect.TypeOf(config.Config{}))
+
+	rulesSchema, _ := Load("rules")
+	compareFields(t, "rule", rulesSchema.Defs["rule"], reflect.TypeOf(rules.CleanupRule{}), "source")
+}
+
+func compareFields(t *testing.T, name string, s *Schema, typ reflect.Type, internal ...string) {
+	t.Helper()
+	fields := make(map[string]bool)
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		tag := strings.Split(f.Tag.Get("json"), ",")[0]
+		if tag == "" || tag == "-" || slices.Contains(internal, tag) {
+			continue
+		}
+		fields[tag] = true
+		prop := s.Properties[tag]
+		if prop == nil {
+			t.Errorf("%s schema is missing %q", name, tag)
+			continue
+		}
+		if f.Type.Kind() == reflect.Struct && len(prop.Properties) > 0 {
+			compareFields(t, name+"."+tag, prop, f.Type)
+		}
+	}
+	for prop := range s.Properties {
+		if !fields[prop] && prop != "$schema" {
+			t.Errorf("%s schema has %q, which is not a field", name, prop)
+		}
+	}
+}
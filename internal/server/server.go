@@ -0,0 +1,283 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/auth"
+	"github.com/ismailtsdln/burrow/internal/cleaner"
+	"github.com/ismailtsdln/burrow/internal/config"
+	"github.com/ismailtsdln/burrow/internal/dismiss"
+	"github.com/ismailtsdln/burrow/internal/history"
+	"github.com/ismailtsdln/burrow/internal/notify"
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/safety"
+	"github.com/ismailtsdln/burrow/internal/scanner"
+	"github.com/ismailtsdln/burrow/internal/units"
+	"github.com/ismailtsdln/burrow/internal/usage"
+)
+
+// Server exposes Burrow's scan, clean, history, and rules operations over HTTP.
+type Server struct {
+	Addr  string
+	Token string
+	mux   *http.ServeMux
+	// cleaning serializes cleanups, so each one scans after the last
+	// finished and gets a trash session of its own
+	cleaning sync.Mutex
+}
+
+// New creates a server that requires the given bearer token on every request.
+func New(addr, token string) *Server {
+	s := &Server{
+		Addr:  addr,
+		Token: token,
+		mux:   http.NewServeMux(),
+	}
+	s.mux.HandleFunc("GET /scan", s.handleScan)
+	s.mux.HandleFunc("POST /clean", s.handleClean)
+	s.mux.HandleFunc("GET /history", s.handleHistory)
+	s.mux.HandleFunc("GET /rules", s.handleRules)
+	return s
+}
+
+// ListenAndServe starts serving requests until the listener fails.
+func (s *Server) ListenAndServe() error {
+	srv := &http.Server{
+		Addr:              s.Addr,
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return srv.ListenAndServe()
+}
+
+// ServeHTTP checks the bearer token before dispatching to the API handlers.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if s.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.Token)) != 1 {
+		writeError(w, http.StatusUnauthorized, "missing or invalid token")
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// CleanRequest is the body accepted by POST /clean.
+type CleanRequest struct {
+	Category  string   `json:"category"`
+	Rules     []string `json:"rules"`
+	DryRun    *bool    `json:"dry_run"`
+	Permanent bool     `json:"permanent"`
+	// Confirm stands in for the CLI's typed confirmation: cleaning Caution
+	// or Manual rules requires the rule's name, or "delete N items" for
+	// several such rules.
+	Confirm string `json:"confirm"`
+}
+
+// PartialResponse is the body of a POST /clean that failed partway, sent
+// with status 207: Result covers what was cleaned, and Session is where undo
+// finds it.
+type PartialResponse struct {
+	Error   string               `json:"error"`
+	Session string               `json:"session"`
+	Result  *cleaner.CleanResult `json:"result"`
+}
+
+func (s *Server) handleScan(w http.ResponseWriter, r *http.Request) {
+	var olderThan time.Duration
+	if v := r.URL.Query().Get("older_than"); v != "" {
+		d, err := units.ParseDuration(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid older_than: %v", err))
+			return
+		}
+		olderThan = d
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	results, err := scan(cfg, r.URL.Query().Get("category"), olderThan)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+func (s *Server) handleClean(w http.ResponseWriter, r *http.Request) {
+	var req CleanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	// Dry run unless the client explicitly opts out
+	dryRun := req.DryRun == nil || *req.DryRun
+	if !dryRun {
+		s.cleaning.Lock()
+		defer s.cleaning.Unlock()
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	results, err := scan(cfg, req.Category, 0)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	selected := selectResults(results.Results, req.Rules)
+	if token := riskToken(selected); !dryRun && token != "" && req.Confirm != token {
+		writeError(w, http.StatusForbidden, fmt.Sprintf("cleaning Caution or Manual rules requires \"confirm\": %q", token))
+		return
+	}
+
+	// Cleaning nothing would still record an empty session, which undo
+	// would then take for the latest cleanup
+	if !dryRun && len(cleaner.NewPlan(selected).Steps) == 0 {
+		writeJSON(w, http.StatusOK, &cleaner.CleanResult{CategoryStats: map[string]int64{}})
+		return
+	}
+
+	var paths []string
+	for _, res := range selected {
+		paths = append(paths, res.FoundPaths...)
+	}
+	overridden := len(safety.OverriddenPaths(paths)) > 0
+	if !dryRun && overridden && runtime.GOOS != "darwin" {
+		writeError(w, http.StatusForbidden, "paths allowed by force_allow_paths must be cleaned interactively")
+		return
+	}
+
+	if !dryRun && (cfg.EnableAuth || overridden) {
+		ok, err := auth.Current().Authenticate("allow a cleanup requested through the Burrow API")
+		if err != nil || !ok {
+			writeError(w, http.StatusForbidden, "local authentication failed")
+			return
+		}
+	}
+
+	c := cleaner.NewCleaner()
+	c.UseSystemTrash = cfg.UseSystemTrash
+	c.TrashChecksums = cfg.TrashChecksums
+	if !dryRun {
+		// Nobody is there to answer the CLI's prompts, so their safe answers apply
+		_, err := c.PrepareUnattended(selected, req.Permanent, cleaner.Unattended{
+			MinFreeSpace: cfg.MinFreeSpaceBytes(),
+			TrashCap:     int64(cfg.TrashCap),
+			PurgeOverCap: cfg.TrashCapAction == "purge",
+		})
+		if err != nil {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+	}
+	res, err := c.Clean(selected, dryRun, req.Permanent)
+	var partial *cleaner.PartialError
+	if errors.As(err, &partial) {
+		// Part of the selection was cleaned; the client needs the session
+		// to undo it
+		if len(cfg.Notifications) > 0 {
+			notify.Send(cfg.Notifications, notify.NewSummary("api", res.TrashSession, res.ReclaimedSpace, res.FileCount, res.CategoryStats))
+		}
+		writeJSON(w, http.StatusMultiStatus, PartialResponse{Error: err.Error(), Session: partial.Session, Result: res})
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !dryRun && len(cfg.Notifications) > 0 {
+		notify.Send(cfg.Notifications, notify.NewSummary("api", res.TrashSession, res.ReclaimedSpace, res.FileCount, res.CategoryStats))
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// selectResults picks the results of the named rules, or the Safe results
+// when no rule is named; risky rules are only ever cleaned by name.
+func selectResults(results []rules.Result, names []string) []rules.Result {
+	var selected []rules.Result
+	for _, res := range results {
+		if len(names) == 0 && res.Rule.RiskLevel == rules.RiskSafe ||
+			slices.ContainsFunc(names, func(name string) bool { return strings.EqualFold(res.Rule.Name, name) }) {
+			selected = append(selected, res)
+		}
+	}
+	return selected
+}
+
+// riskToken returns what a request must confirm to clean the Caution and
+// Manual rules among selected, matching the CLI's typed confirmation, or ""
+// when all of them are Safe.
+func riskToken(selected []rules.Result) string {
+	var risky []string
+	for _, res := range selected {
+		if res.Rule.RiskLevel != rules.RiskSafe && !res.Rule.ReportOnly {
+			risky = append(risky, res.Rule.Name)
+		}
+	}
+	switch len(risky) {
+	case 0:
+		return ""
+	case 1:
+		return risky[0]
+	default:
+		return fmt.Sprintf("delete %d items", len(risky))
+	}
+}
+
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	entries, err := history.NewManager().Load()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+func (s *Server) handleRules(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, rules.NewRegistry().All())
+}
+
+// loadConfig loads the config for a request. A config file that cannot be
+// read fails the request instead of dropping its excluded paths.
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.Load()
+	if cfg == nil {
+		return nil, fmt.Errorf("cannot load config: %w", err)
+	}
+	return cfg, nil
+}
+
+func scan(cfg *config.Config, category string, olderThan time.Duration) (*scanner.ScanResults, error) {
+	opts, _ := dismiss.ScanOptions(cfg)
+	opts.Category = category
+	opts.OlderThan = olderThan
+	results, err := scanner.NewScanner(rules.NewRegistry(), opts).Scan()
+	if err == nil {
+		usage.NewManager().RecordScan()
+	}
+	return results, err
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
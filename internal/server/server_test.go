@@ -0,0 +1,113 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ismailtsdln/burrow/internal/history"
+	"github.com/ismailtsdln/burrow/internal/paths"
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+func TestServer_TokenAuth(t *testing.T) {
+	s := New("", "secret")
+
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"No token", "", http.StatusUnauthorized},
+		{"Wrong token", "Bearer nope", http.StatusUnauthorized},
+		{"Valid token", "Bearer secret", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/rules", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("GET /rules with %q = %d, want %d", tt.header, rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_EmptyTokenRejectsAll(t *testing.T) {
+	s := New("", "")
+	req := httptest.NewRequest(http.MethodGet, "/rules", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("empty server token should reject requests, got %d", rec.Code)
+	}
+}
+
+func TestSelectResults(t *testing.T) {
+	results := []rules.Result{
+		{Rule: rules.CleanupRule{Name: "npm Cache", RiskLevel: rules.RiskSafe}},
+		{Rule: rules.CleanupRule{Name: "Docker Images", RiskLevel: rules.RiskCaution}},
+		{Rule: rules.CleanupRule{Name: "Old Downloads", RiskLevel: rules.RiskManual}},
+	}
+
+	// Without rule names only Safe results are cleaned
+	safe := selectResults(results, nil)
+	if len(safe) != 1 || safe[0].Rule.Name != "npm Cache" || riskToken(safe) != "" {
+		t.Errorf("unnamed selection = %+v", safe)
+	}
+
+	risky := selectResults(results, []string{"docker images"})
+	if len(risky) != 1 || riskToken(risky) != "Docker Images" {
+		t.Errorf("named selection = %+v, token %q", risky, riskToken(risky))
+	}
+	if got := riskToken(selectResults(results, []string{"npm Cache", "Docker Images", "Old Downloads"})); got != "delete 2 items" {
+		t.Errorf("token for two risky rules = %q", got)
+	}
+}
+
+func TestServer_CleanNothingRecordsNoSession(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(paths.DataEnv, t.TempDir())
+	t.Setenv(paths.ConfigEnv, t.TempDir())
+	s := New("", "secret")
+
+	body := strings.NewReader(`{"rules": ["No Such Rule"], "dry_run": false}`)
+	req := httptest.NewRequest(http.MethodPost, "/clean", body)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST /clean = %d (%s), want 200", rec.Code, rec.Body)
+	}
+	if entries, _ := history.NewManager().Load(); len(entries) != 0 {
+		t.Errorf("cleaning nothing recorded %+v", entries)
+	}
+}
+
+func TestServer_UnreadableConfigFailsScan(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(paths.DataEnv, t.TempDir())
+	dir := t.TempDir()
+	t.Setenv(paths.ConfigEnv, dir)
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"excluded_paths": [`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s := New("", "secret")
+
+	req := httptest.NewRequest(http.MethodGet, "/scan", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusInternalServerError || !strings.Contains(rec.Body.String(), "cannot load config") {
+		t.Errorf("GET /scan with a broken config = %d (%s), want 500", rec.Code, rec.Body)
+	}
+}
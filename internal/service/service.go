@@ -0,0 +1,175 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ismailtsdln/burrow/internal/paths"
+)
+
+// Label identifies the launchd agent that runs 'burrow watch'.
+const Label = "io.github.ismailtsdln.burrow.watch"
+
+// Paths returns the plist location and the stdout/stderr log files of the agent.
+func Paths() (plist, stdoutLog, stderrLog string) {
+	home, _ := os.UserHomeDir()
+	plist = filepath.Join(home, "Library", "LaunchAgents", Label+".plist")
+	logDir := paths.LogsDir()
+	return plist, filepath.Join(logDir, "watch.log"), filepath.Join(logDir, "watch.err.log")
+}
+
+var plistTemplate = template.Must(template.New("plist").Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>{{.Label}}</string>
+	<key>ProgramArguments</key>
+	<array>
+{{- range .Args}}
+		<string>{{.}}</string>
+{{- end}}
+	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<true/>
+	<key>ProcessType</key>
+	<string>Background</string>
+	<key>StandardOutPath</key>
+	<string>{{.Stdout}}</string>
+	<key>StandardErrorPath</key>
+	<string>{{.Stderr}}</string>
+</dict>
+</plist>
+`))
+
+// Plist renders the launchd agent definition running the given burrow arguments.
+func Plist(exe string, watchArgs []string) (string, error) {
+	_, stdoutLog, stderrLog := Paths()
+	var buf bytes.Buffer
+	err := plistTemplate.Execute(&buf, map[string]interface{}{
+		"Label":  Label,
+		"Args":   append([]string{exe, "watch"}, watchArgs...),
+		"Stdout": stdoutLog,
+		"Stderr": stderrLog,
+	})
+	return buf.String(), err
+}
+
+// Install writes the agent plist and creates the log directory.
+func Install(watchArgs []string) (string, error) {
+	if err := requireLaunchd(); err != nil {
+		return "", err
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate burrow executable: %w", err)
+	}
+	content, err := Plist(exe, watchArgs)
+	if err != nil {
+		return "", err
+	}
+
+	plist, stdoutLog, _ := Paths()
+	if err := os.MkdirAll(filepath.Dir(stdoutLog), 0755); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(plist), 0755); err != nil {
+		return "", err
+	}
+	return plist, os.WriteFile(plist, []byte(content), 0644)
+}
+
+// Uninstall stops the agent if running and removes its plist.
+func Uninstall() error {
+	if err := requireLaunchd(); err != nil {
+		return err
+	}
+	Stop()
+	plist, _, _ := Paths()
+	if err := os.Remove(plist); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Start loads the agent into the current user's launchd domain.
+func Start() error {
+	if err := requireLaunchd(); err != nil {
+		return err
+	}
+	plist, _, _ := Paths()
+	if _, err := os.Stat(plist); err != nil {
+		return fmt.Errorf("service is not installed; run 'burrow service install' first")
+	}
+	return launchctl("bootstrap", domain(), plist)
+}
+
+// Stop unloads the agent.
+func Stop() error {
+	if err := requireLaunchd(); err != nil {
+		return err
+	}
+	return launchctl("bootout", domain()+"/"+Label)
+}
+
+// Status describes the state of the agent.
+type Status struct {
+	Installed bool
+	Running   bool
+	PID       int
+}
+
+// Query inspects launchd for the agent's state.
+func Query() (Status, error) {
+	var st Status
+	if err := requireLaunchd(); err != nil {
+		return st, err
+	}
+
+	plist, _, _ := Paths()
+	if _, err := os.Stat(plist); err == nil {
+		st.Installed = true
+	}
+
+	out, err := exec.Command("launchctl", "print", domain()+"/"+Label).Output()
+	if err != nil {
+		return st, nil // Not loaded
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "pid = ") {
+			st.PID, _ = strconv.Atoi(strings.TrimPrefix(line, "pid = "))
+			st.Running = st.PID > 0
+		}
+	}
+	return st, nil
+}
+
+func domain() string {
+	return fmt.Sprintf("gui/%d", os.Getuid())
+}
+
+func launchctl(args ...string) error {
+	out, err := exec.Command("launchctl", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("launchctl %s failed: %s", args[0], strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func requireLaunchd() error {
+	if runtime.GOOS != "darwin" {
+		return fmt.Errorf("service management requires macOS launchd")
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlist(t *testing.T) {
+	content, err := Plist("/usr/local/bin/burrow", []string{"--interval", "6h"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"<string>" + Label + "</string>",
+		"<string>/usr/local/bin/burrow</string>",
+		"<string>watch</string>",
+		"<string>--interval</string>",
+		"<string>6h</string>",
+		"watch.log</string>",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("plist missing %q", want)
+		}
+	}
+}
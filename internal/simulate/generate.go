@@ -0,0 +1,93 @@
+package simulate
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/safety"
+	"github.com/ismailtsdln/burrow/internal/units"
+)
+
+// sampleSize is the size of each generated file, unless a rule's minimum
+// size needs more.
+const sampleSize = 16 * units.KB
+
+// globClass matches a bracket expression in a glob.
+var globClass = regexp.MustCompile(`\[!?(.)[^\]]*\]`)
+
+// Generate fills the fixture with sample data for every rule in list: a
+// directory with a file for each rule path, or a file when the path names
+// one, and files matching the include and exclude patterns of file-level
+// rules. Data is made older than the rules' age limits and larger than
+// their size limits. It returns the paths it created. The fixture must have
+// been entered.
+func Generate(f Fixture, list []rules.CleanupRule) ([]string, error) {
+	var created []string
+	for _, r := range f.Rebase(list) {
+		size := max(sampleSize, r.MinSizeBytes()+sampleSize)
+		modTime := time.Now().Add(-r.MinAgeDuration() - units.Day)
+		for _, p := range r.Paths {
+			p = samplePath(safety.ExpandPath(p))
+			if !f.Contains(p) {
+				return created, fmt.Errorf("rule %s: %s is outside the fixture", r.Name, p)
+			}
+
+			var files []string
+			switch {
+			case r.FileLevel():
+				for _, pattern := range append(append([]string{}, r.IncludePatterns...), r.ExcludePatterns...) {
+					files = append(files, filepath.Join(p, samplePath(pattern)))
+				}
+			case looksLikeFile(p):
+				files = []string{p}
+			default:
+				files = []string{filepath.Join(p, "sample.bin")}
+			}
+			for _, file := range files {
+				if err := writeSample(file, size, modTime); err != nil {
+					return created, fmt.Errorf("rule %s: %w", r.Name, err)
+				}
+				created = append(created, file)
+			}
+			if !looksLikeFile(p) {
+				os.Chtimes(p, modTime, modTime)
+			}
+		}
+	}
+	return created, nil
+}
+
+// samplePath turns a glob into a concrete path that the glob matches.
+func samplePath(glob string) string {
+	glob = globClass.ReplaceAllString(glob, "$1")
+	glob = strings.ReplaceAll(glob, "*", "sample")
+	return strings.ReplaceAll(glob, "?", "x")
+}
+
+// dirExtensions are extensions of directories, such as Android's
+// Pixel_7.avd, that would otherwise look like files.
+var dirExtensions = map[string]bool{".avd": true, ".app": true}
+
+// looksLikeFile guesses from the name whether a rule path is a file, such as
+// Docker.raw, rather than a directory such as .npm.
+func looksLikeFile(path string) bool {
+	base := filepath.Base(path)
+	ext := filepath.Ext(base)
+	return ext != "" && ext != base && !strings.Contains(ext, " ") && len(ext) <= 5 && !dirExtensions[ext]
+}
+
+func writeSample(path string, size int64, modTime time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, bytes.Repeat([]byte("burrow"), int(size/6)+1), 0644); err != nil {
+		return err
+	}
+	return os.Chtimes(path, modTime, modTime)
+}
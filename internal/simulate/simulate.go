@@ -0,0 +1,253 @@
+// Package simulate runs the scan, clean, and undo pipeline against a fake
+// home directory, so rules can be tested without touching the real machine.
+package simulate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ismailtsdln/burrow/internal/cleaner"
+	"github.com/ismailtsdln/burrow/internal/paths"
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/safety"
+	"github.com/ismailtsdln/burrow/internal/scanner"
+)
+
+// Fixture is a directory holding a fake home, a fake file system root for
+// absolute rule paths, and Burrow's data directory for the simulation.
+type Fixture struct {
+	Dir string
+}
+
+// NewFixture returns the fixture in dir, which is created if needed. A
+// fixture inside a Git repository is refused: the Git guard would reject
+// every path in it.
+func NewFixture(dir string) (Fixture, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return Fixture{}, err
+	}
+	if err := os.MkdirAll(abs, 0755); err != nil {
+		return Fixture{}, fmt.Errorf("failed to create fixture: %w", err)
+	}
+	if repo := gitRoot(abs); repo != "" {
+		return Fixture{}, fmt.Errorf("fixture %s is inside the Git repository %s, where every path is refused; use a directory outside it", abs, repo)
+	}
+	return Fixture{Dir: abs}, nil
+}
+
+// Home is the fake home directory that ~ expands to.
+func (f Fixture) Home() string { return filepath.Join(f.Dir, "home") }
+
+// Root stands in for / : absolute rule paths such as /tmp are moved below it.
+func (f Fixture) Root() string { return filepath.Join(f.Dir, "root") }
+
+// DataDir holds the trash and history of the simulation.
+func (f Fixture) DataDir() string { return filepath.Join(f.Dir, "data") }
+
+// fixtureEnv are the variables that locate the home, config, caches, and
+// data; Enter points all of them into the fixture.
+func (f Fixture) fixtureEnv() map[string]string {
+	home := f.Home()
+	return map[string]string{
+		"HOME":            home,
+		"USERPROFILE":     home,
+		"LOCALAPPDATA":    filepath.Join(home, "AppData", "Local"),
+		"APPDATA":         filepath.Join(home, "AppData", "Roaming"),
+		"XDG_CONFIG_HOME": filepath.Join(home, ".config"),
+		"XDG_CACHE_HOME":  filepath.Join(home, ".cache"),
+		"XDG_STATE_HOME":  filepath.Join(home, ".local", "state"),
+		"TMPDIR":          filepath.Join(f.Root(), "tmp"),
+		paths.DataEnv:     f.DataDir(),
+		paths.ConfigEnv:   "",
+	}
+}
+
+// Enter points the process environment at the fixture and returns a
+// function that restores it.
+func (f Fixture) Enter() (restore func()) {
+	saved := make(map[string]*string)
+	for key, value := range f.fixtureEnv() {
+		if old, ok := os.LookupEnv(key); ok {
+			saved[key] = &old
+		} else {
+			saved[key] = nil
+		}
+		if value == "" {
+			os.Unsetenv(key)
+		} else {
+			os.Setenv(key, value)
+		}
+	}
+	return func() {
+		for key, old := range saved {
+			if old == nil {
+				os.Unsetenv(key)
+			} else {
+				os.Setenv(key, *old)
+			}
+		}
+	}
+}
+
+// Rebase moves the absolute paths of rules below the fixture's root; paths
+// starting with ~ or an environment variable already expand into the
+// fixture once it is entered.
+func (f Fixture) Rebase(list []rules.CleanupRule) []rules.CleanupRule {
+	out := make([]rules.CleanupRule, len(list))
+	for i, r := range list {
+		r.Paths = make([]string, len(list[i].Paths))
+		for j, p := range list[i].Paths {
+			if filepath.IsAbs(p) {
+				p = filepath.Join(f.Root(), strings.TrimPrefix(p, filepath.VolumeName(p)))
+			}
+			r.Paths[j] = p
+		}
+		out[i] = r
+	}
+	return out
+}
+
+// Contains reports whether path lies inside the fixture.
+func (f Fixture) Contains(path string) bool {
+	return safety.MatchPath(path, f.Dir)
+}
+
+// RuleReport is what happened to one rule's data in the simulation.
+type RuleReport struct {
+	Rule       string   `json:"rule"`
+	Paths      []string `json:"paths"`
+	Size       int64    `json:"size"`
+	ReportOnly bool     `json:"report_only,omitempty"`
+	Cleaned    bool     `json:"cleaned"`
+	Restored   bool     `json:"restored"`
+	Problems   []string `json:"problems,omitempty"`
+}
+
+// Report is the outcome of a simulation.
+type Report struct {
+	Fixture string                `json:"fixture"`
+	Session string                `json:"session,omitempty"`
+	Rules   []RuleReport          `json:"rules"`
+	Skipped []scanner.SkippedPath `json:"skipped,omitempty"`
+	// Unmatched lists rules that found nothing in the fixture.
+	Unmatched []string `json:"unmatched,omitempty"`
+}
+
+// OK reports whether every rule behaved: its data was trashed and, when
+// undo ran, restored.
+func (r *Report) OK() bool {
+	for _, rr := range r.Rules {
+		if len(rr.Problems) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Run scans the fixture with list, trashes everything found except the
+// results of report-only rules, checks that it is gone, and with undo
+// restores the session and checks that everything is back. The fixture must
+// have been entered.
+func Run(f Fixture, list []rules.CleanupRule, undo bool) (*Report, error) {
+	registry := rules.NewRegistryFromRules(f.Rebase(list))
+	results, err := scanner.NewScanner(registry, scanner.ScanOptions{}).Scan()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Fixture: f.Dir, Skipped: results.Skipped}
+	found := make(map[string]bool)
+	var cleanable []rules.Result
+	for _, res := range results.Results {
+		rr := RuleReport{Rule: res.Rule.Name, Paths: res.FoundPaths, Size: res.TotalSize, ReportOnly: res.Rule.ReportOnly}
+		for _, p := range res.FoundPaths {
+			if !f.Contains(p) {
+				return nil, fmt.Errorf("rule %s found %s outside the fixture; stopping", res.Rule.Name, p)
+			}
+		}
+		if !res.Rule.ReportOnly {
+			cleanable = append(cleanable, res)
+		}
+		report.Rules = append(report.Rules, rr)
+		found[res.Rule.Name] = true
+	}
+	for _, r := range list {
+		if !found[r.Name] {
+			report.Unmatched = append(report.Unmatched, r.Name)
+		}
+	}
+	if len(cleanable) == 0 {
+		return report, nil
+	}
+
+	clean, err := cleaner.NewCleaner().Clean(cleanable, false, false)
+	if err != nil {
+		return report, fmt.Errorf("clean failed: %w", err)
+	}
+	report.Session = clean.TrashSession
+	for i := range report.Rules {
+		rr := &report.Rules[i]
+		if rr.ReportOnly {
+			continue
+		}
+		rr.Cleaned = true
+		for _, p := range rr.Paths {
+			if _, err := os.Lstat(p); err == nil {
+				rr.Cleaned = false
+				rr.Problems = append(rr.Problems, "still present after clean: "+p)
+			}
+		}
+	}
+	if !undo {
+		return report, nil
+	}
+
+	restored, err := cleaner.NewTrashManager().Restore(clean.TrashSession)
+	if err != nil {
+		return report, fmt.Errorf("undo failed: %w", err)
+	}
+	failed := make(map[string]string)
+	for _, e := range restored.Entries {
+		switch e.Status {
+		case cleaner.RestoreOK:
+		case cleaner.RestoreUnverified:
+			failed[e.Path] = "restored altered: " + e.Path + " (" + e.Detail + ")"
+		default:
+			failed[e.Path] = fmt.Sprintf("not restored: %s (%s: %s)", e.Path, e.Status, e.Detail)
+		}
+	}
+	for i := range report.Rules {
+		rr := &report.Rules[i]
+		if rr.ReportOnly {
+			continue
+		}
+		rr.Restored = true
+		for _, p := range rr.Paths {
+			if problem, ok := failed[p]; ok {
+				rr.Restored = false
+				rr.Problems = append(rr.Problems, problem)
+			} else if _, err := os.Lstat(p); err != nil {
+				rr.Restored = false
+				rr.Problems = append(rr.Problems, "missing after undo: "+p)
+			}
+		}
+	}
+	return report, nil
+}
+
+// gitRoot returns the working tree that contains dir, or "".
+func gitRoot(dir string) string {
+	for curr := dir; ; {
+		if _, err := os.Stat(filepath.Join(curr, ".git")); err == nil {
+			return curr
+		}
+		parent := filepath.Dir(curr)
+		if parent == curr {
+			return ""
+		}
+		curr = parent
+	}
+}
@@ -0,0 +1,90 @@
+package simulate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+func TestRun_BuiltinRules(t *testing.T) {
+	f, err := NewFixture(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	restore := f.Enter()
+	defer restore()
+
+	list := rules.NewRegistry().All()
+	if _, err := Generate(f, list); err != nil {
+		t.Fatal(err)
+	}
+	report, err := Run(f, list, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.OK() {
+		t.Errorf("simulation reported problems: %+v", report.Rules)
+	}
+	if report.Session == "" {
+		t.Error("nothing was cleaned")
+	}
+	for _, rr := range report.Rules {
+		if !rr.ReportOnly && (!rr.Cleaned || !rr.Restored) {
+			t.Errorf("%s: cleaned %v, restored %v", rr.Rule, rr.Cleaned, rr.Restored)
+		}
+	}
+	// Rules whose paths need something other than the generated data, such
+	// as rules for another OS, may find nothing; most must match
+	if len(report.Unmatched) > len(list)/4 {
+		t.Errorf("%d of %d rules found nothing: %v", len(report.Unmatched), len(list), report.Unmatched)
+	}
+}
+
+func TestRun_WithoutUndo(t *testing.T) {
+	f, err := NewFixture(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	restore := f.Enter()
+	defer restore()
+
+	list := []rules.CleanupRule{
+		{Name: "Scratch", Category: "Custom", Paths: []string{"~/.scratch"}, RiskLevel: rules.RiskSafe},
+		{Name: "Logs", Category: "Custom", Paths: []string{"/var/log/app"}, RiskLevel: rules.RiskSafe, IncludePatterns: []string{"*.log"}},
+	}
+	created, err := Generate(f, list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(f.Root(), "var", "log", "app", "sample.log"); created[1] != want {
+		t.Errorf("absolute rule path generated at %s, want %s", created[1], want)
+	}
+
+	report, err := Run(f, list, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.OK() || len(report.Rules) != 2 {
+		t.Fatalf("report = %+v", report)
+	}
+	for _, p := range created {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("%s was not trashed", p)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(f.DataDir(), "trash", report.Session)); err != nil {
+		t.Errorf("session is not in the fixture's trash: %v", err)
+	}
+}
+
+func TestNewFixture_RefusesGitRepositories(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFixture(filepath.Join(repo, "fixture")); err == nil {
+		t.Error("fixture inside a Git repository was accepted")
+	}
+}
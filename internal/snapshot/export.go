@@ -0,0 +1,133 @@
+package snapshot
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TotalCategory names the row that carries the total of a snapshot.
+const TotalCategory = "(total)"
+
+// Row is one exported measurement: the reclaimable space of one category,
+// or of everything, at one point in time.
+type Row struct {
+	Timestamp time.Time `json:"timestamp"`
+	Category  string    `json:"category"`
+	Bytes     int64     `json:"reclaimable_bytes"`
+}
+
+var csvHeader = []string{"timestamp", "category", "reclaimable_bytes"}
+
+// Rows flattens a snapshot into one row per category, largest first, and a
+// total row.
+func Rows(s Snapshot) []Row {
+	rows := make([]Row, 0, len(s.Categories)+1)
+	for cat, size := range s.Categories {
+		rows = append(rows, Row{Timestamp: s.Timestamp, Category: cat, Bytes: size})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Bytes != rows[j].Bytes {
+			return rows[i].Bytes > rows[j].Bytes
+		}
+		return rows[i].Category < rows[j].Category
+	})
+	return append(rows, Row{Timestamp: s.Timestamp, Category: TotalCategory, Bytes: s.TotalBytes})
+}
+
+// Export writes the rows of s to path as CSV or a JSON array, chosen by the
+// file extension. With appendRows, the rows are added to those already in
+// the file, so repeated runs build a time series.
+func Export(path string, s Snapshot, appendRows bool) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return exportCSV(path, Rows(s), appendRows)
+	case ".json":
+		return exportJSON(path, Rows(s), appendRows)
+	default:
+		return fmt.Errorf("cannot export to %s: use a .csv or .json file", path)
+	}
+}
+
+func exportCSV(path string, rows []Row, appendRows bool) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if appendRows {
+		if err := checkCSVHeader(path); err != nil {
+			return err
+		}
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	f, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	if info, err := f.Stat(); err == nil && info.Size() == 0 {
+		w.Write(csvHeader)
+	}
+	for _, r := range rows {
+		w.Write([]string{r.Timestamp.Format(time.RFC3339), r.Category, strconv.FormatInt(r.Bytes, 10)})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// checkCSVHeader refuses to append to a CSV file with other columns. Missing
+// and empty files are fine.
+func checkCSVHeader(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+	header, err := csv.NewReader(f).Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cannot append to %s: %w", path, err)
+	}
+	if strings.Join(header, ",") != strings.Join(csvHeader, ",") {
+		return fmt.Errorf("cannot append to %s: it is not a burrow stats export", path)
+	}
+	return nil
+}
+
+func exportJSON(path string, rows []Row, appendRows bool) error {
+	if appendRows {
+		data, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if len(strings.TrimSpace(string(data))) > 0 {
+			var existing []Row
+			if err := json.Unmarshal(data, &existing); err != nil {
+				return fmt.Errorf("cannot append to %s: %w", path, err)
+			}
+			rows = append(existing, rows...)
+		}
+	}
+	data, err := json.MarshalIndent(rows, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
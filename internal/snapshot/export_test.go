@@ -0,0 +1,81 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExport_CSVAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.csv")
+	first := Snapshot{Timestamp: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), TotalBytes: 30, Categories: map[string]int64{"Dev": 20, "Logs": 10}}
+	second := Snapshot{Timestamp: first.Timestamp.Add(time.Hour), TotalBytes: 5, Categories: map[string]int64{"Dev": 5}}
+
+	if err := Export(path, first, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := Export(path, second, true); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `timestamp,category,reclaimable_bytes
+2024-01-01T12:00:00Z,Dev,20
+2024-01-01T12:00:00Z,Logs,10
+2024-01-01T12:00:00Z,(total),30
+2024-01-01T13:00:00Z,Dev,5
+2024-01-01T13:00:00Z,(total),5
+`
+	if string(data) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", data, want)
+	}
+
+	// Without --append the file starts over
+	if err := Export(path, second, false); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	if n := strings.Count(string(data), "\n"); n != 3 {
+		t.Errorf("overwritten export has %d lines, want 3:\n%s", n, data)
+	}
+}
+
+func TestExport_JSONAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	s := Snapshot{Timestamp: time.Now(), TotalBytes: 7, Categories: map[string]int64{"Dev": 7}}
+	for i := 0; i < 2; i++ {
+		if err := Export(path, s, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rows []Row
+	if err := json.Unmarshal(data, &rows); err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 {
+		t.Errorf("got %d rows, want 4", len(rows))
+	}
+}
+
+func TestExport_RefusesForeignFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "other.csv")
+	if err := os.WriteFile(path, []byte("a,b\n1,2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Export(path, Snapshot{}, true); err == nil {
+		t.Error("appending to a CSV file with other columns should fail")
+	}
+	if err := Export(filepath.Join(dir, "stats.txt"), Snapshot{}, false); err == nil {
+		t.Error("unknown extension should fail")
+	}
+}
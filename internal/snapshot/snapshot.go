@@ -0,0 +1,183 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/paths"
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+// maxSnapshots bounds the snapshot file; at one scan per hour this is about a month.
+const maxSnapshots = 720
+
+// Snapshot is a compact summary of a single scan.
+type Snapshot struct {
+	Timestamp  time.Time        `json:"timestamp"`
+	TotalBytes int64            `json:"total_bytes"`
+	Categories map[string]int64 `json:"categories"`
+}
+
+// FromResults summarizes scan results into a snapshot.
+func FromResults(results []rules.Result) Snapshot {
+	s := Snapshot{
+		Timestamp:  time.Now(),
+		Categories: make(map[string]int64),
+	}
+	for _, res := range results {
+		s.Categories[res.Rule.Category] += res.TotalSize
+		s.TotalBytes += res.TotalSize
+	}
+	return s
+}
+
+// Manager persists scan snapshots, separately from cleanup history.
+type Manager struct {
+	path string
+}
+
+// NewManager creates a new snapshot manager.
+func NewManager() *Manager {
+	return &Manager{
+		path: paths.SnapshotsFile(),
+	}
+}
+
+// Save appends a snapshot, dropping the oldest ones beyond the limit.
+func (m *Manager) Save(s Snapshot) error {
+	snapshots, _ := m.Load()
+	snapshots = append(snapshots, s)
+
+	if len(snapshots) > maxSnapshots {
+		snapshots = snapshots[len(snapshots)-maxSnapshots:]
+	}
+
+	data, err := json.Marshal(snapshots)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(m.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(m.path, data, 0644)
+}
+
+// Load returns all snapshots sorted oldest first.
+func (m *Manager) Load() ([]Snapshot, error) {
+	data, err := os.ReadFile(m.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Snapshot{}, nil
+		}
+		return nil, err
+	}
+
+	var snapshots []Snapshot
+	if err := json.Unmarshal(data, &snapshots); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Timestamp.Before(snapshots[j].Timestamp)
+	})
+	return snapshots, nil
+}
+
+// Growth is the change of a category between the first and last snapshot.
+type Growth struct {
+	Category string `json:"category"`
+	First    int64  `json:"first"`
+	Last     int64  `json:"last"`
+	Delta    int64  `json:"delta"`
+}
+
+// CategoryGrowth compares the oldest and newest snapshot per category and
+// returns categories sorted by growth, largest first.
+func CategoryGrowth(snapshots []Snapshot) []Growth {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	first, last := snapshots[0], snapshots[len(snapshots)-1]
+
+	seen := make(map[string]bool)
+	var growth []Growth
+	for _, s := range []Snapshot{first, last} {
+		for cat := range s.Categories {
+			if seen[cat] {
+				continue
+			}
+			seen[cat] = true
+			growth = append(growth, Growth{
+				Category: cat,
+				First:    first.Categories[cat],
+				Last:     last.Categories[cat],
+				Delta:    last.Categories[cat] - first.Categories[cat],
+			})
+		}
+	}
+
+	sort.Slice(growth, func(i, j int) bool {
+		return growth[i].Delta > growth[j].Delta
+	})
+	return growth
+}
+
+// MinProjectionSpan is how much snapshot history a projection needs; shorter
+// spans mostly measure a single build or download.
+const MinProjectionSpan = 24 * time.Hour
+
+// Projection estimates how quickly a category fills up again.
+type Projection struct {
+	Category string `json:"category"`
+	Current  int64  `json:"current"`
+	PerWeek  int64  `json:"per_week"`
+}
+
+// Refill returns how long the category takes to regrow to its current size
+// after a cleanup, or zero if it doesn't regrow.
+func (p Projection) Refill() time.Duration {
+	if p.PerWeek <= 0 {
+		return 0
+	}
+	return time.Duration(float64(p.Current) / float64(p.PerWeek) * float64(7*24*time.Hour))
+}
+
+// Project estimates the regrowth rate of every category from the increases
+// between consecutive snapshots; drops are cleanups and are left out. It
+// returns nil when the snapshots span less than a day. Results are sorted by
+// rate, fastest first.
+func Project(snapshots []Snapshot) []Projection {
+	if len(snapshots) < 2 {
+		return nil
+	}
+	span := snapshots[len(snapshots)-1].Timestamp.Sub(snapshots[0].Timestamp)
+	if span < MinProjectionSpan {
+		return nil
+	}
+
+	grown := make(map[string]int64)
+	for i := 1; i < len(snapshots); i++ {
+		for cat, size := range snapshots[i].Categories {
+			if delta := size - snapshots[i-1].Categories[cat]; delta > 0 {
+				grown[cat] += delta
+			}
+		}
+	}
+
+	last := snapshots[len(snapshots)-1]
+	var projections []Projection
+	for cat, growth := range grown {
+		projections = append(projections, Projection{
+			Category: cat,
+			Current:  last.Categories[cat],
+			PerWeek:  int64(float64(growth) / span.Hours() * 24 * 7),
+		})
+	}
+	sort.Slice(projections, func(i, j int) bool {
+		return projections[i].PerWeek > projections[j].PerWeek
+	})
+	return projections
+}
@@ -0,0 +1,58 @@
+package snapshot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCategoryGrowth(t *testing.T) {
+	now := time.Now()
+	snapshots := []Snapshot{
+		{Timestamp: now.Add(-48 * time.Hour), Categories: map[string]int64{"Developer Tools": 100, "System": 50}},
+		{Timestamp: now.Add(-24 * time.Hour), Categories: map[string]int64{"Developer Tools": 300}},
+		{Timestamp: now, Categories: map[string]int64{"Developer Tools": 900, "Package Managers": 200}},
+	}
+
+	growth := CategoryGrowth(snapshots)
+	if len(growth) != 3 {
+		t.Fatalf("expected 3 categories, got %d", len(growth))
+	}
+	if growth[0].Category != "Developer Tools" || growth[0].Delta != 800 {
+		t.Errorf("worst offender = %+v, want Developer Tools +800", growth[0])
+	}
+	if last := growth[len(growth)-1]; last.Category != "System" || last.Delta != -50 {
+		t.Errorf("last = %+v, want System -50", last)
+	}
+}
+
+func TestProject(t *testing.T) {
+	start := time.Now().Add(-14 * 24 * time.Hour)
+	day := func(n int) time.Time { return start.Add(time.Duration(n) * 24 * time.Hour) }
+	snapshots := []Snapshot{
+		{Timestamp: day(0), Categories: map[string]int64{"Package Managers": 1000, "System": 500}},
+		{Timestamp: day(7), Categories: map[string]int64{"Package Managers": 3000, "System": 500}},
+		// Cleaned, then regrew
+		{Timestamp: day(8), Categories: map[string]int64{"Package Managers": 0, "System": 500}},
+		{Timestamp: day(14), Categories: map[string]int64{"Package Managers": 2000, "System": 400}},
+	}
+
+	projections := Project(snapshots)
+	if len(projections) != 1 {
+		t.Fatalf("projections = %+v, want only Package Managers", projections)
+	}
+	p := projections[0]
+	if p.Category != "Package Managers" || p.PerWeek != 2000 || p.Current != 2000 {
+		t.Errorf("projection = %+v, want 2000 B/week from 2000 B", p)
+	}
+	if got := p.Refill(); got != 7*24*time.Hour {
+		t.Errorf("Refill() = %v, want a week", got)
+	}
+
+	if got := Project(snapshots[:1]); got != nil {
+		t.Errorf("Project(one snapshot) = %+v, want nil", got)
+	}
+	short := []Snapshot{{Timestamp: day(0)}, {Timestamp: day(0).Add(time.Hour), Categories: map[string]int64{"System": 10}}}
+	if got := Project(short); got != nil {
+		t.Errorf("Project(one hour) = %+v, want nil", got)
+	}
+}
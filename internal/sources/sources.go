@@ -0,0 +1,184 @@
+// Package sources manages team-shared rule bundles cloned from Git
+// repositories into the rule-sources directory under the data directory.
+package sources
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/paths"
+)
+
+// Source is a Git repository of rule files.
+type Source struct {
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	Commit    string    `json:"commit"`
+	AddedAt   time.Time `json:"added_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Manager clones, updates, and removes rule sources.
+type Manager struct {
+	dir          string // Clones live in dir/<name>
+	manifestPath string
+}
+
+// NewManager creates a manager for the default sources directory.
+func NewManager() *Manager {
+	return &Manager{
+		dir:          Dir(),
+		manifestPath: paths.RuleSourcesFile(),
+	}
+}
+
+// Dir returns the directory holding one clone per rule source.
+func Dir() string {
+	return paths.RuleSourcesDir()
+}
+
+// NameFromURL derives a source name from the last path element of a Git URL,
+// e.g. git@github.com:acme/burrow-rules.git becomes "burrow-rules".
+func NameFromURL(url string) string {
+	url = strings.TrimSuffix(strings.TrimRight(url, "/"), ".git")
+	if i := strings.LastIndexAny(url, "/:"); i >= 0 {
+		url = url[i+1:]
+	}
+	return url
+}
+
+// List returns all registered sources.
+func (m *Manager) List() ([]Source, error) {
+	data, err := os.ReadFile(m.manifestPath)
+	if os.IsNotExist(err) {
+		return []Source{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Source
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", m.manifestPath, err)
+	}
+	return list, nil
+}
+
+// Add clones url and registers it as a new source.
+func (m *Manager) Add(url string) (Source, error) {
+	// A URL starting with "-" would be read as a git option
+	if strings.HasPrefix(url, "-") {
+		return Source{}, fmt.Errorf("invalid source URL %q", url)
+	}
+	name := NameFromURL(url)
+	if name == "" {
+		return Source{}, fmt.Errorf("cannot derive a source name from %q", url)
+	}
+
+	list, err := m.List()
+	if err != nil {
+		return Source{}, err
+	}
+	for _, s := range list {
+		if s.Name == name {
+			return Source{}, fmt.Errorf("source %q already exists (from %s)", name, s.URL)
+		}
+	}
+
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return Source{}, err
+	}
+	dest := filepath.Join(m.dir, name)
+	if _, err := git("", "clone", "--depth", "1", "--", url, dest); err != nil {
+		return Source{}, err
+	}
+
+	src := Source{Name: name, URL: url, AddedAt: time.Now(), UpdatedAt: time.Now()}
+	src.Commit, _ = git(dest, "rev-parse", "--short", "HEAD")
+
+	if err := m.save(append(list, src)); err != nil {
+		os.RemoveAll(dest)
+		return Source{}, err
+	}
+	return src, nil
+}
+
+// Update pulls the named source, or every source when name is empty.
+func (m *Manager) Update(name string) ([]Source, error) {
+	list, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var updated []Source
+	for i, s := range list {
+		if name != "" && s.Name != name {
+			continue
+		}
+		dest := filepath.Join(m.dir, s.Name)
+		if _, err := git(dest, "pull", "--ff-only"); err != nil {
+			return updated, fmt.Errorf("failed to update %s: %w", s.Name, err)
+		}
+		list[i].Commit, _ = git(dest, "rev-parse", "--short", "HEAD")
+		list[i].UpdatedAt = time.Now()
+		updated = append(updated, list[i])
+	}
+
+	if name != "" && len(updated) == 0 {
+		return nil, fmt.Errorf("source not found: %s", name)
+	}
+	return updated, m.save(list)
+}
+
+// Remove deletes the named source and its clone.
+func (m *Manager) Remove(name string) error {
+	list, err := m.List()
+	if err != nil {
+		return err
+	}
+
+	kept := list[:0]
+	found := false
+	for _, s := range list {
+		if s.Name == name {
+			found = true
+			continue
+		}
+		kept = append(kept, s)
+	}
+	if !found {
+		return fmt.Errorf("source not found: %s", name)
+	}
+
+	if err := os.RemoveAll(filepath.Join(m.dir, name)); err != nil {
+		return err
+	}
+	return m.save(kept)
+}
+
+func (m *Manager) save(list []Source) error {
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(m.manifestPath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(m.manifestPath, data, 0644)
+}
+
+// git runs a git command in dir and returns its trimmed output.
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
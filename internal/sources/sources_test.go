@@ -0,0 +1,83 @@
+package sources
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestNameFromURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://github.com/acme/burrow-rules.git", "burrow-rules"},
+		{"https://github.com/acme/burrow-rules/", "burrow-rules"},
+		{"git@github.com:acme/burrow-rules.git", "burrow-rules"},
+		{"/srv/git/team-rules", "team-rules"},
+	}
+
+	for _, tt := range tests {
+		if got := NameFromURL(tt.url); got != tt.want {
+			t.Errorf("NameFromURL(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestManagerLifecycle(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	base := t.TempDir()
+	origin := filepath.Join(base, "team-rules")
+	if err := os.MkdirAll(origin, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(origin, "node.json"), []byte(`[]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "init"},
+	} {
+		if _, err := git(origin, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := &Manager{dir: filepath.Join(base, "sources"), manifestPath: filepath.Join(base, "sources.json")}
+
+	src, err := m.Add(origin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if src.Name != "team-rules" || src.Commit == "" {
+		t.Errorf("unexpected source: %+v", src)
+	}
+	if _, err := os.Stat(filepath.Join(m.dir, "team-rules", "node.json")); err != nil {
+		t.Errorf("clone missing rule file: %v", err)
+	}
+	if _, err := m.Add(origin); err == nil {
+		t.Error("expected adding a duplicate source to fail")
+	}
+	if _, err := m.Add("--upload-pack=touch /tmp/pwned"); err == nil {
+		t.Error("expected a URL starting with - to be rejected")
+	}
+
+	if updated, err := m.Update(""); err != nil || len(updated) != 1 {
+		t.Errorf("Update() = %v, %v; want one updated source", updated, err)
+	}
+
+	if err := m.Remove("team-rules"); err != nil {
+		t.Fatal(err)
+	}
+	if list, _ := m.List(); len(list) != 0 {
+		t.Errorf("expected no sources after remove, got %v", list)
+	}
+	if _, err := os.Stat(filepath.Join(m.dir, "team-rules")); !os.IsNotExist(err) {
+		t.Error("clone directory was not removed")
+	}
+}
@@ -3,21 +3,33 @@ package ui
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
-
 	"time"
+	"unicode"
 
 	"github.com/ismailtsdln/burrow/internal/auth"
 	"github.com/ismailtsdln/burrow/internal/cleaner"
 	"github.com/ismailtsdln/burrow/internal/config"
-	"github.com/ismailtsdln/burrow/internal/history"
+	"github.com/ismailtsdln/burrow/internal/dismiss"
+	"github.com/ismailtsdln/burrow/internal/notify"
+	"github.com/ismailtsdln/burrow/internal/paths"
+	"github.com/ismailtsdln/burrow/internal/priority"
+	"github.com/ismailtsdln/burrow/internal/privilege"
 	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/safety"
 	"github.com/ismailtsdln/burrow/internal/scanner"
+	"github.com/ismailtsdln/burrow/internal/service"
+	"github.com/ismailtsdln/burrow/internal/snapshot"
+	"github.com/ismailtsdln/burrow/internal/units"
 )
 
 // Execute is the main entry point for the CLI.
@@ -27,179 +39,399 @@ func Execute() error {
 		return nil
 	}
 
-	command := os.Args[1]
-	args := os.Args[2:]
-
-	switch command {
-	case "scan":
-		return runScan(args)
-	case "list":
-		return runList(args)
-	case "stats":
-		return runStats(args)
-	case "history":
-		return runHistory()
-	case "clean":
-		return runClean(args)
-	case "undo":
-		return runUndo()
-	case "rules":
-		return runRules(args)
-	case "doctor":
-		return runDoctor()
-	case "version":
-		return runVersion()
-	case "help", "-h", "--help":
+	// Global flags may appear anywhere before a "--"
+	var argv []string
+	raw := os.Args[1:]
+	for i := 0; i < len(raw); i++ {
+		a := raw[i]
+		switch {
+		case a == "--":
+			argv = append(argv, raw[i:]...)
+			i = len(raw)
+		case a == "--plain" || a == "-plain" || a == "--plain=true":
+			SetPlain()
+		case a == "--no-color" || a == "-no-color" || a == "--no-color=true":
+			SetNoColor()
+		case a == "--json" || a == "-json" || a == "--json=true":
+			globalJSON = true
+		case a == "--config" || a == "-config":
+			if i+1 >= len(raw) {
+				return fmt.Errorf("--config requires a file or directory")
+			}
+			i++
+			os.Setenv(paths.ConfigEnv, raw[i])
+		case strings.HasPrefix(a, "--config="):
+			os.Setenv(paths.ConfigEnv, strings.TrimPrefix(a, "--config="))
+		default:
+			argv = append(argv, a)
+		}
+	}
+	if os.Getenv("BURROW_PLAIN") != "" {
+		SetPlain()
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		SetNoColor()
+	}
+	if len(argv) == 0 {
 		printUsage()
 		return nil
-	default:
-		return fmt.Errorf("unknown command: %s. Run 'burrow help' for usage", command)
+	}
+
+	name, args := argv[0], argv[1:]
+	if name == "-h" || name == "--help" || name == "-help" {
+		name = "help"
+	}
+	if name == "help" {
+		err := runHelp(args)
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	c := findCommand(name)
+	if c == nil {
+		return unknownCommand(name)
+	}
+	if !c.flags {
+		if wantsHelp(args) {
+			printCommandHelp(c)
+			return nil
+		}
+		if globalJSON {
+			return fmt.Errorf("burrow %s has no JSON output", c.name)
+		}
+	}
+
+	// A config that cannot be read would silently drop excluded paths and
+	// other safeguards, so only diagnostic commands run without it
+	cfg, err := config.Load()
+	switch {
+	case cfg == nil && !c.diagnoses:
+		return fmt.Errorf("cannot load %s: %w (check it with 'burrow config validate')", paths.ConfigFile(), err)
+	case cfg == nil:
+		fmt.Fprintf(os.Stderr, "Warning: cannot load %s: %v\n", paths.ConfigFile(), err)
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	if cfg != nil {
+		applySafetyPolicy(cfg)
+		paths.SetDataDir(cfg.DataDir)
+	}
+	// Under sudo, leave what this run writes to the invoking user
+	defer func() {
+		if err := privilege.HandBack(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}()
+	if cfg != nil && cfg.Locale != "" {
+		rules.SetLocale(cfg.Locale)
+	} else {
+		rules.SetLocale(rules.SystemLocale())
+	}
+	// Notices go to stderr so they never corrupt --json output
+	if moved, err := paths.Migrate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	} else if moved != "" {
+		fmt.Fprintf(os.Stderr, "Moved Burrow data from %s to %s.\n", paths.LegacyDataDir(), moved)
+		if st, err := service.Query(); err == nil && st.Installed {
+			fmt.Fprintln(os.Stderr, "Run 'burrow service install' again so the watch agent logs to the new location.")
+		}
+	}
+
+	recoverTrash()
+	finalizeExpired(cfg)
+
+	err = c.run(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	return err
+}
+
+// loadConfig loads the config for a command. Execute has already refused an
+// unreadable config file and warned about invalid environment overrides, so
+// an error here means the file broke since.
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.Load()
+	if cfg == nil {
+		return nil, fmt.Errorf("cannot load %s: %w", paths.ConfigFile(), err)
+	}
+	return cfg, nil
+}
+
+// recoverTrash records what an interrupted cleanup moved, so undo can
+// restore it.
+func recoverTrash() {
+	recovered, err := cleaner.NewTrashManager().Recover()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	for _, r := range recovered {
+		if r.Removed {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "Recovered interrupted cleanup %s: %d item(s) recorded; 'burrow undo' can restore them.\n", r.Session, r.Recorded)
+		for _, p := range r.Leftovers {
+			fmt.Fprintf(os.Stderr, "  %s is still in place; a complete copy is in the trash.\n", p)
+		}
 	}
 }
 
 func printUsage() {
-	fmt.Println(Bold + Cyan + "Burrow — Advanced macOS Cleanup for Developers" + Reset)
+	fmt.Println(Bold + Cyan + "Burrow " + Symbol("—", "-") + " Advanced macOS Cleanup for Developers" + Reset)
 	fmt.Println("\n" + Bold + "Usage:" + Reset)
 	fmt.Println("  burrow <command> [flags]")
+	fmt.Println("  burrow help <command>")
 	fmt.Println("\n" + Bold + "Commands:" + Reset)
-	fmt.Printf("  %-10s %s\n", Colorize(Green, "scan"), "Identify cleanup candidates")
-	fmt.Printf("  %-10s %s\n", Colorize(Green, "clean"), "Remove identified files (dry-run by default)")
-	fmt.Printf("  %-10s %s\n", Colorize(Green, "undo"), "Restore last cleanup from trash")
-	fmt.Printf("  %-10s %s\n", Colorize(Green, "list"), "List all detected files")
-	fmt.Printf("  %-10s %s\n", Colorize(Green, "rules"), "List all cleanup rules")
-	fmt.Printf("  %-10s %s\n", Colorize(Green, "stats"), "Show disk reclaimable stats")
-	fmt.Printf("  %-10s %s\n", Colorize(Green, "history"), "Show cleanup history")
-	fmt.Printf("  %-10s %s\n", Colorize(Green, "doctor"), "Check system health and permissions")
-	fmt.Printf("  %-10s %s\n", Colorize(Green, "version"), "Show version information")
-	fmt.Println("\n" + Bold + "Flags:" + Reset)
+	for _, c := range commands {
+		fmt.Printf("  %-10s %s\n", Colorize(Green, c.name), c.summary)
+	}
+	fmt.Println("\n" + Bold + "Global flags:" + Reset)
 	fmt.Println("  -h, --help   Show help for a command")
+	fmt.Println("  --json       JSON output, for commands that support it")
+	fmt.Println("  --no-color   Output without colors (or set NO_COLOR=1)")
+	fmt.Println("  --plain      ASCII output without colors or emoji (or set BURROW_PLAIN=1)")
+	fmt.Println("  --config     Alternate config file or directory (or set BURROW_CONFIG)")
+	fmt.Println("\n" + Bold + "Exit status:" + Reset)
+	fmt.Println("  0 success or nothing found, 1 error, 2 candidates found (scan, clean preview),")
+	fmt.Println("  3 partial failure, 4 cancelled")
 }
 
 func runScan(args []string) error {
 	fs := flag.NewFlagSet("scan", flag.ContinueOnError)
-	category := fs.String("category", "", "Filter by category")
-	olderThan := fs.String("older-than", "", "Filter items older than duration (e.g. 30d, 24h)")
+	sf := addScanFlags(fs)
 	largeFiles := fs.Bool("large", false, "Scan for large files (>100MB) in common directories")
+	var roots stringList
+	fs.Var(&roots, "root", "Directory to search with --large (repeatable)")
+	apps := fs.Bool("apps", false, "Report the cache size of every application, with or without a rule")
+	leftovers := fs.Bool("leftovers", false, "Report data left behind by applications that are no longer installed")
+	exts := fs.String("ext", "", "Comma-separated extensions to include with --large (e.g. dmg,zip,iso)")
+	maxDepth := fs.Int("max-depth", 0, "Recursion limit for --root directories (0 = unlimited)")
+	backend := fs.String("backend", "", "How --large finds files: walk, or spotlight to query the macOS index (default walk)")
 	interactive := fs.Bool("interactive", false, "Interactive mode (select items to clean)")
-	js := fs.Bool("json", false, "Output in JSON format")
 	explain := fs.Bool("explain", false, "Explain why paths were selected")
+	showSkipped := fs.Bool("show-skipped", false, "List rule paths that were skipped and why")
+	timings := fs.Bool("timings", false, "Show the total scan time and the slowest rules and paths")
 	useAuth := fs.Bool("auth", false, "Enable biometric authentication for interactive cleanup")
-	fs.Parse(args)
-
-	var ageDuration time.Duration
-	if *olderThan != "" {
-		// Basic "d" parsing fix since time.ParseDuration doesn't support "d" (days)
-		if strings.HasSuffix(*olderThan, "d") {
-			daysStr := strings.TrimSuffix(*olderThan, "d")
-			var days int
-			fmt.Sscanf(daysStr, "%d", &days)
-			ageDuration = time.Duration(days) * 24 * time.Hour
-		} else {
-			var err error
-			ageDuration, err = time.ParseDuration(*olderThan)
-			if err != nil {
-				return fmt.Errorf("invalid duration format: %s (example: 30d, 24h)", *olderThan)
-			}
-		}
+	noAuth := fs.Bool("no-auth", false, "Skip authentication (only allowed when every selected rule is Safe)")
+	system := fs.Bool("system", false, "Include root-owned system caches")
+	if err := parseFlags(fs, args); err != nil {
+		return err
 	}
+	js := &sf.json
 
-	cfg, _ := config.Load()
-	registry := rules.NewRegistry()
-	s := scanner.NewScanner(registry, scanner.ScanOptions{
-		Category:      *category,
-		ExcludedPaths: cfg.ExcludedPaths,
-		SizeThreshold: cfg.SizeThresholdMB * 1024 * 1024,
-		OlderThan:     ageDuration,
-		LargeFileMode: *largeFiles,
-	})
-
-	if !*js {
-		PrintInfo("Scanning for cleanup candidates...")
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
 	}
-
-	results, err := s.Scan()
+	if *backend == "" {
+		*backend = cfg.LargeFiles.Backend
+	}
+	if *backend != "" && *backend != scanner.BackendWalk && *backend != scanner.BackendSpotlight {
+		return fmt.Errorf("unknown backend %q (use walk or spotlight)", *backend)
+	}
+	results, err := runScanPipeline(cfg, sf, func(opts *scanner.ScanOptions) {
+		opts.LargeFileMode = *largeFiles
+		opts.AppCacheMode = *apps
+		opts.LeftoverMode = *leftovers
+		opts.IncludeSystem = *system
+		if *largeFiles {
+			applyLargeFileOptions(opts, cfg.LargeFiles, roots, *exts, *maxDepth)
+			opts.LargeFileBackend = *backend
+		}
+	})
 	if err != nil {
 		return err
 	}
+	// Remembered so that 'clean --ids' can pick from the listed IDs
+	scanner.SaveListing(results, rules.NewRegistry())
 
 	if *js {
-		data, _ := json.MarshalIndent(results, "", "  ")
+		var out any = results
+		if *explain {
+			out = explainedResults(results)
+		}
+		data, _ := json.MarshalIndent(out, "", "  ")
 		fmt.Println(string(data))
-		return nil
+		return candidates(len(results.Results))
 	}
 
 	if len(results.Results) == 0 {
-		PrintSuccess("No cleanup candidates found. Your system is clean!")
+		printNoCandidates(results)
+		if *showSkipped {
+			printSkipped(results)
+		}
+		if *timings {
+			printTimings(results)
+		}
 		return nil
 	}
 
 	PrintHeader(fmt.Sprintf("%-5s %-30s %-15s %s", "ID", "CATEGORY", "SIZE", "RULE"))
 	fmt.Println(Gray + strings.Repeat("-", 75) + Reset)
-	for i, res := range results.Results {
-		fmt.Printf("%-5d %-30s %-15s %s\n", i+1, Colorize(Blue, res.Rule.Category), Colorize(Yellow, FormatSize(res.TotalSize)), res.Rule.Name)
+	// Listed by value, each under the ID 'clean --ids' knows it by
+	for _, i := range rules.ByValue(results.Results) {
+		res := results.Results[i]
+		fmt.Printf("%-5d %-30s %-15s %s%s%s\n", i+1, Colorize(Blue, categoryLabel(res.Rule.Category)), Colorize(Yellow, FormatSize(res.TotalSize)), res.Rule.Name, volumeLabel(res), rebuildLabel(res))
+		if len(res.Stats) > 0 {
+			sum := res.Summary()
+			fmt.Printf("      %s\n", Colorize(Gray, formatStats(sum)+"; "+formatSizes(sum)))
+		}
+		printItems(res)
 		if *explain {
-			fmt.Printf("      %s %s\n", Colorize(Cyan, "💡"), Colorize(Gray, res.Rule.Explanation))
+			fmt.Printf("      %s %s\n", Colorize(Cyan, Symbol("💡", "why:")), Colorize(Gray, res.Rule.Explanation))
+			if res.Rule.RebuildCost.Level != "" {
+				fmt.Printf("      %s %s\n", Colorize(Cyan, "rebuild cost:"), Colorize(Gray, formatRebuildCost(res.Rule.RebuildCost)))
+			}
 		}
 	}
 
 	fmt.Println(Gray + strings.Repeat("-", 75) + Reset)
 	fmt.Printf(Bold+"Total reclaimable space: %s"+Reset+"\n", Colorize(Green, FormatSize(results.TotalSize)))
+	printDiskSummary(results.Disk)
+	warnScanErrors(results)
+	noteHidden(results)
+	if *showSkipped {
+		printSkipped(results)
+	}
+	if *timings {
+		printTimings(results)
+	}
 
 	if *interactive {
-		return runInteractiveScan(results, *useAuth)
+		return runInteractiveScan(results, *useAuth, *noAuth)
 	}
 
-	fmt.Println("\nRun 'burrow clean' (or use -i) to reclaim space.")
-	return nil
+	fmt.Println("\nRun 'burrow clean --apply' (or use -i) to reclaim space, or 'burrow clean --ids 1,3 --apply' to clean by ID.")
+	return ErrCandidatesFound
 }
 
-func runInteractiveScan(results *scanner.ScanResults, useAuth bool) error {
-	fmt.Println("\n" + Bold + "Interactive Cleanup Selection" + Reset)
-	fmt.Println("Enter IDs to clean (e.g. '1, 3, 5-7') or 'all'. Press Enter to skip.")
-	fmt.Print(Colorize(Green, "Selection > "))
-
-	reader := bufio.NewReader(os.Stdin)
-	input, _ := reader.ReadString('\n')
-	input = strings.TrimSpace(input)
-
-	if input == "" {
-		fmt.Println("No items selected. Exiting.")
-		return nil
+// explainedResults adds the structured explanation of every listed rule to
+// the JSON output of scan --explain.
+func explainedResults(results *scanner.ScanResults) any {
+	explanations := make([]rules.Explanation, 0, len(results.Results))
+	for _, res := range results.Results {
+		explanations = append(explanations, res.Rule.Explain())
 	}
+	return struct {
+		*scanner.ScanResults
+		Explanations []rules.Explanation `json:"explanations"`
+	}{results, explanations}
+}
 
-	selectedIndices := make(map[int]bool)
-
+// parseSelection turns "1, 3, 5-7" or "all" into zero-based indices below n.
+func parseSelection(input string, n int) map[int]bool {
+	selected := make(map[int]bool)
 	if strings.EqualFold(input, "all") {
-		for i := range results.Results {
-			selectedIndices[i] = true
+		for i := 0; i < n; i++ {
+			selected[i] = true
 		}
-	} else {
-		parts := strings.Split(input, ",")
-		for _, part := range parts {
-			part = strings.TrimSpace(part)
-			if strings.Contains(part, "-") {
-				// Handle ranges (e.g., 5-7)
-				rangeParts := strings.Split(part, "-")
-				if len(rangeParts) == 2 {
-					start, err1 := strconv.Atoi(rangeParts[0])
-					end, err2 := strconv.Atoi(rangeParts[1])
-					if err1 == nil && err2 == nil {
-						for i := start; i <= end; i++ {
-							if i > 0 && i <= len(results.Results) {
-								selectedIndices[i-1] = true
-							}
+		return selected
+	}
+
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		if strings.Contains(part, "-") {
+			// Handle ranges (e.g., 5-7)
+			rangeParts := strings.Split(part, "-")
+			if len(rangeParts) == 2 {
+				start, err1 := strconv.Atoi(strings.TrimSpace(rangeParts[0]))
+				end, err2 := strconv.Atoi(strings.TrimSpace(rangeParts[1]))
+				if err1 == nil && err2 == nil {
+					for i := start; i <= end; i++ {
+						if i > 0 && i <= n {
+							selected[i-1] = true
 						}
 					}
 				}
-			} else {
-				// Handle single numbers
-				if idx, err := strconv.Atoi(part); err == nil {
-					if idx > 0 && idx <= len(results.Results) {
-						selectedIndices[idx-1] = true
-					}
-				}
 			}
+		} else if idx, err := strconv.Atoi(part); err == nil && idx > 0 && idx <= n {
+			selected[idx-1] = true
+		}
+	}
+	return selected
+}
+
+// parseNeverCommand recognizes "n <IDs> [duration]" (or "never ...") in the
+// interactive prompt. ok is false for any other input.
+func parseNeverCommand(input string) (ids string, span time.Duration, ok bool, err error) {
+	fields := strings.Fields(input)
+	if len(fields) < 2 || (!strings.EqualFold(fields[0], "n") && !strings.EqualFold(fields[0], "never")) {
+		return "", 0, false, nil
+	}
+	fields = fields[1:]
+	// IDs never contain letters, so a trailing word like "30d" is the duration
+	if last := fields[len(fields)-1]; len(fields) > 1 && strings.IndexFunc(last, unicode.IsLetter) >= 0 {
+		if span, err = units.ParseDuration(last); err != nil {
+			return "", 0, true, err
 		}
+		fields = fields[:len(fields)-1]
+	}
+	return strings.Join(fields, " "), span, true, nil
+}
+
+// dismissResults stops the selected results from being suggested, for span
+// or forever when span is zero. Results of a known rule dismiss the rule;
+// others (e.g. large files) dismiss their individual paths.
+func dismissResults(results []rules.Result, selected map[int]bool, span time.Duration) error {
+	if len(selected) == 0 {
+		PrintWarning("No valid items selected.")
+		return nil
+	}
+
+	var until time.Time
+	if span > 0 {
+		until = time.Now().Add(span)
+	}
+	registry := rules.NewRegistry()
+	mgr := dismiss.NewManager()
+	for i, res := range results {
+		if !selected[i] {
+			continue
+		}
+		var targets []dismiss.Dismissal
+		if _, known := registry.Find(res.Rule.Name); known {
+			targets = append(targets, dismiss.Dismissal{Rule: res.Rule.Name, Until: until})
+		} else {
+			for _, p := range res.FoundPaths {
+				targets = append(targets, dismiss.Dismissal{Path: p, Until: until})
+			}
+		}
+		for _, d := range targets {
+			if err := mgr.Add(d); err != nil {
+				return fmt.Errorf("failed to save dismissal: %w", err)
+			}
+		}
+		if span > 0 {
+			PrintSuccess("%s won't be suggested for %s.", res.Rule.Name, units.FormatDuration(span))
+		} else {
+			PrintSuccess("%s won't be suggested again.", res.Rule.Name)
+		}
+	}
+	return nil
+}
+
+func runInteractiveScan(results *scanner.ScanResults, useAuth, noAuth bool) error {
+	selectedIndices, dismissed, ok := multiSelect(results.Results)
+	if ok {
+		if selectedIndices == nil && dismissed == nil {
+			PrintWarning("Cleanup cancelled.")
+			return ErrCancelled
+		}
+		if len(dismissed) > 0 {
+			if err := dismissResults(results.Results, dismissed, 0); err != nil {
+				return err
+			}
+		}
+	} else {
+		var err error
+		if selectedIndices, err = promptSelection(results); err != nil {
+			return err
+		}
+	}
+	if len(selectedIndices) == 0 {
+		fmt.Println("No items selected. Exiting.")
+		return nil
 	}
 
 	var toClean []rules.Result
@@ -208,6 +440,7 @@ func runInteractiveScan(results *scanner.ScanResults, useAuth bool) error {
 			toClean = append(toClean, res)
 		}
 	}
+	toClean = withoutReportOnly(toClean)
 
 	if len(toClean) == 0 {
 		fmt.Println("No valid items selected.")
@@ -215,154 +448,692 @@ func runInteractiveScan(results *scanner.ScanResults, useAuth bool) error {
 	}
 
 	fmt.Printf("\nSelected %d items for cleanup.\n", len(toClean))
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	c := cleaner.NewCleaner()
+	c.UseSystemTrash = cfg.UseSystemTrash
+	c.TrashChecksums = cfg.TrashChecksums
+	trashCap := checkTrashCap(c, cfg, toClean)
 
-	cfg, _ := config.Load()
-	if cfg.EnableAuth || useAuth {
-		PrintInfo("Authenticating...")
-		authenticator := auth.Current()
-		success, err := authenticator.Authenticate("confirm cleanup")
-		if err != nil {
-			return fmt.Errorf("authentication error: %w", err)
-		}
-		if !success {
-			PrintWarning("Authentication failed. Cleanup aborted.")
-			return nil
+	if !confirmRisk(toClean, false, false, false) {
+		return ErrCancelled
+	}
+	purgeOld := decideTrashCap(cfg, trashCap, false)
+
+	if ok, err := confirmOverrides(toClean); err != nil || !ok {
+		return err
+	}
+	if ok, err := authorizeCleanup(cfg, toClean, useAuth, noAuth, false); err != nil || !ok {
+		return err
+	}
+	if purgeOld {
+		if err := applyTrashCap(c, trashCap); err != nil {
+			return err
 		}
-		PrintSuccess("Authentication successful.")
 	}
 
-	c := cleaner.NewCleaner()
-	res, err := c.Clean(toClean, false, false)
-	if err != nil {
+	res, err := cleanInterruptibly(c, toClean, false)
+	if res == nil {
 		return err
 	}
 
-	PrintSuccess("Successfully reclaimed %s!", FormatSize(res.ReclaimedSpace))
+	notifyCleanup(cfg, "interactive", res)
+	printReclaimed(res, err)
 	fmt.Printf("Files moved to trash: %d\n", res.FileCount)
 	fmt.Printf("Trash Session ID: %s\n", Colorize(Cyan, res.TrashSession))
-	return nil
+	printMilestonesAndTips(cfg, res)
+	return err
+}
+
+// promptSelection asks for result IDs on dumb terminals, where the
+// multi-select list can't be drawn.
+func promptSelection(results *scanner.ScanResults) (map[int]bool, error) {
+	fmt.Println("\n" + Bold + "Interactive Cleanup Selection" + Reset)
+	fmt.Println("Enter IDs to clean (e.g. '1, 3, 5-7') or 'all'. Press Enter to skip.")
+	fmt.Println("Type 'n <IDs> [duration]' to stop suggesting items, for good or for a while (e.g. 'n 2' or 'n 4 30d').")
+
+	// Items dismissed at this prompt stay out of any later selection,
+	// including 'all'
+	dismissed := make(map[int]bool)
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Print(Colorize(Green, "Selection > "))
+		line, _ := reader.ReadString('\n')
+		input := strings.TrimSpace(line)
+
+		ids, span, ok, err := parseNeverCommand(input)
+		if !ok {
+			if input == "" {
+				return nil, nil
+			}
+			selected := parseSelection(input, len(results.Results))
+			for i := range dismissed {
+				delete(selected, i)
+			}
+			return selected, nil
+		}
+		if err != nil {
+			PrintError("%v", err)
+			continue
+		}
+		never := parseSelection(ids, len(results.Results))
+		if err := dismissResults(results.Results, never, span); err != nil {
+			return nil, err
+		}
+		maps.Copy(dismissed, never)
+	}
 }
 
 func runClean(args []string) error {
 	fs := flag.NewFlagSet("clean", flag.ContinueOnError)
-	dryRun := fs.Bool("dry-run", true, "Perform a dry run (default true)")
-	olderThan := fs.String("older-than", "", "Filter items older than duration (e.g. 30d, 24h)")
-	yes := fs.Bool("yes", false, "Confirm cleanup automatically")
-	diff := fs.Bool("diff", false, "Show detailed diff of planned deletions")
+	apply := fs.Bool("apply", false, "Execute the cleanup (without it, clean only previews)")
+	dryRun := fs.Bool("dry-run", true, "Deprecated: --dry-run=false is an alias for --apply")
+	sf := addScanFlags(fs)
+	yes := fs.Bool("yes", false, "Skip the confirmation prompt when applying (Safe rules only)")
+	confirmRisky := fs.Bool("confirm-risky", false, "With --yes, also clean Caution and Manual rules without typing their names")
+	diff := fs.Bool("diff", false, "Show planned deletions as a tree (two levels, with sizes)")
+	noPager := fs.Bool("no-pager", false, "Do not pipe --diff output through $PAGER")
 	permanent := fs.Bool("permanent", false, "Delete files permanently (no trash)")
 	useAuth := fs.Bool("auth", false, "Enable biometric authentication for this cleanup")
-	fs.Parse(args)
-
-	var ageDuration time.Duration
-	if *olderThan != "" {
-		if strings.HasSuffix(*olderThan, "d") {
-			daysStr := strings.TrimSuffix(*olderThan, "d")
-			var days int
-			fmt.Sscanf(daysStr, "%d", &days)
-			ageDuration = time.Duration(days) * 24 * time.Hour
-		} else {
-			var err error
-			ageDuration, err = time.ParseDuration(*olderThan)
-			if err != nil {
-				return fmt.Errorf("invalid duration format: %s (example: 30d, 24h)", *olderThan)
-			}
+	noAuth := fs.Bool("no-auth", false, "Skip authentication (only allowed when every rule is Safe)")
+	system := fs.Bool("system", false, "Clean whitelisted system caches (re-runs with sudo)")
+	free := fs.String("free", "", "Clean just enough to reclaim this much space (e.g. 20GB)")
+	ids := fs.String("ids", "", "Clean these results of the last 'burrow scan' by ID (e.g. 1,3,5-7)")
+	note := fs.String("note", "", "Describe this cleanup in the history and trash, e.g. \"before Xcode 16 upgrade\"")
+	var match stringList
+	fs.Var(&match, "match", "Clean only found paths matching this glob, e.g. 'DerivedData/MyApp-*' (repeatable)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	// Backward-compatible aliases for the old dry-run semantics
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	switch {
+	case set["dry-run"] && *dryRun && *apply:
+		return fmt.Errorf("--dry-run and --apply cannot be combined")
+	case set["dry-run"] && !*dryRun:
+		PrintWarning("--dry-run=false is deprecated; use --apply.")
+		*apply = true
+	case *yes && !*apply && !set["dry-run"]:
+		PrintWarning("'clean --yes' without --apply is deprecated; use 'clean --apply --yes'.")
+		*apply = true
+	}
+
+	if *confirmRisky && !*yes {
+		return fmt.Errorf("--confirm-risky only applies together with --yes")
+	}
+
+	var budget int64
+	if *free != "" {
+		var err error
+		if budget, err = units.ParseSize(*free); err != nil {
+			return err
 		}
 	}
 
-	cfg, _ := config.Load()
-	registry := rules.NewRegistry()
-	s := scanner.NewScanner(registry, scanner.ScanOptions{
-		ExcludedPaths: cfg.ExcludedPaths,
-		SizeThreshold: cfg.SizeThresholdMB * 1024 * 1024,
-		OlderThan:     ageDuration,
-	})
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if *system {
+		return runSystemClean(cfg, *apply, *yes, *confirmRisky, *useAuth)
+	}
+	if sf.json && *apply {
+		return fmt.Errorf("--json only prints the plan; it cannot be combined with --apply")
+	}
 
-	results, err := s.Scan()
+	var results *scanner.ScanResults
+	if *ids != "" {
+		// The IDs pick from results that were already filtered and sized
+		for _, name := range []string{"category", "older-than", "risk", "min-size", "cached", "size-mode", "free"} {
+			if set[name] {
+				return fmt.Errorf("--ids selects from the last scan; it cannot be combined with --%s", name)
+			}
+		}
+		results, err = listedResults(cfg, *ids)
+	} else {
+		results, err = runScanPipeline(cfg, sf, nil)
+	}
 	if err != nil {
 		return err
 	}
+	if len(match) > 0 && len(results.Results) > 0 {
+		results = scanner.MatchPaths(results, match)
+		if len(results.Results) == 0 && !sf.json {
+			PrintWarning("No found paths match %s.", strings.Join(match, ", "))
+			return nil
+		}
+	}
+
+	if sf.json {
+		data, _ := json.MarshalIndent(results, "", "  ")
+		fmt.Println(string(data))
+		return candidates(len(results.Results))
+	}
 
 	if len(results.Results) == 0 {
-		PrintSuccess("No cleanup candidates found. Your system is clean!")
+		printNoCandidates(results)
 		return nil
 	}
 
-	if *dryRun && !*yes {
-		PrintHeader("Cleanup Summary (Dry Run):")
-		fmt.Printf(Bold+"%-30s %-15s %s"+Reset+"\n", "CATEGORY", "SIZE", "RULE")
-		fmt.Println(Gray + strings.Repeat("-", 70) + Reset)
-		for _, res := range results.Results {
-			fmt.Printf("%-30s %-15s %s\n", Colorize(Blue, res.Rule.Category), Colorize(Yellow, FormatSize(res.TotalSize)), res.Rule.Name)
-			if *diff {
-				for _, p := range res.FoundPaths {
-					fmt.Printf("   %s %s\n", Colorize(Red, "-"), Colorize(Gray, p))
-				}
-			}
-		}
-		fmt.Println(Gray + strings.Repeat("-", 70) + Reset)
-		fmt.Printf(Bold+"Total to be reclaimed: %s"+Reset+"\n", Colorize(Green, FormatSize(results.TotalSize)))
+	results.Results = withoutReportOnly(results.Results)
+	results.TotalSize = 0
+	for _, res := range results.Results {
+		results.TotalSize += res.TotalSize
+	}
+	if len(results.Results) == 0 {
+		PrintWarning("Nothing to clean; the remaining candidates are only reported.")
+		return nil
+	}
 
-		if !Confirm("\n" + Colorize(Yellow, "Do you want to proceed with the cleanup?")) {
-			PrintWarning("Cleanup cancelled.")
+	if budget > 0 {
+		selected := planBudget(results.Results, budget, *yes)
+		if len(selected) == 0 {
+			PrintWarning("No candidates selected for the requested budget.")
 			return nil
 		}
+		results.Results = selected
+		results.TotalSize = 0
+		for _, res := range selected {
+			results.TotalSize += res.TotalSize
+		}
 	}
 
-	if cfg.EnableAuth || *useAuth {
-		PrintInfo("Authenticating...")
-		authenticator := auth.Current()
-		success, err := authenticator.Authenticate("confirm cleanup")
-		if err != nil {
-			return fmt.Errorf("authentication error: %w", err)
-		}
-		if !success {
-			PrintWarning("Authentication failed. Cleanup aborted.")
-			return nil
+	// The plan is always shown, even with --yes
+	title := "Cleanup Preview (nothing will be deleted):"
+	if *apply {
+		title = "Cleanup Plan:"
+	}
+	// Only the potentially long --diff listing is paged
+	out := newPager(*noPager || !*diff)
+	fmt.Fprintln(out, "\n"+Bold+title+Reset)
+	fmt.Fprintf(out, Bold+"%-30s %-15s %-9s %s"+Reset+"\n", "CATEGORY", "SIZE", "RISK", "RULE")
+	fmt.Fprintln(out, Gray+strings.Repeat("-", 80)+Reset)
+	for _, res := range results.Results {
+		fmt.Fprintf(out, "%-30s %-15s %s %s%s\n", Colorize(Blue, categoryLabel(res.Rule.Category)), Colorize(Yellow, FormatSize(res.TotalSize)), riskLabel(res.Rule.RiskLevel), res.Rule.Name, volumeLabel(res))
+		if *diff {
+			printTree(out, scanner.BuildTree(res.FoundPaths, 2))
 		}
-		PrintSuccess("Authentication successful.")
 	}
+	fmt.Fprintln(out, Gray+strings.Repeat("-", 80)+Reset)
+	fmt.Fprintf(out, Bold+"Total to be reclaimed: %s"+Reset+"\n", Colorize(Green, FormatSize(results.TotalSize)))
+	out.Flush()
+	noteHidden(results)
 
 	c := cleaner.NewCleaner()
-	res, err := c.Clean(results.Results, false, *permanent)
-	if err != nil {
+	c.UseSystemTrash = cfg.UseSystemTrash
+	c.TrashChecksums = cfg.TrashChecksums
+	c.Note = strings.TrimSpace(*note)
+	var trashCap *cleaner.TrashCap
+	if !*permanent {
+		trashCap = checkTrashCap(c, cfg, results.Results)
+	}
+
+	if !*apply {
+		fmt.Println("\nThis was a preview. Re-run with --apply to clean these items.")
+		return ErrCandidatesFound
+	}
+	if !confirmRisk(results.Results, *yes, *confirmRisky, true) {
+		return ErrCancelled
+	}
+
+	if !*permanent && !c.UseSystemTrash {
+		proceed, switchToPermanent, err := checkTrashSpace(c, cfg, results.Results, *yes)
+		if err != nil || !proceed {
+			return err
+		}
+		if switchToPermanent {
+			*permanent = true
+			trashCap = nil
+		}
+	}
+	purgeOld := decideTrashCap(cfg, trashCap, *yes)
+
+	if ok, err := confirmOverrides(results.Results); err != nil || !ok {
+		return err
+	}
+	if ok, err := authorizeCleanup(cfg, results.Results, *useAuth, *noAuth, *permanent); err != nil || !ok {
+		return err
+	}
+	if purgeOld {
+		if err := applyTrashCap(c, trashCap); err != nil {
+			return err
+		}
+	}
+
+	res, err := cleanInterruptibly(c, results.Results, *permanent)
+	if res == nil {
 		return err
 	}
 
-	PrintSuccess("Successfully reclaimed %s!", FormatSize(res.ReclaimedSpace))
+	notifyCleanup(cfg, "manual", res)
+	printReclaimed(res, err)
 	if *permanent {
 		fmt.Printf("Files permanently deleted: %d\n", res.FileCount)
+	} else if c.UseSystemTrash {
+		fmt.Printf("Files moved to the system trash: %d\n", res.FileCount)
+		PrintInfo("Restore them from your system Trash / Recycle Bin.")
 	} else {
 		fmt.Printf("Files moved to trash: %d\n", res.FileCount)
 		fmt.Printf("Trash Session ID: %s\n", Colorize(Cyan, res.TrashSession))
-		PrintInfo("You can undo this action by running 'burrow undo'.")
+		printUndoHint(cfg, time.Now())
 	}
+	printMilestonesAndTips(cfg, res)
 
-	return nil
+	return err
+}
+
+// planBudget selects Safe results first, then offers Caution results one by one
+// until the target is reached. Manual-risk results are never selected, and
+// Caution results are skipped entirely in non-interactive (--yes) runs.
+func planBudget(results []rules.Result, target int64, yes bool) []rules.Result {
+	selected, total := cleaner.PlanBudget(results, rules.RiskSafe, target)
+	PrintInfo("Safe candidates cover %s of the requested %s.", FormatSize(total), FormatSize(target))
+
+	if total >= target {
+		return selected
+	}
+
+	caution, _ := cleaner.PlanBudget(results, rules.RiskCaution, target-total)
+	for _, res := range caution {
+		if yes {
+			PrintWarning("Skipping Caution rule %s (%s) in non-interactive mode.", res.Rule.Name, FormatSize(res.TotalSize))
+			continue
+		}
+		fmt.Printf("\n%s %s (%s)\n   %s\n", Colorize(Yellow, "[Caution]"), res.Rule.Name, FormatSize(res.TotalSize), Colorize(Gray, res.Rule.Explanation))
+		if Confirm("Include this rule to reach the target?") {
+			selected = append(selected, res)
+			total += res.TotalSize
+		}
+		if total >= target {
+			break
+		}
+	}
+
+	if total < target {
+		PrintWarning("Only %s of the requested %s can be reclaimed with the selected rules.", FormatSize(total), FormatSize(target))
+	}
+	return selected
+}
+
+// checkTrashSpace runs the free-space pre-flight for trash-based cleanups. When
+// the disk is too full it offers to switch to permanent deletion instead; in
+// non-interactive runs the cleanup is aborted.
+func checkTrashSpace(c *cleaner.Cleaner, cfg *config.Config, results []rules.Result, yes bool) (proceed, permanent bool, err error) {
+	p, err := c.CheckSpace(results, cfg.MinFreeSpaceBytes())
+	if err != nil {
+		PrintWarning("Could not check free disk space: %v", err)
+		return true, false, nil
+	}
+	if p.OK() {
+		return true, false, nil
+	}
+
+	if p.NearlyFull {
+		PrintWarning("Disk is nearly full (%s free, minimum %s). Moving files to trash will not free any space until the trash is purged.",
+			FormatSize(p.Available), FormatSize(p.MinFreeSpace))
+	} else {
+		PrintWarning("Not enough free space to copy %s across volumes into trash (%s free, minimum %s must remain).",
+			FormatSize(p.CopyBytes), FormatSize(p.Available), FormatSize(p.MinFreeSpace))
+	}
+
+	if yes {
+		return false, false, fmt.Errorf("insufficient free space for trash-based cleanup; rerun with --permanent to delete directly")
+	}
+	if Confirm(Colorize(Yellow, "Delete permanently instead (cannot be undone)?")) {
+		return true, true, nil
+	}
+	PrintWarning("Cleanup cancelled.")
+	return false, false, ErrCancelled
+}
+
+// authorizeCleanup enforces biometric authentication before files are removed.
+// Authentication is required when enabled in config or requested via --auth.
+// The --no-auth escape hatch is honoured only for trash-based cleanups where
+// every selected rule is Safe; permanent deletes always require authentication.
+func authorizeCleanup(cfg *config.Config, results []rules.Result, useAuth, noAuth, permanent bool) (bool, error) {
+	if !cfg.EnableAuth && !useAuth {
+		return true, nil
+	}
+
+	if noAuth {
+		if permanent {
+			return false, fmt.Errorf("--no-auth cannot be used with --permanent")
+		}
+		for _, res := range results {
+			if res.Rule.RiskLevel != rules.RiskSafe {
+				return false, fmt.Errorf("--no-auth is only allowed when every rule is Safe (%s is %s)", res.Rule.Name, res.Rule.RiskLevel)
+			}
+		}
+		PrintWarning("Skipping authentication (--no-auth, Safe rules only).")
+		return true, nil
+	}
+
+	reason := "confirm cleanup"
+	if permanent {
+		reason = "permanently delete files"
+	}
+
+	PrintInfo("Authenticating...")
+	success, err := auth.Current().Authenticate(reason)
+	if err != nil {
+		return false, fmt.Errorf("authentication error: %w", err)
+	}
+	if !success {
+		PrintWarning("Authentication failed. Cleanup aborted.")
+		return false, ErrCancelled
+	}
+	PrintSuccess("Authentication successful.")
+	return true, nil
+}
+
+// applySafetyPolicy installs the user's protected and force-allowed paths.
+func applySafetyPolicy(cfg *config.Config) {
+	safety.SetPolicy(safety.Policy{
+		ProtectedPaths:  cfg.ProtectedPaths,
+		ForceAllowPaths: cfg.ForceAllowPaths,
+	})
+}
+
+// confirmOverrides asks for explicit approval when any result is only
+// deletable because of force_allow_paths. Touch ID is used on macOS; other
+// platforms require typing a confirmation token.
+func confirmOverrides(results []rules.Result) (bool, error) {
+	var paths []string
+	for _, res := range results {
+		paths = append(paths, res.FoundPaths...)
+	}
+	overridden := safety.OverriddenPaths(paths)
+	if len(overridden) == 0 {
+		return true, nil
+	}
+
+	PrintWarning("The following paths bypass default safety guards via force_allow_paths:")
+	for _, p := range overridden {
+		fmt.Printf("  - %s\n", p)
+	}
+
+	if runtime.GOOS == "darwin" {
+		success, err := auth.Current().Authenticate("delete paths allowed by force_allow_paths")
+		if err != nil {
+			return false, fmt.Errorf("authentication error: %w", err)
+		}
+		if !success {
+			PrintWarning("Authentication failed. Cleanup aborted.")
+			return false, ErrCancelled
+		}
+		return true, nil
+	}
+
+	if !ConfirmTyped("These paths are normally protected.", "override") {
+		fmt.Println("Cleanup cancelled.")
+		return false, ErrCancelled
+	}
+	return true, nil
 }
 
-func runUndo() error {
+// confirmRisk asks for confirmation before cleaning results. Plain y/N is
+// enough for Safe rules; once a Caution or Manual rule is included the user has
+// to type the rule name (or "delete N items" for several rules). Yes skips
+// only the y/N question; the typed confirmation is skipped only with
+// confirmRisky, and risky rules are still called out.
+func confirmRisk(results []rules.Result, yes, confirmRisky, promptSafe bool) bool {
+	var risky []rules.Result
+	for _, res := range results {
+		if res.Rule.RiskLevel != rules.RiskSafe {
+			risky = append(risky, res)
+		}
+	}
+
+	if len(risky) == 0 {
+		if yes || !promptSafe {
+			return true
+		}
+		if !Confirm("\n" + Colorize(Yellow, "Do you want to proceed with the cleanup?")) {
+			PrintWarning("Cleanup cancelled.")
+			return false
+		}
+		return true
+	}
+
+	if yes && confirmRisky {
+		PrintWarning("Cleaning %d Caution/Manual rule(s) without confirmation (--confirm-risky).", len(risky))
+		return true
+	}
+	if yes {
+		PrintInfo("--yes only covers Safe rules; add --confirm-risky to clean Caution and Manual rules without typing their names.")
+	}
+
+	fmt.Println()
+	PrintWarning("This cleanup includes rules that are not marked Safe:")
+	for _, res := range risky {
+		fmt.Printf("  %s %s (%s)\n", riskLabel(res.Rule.RiskLevel), res.Rule.Name, FormatSize(res.TotalSize))
+	}
+
+	token := fmt.Sprintf("delete %d items", len(risky))
+	if len(risky) == 1 {
+		token = risky[0].Rule.Name
+	}
+	if !ConfirmTyped("Review these paths before continuing.", token) {
+		PrintWarning("Cleanup cancelled.")
+		return false
+	}
+	return true
+}
+
+// riskLabel renders a risk level as a fixed-width, colored tag.
+func riskLabel(level rules.RiskLevel) string {
+	label := fmt.Sprintf("%-9s", "["+string(level)+"]")
+	switch level {
+	case rules.RiskSafe:
+		return Colorize(Green, label)
+	case rules.RiskCaution:
+		return Colorize(Yellow, label)
+	default:
+		return Colorize(Red+Bold, label)
+	}
+}
+
+// notifyCleanup posts a cleanup summary to the configured webhooks.
+func notifyCleanup(cfg *config.Config, trigger string, res *cleaner.CleanResult) {
+	if len(cfg.Notifications) == 0 {
+		return
+	}
+	summary := notify.NewSummary(trigger, res.TrashSession, res.ReclaimedSpace, res.FileCount, res.CategoryStats)
+	for _, err := range notify.Send(cfg.Notifications, summary) {
+		PrintWarning("Notification failed: %v", err)
+	}
+}
+
+// runUndo restores the last cleanup, the session given by ID, or every
+// session of a period.
+func runUndo(args []string) error {
+	fs := flag.NewFlagSet("undo", flag.ContinueOnError)
+	all := fs.Bool("all", false, "Restore every session in the trash, newest first")
+	var since units.Duration
+	fs.Var(&since, "since", "Restore every session from this period, newest first (e.g. 1h, 2d)")
+	yes := fs.Bool("yes", false, "Skip the confirmation prompt")
+	dryRun := fs.Bool("dry-run", false, "List what would be restored where, and what is in the way, without restoring")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *all && since > 0 {
+		return fmt.Errorf("--all and --since cannot be combined")
+	}
+	id := fs.Arg(0)
+	if fs.NArg() > 1 || (id != "" && (*all || since > 0)) {
+		return fmt.Errorf("usage: burrow undo [session-id] | --all | --since <period>")
+	}
+	if *dryRun {
+		return previewUndo(id, *all, time.Duration(since))
+	}
+
 	c := cleaner.NewCleaner()
-	PrintInfo("Restoring last cleanup session...")
-	if err := c.Undo(); err != nil {
+	if !*all && since == 0 {
+		var report *cleaner.RestoreReport
+		var err error
+		if id != "" {
+			PrintInfo("Restoring cleanup session %s...", id)
+			report, err = c.UndoSession(id)
+		} else {
+			PrintInfo("Restoring last cleanup session...")
+			report, err = c.Undo()
+		}
+		if report == nil {
+			return err
+		}
+		if perr := printRestoreSummary(report); err == nil {
+			err = perr
+		}
 		return err
 	}
-	PrintSuccess("Successfully restored last cleanup session!")
-	return nil
+
+	var cutoff time.Time
+	if since > 0 {
+		cutoff = time.Now().Add(-time.Duration(since))
+	}
+	sessions, err := cleaner.NewTrashManager().Sessions()
+	if err != nil {
+		return err
+	}
+	var pending []cleaner.TrashSession
+	for _, s := range sessions {
+		if !s.Orphaned && !s.Manifest.Timestamp.Before(cutoff) {
+			pending = append(pending, s)
+		}
+	}
+	if len(pending) == 0 {
+		PrintWarning("No cleanup sessions to restore.")
+		return nil
+	}
+
+	PrintHeader(fmt.Sprintf("%-20s %-10s %s", "SESSION ID", "ENTRIES", "CLEANED"))
+	for _, s := range pending {
+		fmt.Printf("%-20s %-10d %s\n", Colorize(Cyan, s.ID), len(s.Manifest.Entries), formatAgo(s.Manifest.Timestamp))
+	}
+	fmt.Println()
+	if !*yes && !Confirm(fmt.Sprintf("Restore these %d session(s)?", len(pending))) {
+		return ErrCancelled
+	}
+
+	reports, err := c.UndoSince(cutoff)
+	if perr := printRestoreSummary(reports...); err == nil {
+		err = perr
+	}
+	return err
+}
+
+// previewUndo lists what 'undo' with the same flags would restore.
+func previewUndo(id string, all bool, since time.Duration) error {
+	tm := cleaner.NewTrashManager()
+	var reports []*cleaner.RestoreReport
+	var err error
+	if !all && since == 0 {
+		if id == "" {
+			if id, err = tm.LatestSession(); err != nil {
+				return err
+			}
+		}
+		var report *cleaner.RestoreReport
+		if report, err = tm.PreviewRestore(id); report != nil {
+			reports = append(reports, report)
+		}
+	} else {
+		var cutoff time.Time
+		if since > 0 {
+			cutoff = time.Now().Add(-since)
+		}
+		reports, err = tm.PreviewRestoreSince(cutoff)
+	}
+	if len(reports) == 0 {
+		if err == nil {
+			PrintWarning("No cleanup sessions to restore.")
+		}
+		return err
+	}
+
+	var restore, skip, fail int
+	for _, r := range reports {
+		fmt.Println(Bold + "Session " + r.Session + Reset)
+		for _, e := range r.Entries {
+			detail := ""
+			if e.Detail != "" {
+				detail = " " + Colorize(Gray, "("+e.Detail+")")
+			}
+			switch e.Status {
+			case cleaner.RestoreOK:
+				fmt.Printf("  %s %s%s\n", Colorize(Green, "restore"), e.Path, detail)
+			case cleaner.RestoreSkipped:
+				fmt.Printf("  %s    %s%s\n", Colorize(Yellow, "skip"), e.Path, detail)
+			case cleaner.RestoreFailed:
+				fmt.Printf("  %s    %s%s\n", Colorize(Red, "fail"), e.Path, detail)
+			}
+		}
+		restore += r.Count(cleaner.RestoreOK)
+		skip += r.Count(cleaner.RestoreSkipped)
+		fail += r.Count(cleaner.RestoreFailed)
+	}
+	fmt.Printf("\nWould restore %d item(s) from %d session(s); %d would be skipped, %d would fail. Nothing was changed.\n", restore, len(reports), skip, fail)
+	return err
+}
+
+// printRestoreSummary lists entries that were skipped, failed, or restored
+// but not verified, and totals the outcome of an undo, which is partial when
+// any item stayed in the trash or came back altered.
+func printRestoreSummary(reports ...*cleaner.RestoreReport) error {
+	var restored, skipped, failed, unverified int
+	for _, r := range reports {
+		for _, e := range r.Entries {
+			switch e.Status {
+			case cleaner.RestoreSkipped:
+				fmt.Printf("  %s %s %s\n", Colorize(Yellow, "skipped"), e.Path, Colorize(Gray, "("+e.Detail+")"))
+			case cleaner.RestoreFailed:
+				fmt.Printf("  %s  %s %s\n", Colorize(Red, "failed"), e.Path, Colorize(Gray, "("+e.Detail+")"))
+			case cleaner.RestoreUnverified:
+				fmt.Printf("  %s %s %s\n", Colorize(Red, "altered"), e.Path, Colorize(Gray, "("+e.Detail+")"))
+			}
+		}
+		restored += r.Count(cleaner.RestoreOK) + r.Count(cleaner.RestoreUnverified)
+		skipped += r.Count(cleaner.RestoreSkipped)
+		failed += r.Count(cleaner.RestoreFailed)
+		unverified += r.Count(cleaner.RestoreUnverified)
+	}
+
+	summary := fmt.Sprintf("Restored %d item(s) from %d session(s); %d skipped, %d failed.", restored, len(reports), skipped, failed)
+	switch {
+	case failed > 0:
+		PrintError("%s Failed items are still in the trash.", summary)
+	case skipped > 0:
+		PrintWarning("%s Skipped items are still in the trash.", summary)
+	case unverified == 0:
+		PrintSuccess("%s", summary)
+		return nil
+	default:
+		PrintInfo("%s", summary)
+	}
+	if unverified > 0 {
+		PrintError("%d restored item(s) do not match what was trashed; check the altered paths above.", unverified)
+	}
+	return partialf("%d item(s) could not be restored intact", skipped+failed+unverified)
 }
 
 func runList(args []string) error {
 	fs := flag.NewFlagSet("list", flag.ContinueOnError)
-	js := fs.Bool("json", false, "Output in JSON format")
-	fs.Parse(args)
-
-	cfg, _ := config.Load()
-	registry := rules.NewRegistry()
-	s := scanner.NewScanner(registry, scanner.ScanOptions{
-		ExcludedPaths: cfg.ExcludedPaths,
-		SizeThreshold: cfg.SizeThresholdMB * 1024 * 1024,
-	})
+	sf := addScanFlags(fs)
+	noPager := fs.Bool("no-pager", false, "Do not pipe long output through $PAGER")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	js := &sf.json
 
-	results, err := s.Scan()
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	results, err := runScanPipeline(cfg, sf, nil)
 	if err != nil {
 		return err
 	}
@@ -374,46 +1145,111 @@ func runList(args []string) error {
 	}
 
 	if len(results.Results) == 0 {
-		fmt.Println("✨ No cleanup candidates found.")
+		printNoCandidates(results)
 		return nil
 	}
 
+	out := newPager(*noPager)
 	for _, res := range results.Results {
-		fmt.Printf("\n[%s] %s (%s)\n", res.Rule.Category, res.Rule.Name, FormatSize(res.TotalSize))
+		fmt.Fprintf(out, "\n[%s] %s (%s)%s\n", categoryLabel(res.Rule.Category), res.Rule.Name, FormatSize(res.TotalSize), volumeLabel(res))
+		// Pattern rules list individual files, so their stats are shown per searched directory
+		stats := make(map[string]rules.PathStats, len(res.Stats))
+		for _, st := range res.Stats {
+			stats[st.Path] = st
+		}
 		for _, path := range res.FoundPaths {
-			fmt.Printf("  • %s\n", path)
+			if st, ok := stats[path]; ok {
+				// Rules with several paths (e.g. one per model) show what each holds
+				label, size := path, st.Size
+				if st.Item != "" {
+					label = Bold + st.Item + Reset + " " + path
+				}
+				if res.Rule.SparseFiles {
+					size = st.Allocated
+				}
+				if len(res.Stats) > 1 {
+					label += " " + Colorize(Yellow, FormatSize(size))
+				}
+				if res.Rule.SparseFiles && st.Size > size {
+					label += " " + Colorize(Gray, "(sparse, "+FormatSize(st.Size)+" apparent)")
+				}
+				fmt.Fprintf(out, "  %s %s %s\n", Symbol("•", "-"), label, Colorize(Gray, "("+formatStats(st)+")"))
+				delete(stats, path)
+			} else {
+				fmt.Fprintf(out, "  %s %s\n", Symbol("•", "-"), path)
+			}
+		}
+		for _, st := range res.Stats {
+			if _, ok := stats[st.Path]; ok {
+				fmt.Fprintf(out, "  %s %s\n", Colorize(Gray, Symbol("↳", "*")), Colorize(Gray, st.Path+": "+formatStats(st)))
+			}
 		}
 	}
+	out.Flush()
 	return nil
 }
 
 func runRules(args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "test":
+			return runRulesTest(args[1:])
+		case "add-source":
+			return runRulesAddSource(args[1:])
+		case "sources":
+			return runRulesSources(args[1:])
+		case "categories":
+			return runRulesCategories(args[1:])
+		case "search":
+			return runRulesSearch(args[1:])
+		case "validate":
+			return runRulesValidate(args[1:])
+		}
+	}
+
 	fs := flag.NewFlagSet("rules", flag.ContinueOnError)
 	explain := fs.String("explain", "", "Explain a specific rule")
 	js := fs.Bool("json", false, "Output in JSON format")
-	fs.Parse(args)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
 
 	registry := rules.NewRegistry()
 	allRules := registry.All()
 
-	if *js {
-		data, _ := json.MarshalIndent(allRules, "", "  ")
-		fmt.Println(string(data))
-		return nil
+	if _, err := rules.LoadCustomRules(); err != nil && !*js {
+		PrintWarning("Some custom rule files could not be loaded: %v", err)
 	}
 
 	if *explain != "" {
-		for _, r := range allRules {
-			if strings.EqualFold(r.Name, *explain) {
-				fmt.Printf("Rule: %s\n", r.Name)
-				fmt.Printf("Category: %s\n", r.Category)
-				fmt.Printf("Risk: %s\n", r.RiskLevel)
-				fmt.Printf("Description: %s\n", r.Description)
-				fmt.Printf("Explanation: %s\n", r.Explanation)
-				return nil
-			}
+		r, ok := registry.Find(*explain)
+		if !ok {
+			return fmt.Errorf("rule not found: %s", *explain)
+		}
+		if *js {
+			data, _ := json.MarshalIndent(r.Explain(), "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		fmt.Printf("Rule: %s\n", r.Name)
+		fmt.Printf("Category: %s\n", r.Category)
+		fmt.Printf("Risk: %s\n", r.RiskLevel)
+		fmt.Printf("Description: %s\n", r.Description)
+		if r.Stores != "" {
+			fmt.Printf("Stores: %s\n", r.Stores)
+		}
+		fmt.Printf("Explanation: %s\n", r.Explanation)
+		fmt.Printf("Rebuild cost: %s\n", formatRebuildCost(r.RebuildCost))
+		for _, ref := range r.References {
+			fmt.Printf("Reference: %s\n", ref)
 		}
-		return fmt.Errorf("rule not found: %s", *explain)
+		return nil
+	}
+
+	if *js {
+		data, _ := json.MarshalIndent(allRules, "", "  ")
+		fmt.Println(string(data))
+		return nil
 	}
 
 	fmt.Println("Available Cleanup Rules:")
@@ -425,49 +1261,58 @@ func runRules(args []string) error {
 	return nil
 }
 
-func runHistory() error {
-	histMgr := history.NewManager()
-	entries, err := histMgr.Load()
-	if err != nil {
+func runStats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	sf := addScanFlags(fs)
+	showHistory := fs.Bool("history", false, "Chart reclaimable space over time from scan snapshots")
+	limit := fs.Int("limit", 20, "Number of snapshots to chart (with --history)")
+	projection := fs.Bool("projection", false, "Estimate how fast each category regrows from scan snapshots")
+	export := fs.String("export", "", "Write timestamped rows to a .csv or .json file")
+	appendRows := fs.Bool("append", false, "Add to the rows already in the --export file")
+	lifetime := fs.Bool("lifetime", false, "Show local usage totals since Burrow was first used")
+	if err := parseFlags(fs, args); err != nil {
 		return err
 	}
+	js := &sf.json
 
-	if len(entries) == 0 {
-		fmt.Println("No history found. Start cleaning to build history!")
-		return nil
+	if *appendRows && *export == "" {
+		return fmt.Errorf("--append needs --export <file>")
 	}
-
-	PrintHeader("Cleanup History")
-	fmt.Printf(Gray+"%-20s %-15s %-10s %s"+Reset+"\n", "DATE", "RECLAIMED", "FILES", "SESSION ID")
-	fmt.Println(Gray + strings.Repeat("-", 60) + Reset)
-
-	for _, e := range entries {
-		fmt.Printf("%-20s %-15s %-10d %s\n",
-			e.Timestamp.Format("2006-01-02 15:04"),
-			Colorize(Green, FormatSize(e.ReclaimedBytes)),
-			e.FileCount,
-			Colorize(Cyan, e.ID),
-		)
+	if *export != "" && (*showHistory || *projection) {
+		return fmt.Errorf("--export cannot be combined with --history or --projection")
 	}
-	return nil
-}
 
-func runStats(args []string) error {
-	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
-	js := fs.Bool("json", false, "Output in JSON format")
-	fs.Parse(args)
+	if *lifetime && (*export != "" || *showHistory || *projection) {
+		return fmt.Errorf("--lifetime cannot be combined with --export, --history, or --projection")
+	}
 
-	cfg, _ := config.Load()
-	registry := rules.NewRegistry()
-	s := scanner.NewScanner(registry, scanner.ScanOptions{
-		ExcludedPaths: cfg.ExcludedPaths,
-		SizeThreshold: cfg.SizeThresholdMB * 1024 * 1024,
-	})
+	if *lifetime {
+		return runStatsLifetime(*js)
+	}
+	if *showHistory {
+		return runStatsHistory(*js, sf.category, *limit)
+	}
+	if *projection {
+		return runStatsProjection(*js, sf.category)
+	}
 
-	results, err := s.Scan()
+	cfg, err := loadConfig()
 	if err != nil {
 		return err
 	}
+	results, err := runScanPipeline(cfg, sf, nil)
+	if err != nil {
+		return err
+	}
+
+	if *export != "" {
+		snap := snapshot.FromResults(results.Results)
+		if err := snapshot.Export(*export, snap, *appendRows); err != nil {
+			return err
+		}
+		PrintSuccess("Exported %d row(s) to %s.", len(snap.Categories)+1, *export)
+		return nil
+	}
 
 	stats := make(map[string]int64)
 	for _, res := range results.Results {
@@ -475,7 +1320,11 @@ func runStats(args []string) error {
 	}
 
 	if *js {
-		data, _ := json.MarshalIndent(stats, "", "  ")
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"categories":        stats,
+			"total_reclaimable": results.TotalSize,
+			"disk":              results.Disk,
+		}, "", "  ")
 		fmt.Println(string(data))
 		return nil
 	}
@@ -483,16 +1332,220 @@ func runStats(args []string) error {
 	PrintHeader(fmt.Sprintf("%-30s %s", "CATEGORY", "TOTAL SIZE"))
 	fmt.Println(Gray + strings.Repeat("-", 45) + Reset)
 	for cat, size := range stats {
-		fmt.Printf("%-30s %s\n", Colorize(Blue, cat), Colorize(Yellow, FormatSize(size)))
+		fmt.Printf("%-30s %s\n", Colorize(Blue, categoryLabel(cat)), Colorize(Yellow, FormatSize(size)))
 	}
 	fmt.Println(Gray + strings.Repeat("-", 45) + Reset)
 	fmt.Printf(Bold+"%-30s %s"+Reset+"\n", "TOTAL RECLAIMABLE", Colorize(Green, FormatSize(results.TotalSize)))
+	printDiskSummary(results.Disk)
 
 	return nil
 }
 
+// applyLargeFileOptions merges large file settings from config and flags;
+// flags take precedence over the configuration.
+func applyLargeFileOptions(options *scanner.ScanOptions, cfg config.LargeFilesConfig, roots []string, exts string, maxDepth int) {
+	options.LargeFileRoots = cfg.Roots
+	if len(roots) > 0 {
+		options.LargeFileRoots = nil
+		for _, r := range roots {
+			options.LargeFileRoots = append(options.LargeFileRoots, config.LargeFileRoot{Path: r, MaxDepth: maxDepth})
+		}
+	}
+
+	options.Extensions = cfg.Extensions
+	if exts != "" {
+		options.Extensions = strings.Split(exts, ",")
+	}
+
+	if minSize := cfg.MinSizeBytes(); minSize > 0 {
+		options.SizeThreshold = minSize
+	}
+	if options.OlderThan == 0 {
+		switch {
+		case cfg.MinAge > 0:
+			options.OlderThan = time.Duration(cfg.MinAge)
+		case cfg.MinAgeDays > 0:
+			options.OlderThan = time.Duration(cfg.MinAgeDays) * units.Day
+		}
+	}
+}
+
+// volumeLabel marks results found on external or network volumes.
+func volumeLabel(res rules.Result) string {
+	if res.Volume == "" {
+		return ""
+	}
+	return " " + Colorize(Purple, "[volume: "+res.Volume+"]")
+}
+
+// rebuildLabel marks results that are costly to get back after cleaning.
+func rebuildLabel(res rules.Result) string {
+	if res.Rule.RebuildCost.Level != rules.RebuildHigh {
+		return ""
+	}
+	return " " + Colorize(Yellow, "[costly to rebuild]")
+}
+
+// formatRebuildCost renders a rule's rebuild cost for explanations.
+func formatRebuildCost(c rules.RebuildCost) string {
+	if c.Level == "" {
+		return "unknown"
+	}
+	if c.Description == "" {
+		return string(c.Level)
+	}
+	return string(c.Level) + ". " + c.Description
+}
+
+// printDiskSummary shows the home volume's capacity and the projected free
+// space once every reported candidate is removed.
+func printDiskSummary(d *scanner.DiskSummary) {
+	if d == nil || d.Total == 0 {
+		return
+	}
+	usedPct := float64(d.Used) * 100 / float64(d.Total)
+	fmt.Printf("Disk: %s used of %s (%.0f%%), %s free "+Symbol("→", "->")+" %s free after cleanup\n",
+		FormatSize(d.Used),
+		FormatSize(d.Total),
+		usedPct,
+		Colorize(Yellow, FormatSize(d.Free)),
+		Colorize(Green, FormatSize(d.FreeAfterCleanup)),
+	)
+}
+
+// printSkipped lists rule paths that exist but were left out of the results,
+// and rules hidden for being below their minimum size.
+func printSkipped(results *scanner.ScanResults) {
+	if len(results.Skipped) > 0 {
+		fmt.Println("\n" + Bold + "Skipped paths:" + Reset)
+		for _, s := range results.Skipped {
+			fmt.Printf("  %s %s\n      %s\n", Colorize(Yellow, Symbol("⊘", "skip:")), s.Path, Colorize(Gray, s.Rule+" - "+s.Reason))
+		}
+	}
+	if len(results.Hidden) > 0 {
+		fmt.Println("\n" + Bold + "Hidden results:" + Reset)
+		for _, h := range results.Hidden {
+			fmt.Printf("  %s %s %s\n", Colorize(Yellow, Symbol("⊘", "skip:")), h.Rule,
+				Colorize(Gray, fmt.Sprintf("(%s, minimum %s)", FormatSize(h.Size), FormatSize(h.MinSize))))
+		}
+	}
+}
+
+// noteHidden mentions results hidden by --min-size or per-rule minimums, so
+// a rule never disappears without explanation.
+func noteHidden(results *scanner.ScanResults) {
+	if len(results.Hidden) == 0 {
+		return
+	}
+	var total int64
+	for _, h := range results.Hidden {
+		total += h.Size
+	}
+	PrintInfo("%d small result(s) hidden (%s total) by --min-size or per-rule minimums; 'burrow scan --show-skipped' lists them.", len(results.Hidden), FormatSize(total))
+}
+
+// lowerPriority switches to idle CPU and I/O priority when requested on the
+// command line or by scan.idle_priority.
+func lowerPriority(cfg *config.Config, requested bool) {
+	if !requested && !cfg.Scan.IdlePriority {
+		return
+	}
+	if err := priority.Idle(); err != nil {
+		PrintWarning("Could not lower process priority: %v", err)
+	}
+}
+
+// timingLimit is how many rules and paths printTimings lists.
+const timingLimit = 5
+
+// printTimings shows the total scan time and where it went, so slow trees can
+// be excluded.
+func printTimings(results *scanner.ScanResults) {
+	fmt.Printf("\n%sScan time: %s%s\n", Bold, results.Duration.Round(time.Millisecond), Reset)
+	if len(results.Timings) == 0 {
+		return
+	}
+
+	// Timings are sorted slowest first; per-rule totals are derived here
+	byRule := make(map[string]time.Duration)
+	for _, t := range results.Timings {
+		byRule[t.Rule] += t.Duration
+	}
+	ruleNames := make([]string, 0, len(byRule))
+	for name := range byRule {
+		ruleNames = append(ruleNames, name)
+	}
+	sort.Slice(ruleNames, func(i, j int) bool { return byRule[ruleNames[i]] > byRule[ruleNames[j]] })
+
+	fmt.Println(Bold + "Slowest rules:" + Reset)
+	for i, name := range ruleNames {
+		if i == timingLimit {
+			break
+		}
+		fmt.Printf("  %s %s\n", Colorize(Yellow, fmt.Sprintf("%-8s", byRule[name].Round(time.Millisecond))), name)
+	}
+
+	fmt.Println(Bold + "Slowest paths:" + Reset)
+	for i, t := range results.Timings {
+		if i == timingLimit {
+			break
+		}
+		fmt.Printf("  %s %s %s\n", Colorize(Yellow, fmt.Sprintf("%-8s", t.Duration.Round(time.Millisecond))), t.Path, Colorize(Gray, "("+t.Rule+")"))
+	}
+	PrintInfo("Add slow paths you never want cleaned to excluded_paths to skip them.")
+}
+
+// printNoCandidates reports an empty scan, without claiming the system is
+// clean when paths could not be read.
+func printNoCandidates(results *scanner.ScanResults) {
+	if len(results.Errors) == 0 && len(results.Hidden) > 0 {
+		PrintSuccess("No cleanup candidates above the minimum size.")
+		noteHidden(results)
+		return
+	}
+	if len(results.Errors) == 0 {
+		PrintSuccess("No cleanup candidates found. Your system is clean!")
+		warnScanErrors(results)
+		return
+	}
+	PrintWarning("No cleanup candidates found, but the scan was incomplete.")
+	warnScanErrors(results)
+}
+
+// warnScanErrors prints the scan's warnings and summarizes the typed scan
+// errors by kind.
+func warnScanErrors(results *scanner.ScanResults) {
+	for _, w := range results.Warnings {
+		PrintWarning("%s.", w)
+	}
+	if len(results.Errors) == 0 {
+		return
+	}
+	counts := make(map[scanner.ErrorKind]int)
+	for _, e := range results.Errors {
+		counts[e.Kind]++
+	}
+	var parts []string
+	for _, kind := range []scanner.ErrorKind{scanner.KindPermissionDenied, scanner.KindUnsafe, scanner.KindNotFound, scanner.KindIO, scanner.KindTimedOut} {
+		if counts[kind] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[kind], kind))
+		}
+	}
+	PrintWarning("%d path(s) could not be scanned (%s); see 'burrow scan --show-skipped'.", len(results.Errors), strings.Join(parts, ", "))
+	for _, name := range results.TimedOut() {
+		PrintWarning("%s timed out, so its size is unknown or incomplete; retry it with 'burrow scan --rule %q --rule-timeout 0'.", name, name)
+	}
+}
+
+// recordScan stores the results of an unfiltered scan in the status cache and
+// appends a growth snapshot.
+func recordScan(results *scanner.ScanResults, registry *rules.Registry) {
+	scanner.SaveCache(results, registry)
+	snapshot.NewManager().Save(snapshot.FromResults(results.Results))
+}
+
 func runDoctor() error {
-	PrintHeader("Burrow Doctor — Diagnostic Report")
+	PrintHeader("Burrow Doctor " + Symbol("—", "-") + " Diagnostic Report")
 	fmt.Println(Gray + strings.Repeat("-", 40) + Reset)
 
 	// Check Home Directory
@@ -504,15 +1557,39 @@ func runDoctor() error {
 	}
 
 	// Check Burrow Folders
-	burrowDir := filepath.Join(home, ".burrow")
+	burrowDir := paths.DataDir()
 	if _, err := os.Stat(burrowDir); os.IsNotExist(err) {
 		PrintWarning("Burrow Directory: Not found (will be created on first clean)")
 	} else {
 		PrintSuccess("Burrow Directory: %s", burrowDir)
 	}
 
+	cfg, err := config.Load()
+	if _, serr := os.Stat(paths.ConfigFile()); serr != nil {
+		PrintInfo("Config File: %s (not found, using defaults)", paths.ConfigFile())
+	} else if cfg == nil {
+		PrintError("Config File: %s cannot be loaded: %v", paths.ConfigFile(), err)
+	} else {
+		PrintSuccess("Config File: %s", paths.ConfigFile())
+	}
+	if cfg == nil {
+		// Keep diagnosing with the defaults
+		cfg = &config.Config{}
+	} else if len(cfg.EnvOverrides()) > 0 {
+		PrintInfo("Environment Overrides: %s", strings.Join(cfg.EnvOverrides(), ", "))
+	}
+	if lang := rules.Locale(); lang != rules.DefaultLocale {
+		if messages, err := rules.LoadMessages(lang); err != nil {
+			PrintWarning("Locale: %s (message file skipped: %v)", lang, err)
+		} else if len(messages) == 0 {
+			PrintWarning("Locale: %s (no translations; rule texts stay in English)", lang)
+		} else {
+			PrintSuccess("Locale: %s (%d rules translated)", lang, len(messages))
+		}
+	}
+
 	// Check Permissions
-	testFile := filepath.Join(home, ".burrow", "test_perm")
+	testFile := filepath.Join(burrowDir, "test_perm")
 	os.MkdirAll(filepath.Dir(testFile), 0755)
 	if err := os.WriteFile(testFile, []byte("test"), 0644); err != nil {
 		PrintError("Write Permissions: Failed - %v", err)
@@ -522,13 +1599,103 @@ func runDoctor() error {
 	}
 
 	// Check OS
-	PrintSuccess("Operating System: macOS (detected)")
+	PrintSuccess("Operating System: %s (detected)", osName())
+
+	// Check watch service
+	if runtime.GOOS == "darwin" {
+		plist, stdoutLog, stderrLog := service.Paths()
+		if st, err := service.Query(); err == nil && st.Running {
+			PrintSuccess("Watch Service: running (pid %d)", st.PID)
+		} else if err == nil && st.Installed {
+			PrintWarning("Watch Service: installed but not running (%s)", plist)
+		} else {
+			PrintInfo("Watch Service: not installed")
+		}
+		fmt.Printf("   Logs: %s\n   Errors: %s\n", stdoutLog, stderrLog)
+	}
+
+	PrintHeader("Locations")
+	locations := []struct{ name, path string }{
+		{"Config", paths.ConfigFile()},
+		{"Custom rules", paths.RulesDir()},
+		{"Locales", paths.LocalesDir()},
+		{"Data", paths.DataDir()},
+		{"Trash", paths.TrashDir()},
+		{"History", paths.HistoryFile()},
+		{"Scan cache", paths.ScanCacheFile()},
+		{"Last listing", paths.ScanListingFile()},
+		{"Logs", paths.LogsDir()},
+		{"Audit log", paths.AuditLog()},
+	}
+	for _, l := range locations {
+		fmt.Printf("   %-13s %s\n", l.name+":", l.path)
+	}
+	if paths.DataDir() == paths.LegacyDataDir() {
+		PrintWarning("Using legacy %s; it could not be moved to %s.", paths.LegacyDataDir(), paths.DefaultDataDir())
+	}
 
+	// Check each rule's paths
+	PrintHeader("Rule Path Accessibility")
 	fmt.Println(Gray + strings.Repeat("-", 40) + Reset)
+	report := scanner.Diagnose(rules.NewRegistry(), cfg.ExcludedPaths, cfg.DisabledCategories)
+
+	counts := make(map[scanner.PathStatus]int)
+	for _, diag := range report {
+		fmt.Println(Bold + diag.Rule.Name + Reset)
+		for _, p := range diag.Paths {
+			counts[p.Status]++
+			line := fmt.Sprintf("  %-20s %s", p.Status, p.Path)
+			if p.Detail != "" {
+				line += " (" + p.Detail + ")"
+			}
+			switch p.Status {
+			case scanner.PathOK:
+				fmt.Println(Colorize(Green, line))
+			case scanner.PathMissing, scanner.PathExcluded, scanner.PathDisabled:
+				fmt.Println(Colorize(Gray, line))
+			case scanner.PathTCCBlocked, scanner.PathNoAccess, scanner.PathNeedsRoot:
+				fmt.Println(Colorize(Yellow, line))
+			default:
+				fmt.Println(Colorize(Red, line))
+			}
+		}
+	}
+
+	fmt.Println(Gray + strings.Repeat("-", 40) + Reset)
+	fmt.Printf("%d OK, %d missing, %d excluded/disabled, %d blocked, %d unsafe\n",
+		counts[scanner.PathOK],
+		counts[scanner.PathMissing],
+		counts[scanner.PathExcluded]+counts[scanner.PathDisabled],
+		counts[scanner.PathNoAccess]+counts[scanner.PathTCCBlocked]+counts[scanner.PathNeedsRoot],
+		counts[scanner.PathUnsafe],
+	)
+	if counts[scanner.PathTCCBlocked] > 0 {
+		PrintWarning("Some paths are blocked by macOS privacy protection. Grant Full Disk Access to your terminal in System Settings.")
+	}
+
+	fmt.Println(Gray + strings.Repeat("-", 40) + Reset)
+	// Paths that need root are expected; 'clean --system' handles them
+	if problems := counts[scanner.PathNoAccess] + counts[scanner.PathTCCBlocked] + counts[scanner.PathUnsafe] + counts[scanner.PathUnreachable]; problems > 0 {
+		PrintWarning("%d rule path(s) can't be scanned; see the list above.", problems)
+		return nil
+	}
 	PrintInfo("All systems operational. Burrow is ready to dig!")
 	return nil
 }
 
+func osName() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "macOS"
+	case "windows":
+		return "Windows"
+	case "linux":
+		return "Linux"
+	default:
+		return runtime.GOOS
+	}
+}
+
 func runVersion() error {
 	fmt.Println("Burrow v0.3.0")
 	return nil
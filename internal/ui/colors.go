@@ -2,7 +2,8 @@ package ui
 
 import "fmt"
 
-const (
+// ANSI styles. SetPlain clears them for plain output.
+var (
 	Reset  = "\033[0m"
 	Bold   = "\033[1m"
 	Red    = "\033[31m"
@@ -14,6 +15,28 @@ const (
 	Gray   = "\033[37m"
 )
 
+// plain is set by --plain: no ANSI styles, emoji, or drawing characters.
+var plain bool
+
+// SetPlain switches to accessible output with ASCII labels and no styling.
+func SetPlain() {
+	plain = true
+	SetNoColor()
+}
+
+// SetNoColor drops the ANSI styles but keeps emoji and drawing characters.
+func SetNoColor() {
+	Reset, Bold, Red, Green, Yellow, Blue, Purple, Cyan, Gray = "", "", "", "", "", "", "", "", ""
+}
+
+// Symbol returns fancy normally, or its ASCII replacement in plain mode.
+func Symbol(fancy, ascii string) string {
+	if plain {
+		return ascii
+	}
+	return fancy
+}
+
 // Colorize returns the string wrapped in the given color code.
 func Colorize(color, text string) string {
 	return color + text + Reset
@@ -21,22 +44,22 @@ func Colorize(color, text string) string {
 
 // PrintSuccess prints a message in green with a checkmark.
 func PrintSuccess(format string, a ...interface{}) {
-	fmt.Printf(Green+"✅ "+format+Reset+"\n", a...)
+	fmt.Printf(Green+Symbol("✅ ", "OK: ")+format+Reset+"\n", a...)
 }
 
 // PrintError prints a message in red with an X mark.
 func PrintError(format string, a ...interface{}) {
-	fmt.Printf(Red+"❌ "+format+Reset+"\n", a...)
+	fmt.Printf(Red+Symbol("❌ ", "ERR: ")+format+Reset+"\n", a...)
 }
 
 // PrintWarning prints a message in yellow with a warning sign.
 func PrintWarning(format string, a ...interface{}) {
-	fmt.Printf(Yellow+"⚠️  "+format+Reset+"\n", a...)
+	fmt.Printf(Yellow+Symbol("⚠️  ", "WARN: ")+format+Reset+"\n", a...)
 }
 
 // PrintInfo prints a message in blue/cyan with an info icon.
 func PrintInfo(format string, a ...interface{}) {
-	fmt.Printf(Cyan+"ℹ️  "+format+Reset+"\n", a...)
+	fmt.Printf(Cyan+Symbol("ℹ️  ", "INFO: ")+format+Reset+"\n", a...)
 }
 
 // PrintHeader prints a bold header.
@@ -0,0 +1,269 @@
+package ui
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// command is a top-level burrow command.
+type command struct {
+	name    string
+	summary string
+	// aliases are other names newcomers reach for.
+	aliases []string
+	// usage lists the command's forms; empty means "burrow <name> [flags]".
+	usage []string
+	// flags is set when the command parses its own flags, so -h lists them
+	// and it accepts the global --json if it has that flag.
+	flags bool
+	// diagnoses is set for commands that run even when the config file cannot
+	// be read, so they can report why.
+	diagnoses bool
+	run       func(args []string) error
+}
+
+// commands is the command tree in the order 'burrow help' lists it. It is
+// filled in init because help, reached from the commands, reads it.
+var commands []command
+
+func init() {
+	commands = []command{
+		{name: "scan", summary: "Identify cleanup candidates", flags: true, run: runScan},
+		{name: "clean", aliases: []string{"clean-up", "cleanup", "rm"}, summary: "Preview a cleanup; add --apply to remove files", flags: true, run: runClean},
+		{name: "recommend", summary: "Rank candidates and clean a curated, zero-risk plan", flags: true, run: runRecommend},
+		{name: "undo", aliases: []string{"restore"}, summary: "Restore the last cleanup (or a session ID, --all, --since 1h) from trash", flags: true, run: runUndo, usage: []string{
+			"burrow undo [session-id] [--dry-run] [--yes]",
+			"burrow undo --all | --since <period> [--dry-run] [--yes]",
+		}},
+		{name: "trash", summary: "List, show, repair, or purge trash sessions", run: runTrash, usage: []string{
+			"burrow trash [list]",
+			"burrow trash show <id>",
+			"burrow trash repair [id...]",
+			"burrow trash purge <id> [--yes]",
+		}},
+		{name: "list", summary: "List all detected files", flags: true, run: runList},
+		{name: "rules", aliases: []string{"rule"}, summary: "List all cleanup rules (rules search <keyword>, rules test <name>, rules validate)", flags: true, run: runRules, usage: []string{
+			"burrow rules [--explain <name>] [--json]",
+			"burrow rules search <keyword> [--json]",
+			"burrow rules test <name> [--json]",
+			"burrow rules categories [--json] [--names]",
+			"burrow rules sources",
+			"burrow rules add-source <git-url>",
+			"burrow rules validate [file...]",
+		}},
+		{name: "snooze", summary: "Hide a rule or path from suggestions for a while", flags: true, run: runSnooze, usage: []string{
+			"burrow snooze <rule|path> --for 30d",
+			"burrow snooze list",
+			"burrow snooze clear <rule|path> | --all",
+		}},
+		{name: "stats", summary: "Show disk reclaimable stats", flags: true, run: runStats},
+		{name: "status", summary: "Show cached reclaimable space (--bitbar for menu bar)", flags: true, run: runStatus},
+		{name: "history", summary: "Show cleanup history (history show <id>, --search <text>)", flags: true, run: runHistory, usage: []string{
+			"burrow history [--search <text>]",
+			"burrow history show <id>",
+		}},
+		{name: "report", summary: "Print a weekly digest (--period, --format markdown)", flags: true, run: runReport},
+		{name: "config", diagnoses: true, summary: "Check the config file (config validate)", run: runConfig, usage: []string{
+			"burrow config validate [file]",
+		}},
+		{name: "schema", diagnoses: true, summary: "Print the JSON Schema of config or rule files", run: runSchema, usage: []string{
+			"burrow schema print config|rules",
+		}},
+		{name: "doctor", diagnoses: true, summary: "Check system health and permissions", run: func([]string) error { return runDoctor() }, usage: []string{
+			"burrow doctor",
+		}},
+		{name: "verify", diagnoses: true, summary: "Audit trash, history, config, and rules (--repair to fix)", flags: true, run: runVerify},
+		{name: "simulate", summary: "Run scan, clean, and undo against a fixture home (--fixture DIR)", flags: true, run: runSimulate},
+		{name: "serve", summary: "Run the local HTTP JSON API", flags: true, run: runServe},
+		{name: "watch", summary: "Scan periodically in the foreground", flags: true, run: runWatch},
+		{name: "service", summary: "Manage the launchd agent running 'watch'", run: runService, usage: []string{
+			"burrow service install [watch flags]",
+			"burrow service uninstall|start|stop|status",
+		}},
+		{name: "version", diagnoses: true, summary: "Show version information", run: func([]string) error { return runVersion() }, usage: []string{
+			"burrow version",
+		}},
+	}
+}
+
+// globalJSON is set by --json anywhere on the command line.
+var globalJSON bool
+
+// findCommand returns the command with the given name or alias, or nil. A
+// plural or singular slip ("rule", "stat") finds the command too.
+func findCommand(name string) *command {
+	for _, n := range []string{name, strings.TrimSuffix(name, "s"), name + "s"} {
+		for i := range commands {
+			if commands[i].name == n || slices.Contains(commands[i].aliases, n) {
+				return &commands[i]
+			}
+		}
+	}
+	return nil
+}
+
+// unknownCommand reports a command that doesn't exist, suggesting the
+// closest one.
+func unknownCommand(name string) error {
+	var names []string
+	for _, c := range commands {
+		names = append(names, c.name)
+		names = append(names, c.aliases...)
+	}
+	if s := closest(name, names); s != "" {
+		return fmt.Errorf("unknown command: %s. Did you mean 'burrow %s'?", name, findCommand(s).name)
+	}
+	return fmt.Errorf("unknown command: %s. Run 'burrow help' for usage", name)
+}
+
+// wantsHelp reports whether args ask for help before any "--".
+func wantsHelp(args []string) bool {
+	for _, a := range args {
+		switch a {
+		case "--":
+			return false
+		case "-h", "-help", "--help":
+			return true
+		}
+	}
+	return false
+}
+
+// runHelp prints the usage of burrow, or of the command named in args.
+func runHelp(args []string) error {
+	if len(args) == 0 {
+		printUsage()
+		return nil
+	}
+	c := findCommand(args[0])
+	if c == nil {
+		return unknownCommand(args[0])
+	}
+	if !c.flags {
+		printCommandHelp(c)
+		return nil
+	}
+	// The command prints its flags when parsing -h
+	return c.run(append(args[1:], "-h"))
+}
+
+func printCommandHelp(c *command) {
+	fmt.Printf("%sburrow %s%s %s %s\n", Bold, c.name, Reset, Symbol("—", "-"), c.summary)
+	fmt.Println("\n" + Bold + "Usage:" + Reset)
+	usage := c.usage
+	if len(usage) == 0 {
+		usage = []string{"burrow " + c.name + " [flags]"}
+	}
+	for _, u := range usage {
+		fmt.Println("  " + u)
+	}
+	if len(c.aliases) > 0 {
+		fmt.Println("\n" + Bold + "Aliases:" + Reset + " " + strings.Join(c.aliases, ", "))
+	}
+}
+
+// parseFlags parses a command's flags, which may also follow its arguments.
+// It applies the global --json, prints help for -h (returning
+// flag.ErrHelp), and suggests the closest flag for a mistyped one.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	fs.SetOutput(io.Discard)
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				printFlagHelp(fs)
+				return err
+			}
+			return flagError(fs, err)
+		}
+		rest := fs.Args()
+		if len(rest) == 0 {
+			break
+		}
+		if n := len(args) - len(rest); n > 0 && args[n-1] == "--" {
+			positional = append(positional, rest...)
+			break
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+	// Leave the arguments where fs.Args expects them
+	fs.Parse(append([]string{"--"}, positional...))
+
+	if globalJSON {
+		f := fs.Lookup("json")
+		if f == nil {
+			return fmt.Errorf("burrow %s has no JSON output", fs.Name())
+		}
+		f.Value.Set("true")
+	}
+	return nil
+}
+
+func printFlagHelp(fs *flag.FlagSet) {
+	if c := findCommand(fs.Name()); c != nil {
+		printCommandHelp(c)
+	} else {
+		fmt.Println(Bold + "Usage:" + Reset)
+		fmt.Printf("  burrow %s [flags]\n", fs.Name())
+	}
+	fmt.Println("\n" + Bold + "Flags:" + Reset)
+	fs.SetOutput(os.Stdout)
+	fs.PrintDefaults()
+}
+
+// flagError explains a flag parsing error, suggesting the closest defined
+// flag for an unknown one.
+func flagError(fs *flag.FlagSet, err error) error {
+	name, unknown := strings.CutPrefix(err.Error(), "flag provided but not defined: ")
+	if !unknown {
+		return fmt.Errorf("%w (see 'burrow help %s')", err, fs.Name())
+	}
+	name = strings.TrimLeft(name, "-")
+
+	var names []string
+	fs.VisitAll(func(f *flag.Flag) { names = append(names, f.Name) })
+	if s := closest(name, names); s != "" {
+		return fmt.Errorf("unknown flag --%s for burrow %s; did you mean --%s?", name, fs.Name(), s)
+	}
+	return fmt.Errorf("unknown flag --%s for burrow %s (see 'burrow help %s')", name, fs.Name(), fs.Name())
+}
+
+// closest returns the candidate nearest to word, if it is close enough to be
+// a likely typo.
+func closest(word string, candidates []string) string {
+	best, bestDist := "", len(word)/3+2
+	for _, c := range candidates {
+		if d := levenshtein(strings.ToLower(word), strings.ToLower(c)); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+
+	"github.com/ismailtsdln/burrow/internal/cleaner"
+)
+
+// Exit statuses, so scripts can branch on what a command found or did.
+const (
+	ExitOK        = 0 // success; a scan found nothing to clean
+	ExitError     = 1 // the command failed
+	ExitFound     = 2 // a scan or cleanup preview found candidates
+	ExitPartial   = 3 // some items were cleaned or restored, others failed
+	ExitCancelled = 4 // the user declined a prompt or authentication
+)
+
+// Outcomes commands return instead of nil so the exit status reports them.
+var (
+	ErrCandidatesFound = errors.New("cleanup candidates found")
+	ErrPartial         = errors.New("partial failure")
+	ErrCancelled       = errors.New("cancelled")
+)
+
+// partialError is a partial failure with its details.
+type partialError struct {
+	err error
+}
+
+func (e *partialError) Error() string { return e.err.Error() }
+
+func (e *partialError) Unwrap() error { return e.err }
+
+func (e *partialError) Is(target error) bool { return target == ErrPartial }
+
+// partialf formats a partial failure.
+func partialf(format string, args ...any) error {
+	return &partialError{err: fmt.Errorf(format, args...)}
+}
+
+// ExitCode maps an error returned by Execute to the process exit status.
+func ExitCode(err error) int {
+	var pe *cleaner.PartialError
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, ErrCancelled):
+		return ExitCancelled
+	case errors.Is(err, ErrPartial), errors.As(err, &pe):
+		return ExitPartial
+	case errors.Is(err, ErrCandidatesFound):
+		return ExitFound
+	default:
+		return ExitError
+	}
+}
+
+// Reportable reports whether err is worth printing. Found candidates,
+// cancellations, and interruptions are outcomes the command's output already
+// explains, as is a bare ErrPartial passed on from a re-executed burrow.
+func Reportable(err error) bool {
+	return err != nil && err != ErrPartial && !errors.Is(err, ErrCandidatesFound) && !errors.Is(err, ErrCancelled) && !errors.Is(err, cleaner.ErrInterrupted)
+}
+
+// candidates returns ErrCandidatesFound when a scan found n > 0 candidates.
+func candidates(n int) error {
+	if n > 0 {
+		return ErrCandidatesFound
+	}
+	return nil
+}
+
+// printReclaimed reports the space a cleanup reclaimed; err is a partial
+// failure when it stopped early.
+func printReclaimed(res *cleaner.CleanResult, err error) {
+	switch {
+	case err == nil:
+		PrintSuccess("Successfully reclaimed %s!", FormatSize(res.ReclaimedSpace))
+	case errors.Is(err, cleaner.ErrInterrupted):
+		PrintWarning("Cleanup interrupted; reclaimed %s before it stopped.", FormatSize(res.ReclaimedSpace))
+	default:
+		PrintWarning("Cleanup stopped early; reclaimed %s before it failed.", FormatSize(res.ReclaimedSpace))
+	}
+}
+
+// childOutcome maps the exit status of a re-executed burrow, such as the
+// elevated system cleanup, back to the outcome it reported.
+func childOutcome(err error) error {
+	var ee *exec.ExitError
+	if !errors.As(err, &ee) {
+		return err
+	}
+	switch ee.ExitCode() {
+	case ExitFound:
+		return ErrCandidatesFound
+	case ExitPartial:
+		return ErrPartial
+	case ExitCancelled:
+		return ErrCancelled
+	}
+	return err
+}
@@ -0,0 +1,223 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/snapshot"
+	"github.com/ismailtsdln/burrow/internal/units"
+	"github.com/ismailtsdln/burrow/internal/usage"
+)
+
+const chartWidth = 40
+
+func runStatsHistory(js bool, category string, limit int) error {
+	snapshots, err := snapshot.NewManager().Load()
+	if err != nil {
+		return err
+	}
+
+	if limit > 0 && len(snapshots) > limit {
+		snapshots = snapshots[len(snapshots)-limit:]
+	}
+
+	if js {
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"snapshots": snapshots,
+			"growth":    snapshot.CategoryGrowth(snapshots),
+		}, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(snapshots) < 2 {
+		fmt.Println("Not enough scan snapshots yet. Run 'burrow scan' a few times (or the watch service) to track growth.")
+		return nil
+	}
+
+	value := func(s snapshot.Snapshot) int64 {
+		if category != "" {
+			return s.Categories[category]
+		}
+		return s.TotalBytes
+	}
+
+	var max int64
+	for _, s := range snapshots {
+		if v := value(s); v > max {
+			max = v
+		}
+	}
+
+	title := "Reclaimable Space Over Time"
+	if category != "" {
+		title += " " + Symbol("—", "-") + " " + category
+	}
+	PrintHeader(title)
+	fmt.Println(Gray + strings.Repeat("-", 75) + Reset)
+	for _, s := range snapshots {
+		v := value(s)
+		bar := 0
+		if max > 0 {
+			bar = int(v * chartWidth / max)
+		}
+		fmt.Printf("%-17s %-12s %s\n",
+			s.Timestamp.Format("2006-01-02 15:04"),
+			FormatSize(v),
+			Colorize(Green, strings.Repeat(Symbol("█", "#"), bar)),
+		)
+	}
+	fmt.Println(Gray + strings.Repeat("-", 75) + Reset)
+
+	if category != "" {
+		return nil
+	}
+
+	PrintHeader(fmt.Sprintf("%-30s %-15s %s", "CATEGORY", "NOW", "CHANGE"))
+	for _, g := range snapshot.CategoryGrowth(snapshots) {
+		change := Colorize(Gray, "±0 B")
+		if g.Delta > 0 {
+			change = Colorize(Red, "+"+FormatSize(g.Delta))
+		} else if g.Delta < 0 {
+			change = Colorize(Green, "-"+FormatSize(-g.Delta))
+		}
+		fmt.Printf("%-30s %-15s %s\n", Colorize(Blue, g.Category), FormatSize(g.Last), change)
+	}
+
+	if growth := snapshot.CategoryGrowth(snapshots); len(growth) > 0 && growth[0].Delta > 0 {
+		fmt.Printf("\nWorst offender: %s (+%s since %s)\n",
+			Bold+growth[0].Category+Reset,
+			FormatSize(growth[0].Delta),
+			snapshots[0].Timestamp.Format("2006-01-02"),
+		)
+	}
+	return nil
+}
+
+// runStatsProjection estimates how quickly each category regrows, to help
+// decide whether cleaning it is worth the rebuild.
+func runStatsProjection(js bool, category string) error {
+	snapshots, err := snapshot.NewManager().Load()
+	if err != nil {
+		return err
+	}
+
+	projections := snapshot.Project(snapshots)
+	if category != "" {
+		projections = slices.DeleteFunc(projections, func(p snapshot.Projection) bool { return p.Category != category })
+	}
+
+	if js {
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"snapshots":   len(snapshots),
+			"projections": projections,
+		}, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(snapshots) < 2 || snapshots[len(snapshots)-1].Timestamp.Sub(snapshots[0].Timestamp) < snapshot.MinProjectionSpan {
+		fmt.Println("Not enough scan history yet. Projections need snapshots spanning at least a day; run 'burrow scan' regularly or the watch service.")
+		return nil
+	}
+	if len(projections) == 0 {
+		fmt.Println("Nothing has regrown since the first snapshot.")
+		return nil
+	}
+
+	PrintHeader(fmt.Sprintf("%-30s %-12s %-16s %s", "CATEGORY", "NOW", "REGROWS", "BACK TO NOW IN"))
+	fmt.Println(Gray + strings.Repeat("-", 75) + Reset)
+	for _, p := range projections {
+		fmt.Printf("%-30s %-12s %-16s %s\n",
+			Colorize(Blue, p.Category),
+			FormatSize(p.Current),
+			"~"+FormatSize(p.PerWeek)+"/week",
+			formatRefill(p.Refill()),
+		)
+	}
+	fmt.Println(Gray + strings.Repeat("-", 75) + Reset)
+	first := snapshots[0].Timestamp
+	fmt.Printf("Based on %d snapshots since %s. Categories that refill within days are rarely worth cleaning often.\n",
+		len(snapshots), first.Format("2006-01-02"))
+	return nil
+}
+
+// formatRefill renders a refill estimate in days, weeks, or months.
+func formatRefill(d time.Duration) string {
+	switch {
+	case d == 0:
+		return Colorize(Gray, "-")
+	case d < units.Day:
+		return "under a day"
+	case d < 2*units.Week:
+		return fmt.Sprintf("~%d days", int(d/units.Day))
+	case d < 2*units.Month:
+		return fmt.Sprintf("~%d weeks", int(d/units.Week))
+	default:
+		return fmt.Sprintf("~%d months", int(d/units.Month))
+	}
+}
+
+// equivalents put a lifetime total in familiar terms, largest first.
+var equivalents = []struct {
+	name string
+	size int64
+}{
+	{"Blu-ray discs", 25 * units.GB},
+	{"DVDs", 4700 * units.MB},
+	{"CDs", 700 * units.MB},
+	{"MP3 songs", 4 * units.MB},
+	{"floppy disks", 1440 * units.KB},
+}
+
+// runStatsLifetime shows the local usage totals since Burrow was first used.
+func runStatsLifetime(js bool) error {
+	stats, err := usage.NewManager().Load()
+	if err != nil {
+		return err
+	}
+	top := stats.TopRules(5)
+
+	if js {
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"lifetime":  stats,
+			"top_rules": top,
+		}, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if stats.Scans == 0 && stats.Cleanups == 0 {
+		fmt.Println("No usage recorded yet. Run 'burrow scan' and 'burrow clean' to start counting.")
+		return nil
+	}
+
+	PrintHeader("Lifetime Stats")
+	fmt.Printf("Total space reclaimed since %s: %s\n",
+		stats.Since.Format("Jan 2, 2006"), Bold+Colorize(Green, FormatSize(stats.ReclaimedBytes))+Reset)
+	for _, e := range equivalents {
+		if n := stats.ReclaimedBytes / e.size; n >= 1 {
+			fmt.Printf("That's about %d %s worth of space.\n", n, e.name)
+			break
+		}
+	}
+	fmt.Println()
+	fmt.Printf("%-16s %d\n", "Scans:", stats.Scans)
+	fmt.Printf("%-16s %d (%d items)\n", "Cleanups:", stats.Cleanups, stats.ItemsCleaned)
+	fmt.Printf("%-16s %d\n", "Undos:", stats.Undos)
+	if !stats.LastCleanup.IsZero() {
+		fmt.Printf("%-16s %s\n", "Last cleanup:", formatAgo(stats.LastCleanup))
+	}
+
+	if len(top) > 0 {
+		PrintHeader(fmt.Sprintf("%-30s %-10s %s", "MOST-USED RULES", "CLEANUPS", "RECLAIMED"))
+		for _, r := range top {
+			fmt.Printf("%-30s %-10d %s\n", r.Rule, r.Cleanups, FormatSize(r.ReclaimedBytes))
+		}
+	}
+	fmt.Println(Gray + "\nThese stats stay on this machine; nothing is ever sent anywhere." + Reset)
+	return nil
+}
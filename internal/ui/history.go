@@ -0,0 +1,183 @@
+package ui
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ismailtsdln/burrow/internal/cleaner"
+	"github.com/ismailtsdln/burrow/internal/history"
+)
+
+func runHistory(args []string) error {
+	if len(args) > 0 && args[0] == "show" {
+		if len(args) != 2 {
+			return fmt.Errorf("usage: burrow history show <id>")
+		}
+		return runHistoryShow(args[1])
+	}
+
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	search := fs.String("search", "", "Only cleanups whose note, session ID, or rules contain this text")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unknown history command %q (use 'burrow history' or 'burrow history show <id>')", fs.Arg(0))
+	}
+
+	entries, err := history.NewManager().Load()
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No history found. Start cleaning to build history!")
+		return nil
+	}
+	if *search != "" {
+		entries = searchHistory(entries, *search)
+		if len(entries) == 0 {
+			fmt.Printf("No cleanups match %q.\n", *search)
+			return nil
+		}
+	}
+
+	tm := cleaner.NewTrashManager()
+	PrintHeader("Cleanup History")
+	fmt.Printf(Gray+"%-20s %-15s %-10s %-20s %s"+Reset+"\n", "DATE", "RECLAIMED", "FILES", "SESSION ID", "STATE")
+	fmt.Println(Gray + strings.Repeat("-", 90) + Reset)
+
+	for _, e := range entries {
+		fmt.Printf("%-20s %-15s %-10d %s %s\n",
+			e.Timestamp.Format("2006-01-02 15:04"),
+			Colorize(Green, FormatSize(e.ReclaimedBytes)),
+			e.FileCount,
+			Colorize(Cyan, fmt.Sprintf("%-20s", e.ID)),
+			formatSessionState(tm.SessionState(e)),
+		)
+		if e.Note != "" {
+			fmt.Printf("  %s\n", Colorize(Gray, Symbol("↳ ", "note: ")+e.Note))
+		}
+	}
+	return nil
+}
+
+// searchHistory returns the entries whose note, session ID, or rules
+// contain query, ignoring case.
+func searchHistory(entries []history.Entry, query string) []history.Entry {
+	query = strings.ToLower(query)
+	var found []history.Entry
+	for _, e := range entries {
+		fields := append([]string{e.Note, e.ID}, e.Rules...)
+		if slices.ContainsFunc(fields, func(f string) bool { return strings.Contains(strings.ToLower(f), query) }) {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// runHistoryShow lists what one cleanup session touched and what is left of
+// it in the trash.
+func runHistoryShow(id string) error {
+	e, err := history.NewManager().Find(id)
+	if err != nil {
+		return err
+	}
+	tm := cleaner.NewTrashManager()
+	state := tm.SessionState(*e)
+
+	PrintHeader("Cleanup " + e.ID)
+	fmt.Printf("%-12s %s (%s)\n", "Date:", e.Timestamp.Format("2006-01-02 15:04"), formatAgo(e.Timestamp))
+	if e.Note != "" {
+		fmt.Printf("%-12s %s\n", "Note:", e.Note)
+	}
+	fmt.Printf("%-12s %s in %d item(s)\n", "Reclaimed:", FormatSize(e.ReclaimedBytes), e.FileCount)
+	fmt.Printf("%-12s %s\n", "State:", formatSessionState(state))
+	if e.Restored > 0 {
+		fmt.Printf("%-12s %d item(s) by undo\n", "Restored:", e.Restored)
+	}
+	switch {
+	case state == cleaner.SessionFinalized:
+		fmt.Printf("%-12s %s, when its undo window closed\n", "Finalized:", e.Finalized.Format("2006-01-02 15:04"))
+	case state == cleaner.SessionRestorable || state == cleaner.SessionPartial:
+		if until := cleaner.ExpiresAt(e.Timestamp, undoWindow()); !until.IsZero() {
+			fmt.Printf("%-12s %s\n", "Undo until:", until.Format("2006-01-02 15:04"))
+		}
+	}
+
+	if len(e.CategoryStats) > 0 {
+		fmt.Println("\n" + Bold + "Categories" + Reset)
+		cats := make([]string, 0, len(e.CategoryStats))
+		for c := range e.CategoryStats {
+			cats = append(cats, c)
+		}
+		sort.Slice(cats, func(i, j int) bool { return e.CategoryStats[cats[i]] > e.CategoryStats[cats[j]] })
+		for _, c := range cats {
+			fmt.Printf("  %-30s %s\n", c, FormatSize(e.CategoryStats[c]))
+		}
+	}
+	if len(e.Rules) > 0 {
+		fmt.Println("\n" + Bold + "Rules" + Reset)
+		for _, r := range e.Rules {
+			fmt.Println("  " + r)
+		}
+	}
+
+	// The manifest says which items are still in the trash; older entries
+	// that predate recorded paths are listed from it alone
+	inTrash := make(map[string]bool)
+	paths := e.Paths
+	if manifest, err := tm.Manifest(e.ID); err == nil {
+		for _, entry := range manifest.Entries {
+			if _, err := os.Lstat(entry.TrashPath); err == nil {
+				inTrash[entry.OriginalPath] = true
+			}
+			if len(e.Paths) == 0 {
+				paths = append(paths, entry.OriginalPath)
+			}
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	fmt.Println("\n" + Bold + "Paths" + Reset)
+	trashed := state == cleaner.SessionRestorable || state == cleaner.SessionPartial
+	for _, p := range paths {
+		switch {
+		case !trashed:
+			fmt.Println("  " + p)
+		case stillTrashed(p, inTrash):
+			fmt.Printf("  %s %s\n", p, Colorize(Gray, "(in trash)"))
+		default:
+			fmt.Printf("  %s %s\n", p, Colorize(Gray, "(restored)"))
+		}
+	}
+	return nil
+}
+
+// stillTrashed reports whether path, or part of it when it was trashed file
+// by file, is still in the trash.
+func stillTrashed(path string, inTrash map[string]bool) bool {
+	for p := range inTrash {
+		if p == path || strings.HasPrefix(p, path+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatSessionState(s cleaner.SessionState) string {
+	switch s {
+	case cleaner.SessionRestorable:
+		return Colorize(Green, string(s))
+	case cleaner.SessionPartial, cleaner.SessionOrphaned:
+		return Colorize(Yellow, string(s))
+	default:
+		return Colorize(Gray, string(s))
+	}
+}
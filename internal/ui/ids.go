@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ismailtsdln/burrow/internal/config"
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/scanner"
+)
+
+// listedResults returns the results that 'burrow scan' last listed under
+// the given IDs, e.g. "1,3,5-7".
+func listedResults(cfg *config.Config, ids string) (*scanner.ScanResults, error) {
+	listing, err := scanner.LoadListing()
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("--ids refers to the last 'burrow scan', and there is none yet")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the last scan: %w", err)
+	}
+	if err := listing.Validate(cacheTTL(cfg)); err != nil {
+		return nil, fmt.Errorf("the IDs of the last scan no longer apply (%v); run 'burrow scan' again", err)
+	}
+
+	listed := listing.Results.Results
+	indices, err := parseIDs(ids, len(listed))
+	if err != nil {
+		return nil, err
+	}
+
+	selected := &scanner.ScanResults{Results: make([]rules.Result, 0, len(indices))}
+	for _, i := range indices {
+		selected.Results = append(selected.Results, listed[i])
+		selected.TotalSize += listed[i].TotalSize
+	}
+
+	changed := make(map[string]bool)
+	for _, name := range listing.StaleRules(rules.NewRegistry()) {
+		changed[name] = true
+	}
+	for _, res := range selected.Results {
+		if changed[res.Rule.Name] {
+			PrintWarning("%s changed since the scan (%s); its size may differ.", res.Rule.Name, formatAgo(listing.Timestamp))
+		}
+	}
+	return selected, nil
+}
+
+// parseIDs turns "1, 3, 5-7" into sorted zero-based indices below n. Unlike
+// the interactive prompt, it rejects anything it does not understand, since
+// a script should not clean something other than what it asked for.
+func parseIDs(input string, n int) ([]int, error) {
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		first, last, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(strings.TrimSpace(first))
+		end := start
+		if err == nil && isRange {
+			end, err = strconv.Atoi(strings.TrimSpace(last))
+		}
+		if err != nil || start < 1 || end < start {
+			return nil, fmt.Errorf("invalid ID %q (use e.g. 1,3,5-7)", part)
+		}
+		if end > n {
+			return nil, fmt.Errorf("ID %d is out of range; the last scan listed %d result(s)", end, n)
+		}
+		for i := start; i <= end; i++ {
+			seen[i-1] = true
+		}
+	}
+
+	indices := make([]int, 0, len(seen))
+	for i := range seen {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	return indices, nil
+}
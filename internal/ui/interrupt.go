@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ismailtsdln/burrow/internal/cleaner"
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+// trapInterrupts returns a channel that is closed on the first SIGINT or
+// SIGTERM, so a cleanup or purge can stop at a consistent point and report
+// what it did. A second signal quits at once; the trash journal covers
+// that. Call release when the work is done.
+func trapInterrupts() (stop <-chan struct{}, release func()) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	stopped := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-sigs:
+			signal.Stop(sigs)
+			fmt.Fprintln(os.Stderr, "\n"+Colorize(Yellow, "Stopping after the items in progress; press Ctrl-C again to quit now."))
+			close(stopped)
+		case <-done:
+		}
+	}()
+	return stopped, func() {
+		signal.Stop(sigs)
+		close(done)
+	}
+}
+
+// cleanInterruptibly runs the cleanup of results, stopping it cleanly on
+// SIGINT or SIGTERM. An interrupted cleanup returns what it cleaned with a
+// partial error; one interrupted before removing anything is cancelled.
+func cleanInterruptibly(c *cleaner.Cleaner, results []rules.Result, permanent bool) (*cleaner.CleanResult, error) {
+	stop, release := trapInterrupts()
+	defer release()
+	c.Stop = stop
+
+	res, err := c.Clean(results, false, permanent)
+	if res == nil && errors.Is(err, cleaner.ErrInterrupted) {
+		PrintWarning("Cleanup interrupted; nothing was cleaned.")
+		return nil, ErrCancelled
+	}
+	return res, err
+}
@@ -0,0 +1,284 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+// multiSelectHelp lists the keys of the multi-select list.
+const multiSelectHelp = "↑/↓ move · space toggle · a all · / filter · n never suggest · enter clean · q quit"
+
+// selector is the state of the multi-select list: which results are visible
+// under the filter, which are selected, and where the cursor is.
+type selector struct {
+	items     []rules.Result
+	visible   []int // Indices into items
+	cursor    int   // Index into visible
+	filter    string
+	filtering bool
+	selected  map[int]bool
+	dismissed map[int]bool
+	done      bool
+	cancelled bool
+}
+
+func newSelector(items []rules.Result) *selector {
+	s := &selector{items: items, selected: map[int]bool{}, dismissed: map[int]bool{}}
+	s.refilter()
+	return s
+}
+
+// refilter recomputes the visible results, keeping the cursor in range.
+func (s *selector) refilter() {
+	s.visible = s.visible[:0]
+	for i, res := range s.items {
+		if !s.dismissed[i] && matchesFilter(res, s.filter) {
+			s.visible = append(s.visible, i)
+		}
+	}
+	s.cursor = min(s.cursor, max(0, len(s.visible)-1))
+}
+
+// matchesFilter fuzzy-matches filter against the rule name, category, and
+// paths of res.
+func matchesFilter(res rules.Result, filter string) bool {
+	if filter == "" {
+		return true
+	}
+	if fuzzyMatch(filter, res.Rule.Name) || fuzzyMatch(filter, res.Rule.Category) {
+		return true
+	}
+	for _, p := range res.FoundPaths {
+		if fuzzyMatch(filter, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// fuzzyMatch reports whether the characters of pattern appear in text in
+// order, ignoring case, so "dd" matches "DerivedData".
+func fuzzyMatch(pattern, text string) bool {
+	text = strings.ToLower(text)
+	for _, r := range strings.ToLower(pattern) {
+		i := strings.IndexRune(text, r)
+		if i < 0 {
+			return false
+		}
+		text = text[i+len(string(r)):]
+	}
+	return true
+}
+
+// handle applies one key.
+func (s *selector) handle(key string) {
+	if s.filtering {
+		switch key {
+		case "enter", "esc":
+			s.filtering = false
+		case "backspace":
+			if r := []rune(s.filter); len(r) > 0 {
+				s.filter = string(r[:len(r)-1])
+				s.refilter()
+			}
+		case "ctrl-c":
+			s.cancelled = true
+		case "up", "down":
+			s.move(key)
+		default:
+			if len([]rune(key)) == 1 {
+				s.filter += key
+				s.refilter()
+			}
+		}
+		return
+	}
+
+	switch key {
+	case "up", "down", "k", "j":
+		s.move(key)
+	case " ":
+		if len(s.visible) > 0 {
+			i := s.visible[s.cursor]
+			s.selected[i] = !s.selected[i]
+		}
+	case "a":
+		// Select every visible result, or clear them when all are selected
+		all := true
+		for _, i := range s.visible {
+			all = all && s.selected[i]
+		}
+		for _, i := range s.visible {
+			s.selected[i] = !all
+		}
+	case "/":
+		s.filtering = true
+	case "esc":
+		s.filter = ""
+		s.refilter()
+	case "n":
+		if len(s.visible) > 0 {
+			i := s.visible[s.cursor]
+			s.dismissed[i] = true
+			delete(s.selected, i)
+			s.refilter()
+		}
+	case "enter":
+		s.done = true
+	case "q", "ctrl-c":
+		s.cancelled = true
+	}
+}
+
+func (s *selector) move(key string) {
+	switch {
+	case (key == "up" || key == "k") && s.cursor > 0:
+		s.cursor--
+	case (key == "down" || key == "j") && s.cursor < len(s.visible)-1:
+		s.cursor++
+	}
+}
+
+// total returns the number and combined size of the selected results.
+func (s *selector) total() (int, int64) {
+	var n int
+	var size int64
+	for i, res := range s.items {
+		if s.selected[i] {
+			n++
+			size += res.TotalSize
+		}
+	}
+	return n, size
+}
+
+// render draws the list, scrolled to keep the cursor within height rows.
+func (s *selector) render(w io.Writer, height int) {
+	var b strings.Builder
+	b.WriteString("\x1b[H\x1b[2J")
+	fmt.Fprintf(&b, "%s\n%s\n", Bold+"Interactive Cleanup Selection"+Reset, Colorize(Gray, multiSelectHelp))
+	switch {
+	case s.filtering:
+		fmt.Fprintf(&b, "Filter: /%s█\n", s.filter)
+	case s.filter != "":
+		fmt.Fprintf(&b, "Filter: /%s %s\n", s.filter, Colorize(Gray, "(esc clears)"))
+	default:
+		b.WriteString("\n")
+	}
+
+	if height <= 0 {
+		height = 24
+	}
+	rows := max(3, height-5)
+	first := max(0, min(s.cursor-rows/2, len(s.visible)-rows))
+	if len(s.visible) == 0 {
+		b.WriteString(Colorize(Gray, "  No results match the filter.") + "\n")
+	}
+	for pos := first; pos < len(s.visible) && pos < first+rows; pos++ {
+		i := s.visible[pos]
+		res := s.items[i]
+		pointer, box := "  ", "[ ]"
+		if pos == s.cursor {
+			pointer = Colorize(Cyan, "> ")
+		}
+		if s.selected[i] {
+			box = Colorize(Green, "[x]")
+		}
+		fmt.Fprintf(&b, "%s%s %-4d %-22s %-12s %s\n", pointer, box, i+1, Colorize(Blue, categoryLabel(truncate(res.Rule.Category, 20))), Colorize(Yellow, FormatSize(res.TotalSize)), res.Rule.Name)
+	}
+
+	n, size := s.total()
+	fmt.Fprintf(&b, "\n"+Bold+"Selected: %d item(s), %s"+Reset, n, Colorize(Green, FormatSize(size)))
+	io.WriteString(w, b.String())
+}
+
+// truncate shortens s to n runes.
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
+
+// readKey reads one key press, naming arrows and control keys.
+func readKey(r *bufio.Reader) (string, error) {
+	c, _, err := r.ReadRune()
+	if err != nil {
+		return "", err
+	}
+	switch c {
+	case '\r', '\n':
+		return "enter", nil
+	case 127, 8:
+		return "backspace", nil
+	case 3:
+		return "ctrl-c", nil
+	case 27:
+		// Arrow keys arrive as one write; a lone escape has nothing after it
+		if r.Buffered() == 0 {
+			return "esc", nil
+		}
+		if next, _ := r.ReadByte(); next != '[' && next != 'O' {
+			return "esc", nil
+		}
+		switch code, _ := r.ReadByte(); code {
+		case 'A':
+			return "up", nil
+		case 'B':
+			return "down", nil
+		}
+		return "", nil
+	}
+	return string(c), nil
+}
+
+// multiSelect lets the user pick results from a full-screen list. ok is
+// false when the terminal can't support it and the caller should prompt for
+// IDs instead; otherwise it returns the chosen results and those to stop
+// suggesting, both nil when the user quit.
+func multiSelect(items []rules.Result) (selected, dismissed map[int]bool, ok bool) {
+	if plain || !isTerminal(os.Stdin) || !isTerminal(os.Stdout) {
+		return nil, nil, false
+	}
+	if term := os.Getenv("TERM"); term == "" || term == "dumb" {
+		return nil, nil, false
+	}
+	restore, err := rawMode(os.Stdin)
+	if err != nil {
+		return nil, nil, false
+	}
+
+	// Draw on the alternate screen so the scan output is intact afterwards
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	defer func() {
+		fmt.Print("\x1b[?25h\x1b[?1049l")
+		restore()
+	}()
+
+	s := newSelector(items)
+	in := bufio.NewReader(os.Stdin)
+	for !s.done && !s.cancelled {
+		s.render(os.Stdout, terminalHeight(os.Stdout))
+		key, err := readKey(in)
+		if err != nil {
+			s.cancelled = true
+			break
+		}
+		s.handle(key)
+	}
+	if s.cancelled {
+		return nil, s.dismissed, true
+	}
+	selected = make(map[int]bool)
+	for i, on := range s.selected {
+		if on {
+			selected[i] = true
+		}
+	}
+	return selected, s.dismissed, true
+}
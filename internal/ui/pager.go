@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// pager buffers long output and shows it through $PAGER when stdout is a
+// terminal, like git. Output that fits on one screen is printed directly
+// (less -F), and --no-pager or a non-TTY stdout disables paging entirely.
+type pager struct {
+	buf     bytes.Buffer
+	enabled bool
+}
+
+// newPager returns a pager; disabled pagers print straight to stdout on Flush.
+func newPager(noPager bool) *pager {
+	return &pager{enabled: !noPager && isTerminal(os.Stdout)}
+}
+
+// Write implements io.Writer.
+func (p *pager) Write(b []byte) (int, error) {
+	return p.buf.Write(b)
+}
+
+// Flush shows the buffered output and waits for the pager to exit.
+func (p *pager) Flush() {
+	defer p.buf.Reset()
+
+	cmdline := pagerCommand()
+	if !p.enabled || cmdline == "" || cmdline == "cat" {
+		io.Copy(os.Stdout, &p.buf)
+		return
+	}
+
+	fields := strings.Fields(cmdline)
+	cmd := exec.Command(fields[0], fields[1:]...)
+	cmd.Stdin = bytes.NewReader(p.buf.Bytes())
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if os.Getenv("LESS") == "" {
+		// Quit if it fits on one screen, keep colors, don't clear on exit
+		cmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+	if err := cmd.Run(); err != nil {
+		// Fall back to plain output if the pager is missing or fails to start
+		if _, ok := err.(*exec.ExitError); !ok {
+			fmt.Print(p.buf.String())
+		}
+	}
+}
+
+// pagerCommand resolves BURROW_PAGER, then PAGER, then a platform default.
+func pagerCommand() string {
+	for _, env := range []string{"BURROW_PAGER", "PAGER"} {
+		if v, ok := os.LookupEnv(env); ok {
+			return strings.TrimSpace(v)
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "more"
+	}
+	return "less"
+}
+
+// isTerminal reports whether f is attached to a character device.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
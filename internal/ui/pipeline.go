@@ -0,0 +1,239 @@
+package ui
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/config"
+	"github.com/ismailtsdln/burrow/internal/dismiss"
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/scanner"
+	"github.com/ismailtsdln/burrow/internal/units"
+	"github.com/ismailtsdln/burrow/internal/usage"
+)
+
+// scanFlags are the scan filters and tuning flags shared by scan, list,
+// stats, and clean. Registering them through addScanFlags keeps their names,
+// help text, and validation identical across commands.
+type scanFlags struct {
+	category       string
+	olderThan      string
+	risk           string
+	json           bool
+	cached         bool
+	jobs           int
+	filesPerSecond int
+	idle           bool
+	minSize        units.Size
+	sizeMode       string
+	rules          stringList
+	ruleTimeout    string
+}
+
+func addScanFlags(fs *flag.FlagSet) *scanFlags {
+	f := &scanFlags{}
+	fs.StringVar(&f.category, "category", "", "Only rules in this category (see 'burrow rules categories')")
+	fs.StringVar(&f.olderThan, "older-than", "", "Only items older than this (e.g. 30d, 2w, 3mo)")
+	fs.StringVar(&f.risk, "risk", "", "Only rules with these risk levels (e.g. safe or safe,caution)")
+	fs.Var(&f.minSize, "min-size", "Hide results smaller than this in total (e.g. 500MB)")
+	fs.BoolVar(&f.json, "json", false, "Output in JSON format")
+	fs.BoolVar(&f.cached, "cached", false, "Reuse the last scan if it is still fresh instead of rescanning")
+	fs.IntVar(&f.jobs, "jobs", 0, "Maximum rules walked at once (default: scan.max_concurrency, 0 = unlimited)")
+	fs.IntVar(&f.filesPerSecond, "files-per-second", 0, "Throttle the scan to this many files per second (default: scan.files_per_second)")
+	fs.BoolVar(&f.idle, "idle", false, "Run at idle CPU and I/O priority (default: scan.idle_priority)")
+	fs.StringVar(&f.sizeMode, "size-mode", "", "Size that drives totals and sorting: apparent or disk (default: scan.size_mode, or apparent)")
+	fs.Var(&f.rules, "rule", "Only this rule (repeatable), e.g. to retry one that timed out")
+	fs.StringVar(&f.ruleTimeout, "rule-timeout", "", "Give up on a rule's paths after this long, 0 = no limit (default: scan.rule_timeout, or 5m)")
+	return f
+}
+
+// baseScanOptions returns the scanner options implied by the configuration
+// and dismissed suggestions alone, before any command-line filters.
+func baseScanOptions(cfg *config.Config) scanner.ScanOptions {
+	opts, err := dismiss.ScanOptions(cfg)
+	if err != nil {
+		PrintWarning("Ignoring dismissed suggestions: %v", err)
+	}
+	return opts
+}
+
+// options validates the flags and turns them into scanner options.
+func (f *scanFlags) options(cfg *config.Config, registry *rules.Registry) (scanner.ScanOptions, error) {
+	opts := baseScanOptions(cfg)
+
+	if f.category != "" {
+		c, ok := registry.FindCategory(f.category)
+		if !ok {
+			return opts, fmt.Errorf("unknown category: %s (run 'burrow rules categories' to list them)", f.category)
+		}
+		opts.Category = c.Name
+	}
+
+	if f.risk != "" {
+		for _, name := range strings.Split(f.risk, ",") {
+			level, err := rules.ParseRiskLevel(name)
+			if err != nil {
+				return opts, err
+			}
+			opts.Risks = append(opts.Risks, level)
+		}
+	}
+
+	if f.olderThan != "" {
+		if f.cached {
+			return opts, fmt.Errorf("--cached cannot be combined with --older-than")
+		}
+		age, err := units.ParseDuration(f.olderThan)
+		if err != nil {
+			return opts, fmt.Errorf("invalid --older-than: %w", err)
+		}
+		opts.OlderThan = age
+	}
+
+	for _, name := range f.rules {
+		rule, ok := registry.Find(name)
+		if !ok {
+			return opts, fmt.Errorf("unknown rule %q (see 'burrow rules')", name)
+		}
+		opts.Rules = append(opts.Rules, rule.Name)
+	}
+
+	if f.ruleTimeout != "" {
+		if strings.TrimSpace(f.ruleTimeout) == "0" {
+			opts.RuleTimeout = 0
+		} else {
+			timeout, err := units.ParseDuration(f.ruleTimeout)
+			if err != nil {
+				return opts, fmt.Errorf("invalid --rule-timeout: %w", err)
+			}
+			opts.RuleTimeout = timeout
+		}
+	}
+
+	opts.MinSize = int64(f.minSize)
+
+	if f.jobs > 0 {
+		opts.MaxConcurrency = f.jobs
+	}
+	if f.filesPerSecond > 0 {
+		opts.FilesPerSecond = f.filesPerSecond
+	}
+	return opts, nil
+}
+
+// runScanPipeline is the one place commands scan from: it builds the options
+// (letting configure adjust them, e.g. for large-file mode), lowers priority
+// if asked, reuses the cache with --cached, records unfiltered scans for
+// status and growth history, and finally applies --size-mode.
+func runScanPipeline(cfg *config.Config, f *scanFlags, configure func(*scanner.ScanOptions)) (*scanner.ScanResults, error) {
+	registry := rules.NewRegistry()
+	opts, err := f.options(cfg, registry)
+	if err != nil {
+		return nil, err
+	}
+	if configure != nil {
+		configure(&opts)
+	}
+	lowerPriority(cfg, f.idle)
+
+	sizeMode := f.sizeMode
+	if sizeMode == "" {
+		sizeMode = cfg.Scan.SizeMode
+	}
+	if sizeMode, err = scanner.ParseSizeMode(sizeMode); err != nil {
+		return nil, err
+	}
+
+	s := scanner.NewScanner(registry, opts)
+	unfiltered := opts.Category == "" && len(opts.Risks) == 0 && opts.OlderThan == 0 && opts.MinSize == 0 && len(opts.Rules) == 0 && opts.RuleBased() && !opts.IncludeSystem
+
+	// The cache only ever holds unfiltered rule scans
+	if f.cached && opts.RuleBased() && !opts.IncludeSystem {
+		if results, ok := loadCachedScan(registry, cfg, f.json); ok {
+			return scanner.ApplySizeMode(s.Filter(results), sizeMode), nil
+		}
+	}
+
+	if !f.json {
+		PrintInfo("Scanning for cleanup candidates...")
+	}
+	// Unfiltered scans are cached with dismissed suggestions, which are
+	// applied when reading the cache
+	scan := s
+	if unfiltered {
+		scan = scanner.NewScanner(registry, opts.WithoutDismissals())
+	}
+	results, err := scan.Scan()
+	if err != nil {
+		return nil, err
+	}
+	usage.NewManager().RecordScan()
+	if unfiltered {
+		recordScan(results, registry)
+		results = s.Filter(results)
+	}
+	return scanner.ApplySizeMode(results, sizeMode), nil
+}
+
+// defaultCacheTTL bounds how old a scan --cached may reuse when
+// scan.cache_ttl_minutes is not set.
+const defaultCacheTTL = time.Hour
+
+// cacheTTL is how old a scan may be to stand in for a fresh one.
+func cacheTTL(cfg *config.Config) time.Duration {
+	if cfg.Scan.CacheTTLMinutes > 0 {
+		return time.Duration(cfg.Scan.CacheTTLMinutes) * time.Minute
+	}
+	return defaultCacheTTL
+}
+
+// loadCachedScan returns the last scan if it is still valid, rescanning only
+// rules whose paths changed since. Notices are suppressed in quiet (JSON) mode.
+func loadCachedScan(registry *rules.Registry, cfg *config.Config, quiet bool) (*scanner.ScanResults, bool) {
+	cached, err := scanner.LoadCache()
+	if err == nil {
+		err = cached.Validate(cacheTTL(cfg))
+	}
+	if err != nil {
+		if !quiet {
+			PrintInfo("Cached scan not usable (%v); rescanning.", err)
+		}
+		return nil, false
+	}
+
+	if !quiet {
+		PrintInfo("Using the scan from %s ago.", cached.Age().Round(time.Second))
+	}
+	stale := cached.StaleRules(registry)
+	if len(stale) == 0 {
+		return cached.Results, true
+	}
+
+	if !quiet {
+		PrintInfo("Rescanning %d rule(s) whose paths changed since: %s", len(stale), strings.Join(stale, ", "))
+	}
+	// Refresh the complete cached scan so it can be saved back unfiltered
+	full := scanner.NewScanner(registry, baseScanOptions(cfg).WithoutDismissals())
+	results, err := full.Refresh(cached.Results, stale)
+	if err != nil {
+		return nil, false
+	}
+	recordScan(results, registry)
+	return results, true
+}
+
+// withoutReportOnly drops results of report-only rules from a cleanup and
+// points at the tool that reclaims their space instead.
+func withoutReportOnly(results []rules.Result) []rules.Result {
+	var kept []rules.Result
+	for _, res := range results {
+		if res.Rule.ReportOnly {
+			PrintInfo("%s (%s) is only reported: %s", res.Rule.Name, FormatSize(res.TotalSize), res.Rule.Explanation)
+			continue
+		}
+		kept = append(kept, res)
+	}
+	return kept
+}
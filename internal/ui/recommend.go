@@ -0,0 +1,191 @@
+package ui
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/cleaner"
+	"github.com/ismailtsdln/burrow/internal/config"
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+// runRecommend ranks the scan results and offers a curated cleanup plan that
+// takes a single keystroke to apply.
+func runRecommend(args []string) error {
+	fs := flag.NewFlagSet("recommend", flag.ContinueOnError)
+	sf := addScanFlags(fs)
+	limit := fs.Int("limit", 15, "Number of recommendations to list")
+	yes := fs.Bool("yes", false, "Apply the zero-risk plan without asking")
+	useAuth := fs.Bool("auth", false, "Require biometric authentication")
+	noAuth := fs.Bool("no-auth", false, "Skip authentication (only allowed when every selected rule is Safe)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	results, err := runScanPipeline(cfg, sf, nil)
+	if err != nil {
+		return err
+	}
+	recs := cleaner.Recommend(results.Results, time.Now())
+	if *limit > 0 && len(recs) > *limit {
+		recs = recs[:*limit]
+	}
+
+	if sf.json {
+		data, _ := json.MarshalIndent(recs, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	if len(recs) == 0 {
+		fmt.Println("Nothing worth cleaning right now.")
+		return nil
+	}
+
+	PrintHeader(fmt.Sprintf("%-4s %-30s %-12s %-9s %-8s %s", "#", "RULE", "SIZE", "RISK", "REBUILD", "IDLE"))
+	fmt.Println(Gray + strings.Repeat("-", 80) + Reset)
+	for i, r := range recs {
+		rebuild := string(r.Result.Rule.RebuildCost.Level)
+		if rebuild == "" {
+			rebuild = "?"
+		}
+		fmt.Printf("%-4d %-30s %-12s %s %-8s %s\n", i+1, r.Result.Rule.Name, FormatSize(r.Result.TotalSize),
+			riskLabel(r.Result.Rule.RiskLevel), rebuild, formatIdle(r.IdleDays))
+	}
+	fmt.Println(Gray + strings.Repeat("-", 80) + Reset)
+
+	zeroRisk, rest := cleaner.SplitRecommendations(recs)
+	all := append(append([]cleaner.Recommendation{}, zeroRisk...), rest...)
+	if len(zeroRisk) > 0 {
+		fmt.Printf(Bold+"Zero-risk plan: reclaim %s"+Reset+" from %d Safe item(s) that are cheap to rebuild.\n",
+			Colorize(Green, FormatSize(recommendedSize(zeroRisk))), len(zeroRisk))
+	}
+	if len(rest) > 0 {
+		fmt.Printf("Including the other %d item(s) reclaims %s in total.\n", len(rest), FormatSize(recommendedSize(all)))
+	}
+
+	var plan []cleaner.Recommendation
+	switch {
+	case *yes:
+		if len(zeroRisk) == 0 {
+			PrintWarning("No zero-risk items to clean; run without --yes to choose the others.")
+			return nil
+		}
+		plan = zeroRisk
+	default:
+		plan = choosePlan(zeroRisk, all)
+		if plan == nil {
+			fmt.Println("Nothing cleaned.")
+			return ErrCancelled
+		}
+	}
+	return cleanRecommended(cfg, plan, *yes, *useAuth, *noAuth)
+}
+
+// choosePlan asks which plan to apply: Enter for the zero-risk plan, 'a' for
+// everything recommended.
+func choosePlan(zeroRisk, all []cleaner.Recommendation) []cleaner.Recommendation {
+	var options []string
+	if len(zeroRisk) > 0 {
+		options = append(options, "[Enter] clean the zero-risk plan")
+	}
+	if len(all) > len(zeroRisk) {
+		options = append(options, "[a] clean everything listed")
+	}
+	options = append(options, "[q] quit")
+	fmt.Printf("\n%s\n%s ", strings.Join(options, "  "), Colorize(Green, ">"))
+
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "":
+		return zeroRisk
+	case "a", "all":
+		return all
+	default:
+		return nil
+	}
+}
+
+// cleanRecommended moves the chosen recommendations to the trash, with the
+// same confirmations and authorization as 'clean --apply'.
+func cleanRecommended(cfg *config.Config, plan []cleaner.Recommendation, yes, useAuth, noAuth bool) error {
+	var toClean []rules.Result
+	for _, r := range plan {
+		toClean = append(toClean, r.Result)
+	}
+	c := cleaner.NewCleaner()
+	c.UseSystemTrash = cfg.UseSystemTrash
+	c.TrashChecksums = cfg.TrashChecksums
+	trashCap := checkTrashCap(c, cfg, toClean)
+
+	if !confirmRisk(toClean, yes, false, false) {
+		return ErrCancelled
+	}
+	if !c.UseSystemTrash {
+		proceed, permanent, err := checkTrashSpace(c, cfg, toClean, yes)
+		if err != nil || !proceed {
+			return err
+		}
+		if permanent {
+			PrintWarning("Recommendations are only cleaned to the trash; run 'burrow clean --apply --permanent' instead.")
+			return nil
+		}
+	}
+	if ok, err := confirmOverrides(toClean); err != nil || !ok {
+		return err
+	}
+	purgeOld := decideTrashCap(cfg, trashCap, yes)
+	if ok, err := authorizeCleanup(cfg, toClean, useAuth, noAuth, false); err != nil || !ok {
+		return err
+	}
+	if purgeOld {
+		if err := applyTrashCap(c, trashCap); err != nil {
+			return err
+		}
+	}
+
+	res, err := cleanInterruptibly(c, toClean, false)
+	if res == nil {
+		return err
+	}
+	notifyCleanup(cfg, "recommend", res)
+	printReclaimed(res, err)
+	if !c.UseSystemTrash {
+		fmt.Printf("Trash Session ID: %s\n", Colorize(Cyan, res.TrashSession))
+		printUndoHint(cfg, time.Now())
+	}
+	printMilestonesAndTips(cfg, res)
+	return err
+}
+
+func recommendedSize(recs []cleaner.Recommendation) int64 {
+	var total int64
+	for _, r := range recs {
+		total += r.Result.TotalSize
+	}
+	return total
+}
+
+// formatIdle renders how many days a candidate has been idle.
+func formatIdle(days int) string {
+	switch {
+	case days < 0:
+		return Colorize(Gray, "-")
+	case days == 0:
+		return "today"
+	case days < 14:
+		return fmt.Sprintf("%d days", days)
+	case days < 60:
+		return fmt.Sprintf("%d weeks", days/7)
+	default:
+		return fmt.Sprintf("%d months", days/30)
+	}
+}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/cleaner"
+	"github.com/ismailtsdln/burrow/internal/history"
+	"github.com/ismailtsdln/burrow/internal/report"
+	"github.com/ismailtsdln/burrow/internal/snapshot"
+	"github.com/ismailtsdln/burrow/internal/units"
+)
+
+// runReport prints a digest of the last period, meant to be piped into a
+// notification or an email.
+func runReport(args []string) error {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	periodName := fs.String("period", "week", "Period to cover: day, week, month, or a duration such as 2w")
+	format := fs.String("format", "text", "Output format: text or markdown")
+	js := fs.Bool("json", false, "Output in JSON format")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	period, err := reportPeriod(*periodName)
+	if err != nil {
+		return err
+	}
+	if *format != "text" && *format != "markdown" && *format != "md" {
+		return fmt.Errorf("unknown format %q (use text or markdown)", *format)
+	}
+
+	entries, err := history.NewManager().Load()
+	if err != nil {
+		return fmt.Errorf("failed to load history: %w", err)
+	}
+	snapshots, err := snapshot.NewManager().Load()
+	if err != nil {
+		return fmt.Errorf("failed to load snapshots: %w", err)
+	}
+	trash, err := cleaner.NewTrashManager().Usage()
+	if err != nil {
+		return err
+	}
+
+	d := report.Build(time.Now(), period, entries, snapshots, trash)
+	if *js {
+		data, _ := json.MarshalIndent(d, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	d.Write(os.Stdout, *format != "text")
+	return nil
+}
+
+// reportPeriod parses a named period or a duration.
+func reportPeriod(name string) (time.Duration, error) {
+	switch name {
+	case "day":
+		return units.Day, nil
+	case "week":
+		return units.Week, nil
+	case "month":
+		return units.Month, nil
+	}
+	d, err := units.ParseDuration(name)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid period %q (use day, week, month, or a duration such as 2w)", name)
+	}
+	return d, nil
+}
@@ -0,0 +1,289 @@
+package ui
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/scanner"
+	"github.com/ismailtsdln/burrow/internal/sources"
+	"github.com/ismailtsdln/burrow/internal/units"
+)
+
+// runRulesTest evaluates a single rule immediately and explains what the
+// scanner would do with each of its paths.
+func runRulesTest(args []string) error {
+	fs := flag.NewFlagSet("rules test", flag.ContinueOnError)
+	js := fs.Bool("json", false, "Output in JSON format")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	name := strings.Join(fs.Args(), " ")
+	if name == "" {
+		return fmt.Errorf("usage: burrow rules test <name>")
+	}
+
+	rule, ok := rules.NewRegistry().Find(name)
+	if !ok {
+		return fmt.Errorf("rule not found: %s", name)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	s := scanner.NewScanner(rules.NewRegistryFromRules([]rules.CleanupRule{rule}), scanner.OptionsFromConfig(cfg))
+	evals := s.Evaluate(rule)
+
+	if *js {
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"rule":  rule,
+			"paths": evals,
+		}, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	PrintHeader("Rule: " + rule.Name)
+	fmt.Printf("Category: %s   Risk: %s\n", rule.Category, rule.RiskLevel)
+	if len(rule.IncludePatterns) > 0 {
+		fmt.Printf("Include patterns: %s\n", strings.Join(rule.IncludePatterns, ", "))
+	}
+	if len(rule.ExcludePatterns) > 0 {
+		fmt.Printf("Exclude patterns: %s\n", strings.Join(rule.ExcludePatterns, ", "))
+	}
+	if age := rule.MinAgeDuration(); age > 0 {
+		fmt.Printf("Minimum age: %s\n", units.FormatDuration(age))
+	}
+	if threshold := cfg.SizeThresholdBytes(); threshold > 0 {
+		fmt.Printf("Size threshold: %s\n", FormatSize(threshold))
+	}
+	if minSize := rule.MinSizeBytes(); minSize > 0 {
+		fmt.Printf("Minimum result size: %s\n", FormatSize(minSize))
+	}
+
+	var total int64
+	for _, e := range evals {
+		fmt.Println()
+		fmt.Printf("%s %s\n", Colorize(Cyan, Symbol("▸", ">")), e.Pattern)
+		if e.Path != e.Pattern {
+			fmt.Printf("    expands to: %s\n", e.Path)
+		}
+		if e.Status != scanner.PathOK {
+			detail := string(e.Status)
+			if e.Detail != "" {
+				detail += " (" + e.Detail + ")"
+			}
+			fmt.Printf("    %s %s\n", Colorize(Yellow, Symbol("✗", "SKIP")), detail)
+			continue
+		}
+
+		fmt.Printf("    size: %s, modified %s ago\n", FormatSize(e.Size), e.Age.Round(time.Minute))
+		if rule.FileLevel() {
+			fmt.Printf("    matching files: %d\n", e.Files)
+		}
+		if e.Selected {
+			fmt.Printf("    %s would be selected\n", Colorize(Green, Symbol("✓", "OK")))
+			total += e.Size
+		} else {
+			fmt.Printf("    %s filtered: %s\n", Colorize(Yellow, Symbol("✗", "SKIP")), e.Filter)
+		}
+	}
+
+	fmt.Printf("\n"+Bold+"Would reclaim: %s"+Reset+"\n", Colorize(Green, FormatSize(total)))
+	return nil
+}
+
+// runRulesAddSource clones a Git repository of rule files into the managed
+// sources directory.
+func runRulesAddSource(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: burrow rules add-source <git-url>")
+	}
+
+	PrintInfo("Cloning %s...", args[0])
+	src, err := sources.NewManager().Add(args[0])
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	for _, r := range rules.NewRegistry().All() {
+		if strings.HasPrefix(r.Source, filepath.Join(sources.Dir(), src.Name)+string(filepath.Separator)) {
+			count++
+		}
+	}
+	PrintSuccess("Added source %s at %s (%d rules).", src.Name, src.Commit, count)
+	return nil
+}
+
+// runRulesSources handles `burrow rules sources list|update|remove`.
+func runRulesSources(args []string) error {
+	sub := "list"
+	if len(args) > 0 {
+		sub, args = args[0], args[1:]
+	}
+	mgr := sources.NewManager()
+
+	switch sub {
+	case "list":
+		list, err := mgr.List()
+		if err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			fmt.Println("No rule sources. Add one with 'burrow rules add-source <git-url>'.")
+			return nil
+		}
+		PrintHeader(fmt.Sprintf("%-20s %-10s %-18s %s", "NAME", "COMMIT", "UPDATED", "URL"))
+		for _, s := range list {
+			fmt.Printf("%-20s %-10s %-18s %s\n", s.Name, s.Commit, s.UpdatedAt.Format("2006-01-02 15:04"), s.URL)
+		}
+		return nil
+
+	case "update":
+		name := ""
+		if len(args) > 0 {
+			name = args[0]
+		}
+		updated, err := mgr.Update(name)
+		for _, s := range updated {
+			PrintSuccess("Updated %s to %s.", s.Name, s.Commit)
+		}
+		return err
+
+	case "remove":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: burrow rules sources remove <name>")
+		}
+		if err := mgr.Remove(args[0]); err != nil {
+			return err
+		}
+		PrintSuccess("Removed source %s.", args[0])
+		return nil
+
+	default:
+		return fmt.Errorf("unknown sources command: %s (use list, update, or remove)", sub)
+	}
+}
+
+// runRulesCategories lists categories with their rule counts and the space
+// they held in the last full scan.
+func runRulesCategories(args []string) error {
+	fs := flag.NewFlagSet("rules categories", flag.ContinueOnError)
+	js := fs.Bool("json", false, "Output in JSON format")
+	names := fs.Bool("names", false, "Print category names only, one per line (for shell completion)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	registry := rules.NewRegistry()
+	categories := registry.Categories()
+
+	if *names {
+		for _, c := range categories {
+			fmt.Println(c.Name)
+		}
+		return nil
+	}
+
+	type categoryInfo struct {
+		rules.Category
+		Rules       int   `json:"rules"`
+		TypicalSize int64 `json:"typical_size"`
+	}
+
+	sizes := make(map[string]int64)
+	if cached, err := scanner.LoadCache(); err == nil && cached.Results != nil {
+		for _, res := range cached.Results.Results {
+			sizes[strings.ToLower(res.Rule.Category)] += res.TotalSize
+		}
+	}
+
+	var infos []categoryInfo
+	for _, c := range categories {
+		info := categoryInfo{Category: c, TypicalSize: sizes[strings.ToLower(c.Name)]}
+		for _, r := range registry.All() {
+			if strings.EqualFold(r.Category, c.Name) {
+				info.Rules++
+			}
+		}
+		infos = append(infos, info)
+	}
+
+	if *js {
+		data, _ := json.MarshalIndent(infos, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	PrintHeader(fmt.Sprintf("%-22s %-7s %-9s %-12s %s", "CATEGORY", "RULES", "RISK", "LAST SCAN", "DESCRIPTION"))
+	fmt.Println(Gray + strings.Repeat("-", 90) + Reset)
+	for _, i := range infos {
+		size := "-"
+		if i.TypicalSize > 0 {
+			size = FormatSize(i.TypicalSize)
+		}
+		risk := "-"
+		if i.Risk != "" {
+			risk = string(i.Risk)
+		}
+		fmt.Printf("%s %-7d %-9s %-12s %s\n", padCategory(i.Name, 22), i.Rules, risk, size, i.Description)
+	}
+	fmt.Println("\nUse a name with --category, e.g. burrow scan --category \"Developer Tools\".")
+	return nil
+}
+
+// runRulesSearch lists the rules matching a keyword in their name, category,
+// description, or paths.
+func runRulesSearch(args []string) error {
+	fs := flag.NewFlagSet("rules search", flag.ContinueOnError)
+	js := fs.Bool("json", false, "Output in JSON format")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	words := fs.Args()
+
+	query := strings.Join(words, " ")
+	if query == "" {
+		return fmt.Errorf("usage: burrow rules search <keyword>")
+	}
+
+	matches := rules.NewRegistry().Search(query)
+	if *js {
+		if matches == nil {
+			matches = []rules.Match{}
+		}
+		data, _ := json.MarshalIndent(matches, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	if len(matches) == 0 {
+		fmt.Printf("No rules match %q. List them all with 'burrow rules'.\n", query)
+		return nil
+	}
+
+	PrintHeader(fmt.Sprintf("%-30s %-22s %-9s %s", "NAME", "CATEGORY", "RISK", "DESCRIPTION"))
+	fmt.Println(Gray + strings.Repeat("-", 90) + Reset)
+	for _, m := range matches {
+		r := m.Rule
+		fmt.Printf("%-30s %s %s %s\n", r.Name, padCategory(r.Category, 22), riskLabel(r.RiskLevel), r.Description)
+		if m.Field == "path" {
+			for _, p := range r.Paths {
+				for _, w := range words {
+					if strings.Contains(strings.ToLower(p), strings.ToLower(w)) {
+						fmt.Printf("%-30s %s\n", "", Colorize(Gray, p))
+						break
+					}
+				}
+			}
+		}
+	}
+	fmt.Printf("\n%d rule(s) match. See 'burrow rules --explain <name>' for details.\n", len(matches))
+	return nil
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ismailtsdln/burrow/internal/paths"
+	"github.com/ismailtsdln/burrow/internal/schema"
+)
+
+func runSchema(args []string) error {
+	if len(args) < 2 || args[0] != "print" {
+		return fmt.Errorf("usage: burrow schema print <%s>", strings.Join(schema.Names, "|"))
+	}
+	data, err := schema.Source(args[1])
+	if err != nil {
+		return err
+	}
+	os.Stdout.Write(data)
+	return nil
+}
+
+func runConfig(args []string) error {
+	if len(args) == 0 || args[0] != "validate" {
+		return fmt.Errorf("usage: burrow config validate [file]")
+	}
+	file := paths.ConfigFile()
+	if len(args) > 1 {
+		file = args[1]
+	}
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		PrintInfo("No config file at %s; defaults apply.", file)
+		return nil
+	}
+	return validateFiles("config", []string{file})
+}
+
+// runRulesValidate checks custom rule files; without arguments, every file
+// Burrow loads custom rules from.
+func runRulesValidate(args []string) error {
+	files := args
+	if len(files) == 0 {
+		if _, err := os.Stat(paths.CustomRulesFile()); err == nil {
+			files = append(files, paths.CustomRulesFile())
+		}
+		for _, ext := range []string{"*.json", "*.yaml", "*.yml"} {
+			matches, _ := filepath.Glob(filepath.Join(paths.RulesDir(), ext))
+			files = append(files, matches...)
+		}
+	}
+	if len(files) == 0 {
+		PrintInfo("No custom rule files found in %s.", paths.ConfigDir())
+		return nil
+	}
+	return validateFiles("rules", files)
+}
+
+// validateFiles prints every problem as file:line:column, the form editors
+// and CI logs link to, and fails if there were any.
+func validateFiles(name string, files []string) error {
+	problems := 0
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		ext := strings.ToLower(filepath.Ext(file))
+		errs, err := schema.Validate(name, data, ext != ".yaml" && ext != ".yml")
+		if err != nil {
+			return err
+		}
+		if len(errs) == 0 {
+			PrintSuccess("%s is valid.", file)
+			continue
+		}
+		for _, e := range errs {
+			fmt.Printf("%s:%s\n", file, e)
+		}
+		problems += len(errs)
+	}
+	if problems > 0 {
+		return fmt.Errorf("%d problem(s) found", problems)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"os"
+
+	"github.com/ismailtsdln/burrow/internal/server"
+)
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", "127.0.0.1:7777", "Address to listen on")
+	token := fs.String("token", os.Getenv("BURROW_API_TOKEN"), "Bearer token required by clients (default: $BURROW_API_TOKEN or random)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *token == "" {
+		buf := make([]byte, 16)
+		if _, err := rand.Read(buf); err != nil {
+			return err
+		}
+		*token = hex.EncodeToString(buf)
+		PrintInfo("Generated API token: %s", *token)
+	}
+
+	PrintInfo("Burrow API listening on http://%s (endpoints: /scan, /clean, /history, /rules)", *addr)
+	return server.New(*addr, *token).ListenAndServe()
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/daemon"
+	"github.com/ismailtsdln/burrow/internal/service"
+	"github.com/ismailtsdln/burrow/internal/units"
+)
+
+func runWatch(args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	var interval units.Duration
+	fs.Var(&interval, "interval", "Time between scans, e.g. 6h or 1d (default: watch.interval in the config, or 6h)")
+	autoClean := fs.Bool("auto-clean", false, "Move Safe-risk results to trash after each scan (default: watch.auto_clean in the config)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger := log.New(os.Stdout, "burrow: ", log.LstdFlags)
+	return daemon.Run(ctx, daemon.Options{
+		Interval:  time.Duration(interval),
+		AutoClean: *autoClean,
+	}, logger)
+}
+
+func runService(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: burrow service install|uninstall|start|stop|status")
+	}
+
+	sub, args := args[0], args[1:]
+	switch sub {
+	case "install":
+		plist, err := service.Install(args)
+		if err != nil {
+			return err
+		}
+		PrintSuccess("Installed launchd agent: %s", plist)
+		PrintInfo("Start it with 'burrow service start'.")
+	case "uninstall":
+		if err := service.Uninstall(); err != nil {
+			return err
+		}
+		PrintSuccess("Uninstalled launchd agent.")
+	case "start":
+		if err := service.Start(); err != nil {
+			return err
+		}
+		PrintSuccess("Watch service started.")
+	case "stop":
+		if err := service.Stop(); err != nil {
+			return err
+		}
+		PrintSuccess("Watch service stopped.")
+	case "status":
+		st, err := service.Query()
+		if err != nil {
+			return err
+		}
+		plist, stdoutLog, stderrLog := service.Paths()
+		switch {
+		case st.Running:
+			PrintSuccess("Watch service running (pid %d)", st.PID)
+		case st.Installed:
+			PrintWarning("Watch service installed but not running")
+		default:
+			PrintWarning("Watch service not installed")
+		}
+		fmt.Printf("Plist:  %s\n", plist)
+		fmt.Printf("Logs:   %s\n", stdoutLog)
+		fmt.Printf("Errors: %s\n", stderrLog)
+	default:
+		return fmt.Errorf("unknown service command: %s", sub)
+	}
+	return nil
+}
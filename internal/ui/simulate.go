@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"slices"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/simulate"
+)
+
+// runSimulate runs scan, clean, and undo against a fixture home instead of
+// the real one.
+func runSimulate(args []string) error {
+	fs := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	dir := fs.String("fixture", "", "Directory holding the fake home (created if missing)")
+	generate := fs.Bool("generate", false, "Fill the fixture with sample data for every rule first")
+	var only stringList
+	fs.Var(&only, "rule", "Only simulate this rule (repeatable)")
+	noUndo := fs.Bool("no-undo", false, "Leave the cleaned data in the fixture's trash instead of restoring it")
+	js := fs.Bool("json", false, "Output in JSON format")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *dir == "" {
+		return fmt.Errorf("usage: burrow simulate --fixture DIR [--generate] [--rule NAME] [--no-undo] [--json]")
+	}
+
+	fixture, err := simulate.NewFixture(*dir)
+	if err != nil {
+		return err
+	}
+	restore := fixture.Enter()
+	defer restore()
+
+	// Loaded after entering, so custom rules come from the fixture's config
+	var list []rules.CleanupRule
+	for _, r := range rules.NewRegistry().All() {
+		if len(only) == 0 || slices.Contains(only, r.Name) {
+			list = append(list, r)
+		}
+	}
+	for _, name := range only {
+		if !slices.ContainsFunc(list, func(r rules.CleanupRule) bool { return r.Name == name }) {
+			return fmt.Errorf("unknown rule %q (see 'burrow rules')", name)
+		}
+	}
+
+	if *generate {
+		created, err := simulate.Generate(fixture, list)
+		if err != nil {
+			return err
+		}
+		if !*js {
+			PrintInfo("Generated %d sample file(s) in %s", len(created), fixture.Dir)
+		}
+	}
+
+	report, err := simulate.Run(fixture, list, !*noUndo)
+	if err != nil {
+		return err
+	}
+
+	if *js {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		printSimulation(report, !*noUndo)
+	}
+	if !report.OK() {
+		return fmt.Errorf("simulation found problems")
+	}
+	return nil
+}
+
+func printSimulation(report *simulate.Report, undo bool) {
+	PrintHeader("Simulation in " + report.Fixture)
+	arrow := " " + Symbol("→", "->") + " "
+	for _, rr := range report.Rules {
+		steps := fmt.Sprintf("found %d path(s), %s", len(rr.Paths), FormatSize(rr.Size))
+		switch {
+		case rr.ReportOnly:
+			steps += arrow + "report only"
+		case rr.Cleaned && (!undo || rr.Restored):
+			steps += arrow + "trashed"
+			if undo {
+				steps += arrow + "restored"
+			}
+		}
+		mark := Colorize(Green, Symbol("✓", "OK"))
+		if len(rr.Problems) > 0 {
+			mark = Colorize(Red, Symbol("✗", "FAIL"))
+		}
+		fmt.Printf("  %s %-30s %s\n", mark, rr.Rule, steps)
+		for _, p := range rr.Problems {
+			fmt.Printf("      %s\n", Colorize(Red, p))
+		}
+	}
+
+	if len(report.Unmatched) > 0 {
+		fmt.Println("\n" + Bold + "Found nothing" + Reset)
+		for _, name := range report.Unmatched {
+			fmt.Printf("  %s\n", name)
+		}
+	}
+	if len(report.Skipped) > 0 {
+		fmt.Println("\n" + Bold + "Skipped" + Reset)
+		for _, s := range report.Skipped {
+			fmt.Printf("  %-30s %s %s\n", s.Rule, s.Path, Colorize(Gray, "("+s.Reason+")"))
+		}
+	}
+
+	fmt.Println()
+	if report.Session != "" && !undo {
+		PrintInfo("Cleaned data is in the fixture's trash, session %s", report.Session)
+	}
+	if report.OK() {
+		PrintSuccess("%d rule(s) behaved as expected", len(report.Rules))
+	} else {
+		PrintError("Some rules did not behave as expected")
+	}
+}
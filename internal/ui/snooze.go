@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/dismiss"
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/safety"
+	"github.com/ismailtsdln/burrow/internal/units"
+)
+
+func runSnooze(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: burrow snooze <rule|path> --for 30d | snooze list | snooze clear <rule|path>|--all")
+	}
+
+	switch args[0] {
+	case "list":
+		return runSnoozeList()
+	case "clear":
+		return runSnoozeClear(args[1:])
+	default:
+		return runSnoozeAdd(args)
+	}
+}
+
+// runSnoozeAdd hides a rule or path from suggestions until the period ends.
+func runSnoozeAdd(args []string) error {
+	fs := flag.NewFlagSet("snooze", flag.ContinueOnError)
+	var span units.Duration
+	fs.Var(&span, "for", "How long to snooze, e.g. 30d, 2w, or 3mo")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	words := fs.Args()
+
+	target := strings.Join(words, " ")
+	if target == "" || span <= 0 {
+		return fmt.Errorf("usage: burrow snooze <rule|path> --for 30d")
+	}
+
+	d, err := snoozeTarget(target)
+	if err != nil {
+		return err
+	}
+	d.Until = time.Now().Add(time.Duration(span))
+	if err := dismiss.NewManager().Add(d); err != nil {
+		return fmt.Errorf("failed to save snooze: %w", err)
+	}
+	PrintSuccess("Snoozed %s until %s.", d.Target(), d.Until.Format("Jan 2, 2006"))
+	return nil
+}
+
+// snoozeTarget resolves target to a rule, or failing that to an existing path.
+func snoozeTarget(target string) (dismiss.Dismissal, error) {
+	if rule, ok := rules.NewRegistry().Find(target); ok {
+		return dismiss.Dismissal{Rule: rule.Name}, nil
+	}
+	path, err := filepath.Abs(safety.ExpandPath(target))
+	if err != nil {
+		return dismiss.Dismissal{}, err
+	}
+	if _, err := os.Lstat(path); err != nil {
+		return dismiss.Dismissal{}, fmt.Errorf("no rule or path named %q (see 'burrow rules')", target)
+	}
+	return dismiss.Dismissal{Path: path}, nil
+}
+
+func runSnoozeList() error {
+	list, err := dismiss.NewManager().Load()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var active []dismiss.Dismissal
+	for _, d := range list {
+		if d.Active(now) {
+			active = append(active, d)
+		}
+	}
+	if len(active) == 0 {
+		fmt.Println("Nothing is snoozed or dismissed.")
+		return nil
+	}
+
+	PrintHeader(fmt.Sprintf("%-40s %-6s %s", "TARGET", "KIND", "UNTIL"))
+	fmt.Println(Gray + strings.Repeat("-", 75) + Reset)
+	for _, d := range active {
+		kind := "rule"
+		if d.Rule == "" {
+			kind = "path"
+		}
+		until := Colorize(Gray, "never suggest")
+		if !d.Until.IsZero() {
+			until = fmt.Sprintf("%s (%s left)", d.Until.Format("Jan 2, 2006"), formatLeft(d.Until.Sub(now)))
+		}
+		fmt.Printf("%-40s %-6s %s\n", d.Target(), kind, until)
+	}
+	return nil
+}
+
+func runSnoozeClear(args []string) error {
+	fs := flag.NewFlagSet("snooze clear", flag.ContinueOnError)
+	all := fs.Bool("all", false, "Clear every snooze and dismissal")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	target := strings.Join(fs.Args(), " ")
+	if target == "" && !*all {
+		return fmt.Errorf("usage: burrow snooze clear <rule|path> or --all")
+	}
+
+	removed, err := dismiss.NewManager().Remove(target)
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		PrintWarning("Nothing to clear for %s.", target)
+		return nil
+	}
+	PrintSuccess("Cleared %d snooze(s); matching items will be suggested again.", removed)
+	return nil
+}
+
+// formatLeft renders the time remaining on a snooze in days, or hours on the
+// last day.
+func formatLeft(d time.Duration) string {
+	if d >= units.Day {
+		return fmt.Sprintf("%d days", int(d/units.Day))
+	}
+	return fmt.Sprintf("%d hours", max(1, int(d/time.Hour)))
+}
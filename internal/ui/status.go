@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/scanner"
+	"github.com/ismailtsdln/burrow/internal/units"
+)
+
+func runStatus(args []string) error {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	bitbar := fs.Bool("bitbar", false, "Output in SwiftBar/xbar plugin format")
+	js := fs.Bool("json", false, "Output in JSON format")
+	maxAgeFlag := fs.String("max-age", "1h", "Rescan when the cached scan is older than this (e.g. 30m, 1d), 0 = always")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	var maxAge time.Duration
+	if strings.TrimSpace(*maxAgeFlag) != "0" {
+		var err error
+		if maxAge, err = units.ParseDuration(*maxAgeFlag); err != nil {
+			return fmt.Errorf("invalid --max-age: %w", err)
+		}
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	registry := rules.NewRegistry()
+	opts := baseScanOptions(cfg)
+	cached, err := scanner.LoadCache()
+	if err != nil || cached.Age() > maxAge {
+		results, err := scanner.NewScanner(registry, opts.WithoutDismissals()).Scan()
+		if err != nil {
+			return err
+		}
+		recordScan(results, registry)
+		cached = &scanner.CachedScan{Timestamp: time.Now(), Results: results}
+	}
+	// The cache keeps dismissed suggestions; leave them out of the totals
+	cached.Results = scanner.NewScanner(registry, opts).Filter(cached.Results)
+
+	stats := make(map[string]int64)
+	for _, res := range cached.Results.Results {
+		stats[res.Rule.Category] += res.TotalSize
+	}
+	categories := make([]string, 0, len(stats))
+	for cat := range stats {
+		categories = append(categories, cat)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return stats[categories[i]] > stats[categories[j]]
+	})
+
+	switch {
+	case *js:
+		data, _ := json.MarshalIndent(map[string]interface{}{
+			"scanned_at":      cached.Timestamp,
+			"reclaimable":     cached.Results.TotalSize,
+			"category_totals": stats,
+		}, "", "  ")
+		fmt.Println(string(data))
+	case *bitbar:
+		printBitbar(cached, categories, stats)
+	default:
+		fmt.Printf("Reclaimable: %s (scanned %s ago)\n", FormatSize(cached.Results.TotalSize), cached.Age().Round(time.Second))
+		for _, cat := range categories {
+			fmt.Printf("  %-25s %s\n", cat, FormatSize(stats[cat]))
+		}
+	}
+	return nil
+}
+
+// printBitbar renders the status in the SwiftBar/xbar plugin format: the first
+// line is the menu bar title, lines after '---' form the dropdown menu.
+func printBitbar(cached *scanner.CachedScan, categories []string, stats map[string]int64) {
+	exe, err := os.Executable()
+	if err != nil {
+		exe = "burrow"
+	}
+
+	fmt.Printf("🐿 %s\n", FormatSize(cached.Results.TotalSize))
+	fmt.Println("---")
+	fmt.Printf("Reclaimable: %s | size=14\n", FormatSize(cached.Results.TotalSize))
+	for _, cat := range categories {
+		fmt.Printf("%s: %s | font=Menlo\n", cat, FormatSize(stats[cat]))
+	}
+	fmt.Println("---")
+	fmt.Printf("Preview cleanup | bash=%q param1=clean terminal=true refresh=true\n", exe)
+	fmt.Printf("Rescan now | bash=%q param1=status param2=--max-age=0 terminal=false refresh=true\n", exe)
+	fmt.Printf("Undo last cleanup | bash=%q param1=undo terminal=true refresh=true\n", exe)
+	fmt.Printf("Cleanup history | bash=%q param1=history terminal=true\n", exe)
+	fmt.Println("---")
+	fmt.Printf("Last scan: %s ago | color=gray\n", cached.Age().Round(time.Minute))
+}
@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ismailtsdln/burrow/internal/cleaner"
+	"github.com/ismailtsdln/burrow/internal/config"
+	"github.com/ismailtsdln/burrow/internal/privilege"
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/scanner"
+)
+
+// runSystemClean cleans root-owned rule paths. When not running as root it
+// re-executes itself through sudo; the elevated process re-scans, refuses
+// anything outside the privilege whitelist, and asks for an explicit typed
+// confirmation before cleaning.
+func runSystemClean(cfg *config.Config, apply, yes, confirmRisky, useAuth bool) error {
+	if !privilege.IsRoot() {
+		PrintHeader("System Cleanup (requires administrator privileges)")
+		fmt.Println("Burrow will re-run itself with sudo and only touch these locations:")
+		for _, p := range privilege.Whitelist {
+			fmt.Printf("   %s %s\n", Colorize(Yellow, Symbol("•", "-")), p)
+		}
+
+		if !apply {
+			fmt.Println("\nThis was a preview. Run 'burrow clean --system --apply' to size and clean them as root.")
+			return nil
+		}
+
+		privilege.Audit("escalate", privilege.Whitelist, "", nil)
+
+		childArgs := []string{"clean", "--system", "--apply"}
+		if yes {
+			childArgs = append(childArgs, "--yes")
+		}
+		if confirmRisky {
+			childArgs = append(childArgs, "--confirm-risky")
+		}
+		if useAuth {
+			childArgs = append(childArgs, "--auth")
+		}
+		return childOutcome(privilege.Reexec(childArgs))
+	}
+
+	registry := rules.NewRegistry()
+	opts := baseScanOptions(cfg)
+	opts.IncludeSystem = true
+	s := scanner.NewScanner(registry, opts)
+
+	results, err := s.Scan()
+	if err != nil {
+		return err
+	}
+
+	var toClean []rules.Result
+	var paths []string
+	var total int64
+	for _, res := range results.Results {
+		if !res.Rule.RequiresRoot {
+			continue
+		}
+		for _, p := range res.FoundPaths {
+			if !privilege.IsWhitelisted(p) {
+				err := fmt.Errorf("refusing to clean non-whitelisted system path: %s", p)
+				privilege.Audit("system-clean-refused", []string{p}, "", err)
+				return err
+			}
+		}
+		toClean = append(toClean, res)
+		paths = append(paths, res.FoundPaths...)
+		total += res.TotalSize
+	}
+
+	if len(toClean) == 0 {
+		PrintSuccess("No system caches to clean.")
+		return nil
+	}
+
+	PrintHeader("System Cleanup Summary:")
+	fmt.Println(Gray + strings.Repeat("-", 70) + Reset)
+	for _, res := range toClean {
+		fmt.Printf("%-30s %-15s %s %s\n", Colorize(Blue, categoryLabel(res.Rule.Category)), Colorize(Yellow, FormatSize(res.TotalSize)), riskLabel(res.Rule.RiskLevel), res.Rule.Name)
+		for _, p := range res.FoundPaths {
+			fmt.Printf("   %s %s\n", Colorize(Red, "-"), Colorize(Gray, p))
+		}
+	}
+	fmt.Println(Gray + strings.Repeat("-", 70) + Reset)
+	fmt.Printf(Bold+"Total to be reclaimed: %s"+Reset+"\n", Colorize(Green, FormatSize(total)))
+
+	if !apply {
+		fmt.Println("\nThis was a preview. Add --apply to clean these paths.")
+		return ErrCandidatesFound
+	}
+	// Asked here rather than before sudo so that running 'sudo burrow' directly
+	// confirms too; only --yes with --confirm-risky skips it
+	if !(yes && confirmRisky) && !ConfirmTyped("\n"+Colorize(Yellow, "Cleaning as root can affect every user on this Mac."), "sudo") {
+		PrintWarning("System cleanup cancelled.")
+		return ErrCancelled
+	}
+	if !confirmRisk(toClean, yes, confirmRisky, true) {
+		return ErrCancelled
+	}
+
+	if ok, err := authorizeCleanup(cfg, toClean, useAuth, false, false); err != nil || !ok {
+		return err
+	}
+
+	c := cleaner.NewCleaner()
+	c.UseSystemTrash = cfg.UseSystemTrash
+	c.TrashChecksums = cfg.TrashChecksums
+	res, err := cleanInterruptibly(c, toClean, false)
+	if res == nil {
+		privilege.Audit("system-clean", paths, "", err)
+		return err
+	}
+	privilege.Audit("system-clean", paths, res.TrashSession, err)
+	notifyCleanup(cfg, "system", res)
+
+	printReclaimed(res, err)
+	fmt.Printf("Files moved to trash: %d\n", res.FileCount)
+	fmt.Printf("Trash Session ID: %s\n", Colorize(Cyan, res.TrashSession))
+	PrintInfo("Audit log: %s", privilege.AuditLogPath())
+	PrintInfo("Restore with 'sudo burrow undo'.")
+	printMilestonesAndTips(cfg, res)
+	return err
+}
@@ -0,0 +1,18 @@
+//go:build !linux && !darwin
+
+package ui
+
+import (
+	"errors"
+	"os"
+)
+
+// rawMode is not implemented here; interactive prompts fall back to
+// reading whole lines.
+func rawMode(f *os.File) (func(), error) {
+	return nil, errors.New("raw terminal mode is not supported on this platform")
+}
+
+func terminalHeight(f *os.File) int {
+	return 0
+}
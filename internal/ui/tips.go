@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/cleaner"
+	"github.com/ismailtsdln/burrow/internal/config"
+	"github.com/ismailtsdln/burrow/internal/history"
+	"github.com/ismailtsdln/burrow/internal/service"
+	"github.com/ismailtsdln/burrow/internal/units"
+	"github.com/ismailtsdln/burrow/internal/usage"
+)
+
+const (
+	// regrowthCount is how many cleanups of a rule in a month make it worth
+	// scheduling.
+	regrowthCount = 3
+	// bigTrash is the trash size worth a reminder that it still takes space.
+	bigTrash = 10 * units.GB
+)
+
+// printMilestonesAndTips celebrates lifetime milestones the cleanup reached
+// and now and then suggests how to clean better. quiet_tips turns both off.
+func printMilestonesAndTips(cfg *config.Config, res *cleaner.CleanResult) {
+	if cfg.QuietTips {
+		return
+	}
+	m := usage.NewManager()
+	stats, err := m.Load()
+	if err != nil {
+		return
+	}
+
+	for _, ms := range stats.Milestones(res.ReclaimedSpace) {
+		if ms.Bytes > 0 {
+			PrintSuccess("%sMilestone: you've reclaimed %s lifetime!", Symbol("🎉 ", ""), FormatSize(ms.Bytes))
+		} else {
+			PrintSuccess("%sMilestone: %d cleanups with Burrow!", Symbol("🎉 ", ""), ms.Cleanups)
+		}
+	}
+
+	now := time.Now()
+	if !stats.TipDue(now) {
+		return
+	}
+	if tip := cleanupTip(now); tip != "" {
+		PrintInfo("Tip: %s", tip)
+		m.RecordTip()
+	}
+}
+
+// cleanupTip returns the most useful tip for now, or "".
+func cleanupTip(now time.Time) string {
+	entries, _ := history.NewManager().Load()
+	if regrowing := usage.Regrowing(entries, now.AddDate(0, -1, 0), regrowthCount); len(regrowing) > 0 {
+		r := regrowing[0]
+		st, err := service.Query()
+		switch {
+		case err != nil:
+			return fmt.Sprintf("%s regrew %d times this month %s consider scheduling a weekly 'burrow clean --risk safe --apply --yes'.", r.Rule, r.Count, Symbol("—", "-"))
+		case !st.Installed:
+			return fmt.Sprintf("%s regrew %d times this month %s consider scheduling weekly cleanup with 'burrow service install'.", r.Rule, r.Count, Symbol("—", "-"))
+		}
+	}
+
+	if trash, err := cleaner.NewTrashManager().Usage(); err == nil && trash.Bytes >= bigTrash {
+		return fmt.Sprintf("the trash still holds %s from %d cleanup(s); see 'burrow trash list' and free it with 'burrow trash purge <id>'.", FormatSize(trash.Bytes), trash.Sessions)
+	}
+	return ""
+}
@@ -0,0 +1,236 @@
+package ui
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os/user"
+	"strings"
+
+	"github.com/ismailtsdln/burrow/internal/cleaner"
+	"github.com/ismailtsdln/burrow/internal/history"
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/safety"
+)
+
+func runTrash(args []string) error {
+	sub := "list"
+	if len(args) > 0 {
+		sub, args = args[0], args[1:]
+	}
+
+	switch sub {
+	case "list":
+		return runTrashList()
+	case "repair":
+		return runTrashRepair(args)
+	case "purge":
+		return runTrashPurge(args)
+	case "show":
+		return runTrashShow(args)
+	default:
+		return fmt.Errorf("unknown trash command: %s (use list, show, repair, or purge)", sub)
+	}
+}
+
+func runTrashList() error {
+	tm := cleaner.NewTrashManager()
+	sessions, err := tm.Sessions()
+	if err != nil {
+		return err
+	}
+
+	if len(sessions) == 0 {
+		fmt.Println("Trash is empty.")
+		return nil
+	}
+
+	window := undoWindow()
+	PrintHeader(fmt.Sprintf("%-20s %-10s %s", "SESSION ID", "ENTRIES", "STATUS"))
+	fmt.Println(Gray + strings.Repeat("-", 50) + Reset)
+	orphans := 0
+	for _, s := range sessions {
+		if s.Orphaned {
+			orphans++
+			fmt.Printf("%-20s %-10s %s\n", Colorize(Cyan, s.ID), "?", Colorize(Red, "ORPHANED (no manifest)"))
+			continue
+		}
+		status := "restorable"
+		if until := cleaner.ExpiresAt(s.Trashed(), window); !until.IsZero() {
+			status += " until " + until.Format("Jan 2 15:04")
+		}
+		fmt.Printf("%-20s %-10d %s\n", Colorize(Cyan, s.ID), len(s.Manifest.Entries), Colorize(Green, status))
+		if s.Manifest.Note != "" {
+			fmt.Printf("  %s\n", Colorize(Gray, Symbol("↳ ", "note: ")+s.Manifest.Note))
+		}
+	}
+
+	if orphans > 0 {
+		fmt.Println()
+		PrintWarning("%d orphaned session(s) found. Run 'burrow trash repair' to rebuild their manifests or 'burrow trash purge <id>' to delete them.", orphans)
+	}
+	return nil
+}
+
+// runTrashShow prints what a trash session holds and what was recorded about
+// each entry when it was trashed.
+func runTrashShow(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: burrow trash show <session-id>")
+	}
+	id := args[0]
+	manifest, err := cleaner.NewTrashManager().Manifest(id)
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for _, e := range manifest.Entries {
+		total += e.Size
+	}
+	PrintHeader(fmt.Sprintf("Session %s", id))
+	fmt.Printf("Trashed: %s (%s)\n", manifest.Timestamp.Format("Jan 2, 2006 15:04"), formatAgo(manifest.Timestamp))
+	if manifest.Note != "" {
+		fmt.Printf("Note:    %s\n", manifest.Note)
+	}
+	fmt.Printf("Entries: %d, %s\n\n", len(manifest.Entries), FormatSize(total))
+
+	for _, e := range manifest.Entries {
+		path := e.OriginalPath
+		if path == "" {
+			path = Colorize(Red, "(unknown origin) ") + e.TrashPath
+		}
+		fmt.Println(Colorize(Cyan, path))
+		switch {
+		case e.SymlinkTarget != "":
+			fmt.Printf("    symlink to %s\n", e.SymlinkTarget)
+		case e.Mode != 0:
+			fmt.Printf("    %s  %s in %d file(s)  %s\n", e.Mode, FormatSize(e.Size), e.Files, ownerName(e.Owner))
+		}
+		if len(e.Xattrs) > 0 {
+			fmt.Printf("    xattrs: %s\n", strings.Join(e.Xattrs, ", "))
+		}
+		if e.Checksum != "" {
+			fmt.Printf("    %s\n", Colorize(Gray, e.Checksum))
+		}
+	}
+	return nil
+}
+
+// ownerName resolves a recorded "uid:gid" owner to user and group names
+// where they still exist.
+func ownerName(owner string) string {
+	uid, gid, ok := strings.Cut(owner, ":")
+	if !ok {
+		return owner
+	}
+	if u, err := user.LookupId(uid); err == nil {
+		uid = u.Username
+	}
+	if g, err := user.LookupGroupId(gid); err == nil {
+		gid = g.Name
+	}
+	return uid + ":" + gid
+}
+
+func runTrashRepair(args []string) error {
+	tm := cleaner.NewTrashManager()
+
+	var ids []string
+	if len(args) > 0 {
+		ids = args
+	} else {
+		sessions, err := tm.Sessions()
+		if err != nil {
+			return err
+		}
+		for _, s := range sessions {
+			if s.Orphaned {
+				ids = append(ids, s.ID)
+			}
+		}
+	}
+
+	if len(ids) == 0 {
+		PrintSuccess("No orphaned trash sessions found.")
+		return nil
+	}
+
+	// Rule paths are the best hint we have for where trashed items came from
+	var candidates []string
+	for _, r := range rules.NewRegistry().All() {
+		for _, p := range r.Paths {
+			candidates = append(candidates, safety.ExpandPath(p))
+		}
+	}
+
+	for _, id := range ids {
+		// The session's own history entry is a better hint still
+		sessionCandidates := candidates
+		if entry, err := history.NewManager().Find(id); err == nil {
+			sessionCandidates = append(entry.Paths, candidates...)
+		}
+		manifest, err := tm.RepairOrphan(id, sessionCandidates)
+		if err != nil {
+			PrintError("%s: %v", id, err)
+			continue
+		}
+
+		unknown := 0
+		for _, e := range manifest.Entries {
+			if e.OriginalPath == "" {
+				unknown++
+			}
+		}
+		PrintSuccess("%s: rebuilt manifest with %d entries", id, len(manifest.Entries))
+		if unknown > 0 {
+			PrintWarning("%s: %d entries have no known original location and will stay in trash on undo", id, unknown)
+		}
+	}
+	return nil
+}
+
+func runTrashPurge(args []string) error {
+	fs := flag.NewFlagSet("trash purge", flag.ContinueOnError)
+	yes := fs.Bool("yes", false, "Purge without confirmation")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: burrow trash purge <session-id>")
+	}
+	id := fs.Arg(0)
+
+	if !*yes && !Confirm(Colorize(Yellow, fmt.Sprintf("Permanently delete trash session %s?", id))) {
+		PrintWarning("Purge cancelled.")
+		return ErrCancelled
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if ok, err := authorizeCleanup(cfg, nil, false, false, true); err != nil || !ok {
+		return err
+	}
+
+	tm := cleaner.NewTrashManager()
+	stop, release := trapInterrupts()
+	tm.Stop = stop
+	err = tm.Purge(id)
+	release()
+	if errors.Is(err, cleaner.ErrInterrupted) {
+		if m, merr := tm.Manifest(id); merr == nil {
+			PrintWarning("Purge interrupted; session %s keeps %d item(s) that were not deleted yet.", id, len(m.Entries))
+		} else {
+			PrintWarning("Purge interrupted; session %s keeps the items that were not deleted yet.", id)
+		}
+		return ErrPartial
+	}
+	if err != nil {
+		return err
+	}
+	PrintSuccess("Purged trash session %s.", id)
+	return nil
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/ismailtsdln/burrow/internal/cleaner"
+	"github.com/ismailtsdln/burrow/internal/config"
+	"github.com/ismailtsdln/burrow/internal/rules"
+)
+
+// checkTrashCap adds the trash cap to the pre-clean summary: where the
+// cleanup leaves the trash and which old sessions would make room. It
+// returns nil unless sessions should be purged.
+func checkTrashCap(c *cleaner.Cleaner, cfg *config.Config, results []rules.Result) *cleaner.TrashCap {
+	if cfg.TrashCap <= 0 || cfg.UseSystemTrash {
+		return nil
+	}
+	p, err := c.CheckTrashCap(results, int64(cfg.TrashCap))
+	if err != nil {
+		PrintWarning("Could not check the trash size: %v", err)
+		return nil
+	}
+	if !p.Over() {
+		fmt.Printf("Trash after cleanup: %s of its %s cap.\n", FormatSize(p.Used+p.Incoming), FormatSize(p.Cap))
+		return nil
+	}
+
+	PrintWarning("This cleanup takes the trash to %s, over its %s cap.", FormatSize(p.Used+p.Incoming), FormatSize(p.Cap))
+	if len(p.Purge) == 0 {
+		return nil
+	}
+	under := "keeps it under the cap"
+	if !p.Fits() {
+		under = "is not enough to stay under the cap"
+	}
+	fmt.Printf("Purging the %d oldest trash session(s) (%s) %s:\n", len(p.Purge), FormatSize(p.Freed), under)
+	for _, s := range p.Purge {
+		fmt.Printf("   %s %s\n", Colorize(Red, "-"), Colorize(Cyan, s.ID))
+	}
+	return p
+}
+
+// decideTrashCap settles whether to purge the sessions checkTrashCap picked.
+// trash_cap_action "purge" purges them; otherwise the user is asked, and
+// runs with --yes keep them, since purging can't be undone.
+func decideTrashCap(cfg *config.Config, p *cleaner.TrashCap, yes bool) bool {
+	switch {
+	case p == nil:
+		return false
+	case cfg.TrashCapAction == "purge":
+		return true
+	case yes:
+		PrintWarning("Keeping old trash sessions over the cap; set trash_cap_action to \"purge\" to purge them without asking.")
+		return false
+	case Confirm(Colorize(Yellow, fmt.Sprintf("Permanently delete the %d oldest trash session(s) first?", len(p.Purge)))):
+		return true
+	default:
+		PrintInfo("Keeping them; the trash stays over its cap.")
+		return false
+	}
+}
+
+// applyTrashCap purges the sessions picked to make room.
+func applyTrashCap(c *cleaner.Cleaner, p *cleaner.TrashCap) error {
+	if err := c.PurgeForCap(p); err != nil {
+		return fmt.Errorf("failed to purge old trash sessions: %w", err)
+	}
+	PrintSuccess("Purged %d old trash session(s), freeing %s.", len(p.Purge), FormatSize(p.Freed))
+	return nil
+}
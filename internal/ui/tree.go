@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ismailtsdln/burrow/internal/scanner"
+)
+
+// treeChildLimit caps how many children of one node are listed before the
+// rest are folded into a single summary line.
+const treeChildLimit = 10
+
+// printTree renders the planned deletions of a rule as a collapsed tree with
+// per-subtree sizes, largest entries first.
+func printTree(out io.Writer, roots []*scanner.TreeNode) {
+	for _, root := range roots {
+		fmt.Fprintf(out, "   %s %s %s\n", Colorize(Red, "-"), root.Path, Colorize(Yellow, FormatSize(root.Size)))
+		printTreeChildren(out, root.Children, "     ")
+	}
+}
+
+func printTreeChildren(out io.Writer, children []*scanner.TreeNode, indent string) {
+	shown := children
+	if len(shown) > treeChildLimit {
+		shown = shown[:treeChildLimit]
+	}
+
+	for i, c := range shown {
+		last := i == len(shown)-1 && len(children) == len(shown)
+		branch, next := Symbol("├─", "|-"), Symbol("│  ", "|  ")
+		if last {
+			branch, next = Symbol("└─", "`-"), "   "
+		}
+		fmt.Fprintf(out, "%s %s %s\n", Colorize(Gray, indent+branch), c.Name, Colorize(Yellow, FormatSize(c.Size)))
+		printTreeChildren(out, c.Children, indent+next)
+	}
+
+	if rest := children[len(shown):]; len(rest) > 0 {
+		var size int64
+		for _, c := range rest {
+			size += c.Size
+		}
+		fmt.Fprintf(out, "%s%s %s\n", Colorize(Gray, indent+Symbol("└─", "`-")), Colorize(Gray, fmt.Sprintf("%s %d more", Symbol("…", "..."), len(rest))), Colorize(Yellow, FormatSize(size)))
+	}
+}
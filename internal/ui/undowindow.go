@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/cleaner"
+	"github.com/ismailtsdln/burrow/internal/config"
+	"github.com/ismailtsdln/burrow/internal/units"
+)
+
+// finalizeExpired purges the cleanups whose undo window closed, so the
+// trash only holds what can still be undone.
+func finalizeExpired(cfg *config.Config) {
+	if cfg == nil || cfg.UndoWindow <= 0 {
+		return
+	}
+	window := time.Duration(cfg.UndoWindow)
+	purged, err := cleaner.NewCleaner().FinalizeExpired(window, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	if len(purged) > 0 {
+		fmt.Fprintf(os.Stderr, "Finalized %d cleanup(s) older than the %s undo window; they can no longer be undone.\n", len(purged), units.FormatDuration(window))
+	}
+}
+
+// undoWindow returns the configured undo window, or 0 when there is none.
+func undoWindow() time.Duration {
+	cfg, err := loadConfig()
+	if err != nil {
+		return 0
+	}
+	return time.Duration(cfg.UndoWindow)
+}
+
+// printUndoHint tells how to undo a cleanup trashed at t, and until when.
+func printUndoHint(cfg *config.Config, t time.Time) {
+	if cfg != nil && cfg.UndoWindow > 0 {
+		until := cleaner.ExpiresAt(t, time.Duration(cfg.UndoWindow))
+		PrintInfo("You can undo this action by running 'burrow undo' until %s.", until.Format("Jan 2 15:04"))
+		return
+	}
+	PrintInfo("You can undo this action by running 'burrow undo'.")
+}
@@ -1,22 +1,138 @@
 package ui
 
 import (
+	"bufio"
 	"fmt"
+	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/units"
 )
 
 // FormatSize converts bytes to a human-readable string.
 func FormatSize(bytes int64) string {
-	const unit = 1024
-	if bytes < unit {
-		return fmt.Sprintf("%d B", bytes)
+	return units.FormatSize(bytes)
+}
+
+// FormatCount formats n with thousands separators, e.g. 12,431.
+func FormatCount(n int) string {
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0 && s[i-1] != '-'; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+// formatStats describes a result's file count and staleness, e.g.
+// "12,431 files, last touched 84 days ago".
+func formatStats(st rules.PathStats) string {
+	if st.Files == 0 {
+		return "no files"
 	}
-	div, exp := int64(unit), 0
-	for n := bytes / unit; n >= unit; n /= unit {
-		div *= unit
-		exp++
+	noun := "files"
+	if st.Files == 1 {
+		noun = "file"
 	}
-	return fmt.Sprintf("%.2f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+	desc := fmt.Sprintf("%s %s, last touched %s", FormatCount(st.Files), noun, formatAgo(st.Newest))
+	if !st.LastUsed.IsZero() {
+		desc += ", last used " + formatAgo(st.LastUsed)
+	}
+	return desc
+}
+
+// maxItems is how many items of a result scan lists before summing up the
+// rest.
+const maxItems = 8
+
+// printItems lists the items of a result whose rule names them, such as
+// DerivedData projects, largest first. Paths of the same item, such as the
+// cache folders of one app, are summed up.
+func printItems(res rules.Result) {
+	var items []rules.PathStats
+	index := make(map[string]int)
+	for _, st := range res.Stats {
+		if st.Item == "" {
+			continue
+		}
+		i, ok := index[st.Item]
+		if !ok {
+			index[st.Item] = len(items)
+			items = append(items, st)
+			continue
+		}
+		it := &items[i]
+		it.Size += st.Size
+		it.Files += st.Files
+		if st.Newest.After(it.Newest) {
+			it.Newest = st.Newest
+		}
+		if st.LastUsed.After(it.LastUsed) {
+			it.LastUsed = st.LastUsed
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Size > items[j].Size })
+	for i, st := range items {
+		if i == maxItems {
+			fmt.Printf("      %s\n", Colorize(Gray, fmt.Sprintf("%s and %d more (see 'burrow list')", Symbol("…", "..."), len(items)-maxItems)))
+			break
+		}
+		used := "last touched " + formatAgo(st.Newest)
+		if !st.LastUsed.IsZero() {
+			used = "last used " + formatAgo(st.LastUsed)
+		}
+		fmt.Printf("      %s %-30s %-12s %s\n", Symbol("↳", "-"), st.Item, FormatSize(st.Size), Colorize(Gray, used))
+	}
+}
+
+// formatSizes shows the apparent and on-disk size of files, which differ
+// for compressed and sparse files, and for many small ones.
+func formatSizes(st rules.PathStats) string {
+	return fmt.Sprintf("%s apparent, %s on disk", FormatSize(st.Size), FormatSize(st.Allocated))
+}
+
+// categoryLabel prefixes a category name with its icon, except in plain mode.
+func categoryLabel(name string) string {
+	return Symbol(rules.LookupCategory(name).Icon+" ", "") + name
+}
+
+// padCategory pads a category label to width terminal columns; icons take
+// two columns but count as one rune.
+func padCategory(name string, width int) string {
+	if !plain {
+		width--
+	}
+	return fmt.Sprintf("%-*s", width, categoryLabel(name))
+}
+
+// formatAgo renders a past time as a coarse relative age.
+func formatAgo(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%d min ago", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%d hours ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%d days ago", int(d.Hours()/24))
+	}
+}
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(v string) error {
+	*s = append(*s, v)
+	return nil
 }
 
 // Confirm asks the user for confirmation.
@@ -27,3 +143,11 @@ func Confirm(prompt string) bool {
 	s = strings.ToLower(strings.TrimSpace(s))
 	return s == "y" || s == "yes"
 }
+
+// ConfirmTyped asks the user to type an exact token to confirm a dangerous action.
+func ConfirmTyped(prompt, token string) bool {
+	fmt.Printf("%s\nType '%s' to continue: ", prompt, token)
+	reader := bufio.NewReader(os.Stdin)
+	input, _ := reader.ReadString('\n')
+	return strings.TrimSpace(input) == token
+}
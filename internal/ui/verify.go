@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+
+	"github.com/ismailtsdln/burrow/internal/verify"
+)
+
+// runVerify audits the trash, history, config, and rules, and with --repair
+// fixes what it can.
+func runVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	repair := fs.Bool("repair", false, "Fix the issues that can be fixed safely")
+	js := fs.Bool("json", false, "Output in JSON format")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	report := verify.Run()
+	var repairErr error
+	if *repair {
+		repairErr = report.Repair()
+	}
+
+	if *js {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		printVerification(report, *repair)
+	}
+	if repairErr != nil {
+		return repairErr
+	}
+	if !report.OK() {
+		left := 0
+		for _, i := range report.Issues {
+			if !i.Repaired {
+				left++
+			}
+		}
+		return fmt.Errorf("verification found %d unresolved issue(s)", left)
+	}
+	return nil
+}
+
+func printVerification(report *verify.Report, repaired bool) {
+	PrintHeader("Burrow Verify")
+	for _, area := range []string{verify.AreaTrash, verify.AreaHistory, verify.AreaConfig, verify.AreaRules} {
+		var issues []verify.Issue
+		for _, i := range report.Issues {
+			if i.Area == area {
+				issues = append(issues, i)
+			}
+		}
+		if len(issues) == 0 {
+			PrintSuccess("%-8s %d checked, no issues", area, report.Checked[area])
+			continue
+		}
+		PrintWarning("%-8s %d checked, %d issue(s)", area, report.Checked[area], len(issues))
+		for _, i := range issues {
+			fmt.Printf("    %s: %s\n", Colorize(Cyan, i.Subject), i.Problem)
+			switch {
+			case i.Repaired:
+				fmt.Printf("      %s %s\n", Colorize(Green, Symbol("✓", "FIXED")), i.Repair)
+			case i.Repair != "":
+				fmt.Printf("      %s\n", Colorize(Gray, "repair: "+i.Repair))
+			}
+		}
+	}
+
+	fixable := 0
+	for _, i := range report.Issues {
+		if i.Repair != "" && !i.Repaired {
+			fixable++
+		}
+	}
+	if fixable > 0 && !repaired {
+		fmt.Println()
+		PrintInfo("Run 'burrow verify --repair' to fix %d of them.", fixable)
+	}
+}
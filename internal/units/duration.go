@@ -0,0 +1,134 @@
+// Package units parses and formats the human-friendly durations used on the
+// command line and in configuration and rule files.
+package units
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	Day   = 24 * time.Hour
+	Week  = 7 * Day
+	Month = 30 * Day // Calendar months vary; ages only need to be approximate
+)
+
+// durationUnits is ordered so that "mo" is tried before "m" and "ms" before "m".
+var durationUnits = []struct {
+	name string
+	size time.Duration
+}{
+	{"mo", Month},
+	{"ms", time.Millisecond},
+	{"w", Week},
+	{"d", Day},
+	{"h", time.Hour},
+	{"m", time.Minute},
+	{"s", time.Second},
+}
+
+// ParseDuration parses a duration such as "30d", "2w", "3mo", or "1w3d".
+// Besides days, weeks, and months (30 days) it accepts the units of
+// time.ParseDuration except µs and ns; every number needs a unit.
+func ParseDuration(s string) (time.Duration, error) {
+	in := strings.ToLower(strings.TrimSpace(s))
+	if in == "" {
+		return 0, fmt.Errorf("empty duration (example: 30d, 2w, 3mo)")
+	}
+
+	var total time.Duration
+	for rest := in; rest != ""; {
+		n := strings.IndexFunc(rest, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+		if n == 0 {
+			return 0, fmt.Errorf("invalid duration %q: expected a number before %q", s, rest)
+		}
+		if n < 0 {
+			return 0, fmt.Errorf("invalid duration %q: missing unit after %s (use mo, w, d, h, m, or s)", s, rest)
+		}
+		value, err := strconv.ParseFloat(rest[:n], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: bad number %q", s, rest[:n])
+		}
+		rest = rest[n:]
+
+		matched := false
+		for _, u := range durationUnits {
+			if strings.HasPrefix(rest, u.name) {
+				total += time.Duration(value * float64(u.size))
+				rest = rest[len(u.name):]
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			end := strings.IndexFunc(rest, func(r rune) bool { return r >= '0' && r <= '9' })
+			if end < 0 {
+				end = len(rest)
+			}
+			return 0, fmt.Errorf("invalid duration %q: unknown unit %q (use mo, w, d, h, m, or s)", s, rest[:end])
+		}
+	}
+	return total, nil
+}
+
+// FormatDuration renders d in the largest unit that divides it exactly, so
+// ParseDuration(FormatDuration(d)) == d for values ParseDuration produces.
+func FormatDuration(d time.Duration) string {
+	if d == 0 {
+		return "0s"
+	}
+	for _, u := range []struct {
+		name string
+		size time.Duration
+	}{{"mo", Month}, {"w", Week}, {"d", Day}} {
+		if d%u.size == 0 {
+			return fmt.Sprintf("%d%s", d/u.size, u.name)
+		}
+	}
+	return d.String()
+}
+
+// Duration is a time.Duration that is written as a string like "14d" in
+// JSON and YAML files.
+type Duration time.Duration
+
+// MarshalJSON encodes d with FormatDuration.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(FormatDuration(time.Duration(d)))
+}
+
+// UnmarshalJSON decodes a duration string. Bare numbers are rejected because
+// their unit would be ambiguous.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"30d\", got %s", data)
+	}
+	v, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// String implements flag.Value.
+func (d *Duration) String() string {
+	if d == nil || *d == 0 {
+		return ""
+	}
+	return FormatDuration(time.Duration(*d))
+}
+
+// Set implements flag.Value, so flags accept the same syntax as config files.
+func (d *Duration) Set(s string) error {
+	v, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
@@ -0,0 +1,60 @@
+package units
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"30d", 30 * Day, false},
+		{"2w", 2 * Week, false},
+		{"3mo", 3 * Month, false},
+		{"1w3d", 10 * Day, false},
+		{"1.5d", 36 * time.Hour, false},
+		{"24h", 24 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{" 7D ", 7 * Day, false},
+		{"", 0, true},
+		{"30", 0, true},
+		{"d", 0, true},
+		{"3y", 0, true},
+		{"1.2.3d", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDuration(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDurationJSON(t *testing.T) {
+	for _, d := range []time.Duration{3 * Month, 2 * Week, 5 * Day, 90 * time.Minute} {
+		data, err := json.Marshal(Duration(d))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var back Duration
+		if err := json.Unmarshal(data, &back); err != nil {
+			t.Fatalf("unmarshal %s: %v", data, err)
+		}
+		if time.Duration(back) != d {
+			t.Errorf("%v round-tripped through %s as %v", d, data, time.Duration(back))
+		}
+	}
+
+	var d Duration
+	if err := json.Unmarshal([]byte("30"), &d); err == nil {
+		t.Error("bare numbers should be rejected")
+	}
+}
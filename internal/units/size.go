@@ -0,0 +1,106 @@
+package units
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Binary size units, matching FormatSize.
+const (
+	KB int64 = 1 << (10 * (iota + 1))
+	MB
+	GB
+	TB
+)
+
+var sizeUnits = []struct {
+	suffix string
+	factor int64
+}{
+	{"TB", TB}, {"GB", GB}, {"MB", MB}, {"KB", KB},
+	{"T", TB}, {"G", GB}, {"M", MB}, {"K", KB}, {"B", 1},
+}
+
+// ParseSize converts a human-readable size such as "20GB", "1.5G", or
+// "512 MB" to bytes. Units are binary (1 KB = 1024 B) to match FormatSize; a
+// bare number is a byte count.
+func ParseSize(s string) (int64, error) {
+	in := strings.ToUpper(strings.TrimSpace(s))
+
+	factor := int64(1)
+	for _, u := range sizeUnits {
+		if strings.HasSuffix(in, u.suffix) {
+			factor = u.factor
+			in = strings.TrimSpace(strings.TrimSuffix(in, u.suffix))
+			break
+		}
+	}
+
+	value, err := strconv.ParseFloat(in, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size %q (example: 500MB, 1.5GB)", s)
+	}
+	return int64(value * float64(factor)), nil
+}
+
+// FormatSize converts bytes to a human-readable string such as "1.50 GB".
+func FormatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.2f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// Size is a byte count written as a string like "1.5GB" in JSON and YAML
+// files. Plain numbers are accepted as bytes.
+type Size int64
+
+// MarshalJSON encodes s with FormatSize.
+func (s Size) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.ReplaceAll(FormatSize(int64(s)), " ", ""))
+}
+
+// UnmarshalJSON decodes a size string or a byte count.
+func (s *Size) UnmarshalJSON(data []byte) error {
+	var n int64
+	if err := json.Unmarshal(data, &n); err == nil {
+		*s = Size(n)
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return fmt.Errorf("size must be a string such as \"500MB\", got %s", data)
+	}
+	v, err := ParseSize(str)
+	if err != nil {
+		return err
+	}
+	*s = Size(v)
+	return nil
+}
+
+// String implements flag.Value.
+func (s *Size) String() string {
+	if s == nil || *s == 0 {
+		return ""
+	}
+	return FormatSize(int64(*s))
+}
+
+// Set implements flag.Value.
+func (s *Size) Set(v string) error {
+	n, err := ParseSize(v)
+	if err != nil {
+		return err
+	}
+	*s = Size(n)
+	return nil
+}
@@ -0,0 +1,52 @@
+package units
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"500MB", 500 * MB, false},
+		{"1.5GB", 3 * GB / 2, false},
+		{"512 mb", 512 * MB, false},
+		{"2G", 2 * GB, false},
+		{"100", 100, false},
+		{"", 0, true},
+		{"-1GB", 0, true},
+		{"lots", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSize(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSize(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseSize(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSizeJSON(t *testing.T) {
+	var cfg struct {
+		Threshold Size `json:"threshold"`
+		Free      Size `json:"free"`
+	}
+	if err := json.Unmarshal([]byte(`{"threshold": "1.5GB", "free": 4096}`), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Threshold != Size(3*GB/2) || cfg.Free != 4096 {
+		t.Errorf("got %+v", cfg)
+	}
+
+	data, _ := json.Marshal(cfg.Threshold)
+	var back Size
+	if err := json.Unmarshal(data, &back); err != nil || back != cfg.Threshold {
+		t.Errorf("round trip through %s = %d, %v", data, back, err)
+	}
+}
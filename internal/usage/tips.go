@@ -0,0 +1,85 @@
+package usage
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/history"
+	"github.com/ismailtsdln/burrow/internal/units"
+)
+
+// TipInterval is how long to wait between tips, so they stay occasional.
+const TipInterval = 24 * time.Hour
+
+var (
+	reclaimedMilestones = []int64{units.GB, 10 * units.GB, 50 * units.GB, 100 * units.GB, 250 * units.GB, 500 * units.GB, units.TB}
+	cleanupMilestones   = []int{10, 25, 50, 100, 250, 500, 1000}
+)
+
+// Milestone is a lifetime total the latest cleanup reached: Bytes reclaimed
+// or a number of Cleanups.
+type Milestone struct {
+	Bytes    int64 `json:"bytes,omitempty"`
+	Cleanups int   `json:"cleanups,omitempty"`
+}
+
+// Milestones returns the milestones crossed by the latest cleanup, which
+// reclaimed bytes and is already included in s.
+func (s *Stats) Milestones(bytes int64) []Milestone {
+	var reached []Milestone
+	before := s.ReclaimedBytes - bytes
+	for _, m := range reclaimedMilestones {
+		if before < m && s.ReclaimedBytes >= m {
+			reached = append(reached, Milestone{Bytes: m})
+		}
+	}
+	if slices.Contains(cleanupMilestones, s.Cleanups) {
+		reached = append(reached, Milestone{Cleanups: s.Cleanups})
+	}
+	return reached
+}
+
+// RuleCount is how many cleanups in a period included a rule.
+type RuleCount struct {
+	Rule  string `json:"rule"`
+	Count int    `json:"count"`
+}
+
+// Regrowing returns the rules cleaned at least min times since the given
+// time, most often first: their data keeps coming back.
+func Regrowing(entries []history.Entry, since time.Time, min int) []RuleCount {
+	counts := make(map[string]int)
+	for _, e := range entries {
+		if e.Timestamp.Before(since) {
+			continue
+		}
+		for _, r := range e.Rules {
+			counts[r]++
+		}
+	}
+
+	var list []RuleCount
+	for r, n := range counts {
+		if n >= min {
+			list = append(list, RuleCount{Rule: r, Count: n})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Rule < list[j].Rule
+	})
+	return list
+}
+
+// TipDue reports whether enough time has passed since the last tip.
+func (s *Stats) TipDue(now time.Time) bool {
+	return now.Sub(s.LastTip) >= TipInterval
+}
+
+// RecordTip notes that a tip was shown.
+func (m *Manager) RecordTip() error {
+	return m.update(func(s *Stats) { s.LastTip = time.Now() })
+}
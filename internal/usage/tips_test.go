@@ -0,0 +1,50 @@
+package usage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/history"
+	"github.com/ismailtsdln/burrow/internal/units"
+)
+
+func TestStats_Milestones(t *testing.T) {
+	s := &Stats{ReclaimedBytes: 101 * units.GB, Cleanups: 10}
+	got := s.Milestones(60 * units.GB)
+	want := []Milestone{{Bytes: 50 * units.GB}, {Bytes: 100 * units.GB}, {Cleanups: 10}}
+	if len(got) != len(want) {
+		t.Fatalf("milestones = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("milestone %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if got := (&Stats{ReclaimedBytes: 5 * units.GB, Cleanups: 11}).Milestones(units.GB); len(got) != 0 {
+		t.Errorf("no milestone expected, got %+v", got)
+	}
+}
+
+func TestRegrowing(t *testing.T) {
+	now := time.Now()
+	entries := []history.Entry{
+		{Timestamp: now.Add(-time.Hour), Rules: []string{"Xcode DerivedData", "npm Cache"}},
+		{Timestamp: now.Add(-72 * time.Hour), Rules: []string{"Xcode DerivedData"}},
+		{Timestamp: now.Add(-240 * time.Hour), Rules: []string{"Xcode DerivedData", "npm Cache"}},
+		{Timestamp: now.Add(-60 * 24 * time.Hour), Rules: []string{"npm Cache"}},
+	}
+	got := Regrowing(entries, now.AddDate(0, -1, 0), 2)
+	if len(got) != 2 || got[0] != (RuleCount{"Xcode DerivedData", 3}) || got[1] != (RuleCount{"npm Cache", 2}) {
+		t.Errorf("regrowing = %+v", got)
+	}
+}
+
+func TestStats_TipDue(t *testing.T) {
+	now := time.Now()
+	if !(&Stats{}).TipDue(now) {
+		t.Error("first tip should be due")
+	}
+	if (&Stats{LastTip: now.Add(-time.Hour)}).TipDue(now) {
+		t.Error("tip shown an hour ago should not be due")
+	}
+}
@@ -0,0 +1,165 @@
+// Package usage keeps purely local statistics about how Burrow is used:
+// scans run, space reclaimed, and the rules that reclaim it. Nothing is
+// ever sent anywhere.
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/history"
+	"github.com/ismailtsdln/burrow/internal/paths"
+)
+
+// Stats are the lifetime totals.
+type Stats struct {
+	// Since is when Burrow was first used, or the oldest cleanup in the
+	// history when the stats were started after it.
+	Since          time.Time            `json:"since"`
+	Scans          int                  `json:"scans"`
+	Cleanups       int                  `json:"cleanups"`
+	Undos          int                  `json:"undos"`
+	ReclaimedBytes int64                `json:"reclaimed_bytes"`
+	ItemsCleaned   int                  `json:"items_cleaned"`
+	Rules          map[string]RuleUsage `json:"rules,omitempty"`
+	LastScan       time.Time            `json:"last_scan,omitzero"`
+	LastCleanup    time.Time            `json:"last_cleanup,omitzero"`
+	LastTip        time.Time            `json:"last_tip,omitzero"`
+}
+
+// RuleUsage is how often a rule's findings were cleaned and how much that
+// reclaimed.
+type RuleUsage struct {
+	Cleanups       int   `json:"cleanups"`
+	ReclaimedBytes int64 `json:"reclaimed_bytes"`
+}
+
+// RuleTotal is a rule's usage with its name, for ranking.
+type RuleTotal struct {
+	Rule string `json:"rule"`
+	RuleUsage
+}
+
+// TopRules returns the n rules that reclaimed the most, most first; n <= 0
+// returns them all.
+func (s *Stats) TopRules(n int) []RuleTotal {
+	list := make([]RuleTotal, 0, len(s.Rules))
+	for name, u := range s.Rules {
+		list = append(list, RuleTotal{Rule: name, RuleUsage: u})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].ReclaimedBytes != list[j].ReclaimedBytes {
+			return list[i].ReclaimedBytes > list[j].ReclaimedBytes
+		}
+		return list[i].Rule < list[j].Rule
+	})
+	if n > 0 && len(list) > n {
+		list = list[:n]
+	}
+	return list
+}
+
+// Manager persists the stats in the data directory.
+type Manager struct {
+	path string
+}
+
+// NewManager creates a manager for the default usage file.
+func NewManager() *Manager {
+	return &Manager{path: paths.UsageFile()}
+}
+
+// Load returns the stored stats. Before anything was recorded, they are
+// started from the cleanup history.
+func (m *Manager) Load() (*Stats, error) {
+	data, err := os.ReadFile(m.path)
+	if os.IsNotExist(err) {
+		return fromHistory(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var s Stats
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", m.path, err)
+	}
+	return &s, nil
+}
+
+// RecordScan counts a scan.
+func (m *Manager) RecordScan() error {
+	return m.update(func(s *Stats) {
+		s.Scans++
+		s.LastScan = time.Now()
+	})
+}
+
+// RecordCleanup counts a cleanup that reclaimed byRule bytes per rule in
+// items paths.
+func (m *Manager) RecordCleanup(byRule map[string]int64, items int) error {
+	return m.update(func(s *Stats) {
+		s.Cleanups++
+		s.ItemsCleaned += items
+		s.LastCleanup = time.Now()
+		if s.Rules == nil {
+			s.Rules = make(map[string]RuleUsage)
+		}
+		for name, bytes := range byRule {
+			u := s.Rules[name]
+			u.Cleanups++
+			u.ReclaimedBytes += bytes
+			s.Rules[name] = u
+			s.ReclaimedBytes += bytes
+		}
+	})
+}
+
+// RecordUndo counts an undo. Restored space still counts as reclaimed: it
+// was free until the undo.
+func (m *Manager) RecordUndo() error {
+	return m.update(func(s *Stats) { s.Undos++ })
+}
+
+func (m *Manager) update(change func(*Stats)) error {
+	s, err := m.Load()
+	if err != nil {
+		return err
+	}
+	if s.Since.IsZero() {
+		s.Since = time.Now()
+	}
+	change(s)
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(m.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(m.path, data, 0644)
+}
+
+// fromHistory starts the stats from the cleanups in the history, so space
+// reclaimed before the stats existed is not lost.
+func fromHistory() *Stats {
+	s := &Stats{}
+	entries, _ := history.NewManager().Load()
+	for _, e := range entries {
+		s.Cleanups++
+		s.ReclaimedBytes += e.ReclaimedBytes
+		s.ItemsCleaned += e.FileCount
+		if s.Since.IsZero() || e.Timestamp.Before(s.Since) {
+			s.Since = e.Timestamp
+		}
+		if e.Timestamp.After(s.LastCleanup) {
+			s.LastCleanup = e.Timestamp
+		}
+	}
+	return s
+}
@@ -0,0 +1,40 @@
+package usage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/history"
+	"github.com/ismailtsdln/burrow/internal/paths"
+)
+
+func TestManager_Record(t *testing.T) {
+	t.Setenv(paths.DataEnv, t.TempDir())
+	started := time.Now().Add(-48 * time.Hour)
+	history.NewManager().Save(history.Entry{ID: "20240101_000000", Timestamp: started, ReclaimedBytes: 100, FileCount: 2})
+
+	m := &Manager{path: filepath.Join(t.TempDir(), "usage.json")}
+	if err := m.RecordScan(); err != nil {
+		t.Fatal(err)
+	}
+	m.RecordCleanup(map[string]int64{"npm Cache": 50, "Yarn Cache": 70}, 3)
+	m.RecordCleanup(map[string]int64{"npm Cache": 40}, 1)
+	m.RecordUndo()
+
+	s, err := m.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The cleanup already in the history counts once
+	if s.Scans != 1 || s.Cleanups != 3 || s.Undos != 1 || s.ItemsCleaned != 6 || s.ReclaimedBytes != 260 {
+		t.Errorf("unexpected totals %+v", s)
+	}
+	if !s.Since.Equal(started) {
+		t.Errorf("since = %v, want the oldest history entry %v", s.Since, started)
+	}
+	top := s.TopRules(1)
+	if len(top) != 1 || top[0].Rule != "npm Cache" || top[0].Cleanups != 2 || top[0].ReclaimedBytes != 90 {
+		t.Errorf("top rules = %+v", top)
+	}
+}
@@ -0,0 +1,270 @@
+// Package verify audits Burrow's own state — the trash, the history, the
+// config, and the rules — and repairs what can be repaired safely.
+package verify
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/cleaner"
+	"github.com/ismailtsdln/burrow/internal/config"
+	"github.com/ismailtsdln/burrow/internal/history"
+	"github.com/ismailtsdln/burrow/internal/paths"
+	"github.com/ismailtsdln/burrow/internal/rules"
+	"github.com/ismailtsdln/burrow/internal/safety"
+	"github.com/ismailtsdln/burrow/internal/schema"
+)
+
+// Areas of Burrow's state that are checked, in report order.
+const (
+	AreaTrash   = "trash"
+	AreaHistory = "history"
+	AreaConfig  = "config"
+	AreaRules   = "rules"
+)
+
+// sessionLayout is how MoveToTrash names sessions.
+const sessionLayout = "20060102_150405"
+
+// Issue is one inconsistency found by Run.
+type Issue struct {
+	Area    string `json:"area"`
+	Subject string `json:"subject"`
+	Problem string `json:"problem"`
+	// Repair describes what Repair does about the issue; it is empty when
+	// the issue needs a person.
+	Repair   string `json:"repair,omitempty"`
+	Repaired bool   `json:"repaired,omitempty"`
+
+	fix func() error
+}
+
+// Report is the outcome of a verification.
+type Report struct {
+	// Checked counts the items examined per area.
+	Checked map[string]int `json:"checked"`
+	Issues  []Issue        `json:"issues"`
+}
+
+// OK reports whether no issue is left unrepaired.
+func (r *Report) OK() bool {
+	for _, i := range r.Issues {
+		if !i.Repaired {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Report) add(i Issue) {
+	r.Issues = append(r.Issues, i)
+}
+
+// Run checks every area without changing anything.
+func Run() *Report {
+	r := &Report{Checked: map[string]int{}, Issues: []Issue{}}
+	checkTrash(r, cleaner.NewTrashManager())
+	checkHistory(r, history.NewManager())
+	checkConfig(r)
+	checkRules(r)
+	return r
+}
+
+// Repair applies the fixes of the issues that have one and marks them
+// repaired. Fixes that fail are reported together.
+func (r *Report) Repair() error {
+	var errs []error
+	for i := range r.Issues {
+		issue := &r.Issues[i]
+		if issue.fix == nil || issue.Repaired {
+			continue
+		}
+		if err := issue.fix(); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", issue.Area, issue.Subject, err))
+			continue
+		}
+		issue.Repaired = true
+	}
+	return errors.Join(errs...)
+}
+
+// checkTrash checks that every session has a readable manifest and every
+// entry in it still exists in the trash.
+func checkTrash(r *Report, tm *cleaner.TrashManager) {
+	sessions, err := tm.Sessions()
+	if err != nil {
+		r.add(Issue{Area: AreaTrash, Subject: tm.TrashBaseDir, Problem: err.Error()})
+		return
+	}
+
+	for _, s := range sessions {
+		r.Checked[AreaTrash]++
+		if s.Interrupted {
+			r.add(Issue{
+				Area:    AreaTrash,
+				Subject: s.ID,
+				Problem: "a cleanup is still moving files into this session, or was interrupted",
+				Repair:  "record what was moved (skipped while the cleanup is running)",
+				fix: func() error {
+					_, err := tm.Recover()
+					return err
+				},
+			})
+			continue
+		}
+		if s.Orphaned {
+			_, err := tm.Manifest(s.ID)
+			r.add(Issue{
+				Area:    AreaTrash,
+				Subject: s.ID,
+				Problem: fmt.Sprintf("manifest is missing or unreadable (%v)", errors.Unwrap(err)),
+				Repair:  "rebuild the manifest from the session's contents, its history entry, and the rule paths",
+				fix: func() error {
+					candidates := rulePaths()
+					if entry, err := history.NewManager().Find(s.ID); err == nil {
+						candidates = append(entry.Paths, candidates...)
+					}
+					_, err := tm.RepairOrphan(s.ID, candidates)
+					return err
+				},
+			})
+			continue
+		}
+
+		for _, e := range s.Manifest.Entries {
+			if _, err := os.Lstat(e.TrashPath); err != nil {
+				r.add(Issue{
+					Area:    AreaTrash,
+					Subject: s.ID,
+					Problem: fmt.Sprintf("%s is in the manifest but not in the trash", e.TrashPath),
+					Repair:  "drop the missing entries from the manifest",
+					fix: func() error {
+						_, err := tm.PruneMissing(s.ID)
+						return err
+					},
+				})
+			} else if e.OriginalPath == "" {
+				r.add(Issue{
+					Area:    AreaTrash,
+					Subject: s.ID,
+					Problem: fmt.Sprintf("%s has no known original location; undo leaves it in the trash", e.TrashPath),
+				})
+			}
+		}
+	}
+}
+
+// checkHistory checks that every history entry names a session Burrow could
+// have created, and that no two entries share one.
+func checkHistory(r *Report, m *history.Manager) {
+	entries, err := m.Load()
+	if err != nil {
+		r.add(Issue{Area: AreaHistory, Subject: paths.HistoryFile(), Problem: fmt.Sprintf("does not parse: %v", err)})
+		return
+	}
+
+	seen := make(map[string]int)
+	for _, e := range entries {
+		r.Checked[AreaHistory]++
+		// Older versions recorded every cleanup that bypassed the trash under
+		// the bare marker
+		if seen[e.ID]++; seen[e.ID] == 2 && cleaner.BypassMarker(e.ID) != e.ID {
+			r.add(Issue{Area: AreaHistory, Subject: e.ID, Problem: "several history entries share this session ID"})
+		}
+		if !validSessionID(e.ID) {
+			r.add(Issue{
+				Area:    AreaHistory,
+				Subject: fmt.Sprintf("%q", e.ID),
+				Problem: "entry does not reference a valid cleanup session",
+				Repair:  "remove the entry from the history",
+				fix:     func() error { return m.Remove(e.ID) },
+			})
+		}
+	}
+}
+
+// validSessionID reports whether id names a session of Burrow's trash, the
+// time it started with a -N suffix if another started in the same second, or
+// a cleanup that bypassed the trash: a marker, bare or followed by the time.
+func validSessionID(id string) bool {
+	if m := cleaner.BypassMarker(id); m != "" {
+		if id == m {
+			return true
+		}
+		id = strings.TrimPrefix(id, m+"-")
+	}
+	if stamp, n, ok := strings.Cut(id, "-"); ok {
+		if i, err := strconv.Atoi(n); err != nil || i < 2 {
+			return false
+		}
+		id = stamp
+	}
+	_, err := time.ParseInLocation(sessionLayout, id, time.Local)
+	return err == nil
+}
+
+// checkConfig checks the config file against its schema and the
+// environment overrides.
+func checkConfig(r *Report) {
+	file := paths.ConfigFile()
+	if data, err := os.ReadFile(file); err == nil {
+		r.Checked[AreaConfig]++
+		errs, err := schema.Validate("config", data, true)
+		if err != nil {
+			r.add(Issue{Area: AreaConfig, Subject: file, Problem: err.Error()})
+		}
+		for _, e := range errs {
+			r.add(Issue{Area: AreaConfig, Subject: file, Problem: e.Error()})
+		}
+	}
+	if _, err := config.Load(); err != nil {
+		r.add(Issue{Area: AreaConfig, Subject: file, Problem: err.Error()})
+	}
+}
+
+// checkRules checks that custom rule files load and that no two rules share
+// a name, since commands that take a rule name pick the first.
+func checkRules(r *Report) {
+	if _, err := rules.LoadCustomRules(); err != nil {
+		for _, line := range strings.Split(err.Error(), "\n") {
+			r.add(Issue{Area: AreaRules, Subject: paths.ConfigDir(), Problem: line})
+		}
+	}
+
+	byName := make(map[string][]rules.CleanupRule)
+	var order []string
+	for _, rule := range rules.NewRegistry().All() {
+		r.Checked[AreaRules]++
+		key := strings.ToLower(rule.Name)
+		if _, ok := byName[key]; !ok {
+			order = append(order, key)
+		}
+		byName[key] = append(byName[key], rule)
+	}
+	for _, key := range order {
+		if same := byName[key]; len(same) > 1 {
+			r.add(Issue{
+				Area:    AreaRules,
+				Subject: same[0].Name,
+				Problem: fmt.Sprintf("%d rules share this name; commands that take a rule name use the first", len(same)),
+			})
+		}
+	}
+}
+
+// rulePaths returns the expanded paths of every rule, the best hint of where
+// the items of an orphaned session came from.
+func rulePaths() []string {
+	var candidates []string
+	for _, r := range rules.NewRegistry().All() {
+		for _, p := range r.Paths {
+			candidates = append(candidates, filepath.Clean(safety.ExpandPath(p)))
+		}
+	}
+	return candidates
+}
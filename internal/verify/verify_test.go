@@ -0,0 +1,113 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ismailtsdln/burrow/internal/cleaner"
+	"github.com/ismailtsdln/burrow/internal/history"
+	"github.com/ismailtsdln/burrow/internal/paths"
+)
+
+func TestRun_RepairsTrashAndHistory(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv(paths.DataEnv, filepath.Join(home, "data"))
+	t.Setenv(paths.ConfigEnv, "")
+
+	// A session with one entry whose trashed copy disappeared
+	var srcs []string
+	for _, name := range []string{"kept", "lost"} {
+		p := filepath.Join(home, name)
+		if err := os.WriteFile(p, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		srcs = append(srcs, p)
+	}
+	tm := cleaner.NewTrashManager()
+	id, err := tm.MoveToTrash(srcs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(tm.TrashBaseDir, id, "lost")); err != nil {
+		t.Fatal(err)
+	}
+	// An orphaned session
+	orphan := filepath.Join(tm.TrashBaseDir, "20200101_000000")
+	os.MkdirAll(orphan, 0755)
+	os.WriteFile(filepath.Join(orphan, "blob"), []byte("x"), 0644)
+
+	hm := history.NewManager()
+	hm.Save(history.Entry{ID: id, Timestamp: time.Now()})
+	hm.Save(history.Entry{ID: "../etc", Timestamp: time.Now()})
+	// Older versions recorded every permanent cleanup under the bare marker
+	hm.Save(history.Entry{ID: "PERMANENT", Timestamp: time.Now()})
+	hm.Save(history.Entry{ID: "PERMANENT", Timestamp: time.Now()})
+	hm.Save(history.Entry{ID: "SYSTEM-TRASH-20260105_093000", Timestamp: time.Now()})
+	hm.Save(history.Entry{ID: "SYSTEM-TRASH-20260105_093000-2", Timestamp: time.Now()})
+
+	report := Run()
+	want := map[string]int{AreaTrash: 2, AreaHistory: 1}
+	got := map[string]int{}
+	for _, i := range report.Issues {
+		got[i.Area]++
+		if i.Repair == "" {
+			t.Errorf("issue has no repair: %+v", i)
+		}
+	}
+	if got[AreaTrash] != want[AreaTrash] || got[AreaHistory] != want[AreaHistory] || len(report.Issues) != 3 {
+		t.Fatalf("issues = %+v", report.Issues)
+	}
+	if report.OK() {
+		t.Error("report with issues should not be OK")
+	}
+
+	if err := report.Repair(); err != nil {
+		t.Fatal(err)
+	}
+	if !report.OK() {
+		t.Errorf("issues left after repair: %+v", report.Issues)
+	}
+	// The orphan's item matches no rule path, which only a person can fix
+	if again := Run(); len(again.Issues) != 1 || again.Issues[0].Subject != "20200101_000000" || again.Issues[0].Repair != "" {
+		t.Errorf("issues after repair: %+v", again.Issues)
+	}
+	if _, err := hm.Find("../etc"); err == nil {
+		t.Error("invalid history entry was kept")
+	}
+	manifest, err := tm.Manifest(id)
+	if err != nil || len(manifest.Entries) != 1 || manifest.Entries[0].OriginalPath != srcs[0] {
+		t.Errorf("manifest after repair = %+v, %v", manifest, err)
+	}
+}
+
+func TestRun_ReportsConfigAndRuleProblems(t *testing.T) {
+	home := t.TempDir()
+	config := filepath.Join(home, ".config")
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", config)
+	t.Setenv(paths.DataEnv, filepath.Join(home, "data"))
+	t.Setenv(paths.ConfigEnv, "")
+
+	os.MkdirAll(filepath.Dir(paths.ConfigFile()), 0755)
+	os.WriteFile(paths.ConfigFile(), []byte(`{"size_treshold": 10}`), 0644)
+	os.WriteFile(paths.CustomRulesFile(), []byte(`[
+		{"name": "Logs", "category": "Custom", "paths": ["~/a"]},
+		{"name": "logs", "category": "Custom", "paths": ["~/b"]}
+	]`), 0644)
+
+	report := Run()
+	got := map[string]int{}
+	for _, i := range report.Issues {
+		got[i.Area]++
+		if i.Repair != "" {
+			t.Errorf("config and rule issues need a person: %+v", i)
+		}
+	}
+	if got[AreaConfig] == 0 || got[AreaRules] != 1 {
+		t.Errorf("issues = %+v", report.Issues)
+	}
+}